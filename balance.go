@@ -0,0 +1,193 @@
+package hackeserasdk
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ─── Load Balancing ─────────────────────────────────────────────────────────
+
+const defaultTargetCooldown = 30 * time.Second
+
+// Target is one API key and endpoint requests can be sent to.
+type Target struct {
+	// BaseURL defaults to the client's base URL.
+	BaseURL string
+	APIKey  string
+	// Weight is the target's share of requests relative to the other targets.
+	// Values below 1 count as 1.
+	Weight int
+}
+
+// LoadBalanceConfig configures distribution of requests across targets.
+type LoadBalanceConfig struct {
+	Targets []Target
+	// Cooldown is how long a target is skipped after a transport error, 429, or
+	// 5xx response. A longer Retry-After on the response is honored. Defaults to 30s.
+	Cooldown time.Duration
+}
+
+// TargetStatus reports a target's health and traffic. The API key is omitted.
+type TargetStatus struct {
+	BaseURL  string
+	Weight   int
+	Healthy  bool
+	Requests int64
+	Failures int64
+	// DownUntil is when an unhealthy target is tried again.
+	DownUntil time.Time
+}
+
+type balancer struct {
+	cooldown time.Duration
+	// basePath is the escaped path of the client's base URL, which route
+	// replaces with the target's.
+	basePath string
+
+	mu      sync.Mutex
+	targets []*balancedTarget
+}
+
+type balancedTarget struct {
+	Target
+	base      *url.URL
+	current   int
+	downUntil time.Time
+	requests  int64
+	failures  int64
+}
+
+// WithLoadBalancing spreads requests across several API keys or regional
+// endpoints using smooth weighted round-robin, so throughput is not capped by a
+// single key's rate limit. A target that fails is skipped for the cooldown; if
+// every target is cooling down, the one due back first is used. Each retry
+// attempt picks a target afresh, so WithRetry moves on from a failing one.
+// If a target's BaseURL does not parse, every request fails with that error.
+//
+//	client.WithLoadBalancing(hackeserasdk.LoadBalanceConfig{Targets: []hackeserasdk.Target{
+//		{APIKey: keyA, Weight: 3},
+//		{APIKey: keyB, Weight: 1},
+//		{BaseURL: "https://eu.api-ai.hackersera.com", APIKey: keyEU, Weight: 2},
+//	}})
+func (c *Client) WithLoadBalancing(cfg LoadBalanceConfig) *Client {
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = defaultTargetCooldown
+	}
+	b := &balancer{cooldown: cfg.Cooldown}
+	if u, err := url.Parse(c.baseURL); err == nil {
+		b.basePath = u.EscapedPath()
+	}
+	for _, t := range cfg.Targets {
+		if t.BaseURL == "" {
+			t.BaseURL = c.baseURL
+		}
+		t.BaseURL = strings.TrimRight(t.BaseURL, "/")
+		if t.Weight < 1 {
+			t.Weight = 1
+		}
+		base, err := url.Parse(t.BaseURL)
+		if err != nil {
+			c.configErr = fmt.Errorf("load balancing: invalid target base URL %q: %w", t.BaseURL, err)
+			return c
+		}
+		b.targets = append(b.targets, &balancedTarget{Target: t, base: base})
+	}
+	if len(b.targets) == 0 {
+		b = nil
+	}
+	c.balancer = b
+	return c
+}
+
+// TargetStatuses returns the state of each load-balanced target, in
+// configuration order. It returns nil without WithLoadBalancing.
+func (c *Client) TargetStatuses() []TargetStatus {
+	b := c.balancer
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	out := make([]TargetStatus, len(b.targets))
+	for i, t := range b.targets {
+		out[i] = TargetStatus{
+			BaseURL:   t.BaseURL,
+			Weight:    t.Weight,
+			Healthy:   !now.Before(t.downUntil),
+			Requests:  t.requests,
+			Failures:  t.failures,
+			DownUntil: t.downUntil,
+		}
+	}
+	return out
+}
+
+// pick chooses the next target by smooth weighted round-robin over the healthy
+// targets.
+func (b *balancer) pick() *balancedTarget {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	var best, soonest *balancedTarget
+	total := 0
+	for _, t := range b.targets {
+		if now.Before(t.downUntil) {
+			if soonest == nil || t.downUntil.Before(soonest.downUntil) {
+				soonest = t
+			}
+			continue
+		}
+		t.current += t.Weight
+		total += t.Weight
+		if best == nil || t.current > best.current {
+			best = t
+		}
+	}
+	if best == nil {
+		best = soonest
+	} else {
+		best.current -= total
+	}
+	best.requests++
+	return best
+}
+
+// observe records the result of a request sent to t.
+func (b *balancer) observe(t *balancedTarget, resp *http.Response, err error) {
+	if err == nil && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return
+	}
+	down := b.cooldown
+	if resp != nil {
+		if after := retryAfter(resp); after > down {
+			down = after
+		}
+	}
+	b.mu.Lock()
+	t.failures++
+	t.downUntil = time.Now().Add(down)
+	b.mu.Unlock()
+}
+
+// route returns req addressed to t: the client's base URL is replaced by the
+// target's and the Authorization header carries the target's key.
+func (c *Client) route(req *http.Request, t *balancedTarget) *http.Request {
+	out := req.Clone(req.Context())
+	u := *t.base
+	escaped := t.base.EscapedPath() + strings.TrimPrefix(req.URL.EscapedPath(), c.balancer.basePath)
+	if path, err := url.PathUnescape(escaped); err == nil {
+		u.Path, u.RawPath = path, escaped
+	}
+	u.RawQuery = req.URL.RawQuery
+	out.URL = &u
+	out.Host = ""
+	if t.APIKey != "" {
+		out.Header.Set("Authorization", "Bearer "+t.APIKey)
+	}
+	return out
+}
@@ -0,0 +1,113 @@
+package hackeserasdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadBalancingWeights(t *testing.T) {
+	primary := newScriptedServer(t)
+	defer primary.Close()
+	secondary := newScriptedServer(t)
+	defer secondary.Close()
+
+	client := NewClient(primary.URL, "unused").WithLoadBalancing(LoadBalanceConfig{Targets: []Target{
+		{APIKey: "key-a", Weight: 3},
+		{BaseURL: secondary.URL + "/", APIKey: "key-b", Weight: 1},
+	}})
+	for i := 0; i < 8; i++ {
+		if _, err := client.ChatCompletion(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if a, b := primary.count("/v1/chat/completions"), secondary.count("/v1/chat/completions"); a != 6 || b != 2 {
+		t.Errorf("expected a 6/2 split, got %d/%d", a, b)
+	}
+	if got := primary.last("/v1/chat/completions").Header.Get("Authorization"); got != "Bearer key-a" {
+		t.Errorf("expected primary key, got %q", got)
+	}
+	if got := secondary.last("/v1/chat/completions").Header.Get("Authorization"); got != "Bearer key-b" {
+		t.Errorf("expected secondary key, got %q", got)
+	}
+	statuses := client.TargetStatuses()
+	if len(statuses) != 2 || statuses[0].Requests != 6 || statuses[1].BaseURL != secondary.URL || !statuses[1].Healthy {
+		t.Errorf("unexpected target statuses: %+v", statuses)
+	}
+}
+
+func TestLoadBalancingSkipsFailingTarget(t *testing.T) {
+	failing := newScriptedServer(t).handle("/v1/models", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":{"message":"overloaded","type":"server_error"}}`))
+	})
+	defer failing.Close()
+	healthy := newScriptedServer(t).handle("/v1/models", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"object":"list","data":[{"id":"hackersera-ai"}]}`))
+	})
+	defer healthy.Close()
+
+	client := NewClient(failing.URL, "test-key").
+		WithRetry(RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond}).
+		WithLoadBalancing(LoadBalanceConfig{Targets: []Target{{Weight: 5}, {BaseURL: healthy.URL}}, Cooldown: time.Minute})
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.ListModels(context.Background()); err != nil {
+			t.Fatalf("expected retry to reach the healthy target, got %v", err)
+		}
+	}
+	if n := failing.count("/v1/models"); n != 1 {
+		t.Errorf("expected failing target to be skipped during cooldown, got %d requests", n)
+	}
+	statuses := client.TargetStatuses()
+	if statuses[0].Healthy || statuses[0].Failures != 1 || !statuses[1].Healthy {
+		t.Errorf("unexpected target statuses: %+v", statuses)
+	}
+}
+
+func TestLoadBalancingAllTargetsDown(t *testing.T) {
+	srv := newScriptedServer(t)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithLoadBalancing(LoadBalanceConfig{Targets: []Target{{}, {}}})
+	b := client.balancer
+	b.targets[0].downUntil = time.Now().Add(time.Hour)
+	b.targets[1].downUntil = time.Now().Add(time.Minute)
+	if got := b.pick(); got != b.targets[1] {
+		t.Error("expected the target due back first")
+	}
+}
+
+func TestLoadBalancingRoutesEscapedPaths(t *testing.T) {
+	srv := newScriptedServer(t)
+	defer srv.Close()
+
+	client := NewClient("http://primary.invalid/api", "test-key").WithLoadBalancing(LoadBalanceConfig{Targets: []Target{{BaseURL: srv.URL + "/eu"}}})
+	req := httptest.NewRequest(http.MethodDelete, "http://primary.invalid/api/v1/memories/a%2Fb?hard=true", nil)
+	got := client.route(req, client.balancer.targets[0]).URL
+	if want := srv.URL + "/eu/v1/memories/a%2Fb?hard=true"; got.String() != want {
+		t.Errorf("routed to %s, want %s", got, want)
+	}
+}
+
+func TestLoadBalancingInvalidTarget(t *testing.T) {
+	srv := newScriptedServer(t)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithLoadBalancing(LoadBalanceConfig{Targets: []Target{{BaseURL: "http://bad host"}}})
+	_, err := client.ListModels(context.Background())
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) || !strings.Contains(err.Error(), "invalid target base URL") {
+		t.Fatalf("expected the configuration error, got %v", err)
+	}
+	if n := srv.count("/v1/models"); n != 0 {
+		t.Errorf("expected no request sent, got %d", n)
+	}
+}
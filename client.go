@@ -1,13 +1,15 @@
 package hackeserasdk
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -21,19 +23,56 @@ type Client struct {
 	userID            string
 	conversationID    string
 	cognitiveDisabled bool
+	translateContext  bool
+	namespace         string
+	searchCache       *searchCache
+	stats             clientStats
+	limiter           *modelLimiter
+	degraded          *degradedMode
+	retry             *RetryConfig
+	profileDefaults   *profileDefaults
+	rateLimit         rateLimitTracker
+	middleware        []Middleware
+	lazy              *lazyInit
+	shutdown          shutdownState
+	balancer          *balancer
+	embeddingInfo     embeddingInfoCache
+	maxStreamLine     int
+	embeddingCache    *embeddingCache
+	signing           *requestSigning
+	outbox            *outbox
+	logger            *clientLogger
+	hooks             hookSet
+	endpointTimeouts  map[string]time.Duration
+	strictStreaming   bool
+	configErr         error
+
+	// Chat, Documents, Conversations, Knowledge, and Usage group the
+	// client's methods by API area.
+	Chat          *ChatClient
+	Documents     *DocumentsClient
+	Conversations *ConversationsClient
+	Knowledge     *KnowledgeClient
+	Usage         *UsageClient
 }
 
 // NewClient creates a new SDK client.
 //
 //	client := hackeserasdk.NewClient("https://api-ai.hackersera.com", "your-api-key")
 func NewClient(baseURL, apiKey string) *Client {
-	return &Client{
+	c := &Client{
 		baseURL: strings.TrimRight(baseURL, "/"),
 		apiKey:  apiKey,
 		httpClient: &http.Client{
 			Timeout: 5 * time.Minute,
 		},
 	}
+	c.Chat = &ChatClient{client: c}
+	c.Documents = &DocumentsClient{client: c}
+	c.Conversations = &ConversationsClient{client: c}
+	c.Knowledge = &KnowledgeClient{client: c}
+	c.Usage = &UsageClient{client: c}
+	return c
 }
 
 // WithHTTPClient sets a custom http.Client for the SDK client.
@@ -62,11 +101,39 @@ func (c *Client) SetCognitiveDisabled(disabled bool) *Client {
 	return c
 }
 
+// SetTranslateContext sets the default X-Translate-Context header for all requests.
+// When enabled, retrieved chunks are translated to the user's preferred language.
+func (c *Client) SetTranslateContext(enabled bool) *Client {
+	c.translateContext = enabled
+	return c
+}
+
+// SetNamespace sets the default X-Namespace header for all requests.
+// The namespace isolates documents, conversations, facts, the knowledge graph, and
+// user profiles per tenant, so one customer's learned facts never reach another's prompts.
+// Pass an empty string to clear.
+func (c *Client) SetNamespace(namespace string) *Client {
+	c.namespace = namespace
+	return c
+}
+
 // ─── Chat Completions ───────────────────────────────────────────────────────
 
 // ChatCompletion sends a non-streaming chat completion request.
 func (c *Client) ChatCompletion(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
 	req.Stream = false
+	applyOverrides(ctx, &req, nil)
+	if err := req.prepare(); err != nil {
+		return nil, err
+	}
+	c.degradeChat(ctx, &req)
+	c.applyProfileDefaults(ctx, &req, nil)
+
+	release, err := c.limiter.acquire(ctx, req.Model)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
 	body, err := json.Marshal(req)
 	if err != nil {
@@ -79,7 +146,7 @@ func (c *Client) ChatCompletion(ctx context.Context, req ChatRequest) (*ChatResp
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -93,6 +160,9 @@ func (c *Client) ChatCompletion(ctx context.Context, req ChatRequest) (*ChatResp
 	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
+	chatResp.ClientRequestID = httpReq.Header.Get(HeaderClientRequestID)
+	chatResp.RequestID = resp.Header.Get(HeaderRequestID)
+	chatResp.RateLimit = parseRateLimit(resp.Header)
 
 	return &chatResp, nil
 }
@@ -101,20 +171,32 @@ func (c *Client) ChatCompletion(ctx context.Context, req ChatRequest) (*ChatResp
 // Options override the client-level defaults for this single request.
 func (c *Client) ChatCompletionWithOptions(ctx context.Context, req ChatRequest, opts RequestOptions) (*ChatResponse, error) {
 	req.Stream = false
+	applyOverrides(ctx, &req, &opts)
+	if err := req.prepare(); err != nil {
+		return nil, err
+	}
+	c.degradeChat(ctx, &req)
+	c.applyProfileDefaults(ctx, &req, &opts)
+
+	release, err := c.limiter.acquire(ctx, req.Model)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
 	body, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/chat/completions", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(withOptionsTimeout(ctx, opts), http.MethodPost, c.baseURL+"/v1/chat/completions", bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 	applyOptions(httpReq, opts)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -128,166 +210,52 @@ func (c *Client) ChatCompletionWithOptions(ctx context.Context, req ChatRequest,
 	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
+	chatResp.ClientRequestID = httpReq.Header.Get(HeaderClientRequestID)
+	chatResp.RequestID = resp.Header.Get(HeaderRequestID)
+	chatResp.RateLimit = parseRateLimit(resp.Header)
 
 	return &chatResp, nil
 }
 
 // ChatCompletionStream sends a streaming chat completion request.
 // Returns a channel that emits ChatStreamChunk values.
-// The channel is closed when the stream ends.
+// The channel is closed when the stream ends. Cancel ctx to stop reading early;
+// StreamChat offers an iterator alternative.
 func (c *Client) ChatCompletionStream(ctx context.Context, req ChatRequest) (<-chan ChatStreamChunk, <-chan error) {
-	chunks := make(chan ChatStreamChunk, 100)
-	errs := make(chan error, 1)
-
-	go func() {
-		defer close(chunks)
-		defer close(errs)
-
-		req.Stream = true
-
-		body, err := json.Marshal(req)
-		if err != nil {
-			errs <- fmt.Errorf("marshal request: %w", err)
-			return
-		}
-
-		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/chat/completions", bytes.NewReader(body))
-		if err != nil {
-			errs <- fmt.Errorf("create request: %w", err)
-			return
-		}
-		c.setHeaders(httpReq)
-
-		// Use a client without timeout for streaming
-		streamClient := &http.Client{}
-		resp, err := streamClient.Do(httpReq)
-		if err != nil {
-			errs <- fmt.Errorf("send request: %w", err)
-			return
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			errs <- c.parseError(resp)
-			return
-		}
-
-		scanner := bufio.NewScanner(resp.Body)
-		scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
-
-		for scanner.Scan() {
-			line := scanner.Text()
-
-			if line == "" {
-				continue
-			}
-
-			// Remove "data: " prefix
-			if !strings.HasPrefix(line, "data: ") {
-				continue
-			}
-			data := strings.TrimPrefix(line, "data: ")
-
-			// End of stream
-			if data == "[DONE]" {
-				return
-			}
-
-			var chunk ChatStreamChunk
-			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
-				continue
-			}
-
-			select {
-			case chunks <- chunk:
-			case <-ctx.Done():
-				return
-			}
-		}
-
-		if err := scanner.Err(); err != nil {
-			errs <- fmt.Errorf("read stream: %w", err)
-		}
-	}()
-
-	return chunks, errs
+	return streamChannels(ctx, func() (*ChatStream, error) {
+		return c.StreamChat(ctx, req)
+	})
 }
 
 // ChatCompletionStreamWithOptions sends a streaming chat completion request with per-request options.
 func (c *Client) ChatCompletionStreamWithOptions(ctx context.Context, req ChatRequest, opts RequestOptions) (<-chan ChatStreamChunk, <-chan error) {
-	chunks := make(chan ChatStreamChunk, 100)
-	errs := make(chan error, 1)
-
-	go func() {
-		defer close(chunks)
-		defer close(errs)
-
-		req.Stream = true
-
-		body, err := json.Marshal(req)
-		if err != nil {
-			errs <- fmt.Errorf("marshal request: %w", err)
-			return
-		}
-
-		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/chat/completions", bytes.NewReader(body))
-		if err != nil {
-			errs <- fmt.Errorf("create request: %w", err)
-			return
-		}
-		c.setHeaders(httpReq)
-		applyOptions(httpReq, opts)
-
-		streamClient := &http.Client{}
-		resp, err := streamClient.Do(httpReq)
-		if err != nil {
-			errs <- fmt.Errorf("send request: %w", err)
-			return
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			errs <- c.parseError(resp)
-			return
-		}
-
-		scanner := bufio.NewScanner(resp.Body)
-		scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
-
-		for scanner.Scan() {
-			line := scanner.Text()
-
-			if line == "" {
-				continue
-			}
-
-			if !strings.HasPrefix(line, "data: ") {
-				continue
-			}
-			data := strings.TrimPrefix(line, "data: ")
-
-			if data == "[DONE]" {
-				return
-			}
-
-			var chunk ChatStreamChunk
-			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
-				continue
-			}
-
-			select {
-			case chunks <- chunk:
-			case <-ctx.Done():
-				return
-			}
-		}
+	return streamChannels(ctx, func() (*ChatStream, error) {
+		return c.StreamChatWithOptions(ctx, req, opts)
+	})
+}
 
-		if err := scanner.Err(); err != nil {
-			errs <- fmt.Errorf("read stream: %w", err)
-		}
-	}()
+// ChatCompletionStreamStart is ChatCompletionStream with the handshake done
+// synchronously: validation, transport, and non-200 errors (a 401, or a 429
+// with its Retry-After and rate limit headers) are returned as the *APIError
+// before any channel is handed out. The error channel then only reports
+// failures after the first chunk.
+func (c *Client) ChatCompletionStreamStart(ctx context.Context, req ChatRequest) (<-chan ChatStreamChunk, <-chan error, error) {
+	stream, err := c.StreamChat(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	chunks, errs := pumpStream(ctx, stream)
+	return chunks, errs, nil
+}
 
-	return chunks, errs
+// ChatCompletionStreamStartWithOptions is ChatCompletionStreamStart with per-request options.
+func (c *Client) ChatCompletionStreamStartWithOptions(ctx context.Context, req ChatRequest, opts RequestOptions) (<-chan ChatStreamChunk, <-chan error, error) {
+	stream, err := c.StreamChatWithOptions(ctx, req, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	chunks, errs := pumpStream(ctx, stream)
+	return chunks, errs, nil
 }
 
 // ─── Models ─────────────────────────────────────────────────────────────────
@@ -300,7 +268,7 @@ func (c *Client) ListModels(ctx context.Context) (*ModelList, error) {
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -326,7 +294,7 @@ func (c *Client) GetModel(ctx context.Context, modelID string) (*Model, error) {
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -348,18 +316,43 @@ func (c *Client) GetModel(ctx context.Context, modelID string) (*Model, error) {
 
 // CreateEmbedding creates an embedding for the given input text.
 func (c *Client) CreateEmbedding(ctx context.Context, req EmbeddingRequest) (*EmbeddingResponse, error) {
+	return c.CreateEmbeddingWithOptions(ctx, req, RequestOptions{})
+}
+
+// CreateEmbeddingWithOptions generates embeddings with per-request options.
+// Set opts.UserID (or req.User) to attribute embedding usage to an end user.
+func (c *Client) CreateEmbeddingWithOptions(ctx context.Context, req EmbeddingRequest, opts RequestOptions) (*EmbeddingResponse, error) {
+	if err := c.checkDimensions(ctx, req.Model, req.Dimensions); err != nil {
+		return nil, err
+	}
+	if c.embeddingCache != nil {
+		if inputs, ok := embeddingInputs(req.Input); ok {
+			return c.createEmbeddingCached(ctx, req, opts, inputs)
+		}
+	}
+	return c.createEmbedding(ctx, req, opts)
+}
+
+func (c *Client) createEmbedding(ctx context.Context, req EmbeddingRequest, opts RequestOptions) (*EmbeddingResponse, error) {
+	release, err := c.limiter.acquire(ctx, req.Model)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	body, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/embeddings", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(withOptionsTimeout(ctx, opts), http.MethodPost, c.baseURL+"/v1/embeddings", bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
+	applyOptions(httpReq, opts)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -370,13 +363,188 @@ func (c *Client) CreateEmbedding(ctx context.Context, req EmbeddingRequest) (*Em
 	}
 
 	var embResp EmbeddingResponse
-	if err := json.NewDecoder(resp.Body).Decode(&embResp); err != nil {
+	if err := decodeEmbeddings(resp.Body, req.Float32, &embResp); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
+	embResp.RateLimit = parseRateLimit(resp.Header)
 
 	return &embResp, nil
 }
 
+// GetEmbeddingInfo returns an embedding model's native and supported reduced
+// dimensions. CreateEmbedding and ReembedAll use it to check Dimensions before
+// sending; call it directly to verify a vector store schema at startup.
+func (c *Client) GetEmbeddingInfo(ctx context.Context, model string) (*EmbeddingModelInfo, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/embeddings/models/"+url.PathEscape(model), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var info EmbeddingModelInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &info, nil
+}
+
+// ─── Audio ──────────────────────────────────────────────────────────────────
+
+// TranscribeAudio converts speech to text in the spoken language. Request
+// AudioFormatVerboseJSON with TimestampGranularities for timed segments and words.
+//
+//	f, _ := os.Open("call.mp3")
+//	defer f.Close()
+//	resp, err := client.TranscribeAudio(ctx, hackeserasdk.AudioRequest{
+//		File:                   f,
+//		Filename:               "call.mp3",
+//		ResponseFormat:         hackeserasdk.AudioFormatVerboseJSON,
+//		TimestampGranularities: []string{hackeserasdk.TimestampWord},
+//	})
+func (c *Client) TranscribeAudio(ctx context.Context, req AudioRequest) (*AudioResponse, error) {
+	return c.sendAudio(ctx, "/v1/audio/transcriptions", req, true)
+}
+
+// TranslateAudio converts speech in any supported language to English text.
+func (c *Client) TranslateAudio(ctx context.Context, req AudioRequest) (*AudioResponse, error) {
+	return c.sendAudio(ctx, "/v1/audio/translations", req, false)
+}
+
+func (c *Client) sendAudio(ctx context.Context, path string, req AudioRequest, transcribe bool) (*AudioResponse, error) {
+	if req.File == nil {
+		return nil, invalidf("file", "audio file reader is required")
+	}
+	if !transcribe && (req.Language != "" || len(req.TimestampGranularities) > 0) {
+		return nil, invalidf("language", "language and timestamp_granularities apply to transcriptions only")
+	}
+	if len(req.TimestampGranularities) > 0 && req.ResponseFormat != AudioFormatVerboseJSON {
+		return nil, invalidf("timestamp_granularities", "timestamp_granularities requires response_format %q", AudioFormatVerboseJSON)
+	}
+	if req.Temperature != nil && (*req.Temperature < 0 || *req.Temperature > 1) {
+		return nil, invalidf("temperature", "temperature must be between 0 and 1, got %v", *req.Temperature)
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	part, err := mw.CreateFormFile("file", req.Filename)
+	if err != nil {
+		return nil, fmt.Errorf("create form file: %w", err)
+	}
+	if _, err := io.Copy(part, req.File); err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+	if req.Model != "" {
+		mw.WriteField("model", req.Model)
+	}
+	if req.Language != "" {
+		mw.WriteField("language", req.Language)
+	}
+	if req.Prompt != "" {
+		mw.WriteField("prompt", req.Prompt)
+	}
+	if req.ResponseFormat != "" {
+		mw.WriteField("response_format", req.ResponseFormat)
+	}
+	if req.Temperature != nil {
+		mw.WriteField("temperature", strconv.FormatFloat(*req.Temperature, 'f', -1, 64))
+	}
+	for _, g := range req.TimestampGranularities {
+		mw.WriteField("timestamp_granularities[]", g)
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart body: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, &buf)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+	httpReq.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var audioResp AudioResponse
+	switch req.ResponseFormat {
+	case "", AudioFormatJSON, AudioFormatVerboseJSON:
+		if err := json.NewDecoder(resp.Body).Decode(&audioResp); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+	default:
+		text, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("read response: %w", err)
+		}
+		audioResp.Text = string(text)
+	}
+
+	return &audioResp, nil
+}
+
+// CreateSpeech synthesizes speech from text. The audio is streamed: read it
+// from the returned body as it arrives and close the body when done. The request
+// has no overall timeout, like StreamChat; bound it with ctx.
+//
+//	audio, err := client.CreateSpeech(ctx, hackeserasdk.SpeechRequest{Input: "Your ticket is resolved.", Voice: "alloy"})
+//	if err != nil {
+//		return err
+//	}
+//	defer audio.Close()
+//	_, err = io.Copy(w, audio)
+func (c *Client) CreateSpeech(ctx context.Context, req SpeechRequest) (io.ReadCloser, error) {
+	if req.Input == "" {
+		return nil, invalidf("input", "input text is required")
+	}
+	if req.Voice == "" {
+		return nil, invalidf("voice", "voice is required")
+	}
+	if req.Speed != nil && (*req.Speed < 0.25 || *req.Speed > 4) {
+		return nil, invalidf("speed", "speed must be between 0.25 and 4.0, got %g", *req.Speed)
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/audio/speech", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.doUntimed(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, c.parseError(resp)
+	}
+	return resp.Body, nil
+}
+
 // ─── Health ─────────────────────────────────────────────────────────────────
 
 // Health checks the health of the API server.
@@ -386,7 +554,8 @@ func (c *Client) Health(ctx context.Context) (*HealthResponse, error) {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(httpReq)
+	// Health is not retried: a 503 here is a status report, not a failure.
+	resp, err := c.doWith(c.httpClient, httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -400,6 +569,7 @@ func (c *Client) Health(ctx context.Context) (*HealthResponse, error) {
 	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
+	c.observeHealth(health.Status)
 
 	return &health, nil
 }
@@ -410,6 +580,16 @@ func (c *Client) Health(ctx context.Context) (*HealthResponse, error) {
 // Returns immediately with status "processing" (202 Accepted); ingestion is async.
 // Poll with GetDocument() to check when indexing completes.
 func (c *Client) UploadDocument(ctx context.Context, req DocumentUploadRequest) (*DocumentResponse, error) {
+	if c.queueUpload(ctx, req) {
+		return nil, ErrUploadQueued
+	}
+	if c.outbox != nil {
+		return sendViaOutbox(c, ctx, OutboxUploadDocument, req, c.uploadDocument)
+	}
+	return c.uploadDocument(ctx, req)
+}
+
+func (c *Client) uploadDocument(ctx context.Context, req DocumentUploadRequest) (*DocumentResponse, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("marshal request: %w", err)
@@ -421,7 +601,7 @@ func (c *Client) UploadDocument(ctx context.Context, req DocumentUploadRequest)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -436,6 +616,8 @@ func (c *Client) UploadDocument(ctx context.Context, req DocumentUploadRequest)
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
+	c.InvalidateSearchCache()
+
 	return &docResp, nil
 }
 
@@ -455,7 +637,7 @@ func (c *Client) UploadDocuments(ctx context.Context, docs []DocumentUploadReque
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -470,45 +652,85 @@ func (c *Client) UploadDocuments(ctx context.Context, docs []DocumentUploadReque
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
+	c.InvalidateSearchCache()
+
 	return &listResp, nil
 }
 
-// ListDocuments returns all documents in the knowledge base.
-func (c *Client) ListDocuments(ctx context.Context) (*DocumentListResponse, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/documents", nil)
+// UploadDocumentFile uploads a binary document (PDF, image, office file) for RAG ingestion
+// as multipart/form-data. Set OCR to extract text from scanned pages and screenshots.
+// Returns immediately with status "processing" (202 Accepted); ingestion is async.
+func (c *Client) UploadDocumentFile(ctx context.Context, req DocumentFileUploadRequest) (*DocumentResponse, error) {
+	if req.File == nil {
+		return nil, invalidf("file", "file reader is required")
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	part, err := mw.CreateFormFile("file", req.Filename)
+	if err != nil {
+		return nil, fmt.Errorf("create form file: %w", err)
+	}
+	if _, err := io.Copy(part, req.File); err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+	if len(req.Tags) > 0 {
+		tags, err := json.Marshal(req.Tags)
+		if err != nil {
+			return nil, fmt.Errorf("marshal tags: %w", err)
+		}
+		mw.WriteField("tags", string(tags))
+	}
+	if req.OCR {
+		mw.WriteField("ocr", "true")
+	}
+	if len(req.OCRLanguages) > 0 {
+		mw.WriteField("ocr_languages", strings.Join(req.OCRLanguages, ","))
+	}
+	if req.CallbackURL != "" {
+		mw.WriteField("callback_url", req.CallbackURL)
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart body: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/documents/upload", &buf)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
+	httpReq.Header.Set("Content-Type", mw.FormDataContentType())
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
 		return nil, c.parseError(resp)
 	}
 
-	var listResp DocumentListResponse
-	if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
+	var docResp DocumentResponse
+	if err := json.NewDecoder(resp.Body).Decode(&docResp); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &listResp, nil
+	c.InvalidateSearchCache()
+
+	return &docResp, nil
 }
 
-// GetDocument returns a single document by ID.
-// Use this to poll document status after uploading.
-func (c *Client) GetDocument(ctx context.Context, docID string) (*DocumentResponse, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/documents/"+docID, nil)
+// ListDocuments returns all documents in the knowledge base.
+func (c *Client) ListDocuments(ctx context.Context) (*DocumentListResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/documents", nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -518,23 +740,24 @@ func (c *Client) GetDocument(ctx context.Context, docID string) (*DocumentRespon
 		return nil, c.parseError(resp)
 	}
 
-	var docResp DocumentResponse
-	if err := json.NewDecoder(resp.Body).Decode(&docResp); err != nil {
+	var listResp DocumentListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &docResp, nil
+	return &listResp, nil
 }
 
-// DeleteDocument soft-deletes a document and removes its chunks.
-func (c *Client) DeleteDocument(ctx context.Context, docID string) (*DocumentDeleteResponse, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+"/v1/documents/"+docID, nil)
+// GetDocument returns a single document by ID.
+// Use this to poll document status after uploading.
+func (c *Client) GetDocument(ctx context.Context, docID string) (*DocumentResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/documents/"+docID, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -544,31 +767,43 @@ func (c *Client) DeleteDocument(ctx context.Context, docID string) (*DocumentDel
 		return nil, c.parseError(resp)
 	}
 
-	var delResp DocumentDeleteResponse
-	if err := json.NewDecoder(resp.Body).Decode(&delResp); err != nil {
+	var docResp DocumentResponse
+	if err := json.NewDecoder(resp.Body).Decode(&docResp); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &delResp, nil
+	return &docResp, nil
 }
 
-// ─── Search (RAG) ───────────────────────────────────────────────────────────
+// GetDocuments returns the status of many documents, e.g. to poll a batch
+// upload, in one request per MaxGetDocumentsBatch IDs instead of one per document.
+func (c *Client) GetDocuments(ctx context.Context, ids []string) (*DocumentBatchGetResponse, error) {
+	result := &DocumentBatchGetResponse{Data: []DocumentResponse{}}
+	for start := 0; start < len(ids); start += MaxGetDocumentsBatch {
+		end := min(start+MaxGetDocumentsBatch, len(ids))
+		batch, err := c.getDocumentsBatch(ctx, ids[start:end])
+		if err != nil {
+			return nil, err
+		}
+		result.Data = append(result.Data, batch.Data...)
+		result.NotFound = append(result.NotFound, batch.NotFound...)
+	}
+	return result, nil
+}
 
-// Search performs a semantic search over the knowledge base.
-// Uses hybrid search (pgvector cosine + keyword RRF) for best results.
-func (c *Client) Search(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
-	body, err := json.Marshal(req)
+func (c *Client) getDocumentsBatch(ctx context.Context, ids []string) (*DocumentBatchGetResponse, error) {
+	body, err := json.Marshal(DocumentBatchGetRequest{IDs: ids})
 	if err != nil {
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/search", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/documents/batch-get", bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -578,31 +813,253 @@ func (c *Client) Search(ctx context.Context, req SearchRequest) (*SearchResponse
 		return nil, c.parseError(resp)
 	}
 
-	var searchResp SearchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
+	var batchResp DocumentBatchGetResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batchResp); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &searchResp, nil
+	return &batchResp, nil
 }
 
-// ─── Conversations ──────────────────────────────────────────────────────────
+// DeleteDocument soft-deletes a document and removes its chunks.
+func (c *Client) DeleteDocument(ctx context.Context, docID string) (*DocumentDeleteResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+"/v1/documents/"+docID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
 
-// ListConversations returns a list of conversations.
-// Use limit to control the number of results (default: 50).
-func (c *Client) ListConversations(ctx context.Context, limit int) (*ConversationListResponse, error) {
-	url := c.baseURL + "/v1/conversations"
-	if limit > 0 {
-		url += "?limit=" + strconv.Itoa(limit)
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var delResp DocumentDeleteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&delResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	c.InvalidateSearchCache()
+
+	return &delResp, nil
+}
+
+// SummarizeDocument produces a summary of an indexed document.
+// Set opts.Store to persist the summary as document metadata, making it available
+// on DocumentResponse.Summary and as compact retrieval context.
+func (c *Client) SummarizeDocument(ctx context.Context, docID string, opts SummaryOptions) (*DocumentSummary, error) {
+	body, err := json.Marshal(opts)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/documents/"+docID+"/summarize", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var summary DocumentSummary
+	if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &summary, nil
+}
+
+// ListDocumentTags returns every tag key/value pair in use with its document count.
+func (c *Client) ListDocumentTags(ctx context.Context) (*DocumentTagListResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/documents/tags", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var tagList DocumentTagListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tagList); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &tagList, nil
+}
+
+// RenameDocumentTag renames a tag key or key/value pair across all documents.
+// Cached searches are invalidated, since tag filters may now match differently.
+func (c *Client) RenameDocumentTag(ctx context.Context, req TagRenameRequest) (*TagUpdateResponse, error) {
+	if req.Key == "" || (req.NewKey == "" && req.NewValue == "") {
+		return nil, invalidf("key", "key and a new key or value are required")
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/documents/tags/rename", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var updResp TagUpdateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&updResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	c.InvalidateSearchCache()
+
+	return &updResp, nil
+}
+
+// MergeDocumentTags rewrites several values of a tag key to one canonical value.
+func (c *Client) MergeDocumentTags(ctx context.Context, req TagMergeRequest) (*TagUpdateResponse, error) {
+	if req.Key == "" || req.Into == "" || len(req.Values) == 0 {
+		return nil, invalidf("key", "key, values, and into are required")
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/documents/tags/merge", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var updResp TagUpdateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&updResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	c.InvalidateSearchCache()
+
+	return &updResp, nil
+}
+
+// ExportKnowledgeBase streams every document and chunk (and optionally embeddings)
+// to w in the NDJSON archive format described by ExportRecord. The body is copied
+// as it arrives, so large knowledge bases are never buffered in memory.
+// Returns the number of bytes written.
+//
+// Exports of large knowledge bases can outlast the default 5-minute client timeout;
+// bound the call with ctx and configure WithHTTPClient accordingly.
+func (c *Client) ExportKnowledgeBase(ctx context.Context, w io.Writer, opts ExportOptions) (int64, error) {
+	url := c.baseURL + "/v1/knowledge/export"
+	if opts.IncludeEmbeddings {
+		url += "?include_embeddings=true"
 	}
 
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+	httpReq.Header.Set("Accept", "application/x-ndjson")
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return 0, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, c.parseError(resp)
+	}
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("copy export: %w", err)
+	}
+
+	return n, nil
+}
+
+// CreateSnapshot captures the current documents, facts, and knowledge graph under a name.
+// Snapshots are taken asynchronously; poll ListSnapshots until Status is "ready".
+func (c *Client) CreateSnapshot(ctx context.Context, name string) (*KnowledgeSnapshot, error) {
+	body, err := json.Marshal(SnapshotCreateRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/knowledge/snapshots", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var snapshot KnowledgeSnapshot
+	if err := json.NewDecoder(resp.Body).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+// ListSnapshots returns all knowledge base snapshots, newest first.
+func (c *Client) ListSnapshots(ctx context.Context) (*SnapshotListResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/knowledge/snapshots", nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -612,7 +1069,7 @@ func (c *Client) ListConversations(ctx context.Context, limit int) (*Conversatio
 		return nil, c.parseError(resp)
 	}
 
-	var listResp ConversationListResponse
+	var listResp SnapshotListResponse
 	if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
@@ -620,72 +1077,1036 @@ func (c *Client) ListConversations(ctx context.Context, limit int) (*Conversatio
 	return &listResp, nil
 }
 
-// GetConversation returns a conversation with all its turns.
-func (c *Client) GetConversation(ctx context.Context, conversationID string) (*ConversationDetail, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/conversations/"+conversationID, nil)
+// RestoreSnapshot replaces the current documents, facts, and knowledge graph with
+// the snapshot's contents. Restore runs asynchronously (202 Accepted).
+// Retrieval results are in flux until it completes, so the search cache is cleared.
+func (c *Client) RestoreSnapshot(ctx context.Context, snapshotID string) (*SnapshotRestoreResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/knowledge/snapshots/"+snapshotID+"/restore", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var restoreResp SnapshotRestoreResponse
+	if err := json.NewDecoder(resp.Body).Decode(&restoreResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	c.InvalidateSearchCache()
+
+	return &restoreResp, nil
+}
+
+// ReembedAll starts a background job that re-embeds every chunk in the knowledge
+// base with req.NewModel, so the corpus can move to a new embedding model (including
+// one with different dimensions) without deleting and re-uploading documents.
+// The old vectors keep serving search until the job completes.
+func (c *Client) ReembedAll(ctx context.Context, req ReembedRequest) (*ReembedJob, error) {
+	if err := c.checkDimensions(ctx, req.NewModel, req.Dimensions); err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/knowledge/reembed", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var job ReembedJob
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	c.InvalidateSearchCache()
+
+	return &job, nil
+}
+
+// GetReembedJob returns the current progress of a re-embedding job.
+func (c *Client) GetReembedJob(ctx context.Context, jobID string) (*ReembedJob, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/knowledge/reembed/"+jobID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var job ReembedJob
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &job, nil
+}
+
+// defaultReembedPollInterval is used by WaitForReembedJob when interval is not positive.
+const defaultReembedPollInterval = 2 * time.Second
+
+// WaitForReembedJob polls a re-embedding job every interval (2s if interval is
+// not positive) until it completes, fails, or ctx is done. onProgress, if
+// non-nil, is called after every poll. Cached searches are invalidated when the
+// job finishes, since every score has changed.
+func (c *Client) WaitForReembedJob(ctx context.Context, jobID string, interval time.Duration, onProgress func(*ReembedJob)) (*ReembedJob, error) {
+	if interval <= 0 {
+		interval = defaultReembedPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		job, err := c.GetReembedJob(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if onProgress != nil {
+			onProgress(job)
+		}
+		if job.Done() {
+			c.InvalidateSearchCache()
+			if job.Status == JobStatusFailed {
+				return job, fmt.Errorf("reembed job %s failed: %s", job.ID, job.Error)
+			}
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ─── Files ──────────────────────────────────────────────────────────────────
+
+// UploadFile stores a file for use by other endpoints, such as batch and
+// fine-tuning jobs, which reference it by the returned ID. The file is streamed
+// as it is read, so large files are not held in memory; in exchange the
+// upload is not retried, and it runs without the client timeout, so bound it
+// with ctx. WithRequestSigning still buffers the body to hash it.
+func (c *Client) UploadFile(ctx context.Context, req FileUploadRequest) (*FileObject, error) {
+	if req.File == nil {
+		return nil, invalidf("file", "file reader is required")
+	}
+	if req.Purpose == "" {
+		return nil, invalidf("purpose", "purpose is required")
+	}
+
+	pr, pw := io.Pipe()
+	// Closing the read side stops the writer if the request ends early.
+	defer pr.Close()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeFileUpload(mw, req))
+	}()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/files", pr)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+	httpReq.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := c.doUntimed(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, c.parseError(resp)
+	}
+
+	var file FileObject
+	if err := json.NewDecoder(resp.Body).Decode(&file); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &file, nil
+}
+
+// writeFileUpload writes the multipart body of an UploadFile request.
+func writeFileUpload(mw *multipart.Writer, req FileUploadRequest) error {
+	// Purpose goes first so servers can route the upload before the file ends.
+	if err := mw.WriteField("purpose", req.Purpose); err != nil {
+		return err
+	}
+	part, err := mw.CreateFormFile("file", req.Filename)
+	if err != nil {
+		return fmt.Errorf("create form file: %w", err)
+	}
+	if _, err := io.Copy(part, req.File); err != nil {
+		return fmt.Errorf("read file: %w", err)
+	}
+	return mw.Close()
+}
+
+// ListFiles returns stored files, optionally only those with the given purpose.
+func (c *Client) ListFiles(ctx context.Context, purpose string) (*FileListResponse, error) {
+	endpoint := c.baseURL + "/v1/files"
+	if purpose != "" {
+		endpoint += "?purpose=" + url.QueryEscape(purpose)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var list FileListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &list, nil
+}
+
+// GetFile returns a file's metadata.
+func (c *Client) GetFile(ctx context.Context, fileID string) (*FileObject, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/files/"+url.PathEscape(fileID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var file FileObject
+	if err := json.NewDecoder(resp.Body).Decode(&file); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &file, nil
+}
+
+// GetFileContent downloads a file's content. The body is streamed without the
+// client timeout; close it when done.
+func (c *Client) GetFileContent(ctx context.Context, fileID string) (io.ReadCloser, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/files/"+url.PathEscape(fileID)+"/content", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.doUntimed(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, c.parseError(resp)
+	}
+	return resp.Body, nil
+}
+
+// DeleteFile deletes a stored file.
+func (c *Client) DeleteFile(ctx context.Context, fileID string) (*FileDeleteResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+"/v1/files/"+url.PathEscape(fileID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var delResp FileDeleteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&delResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &delResp, nil
+}
+
+// ─── Search (RAG) ───────────────────────────────────────────────────────────
+
+// Search performs a semantic search over the knowledge base.
+// Uses hybrid search (pgvector cosine + keyword RRF) for best results.
+// Responses are served from the client-side cache when enabled with WithSearchCache.
+func (c *Client) Search(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/search", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	// Key on the headers actually sent, so client, context, and per-call
+	// namespaces and users never share entries.
+	var cacheKey string
+	if c.searchCache != nil {
+		cacheKey = searchCacheKey(req, httpReq.Header.Get("X-Namespace"), httpReq.Header.Get("X-User-ID"))
+		if cached, ok := c.searchCache.get(cacheKey); ok {
+			return cached, nil
+		}
+	}
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var searchResp SearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	if c.searchCache != nil {
+		c.searchCache.put(cacheKey, &searchResp)
+	}
+
+	return &searchResp, nil
+}
+
+// ─── Language ───────────────────────────────────────────────────────────────
+
+// DetectLanguage detects the language of the given text.
+func (c *Client) DetectLanguage(ctx context.Context, text string) (*LanguageDetectResponse, error) {
+	body, err := json.Marshal(LanguageDetectRequest{Text: text})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/language/detect", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var detectResp LanguageDetectResponse
+	if err := json.NewDecoder(resp.Body).Decode(&detectResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &detectResp, nil
+}
+
+// Translate translates text into the target language.
+// The source language is detected automatically unless SourceLang is set.
+func (c *Client) Translate(ctx context.Context, req TranslateRequest) (*TranslateResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/translate", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var translateResp TranslateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&translateResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &translateResp, nil
+}
+
+// ─── Conversations ──────────────────────────────────────────────────────────
+
+// ListConversations returns a list of conversations.
+// Use limit to control the number of results (default: 50).
+func (c *Client) ListConversations(ctx context.Context, limit int) (*ConversationListResponse, error) {
+	return c.ListConversationsWithOptions(ctx, ConversationListOptions{Limit: limit})
+}
+
+// ListConversationsWithOptions returns conversations, optionally filtered by the
+// metadata attached to their chat requests.
+func (c *Client) ListConversationsWithOptions(ctx context.Context, opts ConversationListOptions) (*ConversationListResponse, error) {
+	params := url.Values{}
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	for k, v := range opts.Metadata {
+		params.Set("metadata["+k+"]", v)
+	}
+	url := c.baseURL + "/v1/conversations"
+	if len(params) > 0 {
+		url += "?" + params.Encode()
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var listResp ConversationListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &listResp, nil
+}
+
+// GetConversation returns a conversation with all its turns.
+func (c *Client) GetConversation(ctx context.Context, conversationID string) (*ConversationDetail, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/conversations/"+conversationID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var detail ConversationDetail
+	if err := json.NewDecoder(resp.Body).Decode(&detail); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &detail, nil
+}
+
+// SearchConversations performs a full-text search across all conversation turns.
+func (c *Client) SearchConversations(ctx context.Context, query string, limit int) (*ConversationSearchResponse, error) {
+	url := c.baseURL + "/v1/conversations/search?query=" + query
+	if limit > 0 {
+		url += "&limit=" + strconv.Itoa(limit)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var searchResp ConversationSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &searchResp, nil
+}
+
+// DeleteConversation deletes a conversation and all its turns.
+func (c *Client) DeleteConversation(ctx context.Context, conversationID string) (*ConversationDeleteResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+"/v1/conversations/"+conversationID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var delResp ConversationDeleteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&delResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &delResp, nil
+}
+
+// AppendConversationTurn adds a turn to an existing conversation without running
+// a completion, e.g. to record tool activity for audit.
+func (c *Client) AppendConversationTurn(ctx context.Context, conversationID string, req ConversationTurnRequest) (*ConversationTurn, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/conversations/"+conversationID+"/turns", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, c.parseError(resp)
+	}
+
+	var turn ConversationTurn
+	if err := json.NewDecoder(resp.Body).Decode(&turn); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &turn, nil
+}
+
+// ─── Feedback ───────────────────────────────────────────────────────────────
+
+// SubmitFeedback submits feedback on an AI response.
+// Positive feedback (rating: 1) reinforces good patterns; negative feedback (rating: -1)
+// with corrections teaches the system what went wrong.
+func (c *Client) SubmitFeedback(ctx context.Context, req FeedbackRequest) (*FeedbackResponse, error) {
+	if c.outbox != nil {
+		return sendViaOutbox(c, ctx, OutboxSubmitFeedback, req, c.submitFeedback)
+	}
+	return c.submitFeedback(ctx, req)
+}
+
+func (c *Client) submitFeedback(ctx context.Context, req FeedbackRequest) (*FeedbackResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/feedback", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var fbResp FeedbackResponse
+	if err := json.NewDecoder(resp.Body).Decode(&fbResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &fbResp, nil
+}
+
+// ─── User Profiles ──────────────────────────────────────────────────────────
+
+// GetProfile returns the user profile for the given user ID.
+// Requires X-User-ID header — set via SetUserID() or pass opts.
+func (c *Client) GetProfile(ctx context.Context, userID string) (*UserProfile, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/profile", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+	httpReq.Header.Set("X-User-ID", userID)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var profile UserProfile
+	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &profile, nil
+}
+
+// UpdateProfile updates the user profile for the given user ID.
+// Preferences are merged — existing keys are updated, new keys are added.
+func (c *Client) UpdateProfile(ctx context.Context, userID string, req ProfileUpdateRequest) (*UserProfile, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL+"/v1/profile", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+	httpReq.Header.Set("X-User-ID", userID)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var profile UserProfile
+	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	c.forgetProfile(userID)
+	return &profile, nil
+}
+
+// ─── User Memories ──────────────────────────────────────────────────────────
+
+// RememberForUser stores a memory for userID. It is included in that user's chats
+// (those sent with X-User-ID set to userID) and never in other users' prompts.
+func (c *Client) RememberForUser(ctx context.Context, userID string, req UserMemoryRequest) (*UserMemory, error) {
+	if userID == "" {
+		return nil, invalidf("user_id", "user ID is required")
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/memories", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+	httpReq.Header.Set("X-User-ID", userID)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, c.parseError(resp)
+	}
+
+	var memory UserMemory
+	if err := json.NewDecoder(resp.Body).Decode(&memory); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &memory, nil
+}
+
+// ListUserMemories returns the memories stored for userID.
+func (c *Client) ListUserMemories(ctx context.Context, userID string) (*UserMemoryListResponse, error) {
+	if userID == "" {
+		return nil, invalidf("user_id", "user ID is required")
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/memories", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+	httpReq.Header.Set("X-User-ID", userID)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var listResp UserMemoryListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &listResp, nil
+}
+
+// ForgetUserMemory deletes one of userID's memories.
+func (c *Client) ForgetUserMemory(ctx context.Context, userID, memoryID string) (*UserMemoryDeleteResponse, error) {
+	if userID == "" {
+		return nil, invalidf("user_id", "user ID is required")
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+"/v1/memories/"+url.PathEscape(memoryID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+	httpReq.Header.Set("X-User-ID", userID)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var delResp UserMemoryDeleteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&delResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &delResp, nil
+}
+
+// ─── Knowledge Graph ────────────────────────────────────────────────────────
+
+// QueryKnowledgeGraph queries the knowledge graph for related concepts.
+func (c *Client) QueryKnowledgeGraph(ctx context.Context, query string, limit int) (*KnowledgeGraphResponse, error) {
+	return c.QueryKnowledgeGraphAsOf(ctx, query, limit, time.Time{})
+}
+
+// QueryKnowledgeGraphAsOf queries the knowledge graph as it was at asOf, e.g. before
+// a large ingestion. A zero asOf queries the current graph. Compare two results with
+// DiffKnowledgeGraphs.
+func (c *Client) QueryKnowledgeGraphAsOf(ctx context.Context, query string, limit int, asOf time.Time) (*KnowledgeGraphResponse, error) {
+	params := url.Values{"query": {query}}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	if !asOf.IsZero() {
+		params.Set("as_of", asOf.UTC().Format(time.RFC3339))
+	}
+	url := c.baseURL + "/v1/knowledge/graph?" + params.Encode()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var graphResp KnowledgeGraphResponse
+	if err := json.NewDecoder(resp.Body).Decode(&graphResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &graphResp, nil
+}
+
+// GetKnowledgeGraphStats returns knowledge graph counts, the top concepts of
+// the last 7 days, and concepts that emerged this week.
+func (c *Client) GetKnowledgeGraphStats(ctx context.Context) (*KnowledgeGraphStats, error) {
+	return c.GetKnowledgeGraphStatsWithOptions(ctx, KnowledgeGraphStatsOptions{})
+}
+
+// GetKnowledgeGraphStatsWithOptions is GetKnowledgeGraphStats with a custom
+// range and number of top concepts, e.g. for a monthly "what is the org asking
+// about" report.
+func (c *Client) GetKnowledgeGraphStatsWithOptions(ctx context.Context, opts KnowledgeGraphStatsOptions) (*KnowledgeGraphStats, error) {
+	params := url.Values{}
+	opts.Range.query(params)
+	if opts.TopN > 0 {
+		params.Set("top", strconv.Itoa(opts.TopN))
+	}
+	endpoint := c.baseURL + "/v1/knowledge/graph/stats"
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var stats KnowledgeGraphStats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// ExtractEntities extracts typed entities (hosts, CVEs, organizations, products) and
+// keywords from text or an indexed document. Exactly one of req.Text or req.DocumentID
+// must be set. With req.Apply, the server also adds the results to the document's
+// tags and to the knowledge graph.
+func (c *Client) ExtractEntities(ctx context.Context, req EntityExtractionRequest) (*EntityExtractionResponse, error) {
+	if (req.Text == "") == (req.DocumentID == "") {
+		return nil, invalidf("text", "exactly one of text or document_id must be set")
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/knowledge/extract", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var extractResp EntityExtractionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&extractResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &extractResp, nil
+}
+
+// ─── Learned Facts ──────────────────────────────────────────────────────────
+
+// ListFacts returns learned facts from the knowledge base.
+// Set verified to non-nil to filter by verification status.
+func (c *Client) ListFacts(ctx context.Context, limit int, verified *bool) (*FactListResponse, error) {
+	url := c.baseURL + "/v1/knowledge/facts"
+	sep := "?"
+	if limit > 0 {
+		url += sep + "limit=" + strconv.Itoa(limit)
+		sep = "&"
+	}
+	if verified != nil {
+		url += sep + "verified=" + strconv.FormatBool(*verified)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var factsResp FactListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&factsResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &factsResp, nil
+}
+
+// CreateFact creates a single fact in the knowledge base.
+func (c *Client) CreateFact(ctx context.Context, req FactCreateRequest) (*Fact, error) {
+	if c.outbox != nil {
+		return sendViaOutbox(c, ctx, OutboxCreateFact, req, c.createFact)
+	}
+	return c.createFact(ctx, req)
+}
+
+func (c *Client) createFact(ctx context.Context, req FactCreateRequest) (*Fact, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/knowledge/facts", bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		return nil, c.parseError(resp)
 	}
 
-	var detail ConversationDetail
-	if err := json.NewDecoder(resp.Body).Decode(&detail); err != nil {
+	var fact Fact
+	if err := json.NewDecoder(resp.Body).Decode(&fact); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &detail, nil
+	return &fact, nil
 }
 
-// SearchConversations performs a full-text search across all conversation turns.
-func (c *Client) SearchConversations(ctx context.Context, query string, limit int) (*ConversationSearchResponse, error) {
-	url := c.baseURL + "/v1/conversations/search?query=" + query
-	if limit > 0 {
-		url += "&limit=" + strconv.Itoa(limit)
+// CreateFacts creates multiple facts in the knowledge base in a single request.
+func (c *Client) CreateFacts(ctx context.Context, facts []FactCreateRequest) (*FactListResponse, error) {
+	req := FactBatchCreateRequest{Facts: facts}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/knowledge/facts", bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		return nil, c.parseError(resp)
 	}
 
-	var searchResp ConversationSearchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
+	var factsResp FactListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&factsResp); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &searchResp, nil
+	return &factsResp, nil
 }
 
-// DeleteConversation deletes a conversation and all its turns.
-func (c *Client) DeleteConversation(ctx context.Context, conversationID string) (*ConversationDeleteResponse, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+"/v1/conversations/"+conversationID, nil)
+// UpdateFact updates an existing fact by ID.
+// Only provided fields are updated.
+func (c *Client) UpdateFact(ctx context.Context, factID int, req FactUpdateRequest) (*Fact, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL+"/v1/knowledge/facts/"+strconv.Itoa(factID), bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -695,62 +2116,73 @@ func (c *Client) DeleteConversation(ctx context.Context, conversationID string)
 		return nil, c.parseError(resp)
 	}
 
-	var delResp ConversationDeleteResponse
-	if err := json.NewDecoder(resp.Body).Decode(&delResp); err != nil {
+	var fact Fact
+	if err := json.NewDecoder(resp.Body).Decode(&fact); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &delResp, nil
+	return &fact, nil
 }
 
-// ─── Feedback ───────────────────────────────────────────────────────────────
+// LinkFactToDocument records a document or chunk as supporting evidence for a fact.
+// Set req.ChunkID to cite a specific passage, or req.DocumentID to cite the whole document.
+// The returned fact includes the updated SupportingChunks.
+func (c *Client) LinkFactToDocument(ctx context.Context, factID int, req FactLinkRequest) (*Fact, error) {
+	if req.DocumentID == "" && req.ChunkID == "" {
+		return nil, invalidf("document_id", "document_id or chunk_id is required")
+	}
 
-// SubmitFeedback submits feedback on an AI response.
-// Positive feedback (rating: 1) reinforces good patterns; negative feedback (rating: -1)
-// with corrections teaches the system what went wrong.
-func (c *Client) SubmitFeedback(ctx context.Context, req FeedbackRequest) (*FeedbackResponse, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/feedback", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/knowledge/facts/"+strconv.Itoa(factID)+"/links", bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		return nil, c.parseError(resp)
 	}
 
-	var fbResp FeedbackResponse
-	if err := json.NewDecoder(resp.Body).Decode(&fbResp); err != nil {
+	var fact Fact
+	if err := json.NewDecoder(resp.Body).Decode(&fact); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &fbResp, nil
+	return &fact, nil
 }
 
-// ─── User Profiles ──────────────────────────────────────────────────────────
+// ─── Cognitive Intelligence ─────────────────────────────────────────────────
 
-// GetProfile returns the user profile for the given user ID.
-// Requires X-User-ID header — set via SetUserID() or pass opts.
-func (c *Client) GetProfile(ctx context.Context, userID string) (*UserProfile, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/profile", nil)
+// GetCognitiveStats returns system-wide cognitive statistics.
+func (c *Client) GetCognitiveStats(ctx context.Context) (*CognitiveStatsResponse, error) {
+	return c.GetCognitiveStatsAsOf(ctx, time.Time{})
+}
+
+// GetCognitiveStatsAsOf returns cognitive statistics as they were at asOf.
+// A zero asOf returns the current statistics.
+func (c *Client) GetCognitiveStatsAsOf(ctx context.Context, asOf time.Time) (*CognitiveStatsResponse, error) {
+	url := c.baseURL + "/v1/cognitive/stats"
+	if !asOf.IsZero() {
+		url += "?as_of=" + asOf.UTC().Format(time.RFC3339)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
-	httpReq.Header.Set("X-User-ID", userID)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -760,30 +2192,25 @@ func (c *Client) GetProfile(ctx context.Context, userID string) (*UserProfile, e
 		return nil, c.parseError(resp)
 	}
 
-	var profile UserProfile
-	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+	var stats CognitiveStatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &profile, nil
+	return &stats, nil
 }
 
-// UpdateProfile updates the user profile for the given user ID.
-// Preferences are merged — existing keys are updated, new keys are added.
-func (c *Client) UpdateProfile(ctx context.Context, userID string, req ProfileUpdateRequest) (*UserProfile, error) {
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
-	}
+// ─── Usage ──────────────────────────────────────────────────────────────────
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL+"/v1/profile", bytes.NewReader(body))
+// GetUsage returns aggregated usage statistics.
+func (c *Client) GetUsage(ctx context.Context) (*UsageResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/usage", nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
-	httpReq.Header.Set("X-User-ID", userID)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -793,30 +2220,36 @@ func (c *Client) UpdateProfile(ctx context.Context, userID string, req ProfileUp
 		return nil, c.parseError(resp)
 	}
 
-	var profile UserProfile
-	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+	var usageResp UsageResponse
+	if err := json.NewDecoder(resp.Body).Decode(&usageResp); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &profile, nil
+	return &usageResp, nil
 }
 
-// ─── Knowledge Graph ────────────────────────────────────────────────────────
-
-// QueryKnowledgeGraph queries the knowledge graph for related concepts.
-func (c *Client) QueryKnowledgeGraph(ctx context.Context, query string, limit int) (*KnowledgeGraphResponse, error) {
-	url := c.baseURL + "/v1/knowledge/graph?query=" + query
-	if limit > 0 {
-		url += "&limit=" + strconv.Itoa(limit)
+// GetUserUsage returns one user's tokens, request and turn counts, average
+// latency, and feedback over r, for per-seat reporting.
+//
+//	usage, err := client.GetUserUsage(ctx, "user-42", hackeserasdk.LastDays(30))
+func (c *Client) GetUserUsage(ctx context.Context, userID string, r TimeRange) (*UserUsage, error) {
+	if userID == "" {
+		return nil, invalidf("user_id", "user ID is required")
+	}
+	params := url.Values{}
+	r.query(params)
+	endpoint := c.baseURL + "/v1/usage/users/" + url.PathEscape(userID)
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -826,36 +2259,23 @@ func (c *Client) QueryKnowledgeGraph(ctx context.Context, query string, limit in
 		return nil, c.parseError(resp)
 	}
 
-	var graphResp KnowledgeGraphResponse
-	if err := json.NewDecoder(resp.Body).Decode(&graphResp); err != nil {
+	var usage UserUsage
+	if err := json.NewDecoder(resp.Body).Decode(&usage); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &graphResp, nil
+	return &usage, nil
 }
 
-// ─── Learned Facts ──────────────────────────────────────────────────────────
-
-// ListFacts returns learned facts from the knowledge base.
-// Set verified to non-nil to filter by verification status.
-func (c *Client) ListFacts(ctx context.Context, limit int, verified *bool) (*FactListResponse, error) {
-	url := c.baseURL + "/v1/knowledge/facts"
-	sep := "?"
-	if limit > 0 {
-		url += sep + "limit=" + strconv.Itoa(limit)
-		sep = "&"
-	}
-	if verified != nil {
-		url += sep + "verified=" + strconv.FormatBool(*verified)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+// GetRecentUsage returns recent usage records.
+func (c *Client) GetRecentUsage(ctx context.Context) (*UsageRecentResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/usage/recent", nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -865,28 +2285,29 @@ func (c *Client) ListFacts(ctx context.Context, limit int, verified *bool) (*Fac
 		return nil, c.parseError(resp)
 	}
 
-	var factsResp FactListResponse
-	if err := json.NewDecoder(resp.Body).Decode(&factsResp); err != nil {
+	var recentResp UsageRecentResponse
+	if err := json.NewDecoder(resp.Body).Decode(&recentResp); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &factsResp, nil
+	return &recentResp, nil
 }
 
-// CreateFact creates a single fact in the knowledge base.
-func (c *Client) CreateFact(ctx context.Context, req FactCreateRequest) (*Fact, error) {
+// CreateUsageAlert registers a usage-threshold notification. When the metric exceeds
+// the threshold within the window, the server POSTs a UsageAlertEvent to the webhook URL.
+func (c *Client) CreateUsageAlert(ctx context.Context, req UsageAlertRequest) (*UsageAlert, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/knowledge/facts", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/usage/alerts", bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -896,62 +2317,49 @@ func (c *Client) CreateFact(ctx context.Context, req FactCreateRequest) (*Fact,
 		return nil, c.parseError(resp)
 	}
 
-	var fact Fact
-	if err := json.NewDecoder(resp.Body).Decode(&fact); err != nil {
+	var alert UsageAlert
+	if err := json.NewDecoder(resp.Body).Decode(&alert); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &fact, nil
+	return &alert, nil
 }
 
-// CreateFacts creates multiple facts in the knowledge base in a single request.
-func (c *Client) CreateFacts(ctx context.Context, facts []FactCreateRequest) (*FactListResponse, error) {
-	req := FactBatchCreateRequest{Facts: facts}
-
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/knowledge/facts", bytes.NewReader(body))
+// ListUsageAlerts returns all registered usage alerts.
+func (c *Client) ListUsageAlerts(ctx context.Context) (*UsageAlertListResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/usage/alerts", nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+	if resp.StatusCode != http.StatusOK {
 		return nil, c.parseError(resp)
 	}
 
-	var factsResp FactListResponse
-	if err := json.NewDecoder(resp.Body).Decode(&factsResp); err != nil {
+	var listResp UsageAlertListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &factsResp, nil
+	return &listResp, nil
 }
 
-// UpdateFact updates an existing fact by ID.
-// Only provided fields are updated.
-func (c *Client) UpdateFact(ctx context.Context, factID int, req FactUpdateRequest) (*Fact, error) {
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL+"/v1/knowledge/facts/"+strconv.Itoa(factID), bytes.NewReader(body))
+// DeleteUsageAlert removes a usage alert.
+func (c *Client) DeleteUsageAlert(ctx context.Context, alertID string) (*UsageAlertDeleteResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+"/v1/usage/alerts/"+alertID, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -961,25 +2369,25 @@ func (c *Client) UpdateFact(ctx context.Context, factID int, req FactUpdateReque
 		return nil, c.parseError(resp)
 	}
 
-	var fact Fact
-	if err := json.NewDecoder(resp.Body).Decode(&fact); err != nil {
+	var delResp UsageAlertDeleteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&delResp); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &fact, nil
+	return &delResp, nil
 }
 
-// ─── Cognitive Intelligence ─────────────────────────────────────────────────
+// ─── Cache Stats ────────────────────────────────────────────────────────────
 
-// GetCognitiveStats returns system-wide cognitive statistics.
-func (c *Client) GetCognitiveStats(ctx context.Context) (*CognitiveStatsResponse, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/cognitive/stats", nil)
+// GetCacheStats returns response cache statistics.
+func (c *Client) GetCacheStats(ctx context.Context) (*CacheStatsResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/cache/stats", nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -989,25 +2397,39 @@ func (c *Client) GetCognitiveStats(ctx context.Context) (*CognitiveStatsResponse
 		return nil, c.parseError(resp)
 	}
 
-	var stats CognitiveStatsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+	var statsResp CacheStatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&statsResp); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &stats, nil
+	return &statsResp, nil
 }
 
-// ─── Usage ──────────────────────────────────────────────────────────────────
+// ─── Retention ──────────────────────────────────────────────────────────────
 
-// GetUsage returns aggregated usage statistics.
-func (c *Client) GetUsage(ctx context.Context) (*UsageResponse, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/usage", nil)
+// SetRetentionPolicy sets the retention policy for policy.Scope, replacing any
+// existing one. Expired records are removed by the server's periodic purge, or
+// immediately with PurgeExpired.
+func (c *Client) SetRetentionPolicy(ctx context.Context, policy RetentionPolicy) (*RetentionPolicy, error) {
+	if policy.Scope == "" {
+		return nil, invalidf("scope", "scope is required")
+	}
+	if policy.MaxAge < 0 {
+		return nil, invalidf("max_age_seconds", "max age must not be negative, got %v", policy.MaxAge)
+	}
+
+	body, err := json.Marshal(policy)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL+"/v1/retention/"+policy.Scope, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -1017,23 +2439,23 @@ func (c *Client) GetUsage(ctx context.Context) (*UsageResponse, error) {
 		return nil, c.parseError(resp)
 	}
 
-	var usageResp UsageResponse
-	if err := json.NewDecoder(resp.Body).Decode(&usageResp); err != nil {
+	var updated RetentionPolicy
+	if err := json.NewDecoder(resp.Body).Decode(&updated); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &usageResp, nil
+	return &updated, nil
 }
 
-// GetRecentUsage returns recent usage records.
-func (c *Client) GetRecentUsage(ctx context.Context) (*UsageRecentResponse, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/usage/recent", nil)
+// ListRetentionPolicies returns the retention policy of every scope that has one.
+func (c *Client) ListRetentionPolicies(ctx context.Context) (*RetentionPolicyListResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/retention", nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -1043,25 +2465,24 @@ func (c *Client) GetRecentUsage(ctx context.Context) (*UsageRecentResponse, erro
 		return nil, c.parseError(resp)
 	}
 
-	var recentResp UsageRecentResponse
-	if err := json.NewDecoder(resp.Body).Decode(&recentResp); err != nil {
+	var listResp RetentionPolicyListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &recentResp, nil
+	return &listResp, nil
 }
 
-// ─── Cache Stats ────────────────────────────────────────────────────────────
-
-// GetCacheStats returns response cache statistics.
-func (c *Client) GetCacheStats(ctx context.Context) (*CacheStatsResponse, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/cache/stats", nil)
+// PurgeExpired deletes conversations, usage records, and cache entries older than
+// their scope's retention policy now, instead of waiting for the periodic purge.
+func (c *Client) PurgeExpired(ctx context.Context) (*PurgeResponse, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/retention/purge", nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -1071,12 +2492,12 @@ func (c *Client) GetCacheStats(ctx context.Context) (*CacheStatsResponse, error)
 		return nil, c.parseError(resp)
 	}
 
-	var statsResp CacheStatsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&statsResp); err != nil {
+	var purgeResp PurgeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&purgeResp); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	return &statsResp, nil
+	return &purgeResp, nil
 }
 
 // ─── Readiness ──────────────────────────────────────────────────────────────
@@ -1088,7 +2509,7 @@ func (c *Client) Ready(ctx context.Context) (*ReadyResponse, error) {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -1116,7 +2537,7 @@ func (c *Client) GetMetrics(ctx context.Context) (string, error) {
 	}
 	c.setHeaders(httpReq)
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return "", fmt.Errorf("send request: %w", err)
 	}
@@ -1136,8 +2557,72 @@ func (c *Client) GetMetrics(ctx context.Context) (string, error) {
 
 // ─── Helpers ────────────────────────────────────────────────────────────────
 
+// do sends an HTTP request with the configured http.Client, retrying per WithRetry.
+// The call counts as in flight for Shutdown until the response body is closed.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	req, done, err := c.shutdown.track(req, c.requestTimeout(req))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.ensureInit(req.Context()); err != nil {
+		done()
+		return nil, err
+	}
+	resp, err := c.doRetry(c.degradedHTTPClient(), req)
+	return c.shutdown.hold(resp, err, done)
+}
+
+// doUntimed is do without the http.Client timeout, for transfers that take as
+// long as they take (audio, file uploads and downloads). Bound such calls with ctx.
+func (c *Client) doUntimed(req *http.Request) (*http.Response, error) {
+	req, done, err := c.shutdown.track(req, c.requestTimeout(req))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.ensureInit(req.Context()); err != nil {
+		done()
+		return nil, err
+	}
+	resp, err := c.doRetry(c.untimedHTTPClient(), req)
+	return c.shutdown.hold(resp, err, done)
+}
+
+// untimedHTTPClient is the configured http.Client without its Timeout. It
+// shares the transport, so proxy, TLS, and connection pooling still apply.
+func (c *Client) untimedHTTPClient() *http.Client {
+	hc := *c.httpClient
+	hc.Timeout = 0
+	return &hc
+}
+
+// doWith sends an HTTP request with hc through the middleware chain and records
+// it in the client stats. With WithLoadBalancing, it goes to the next target.
+func (c *Client) doWith(hc *http.Client, req *http.Request) (*http.Response, error) {
+	if c.configErr != nil {
+		return nil, c.configErr
+	}
+	var target *balancedTarget
+	if c.balancer != nil {
+		target = c.balancer.pick()
+		req = c.route(req, target)
+	}
+	resp, err := c.roundTrip(hc)(req)
+	if target != nil {
+		c.balancer.observe(target, resp, err)
+	}
+	c.stats.recordAttempt(resp, err)
+	c.rateLimit.record(resp)
+	c.observeOutbox(resp)
+	return resp, err
+}
+
 func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("Content-Type", "application/json")
+	if id, ok := ClientRequestIDFromContext(req.Context()); ok {
+		setRequestID(req.Header, id)
+	} else if id := newClientRequestID(); id != "" {
+		setRequestID(req.Header, id)
+	}
 	if c.apiKey != "" {
 		req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	}
@@ -1147,12 +2632,27 @@ func (c *Client) setHeaders(req *http.Request) {
 	if c.conversationID != "" {
 		req.Header.Set("X-Conversation-ID", c.conversationID)
 	}
-	if c.cognitiveDisabled {
+	if c.cognitiveDisabled || (c.degraded != nil && c.degraded.policy.DisableCognitive && c.Degraded()) {
 		req.Header.Set("X-Cognitive-Disabled", "true")
 	}
+	if c.translateContext {
+		req.Header.Set("X-Translate-Context", "true")
+	}
+	if c.namespace != "" {
+		req.Header.Set("X-Namespace", c.namespace)
+	}
+	if key, ok := idempotencyKeyFromContext(req.Context()); ok {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+	if opts, ok := RequestOptionsFromContext(req.Context()); ok {
+		applyOptions(req, opts)
+	}
 }
 
 func applyOptions(req *http.Request, opts RequestOptions) {
+	if opts.ClientRequestID != "" {
+		setRequestID(req.Header, opts.ClientRequestID)
+	}
 	if opts.UserID != "" {
 		req.Header.Set("X-User-ID", opts.UserID)
 	}
@@ -1162,26 +2662,58 @@ func applyOptions(req *http.Request, opts RequestOptions) {
 	if opts.CognitiveDisabled {
 		req.Header.Set("X-Cognitive-Disabled", "true")
 	}
+	if opts.TranslateContext {
+		req.Header.Set("X-Translate-Context", "true")
+	}
+	if opts.IncludeCitations {
+		req.Header.Set("X-Include-Citations", "true")
+	}
+	if opts.IncludeCognitiveTrace {
+		req.Header.Set("X-Include-Cognitive-Trace", "true")
+	}
+	if opts.IncludeConfidence {
+		req.Header.Set("X-Include-Confidence", "true")
+	}
+	if opts.Namespace != "" {
+		req.Header.Set("X-Namespace", opts.Namespace)
+	}
+	for k, v := range opts.Headers {
+		req.Header.Set(k, v)
+	}
 }
 
 func (c *Client) parseError(resp *http.Response) error {
-	body, _ := io.ReadAll(resp.Body)
-
-	var errResp ErrorResponse
-	if err := json.Unmarshal(body, &errResp); err != nil {
-		return &APIError{
-			StatusCode: resp.StatusCode,
-			ErrorBody: ErrorResponse{
-				Error: ErrorDetail{
-					Message: string(body),
-					Type:    "unknown_error",
-				},
-			},
-		}
-	}
+	body, readErr := io.ReadAll(resp.Body)
 
-	return &APIError{
+	apiErr := &APIError{
 		StatusCode: resp.StatusCode,
-		ErrorBody:  errResp,
+		RequestID:  resp.Header.Get(HeaderRequestID),
+		RateLimit:  parseRateLimit(resp.Header),
+		Header:     resp.Header,
+		Body:       body,
+	}
+	if readErr != nil {
+		apiErr.Err = fmt.Errorf("read error body: %w", readErr)
+	}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.Endpoint = resp.Request.URL.Path
+		apiErr.ClientRequestID = resp.Request.Header.Get(HeaderClientRequestID)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		apiErr.RetryAfter = retryAfter(resp)
+	}
+
+	if err := json.Unmarshal(body, &apiErr.ErrorBody); err != nil {
+		if resp.Request != nil && len(body) > 0 {
+			c.logger.malformed(resp.Request.Context(), "malformed error response", err, slog.Int("status", resp.StatusCode))
+		}
+		apiErr.ErrorBody = ErrorResponse{
+			Error: ErrorDetail{
+				Message: string(body),
+				Type:    "unknown_error",
+			},
+		}
 	}
+	return apiErr
 }
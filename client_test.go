@@ -2,13 +2,20 @@ package hackeserasdk
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
+	"time"
 )
 
 // ─── Helpers ────────────────────────────────────────────────────────────────
@@ -40,6 +47,174 @@ func newTestServerFunc(handler http.HandlerFunc) *httptest.Server {
 	return httptest.NewServer(handler)
 }
 
+// scriptedServer is a test backend for flows that span several requests. Chat
+// completions are answered from replies, one per request in order; stream
+// requests get the sse chunks if set, else the reply as one content delta.
+// Other paths are served by handlers added with handle. Every request is
+// recorded under a lock, so handlers may run concurrently.
+type scriptedServer struct {
+	*httptest.Server
+	t *testing.T
+
+	// onChat, if set, runs before each chat completion is answered.
+	onChat func(ChatRequest)
+
+	mu       sync.Mutex
+	replies  []Message
+	sse      []string
+	handlers map[string]http.HandlerFunc
+	calls    []scriptedCall
+}
+
+// scriptedCall is one request received by a scriptedServer.
+type scriptedCall struct {
+	Path   string
+	Header http.Header
+	// Chat is the decoded body of a chat completion request.
+	Chat ChatRequest
+}
+
+func newScriptedServer(t *testing.T, replies ...Message) *scriptedServer {
+	t.Helper()
+	s := &scriptedServer{t: t, replies: replies, handlers: map[string]http.HandlerFunc{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// assistantReply is a scripted text reply.
+func assistantReply(content string) Message {
+	return Message{Role: "assistant", Content: content}
+}
+
+// toolCallReply is a scripted reply calling one function tool.
+func toolCallReply(id, name, args string) Message {
+	return Message{Role: "assistant", ToolCalls: []ToolCall{{
+		ID: id, Type: ToolTypeFunction, Function: FunctionCall{Name: name, Arguments: args},
+	}}}
+}
+
+// handle serves path with h instead of the script.
+func (s *scriptedServer) handle(path string, h http.HandlerFunc) *scriptedServer {
+	s.mu.Lock()
+	s.handlers[path] = h
+	s.mu.Unlock()
+	return s
+}
+
+// withSSE sets the raw chunk payloads sent to stream requests.
+func (s *scriptedServer) withSSE(chunks ...string) *scriptedServer {
+	s.mu.Lock()
+	s.sse = chunks
+	s.mu.Unlock()
+	return s
+}
+
+// chats returns the chat completion requests received so far.
+func (s *scriptedServer) chats() []ChatRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []ChatRequest
+	for _, c := range s.calls {
+		if c.Path == "/v1/chat/completions" {
+			out = append(out, c.Chat)
+		}
+	}
+	return out
+}
+
+// count returns the number of requests received for path.
+func (s *scriptedServer) count(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, c := range s.calls {
+		if c.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+// last returns the most recent request received for path.
+func (s *scriptedServer) last(path string) scriptedCall {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.calls) - 1; i >= 0; i-- {
+		if s.calls[i].Path == path {
+			return s.calls[i]
+		}
+	}
+	return scriptedCall{}
+}
+
+func (s *scriptedServer) serve(w http.ResponseWriter, r *http.Request) {
+	call := scriptedCall{Path: r.URL.Path, Header: r.Header.Clone()}
+	s.mu.Lock()
+	h, custom := s.handlers[r.URL.Path]
+	s.mu.Unlock()
+	if custom {
+		s.record(call)
+		h(w, r)
+		return
+	}
+	if r.URL.Path != "/v1/chat/completions" {
+		s.record(call)
+		s.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		http.NotFound(w, r)
+		return
+	}
+
+	json.NewDecoder(r.Body).Decode(&call.Chat)
+	n := s.record(call)
+	if s.onChat != nil {
+		s.onChat(call.Chat)
+	}
+
+	s.mu.Lock()
+	sse := s.sse
+	var reply Message
+	ok := n <= len(s.replies)
+	if ok {
+		reply = s.replies[n-1]
+	}
+	s.mu.Unlock()
+
+	if call.Chat.Stream {
+		if sse == nil {
+			data, _ := json.Marshal(ChatStreamChunk{Choices: []ChunkChoice{{Delta: Delta{Role: "assistant", Content: messageText(reply)}}}})
+			sse = []string{string(data)}
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, c := range sse {
+			fmt.Fprintf(w, "data: %s\n\n", c)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		return
+	}
+	if !ok && len(s.replies) > 0 {
+		s.t.Errorf("unexpected chat completion %d: only %d replies scripted", n, len(s.replies))
+		http.Error(w, "script exhausted", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-scripted", Model: call.Chat.Model, Choices: []Choice{{Message: reply}}})
+}
+
+// record stores call and returns how many chat completions have been received
+// including it.
+func (s *scriptedServer) record(call scriptedCall) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, call)
+	n := 0
+	for _, c := range s.calls {
+		if c.Path == "/v1/chat/completions" {
+			n++
+		}
+	}
+	return n
+}
+
 // ─── Chat Completions ───────────────────────────────────────────────────────
 
 func TestChatCompletion(t *testing.T) {
@@ -213,509 +388,2126 @@ func TestChatCompletionWithToolCalling(t *testing.T) {
 	}
 }
 
-func TestChatCompletionWithAllParams(t *testing.T) {
+func TestChatCompletionWithBuiltInTools(t *testing.T) {
 	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
 		body, _ := io.ReadAll(r.Body)
-		var req ChatRequest
-		json.Unmarshal(body, &req)
-
-		if req.User != "user-1" {
-			t.Errorf("expected user user-1, got %q", req.User)
+		var raw struct {
+			Tools []map[string]json.RawMessage `json:"tools"`
 		}
-		if req.PresencePenalty == nil || *req.PresencePenalty != 0.5 {
-			t.Errorf("expected presence_penalty 0.5")
+		json.Unmarshal(body, &raw)
+
+		if len(raw.Tools) != 2 {
+			t.Fatalf("expected 2 tools, got %d", len(raw.Tools))
 		}
-		if req.FrequencyPenalty == nil || *req.FrequencyPenalty != 0.3 {
-			t.Errorf("expected frequency_penalty 0.3")
+		if _, ok := raw.Tools[0]["function"]; ok {
+			t.Error("expected built-in tool to omit function")
 		}
-		if req.Seed == nil || *req.Seed != 42 {
-			t.Errorf("expected seed 42")
+		if string(raw.Tools[0]["web_search"]) != `{"max_results":3}` {
+			t.Errorf("expected web_search config, got %s", raw.Tools[0]["web_search"])
 		}
-		if req.ResponseFormat == nil || req.ResponseFormat.Type != "json_object" {
-			t.Errorf("expected response_format json_object")
+		if string(raw.Tools[1]["type"]) != `"knowledge_search"` {
+			t.Errorf("expected knowledge_search tool, got %s", raw.Tools[1]["type"])
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(ChatResponse{
-			ID:      "chatcmpl-params",
-			Choices: []Choice{{Message: Message{Role: "assistant", Content: `{"answer":"ok"}`}}},
-		})
+		w.Write([]byte(`{"id":"chatcmpl-builtin","choices":[{"index":0,"message":{"role":"assistant","content":"See results.",
+			"tool_calls":[{"id":"ws-1","type":"web_search","web_search":{"query":"CVE-2024-3094",
+			"results":[{"title":"xz backdoor","url":"https://example.com/xz"}]}}]},"finish_reason":"stop"}]}`))
 	})
 	defer srv.Close()
 
 	client := NewClient(srv.URL, "test-key")
-	_, err := client.ChatCompletion(context.Background(), ChatRequest{
-		Model:            ModelDefault,
-		Messages:         []Message{{Role: "user", Content: "test"}},
-		User:             "user-1",
-		PresencePenalty:  Float64Ptr(0.5),
-		FrequencyPenalty: Float64Ptr(0.3),
-		Seed:             IntPtr(42),
-		ResponseFormat:   &ResponseFormat{Type: "json_object"},
+	resp, err := client.ChatCompletion(context.Background(), ChatRequest{
+		Model:    ModelDefault,
+		Messages: []Message{{Role: "user", Content: "What is CVE-2024-3094?"}},
+		Tools: []Tool{
+			WebSearchTool(&WebSearchConfig{MaxResults: 3}),
+			KnowledgeSearchTool(nil),
+		},
 	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-}
 
-func TestChatCompletionStream(t *testing.T) {
-	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/event-stream")
-		w.WriteHeader(http.StatusOK)
+	call := resp.Choices[0].Message.ToolCalls[0]
+	if !call.BuiltIn() || call.WebSearch == nil {
+		t.Fatalf("expected built-in web search call, got %+v", call)
+	}
+	if call.WebSearch.Query != "CVE-2024-3094" || len(call.WebSearch.Results) != 1 || call.WebSearch.Results[0].URL != "https://example.com/xz" {
+		t.Errorf("unexpected web search result: %+v", call.WebSearch)
+	}
 
-		chunks := []string{
-			`{"id":"chatcmpl-s1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"role":"assistant","content":"Hello"}}]}`,
-			`{"id":"chatcmpl-s1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":" world"}}]}`,
-			`{"id":"chatcmpl-s1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{},"finish_reason":"stop"}],"usage":{"prompt_tokens":5,"completion_tokens":2,"total_tokens":7}}`,
+	resent, _ := json.Marshal(call)
+	if strings.Contains(string(resent), `"function"`) {
+		t.Errorf("expected re-sent built-in call to omit function, got %s", resent)
+	}
+}
+
+func TestToolChoiceMarshalling(t *testing.T) {
+	tools := []Tool{{Type: ToolTypeFunction, Function: ToolFunction{Name: "get_weather"}}}
+	cases := []struct {
+		choice *ToolChoice
+		want   string
+	}{
+		{ToolChoiceAuto(), `"auto"`},
+		{ToolChoiceNone(), `"none"`},
+		{ToolChoiceRequired(), `"required"`},
+		{ToolChoiceFunction("get_weather"), `{"function":{"name":"get_weather"},"type":"function"}`},
+	}
+	for _, tc := range cases {
+		req := ChatRequest{Model: ModelDefault, Tools: tools, ToolChoice: tc.choice}
+		if err := req.normalize(); err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.want, err)
 		}
-		for _, c := range chunks {
-			fmt.Fprintf(w, "data: %s\n\n", c)
+		data, _ := json.Marshal(req)
+		var raw map[string]json.RawMessage
+		json.Unmarshal(data, &raw)
+		if string(raw["tool_choice"]) != tc.want {
+			t.Errorf("expected tool_choice %s, got %s", tc.want, raw["tool_choice"])
 		}
-		fmt.Fprint(w, "data: [DONE]\n\n")
-	})
-	defer srv.Close()
-
-	client := NewClient(srv.URL, "test-key")
-	chunks, errs := client.ChatCompletionStream(context.Background(), ChatRequest{
-		Model:    ModelDefault,
-		Messages: []Message{{Role: "user", Content: "Hi"}},
-	})
 
-	var content strings.Builder
-	for chunk := range chunks {
-		if len(chunk.Choices) > 0 {
-			content.WriteString(chunk.Choices[0].Delta.Content)
+		var decoded ChatRequest
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if *decoded.ToolChoice != *tc.choice {
+			t.Errorf("expected %s to round-trip, got %+v", tc.want, decoded.ToolChoice)
 		}
 	}
+}
 
-	// Check for errors
-	for err := range errs {
-		if err != nil {
-			t.Fatalf("unexpected stream error: %v", err)
-		}
+func TestToolChoiceConstructorsReturnFreshValues(t *testing.T) {
+	req := ChatRequest{ToolChoice: ToolChoiceAuto()}
+	if err := json.Unmarshal([]byte(`"none"`), req.ToolChoice); err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
+	if ToolChoiceAuto().Mode() != "auto" {
+		t.Error("decoding into a request's tool choice changed ToolChoiceAuto")
+	}
+}
 
-	if content.String() != "Hello world" {
-		t.Errorf("expected streamed content %q, got %q", "Hello world", content.String())
+func TestToolChoiceValidation(t *testing.T) {
+	tools := []Tool{{Type: ToolTypeFunction, Function: ToolFunction{Name: "get_weather"}}}
+	cases := []struct {
+		req  ChatRequest
+		want string
+	}{
+		{ChatRequest{ToolChoice: ToolChoiceRequired()}, "at least one tool"},
+		{ChatRequest{Tools: tools, ToolChoice: ToolChoiceFunction("get_time")}, `"get_time" is not among`},
+		{ChatRequest{Tools: tools, ToolChoice: ToolChoiceFunction("")}, "needs a name"},
+	}
+	for _, tc := range cases {
+		err := tc.req.normalize()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("expected error containing %q, got %v", tc.want, err)
+		}
 	}
 }
 
-func TestChatCompletionStreamWithOptions(t *testing.T) {
+func TestChatCompletionSafetyAnnotations(t *testing.T) {
 	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Header.Get("X-User-ID") != "stream-user" {
-			t.Errorf("expected X-User-ID=stream-user, got %q", r.Header.Get("X-User-ID"))
-		}
-		w.Header().Set("Content-Type", "text/event-stream")
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprintf(w, "data: %s\n\n", `{"id":"s1","choices":[{"index":0,"delta":{"content":"ok"}}]}`)
-		fmt.Fprint(w, "data: [DONE]\n\n")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"chatcmpl-s","choices":[{"index":0,"message":{"role":"assistant","content":"Use [redacted] to test."},
+			"finish_reason":"content_filter","safety":{
+				"categories":[{"category":"violence","severity":"low"},{"category":"weapons","severity":"medium","filtered":true}],
+				"redactions":[{"start":4,"end":14,"category":"weapons"}]}}]}`)
 	})
 	defer srv.Close()
 
 	client := NewClient(srv.URL, "test-key")
-	chunks, errs := client.ChatCompletionStreamWithOptions(context.Background(), ChatRequest{
-		Model:    ModelDefault,
-		Messages: []Message{{Role: "user", Content: "test"}},
-	}, RequestOptions{UserID: "stream-user"})
-
-	for range chunks {
-	}
-	for err := range errs {
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
+	resp, err := client.ChatCompletion(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-}
-
-// ─── Error Handling ─────────────────────────────────────────────────────────
-
-func TestAPIError(t *testing.T) {
-	errBody := ErrorResponse{
-		Error: ErrorDetail{
-			Message: "Missing Authorization header",
-			Type:    "invalid_request_error",
-			Code:    StringPtr("invalid_api_key"),
-		},
+	safety := resp.Choices[0].Safety
+	if safety == nil || len(safety.Categories) != 2 || !safety.Categories[1].Filtered {
+		t.Fatalf("unexpected safety annotations: %+v", safety)
 	}
-
-	srv := newTestServer(t, http.MethodGet, "/v1/models", http.StatusUnauthorized, errBody)
-	defer srv.Close()
-
-	client := NewClient(srv.URL, "")
-	_, err := client.ListModels(context.Background())
-	if err == nil {
-		t.Fatal("expected error, got nil")
+	r := safety.Redactions[0]
+	if got := (CitationSpan{Start: r.Start, End: r.End}).Text(messageText(resp.Choices[0].Message)); got != "[redacted]" {
+		t.Errorf("expected redaction span to cover the marker, got %q", got)
 	}
+}
 
-	apiErr, ok := err.(*APIError)
-	if !ok {
-		t.Fatalf("expected *APIError, got %T", err)
+func TestSafetyAnnotationsFlagged(t *testing.T) {
+	var none *SafetyAnnotations
+	if none.Flagged(SeverityLow) {
+		t.Error("expected nil annotations not to be flagged")
 	}
-	if apiErr.StatusCode != 401 {
-		t.Errorf("expected status 401, got %d", apiErr.StatusCode)
+	low := &SafetyAnnotations{Categories: []SafetyCategory{{Category: "violence", Severity: SeverityLow}}}
+	if !low.Flagged(SeverityLow) || low.Flagged(SeverityMedium) {
+		t.Error("expected low severity to meet only a low threshold")
 	}
-	if apiErr.Error() != "Missing Authorization header" {
-		t.Errorf("expected error message %q, got %q", "Missing Authorization header", apiErr.Error())
+	filtered := &SafetyAnnotations{Categories: []SafetyCategory{{Category: "hate", Severity: SeveritySafe, Filtered: true}}}
+	if !filtered.Flagged(SeverityHigh) {
+		t.Error("expected filtered category to be flagged at any threshold")
 	}
-	if apiErr.ErrorBody.Error.Type != "invalid_request_error" {
-		t.Errorf("expected error type %q, got %q", "invalid_request_error", apiErr.ErrorBody.Error.Type)
+	unknown := &SafetyAnnotations{Categories: []SafetyCategory{{Category: "hate", Severity: "severe"}}}
+	if !unknown.Flagged(SeverityHigh) {
+		t.Error("expected unknown severity to be treated as high")
 	}
 }
 
-func TestAPIErrorNonJSON(t *testing.T) {
+func TestChatCompletionLogProbs(t *testing.T) {
 	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("internal server error"))
+		var raw map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&raw)
+		if raw["n"] != float64(2) || raw["logprobs"] != true || raw["top_logprobs"] != float64(2) {
+			t.Errorf("unexpected sampling params: n=%v logprobs=%v top_logprobs=%v", raw["n"], raw["logprobs"], raw["top_logprobs"])
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"chatcmpl-lp","choices":[
+			{"index":0,"message":{"role":"assistant","content":"Yes"},"finish_reason":"stop","logprobs":{"content":[
+				{"token":"Yes","logprob":-0.02,"bytes":[89,101,115],"top_logprobs":[{"token":"Yes","logprob":-0.02},{"token":"No","logprob":-4.1}]}]}},
+			{"index":1,"message":{"role":"assistant","content":"No"},"finish_reason":"stop","logprobs":null}]}`)
 	})
 	defer srv.Close()
 
 	client := NewClient(srv.URL, "test-key")
-	_, err := client.ListModels(context.Background())
-	if err == nil {
-		t.Fatal("expected error, got nil")
+	resp, err := client.ChatCompletion(context.Background(), ChatRequest{
+		Model:       ModelDefault,
+		Messages:    []Message{{Role: "user", Content: "Is port 22 open?"}},
+		N:           IntPtr(2),
+		LogProbs:    BoolPtr(true),
+		TopLogProbs: IntPtr(2),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-
-	apiErr, ok := err.(*APIError)
-	if !ok {
-		t.Fatalf("expected *APIError, got %T", err)
+	if len(resp.Choices) != 2 {
+		t.Fatalf("expected 2 choices, got %d", len(resp.Choices))
 	}
-	if apiErr.StatusCode != 500 {
-		t.Errorf("expected status 500, got %d", apiErr.StatusCode)
+	lp := resp.Choices[0].LogProbs
+	if lp == nil || len(lp.Content) != 1 {
+		t.Fatalf("expected one token logprob, got %+v", lp)
 	}
-	if apiErr.ErrorBody.Error.Type != "unknown_error" {
-		t.Errorf("expected error type unknown_error, got %q", apiErr.ErrorBody.Error.Type)
+	tok := lp.Content[0]
+	if tok.Token != "Yes" || tok.LogProb != -0.02 || len(tok.Bytes) != 3 {
+		t.Errorf("unexpected token logprob: %+v", tok)
+	}
+	if len(tok.TopLogProbs) != 2 || tok.TopLogProbs[1].Token != "No" {
+		t.Errorf("unexpected top logprobs: %+v", tok.TopLogProbs)
+	}
+	if resp.Choices[1].LogProbs != nil {
+		t.Errorf("expected nil logprobs for second choice, got %+v", resp.Choices[1].LogProbs)
 	}
 }
 
-// ─── Client Configuration ───────────────────────────────────────────────────
+func TestLogProbsValidation(t *testing.T) {
+	cases := []struct {
+		req  ChatRequest
+		want string
+	}{
+		{ChatRequest{N: IntPtr(0)}, "n must be at least 1"},
+		{ChatRequest{TopLogProbs: IntPtr(3)}, "requires logprobs"},
+		{ChatRequest{LogProbs: BoolPtr(false), TopLogProbs: IntPtr(3)}, "requires logprobs"},
+		{ChatRequest{LogProbs: BoolPtr(true), TopLogProbs: IntPtr(MaxTopLogProbs + 1)}, "top_logprobs must be between"},
+	}
+	for _, tc := range cases {
+		err := tc.req.normalize()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("expected error containing %q, got %v", tc.want, err)
+		}
+	}
+	ok := ChatRequest{N: IntPtr(3), LogProbs: BoolPtr(true), TopLogProbs: IntPtr(MaxTopLogProbs)}
+	if err := ok.normalize(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
 
-func TestSetHeaders(t *testing.T) {
+func TestChatCompletionContextDocuments(t *testing.T) {
 	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Header.Get("Authorization") != "Bearer my-key" {
-			t.Errorf("expected auth header, got %q", r.Header.Get("Authorization"))
-		}
-		if r.Header.Get("Content-Type") != "application/json" {
-			t.Errorf("expected content-type, got %q", r.Header.Get("Content-Type"))
-		}
-		if r.Header.Get("X-User-ID") != "global-user" {
-			t.Errorf("expected X-User-ID=global-user, got %q", r.Header.Get("X-User-ID"))
-		}
-		if r.Header.Get("X-Conversation-ID") != "global-conv" {
-			t.Errorf("expected X-Conversation-ID=global-conv, got %q", r.Header.Get("X-Conversation-ID"))
-		}
-		if r.Header.Get("X-Cognitive-Disabled") != "true" {
-			t.Errorf("expected X-Cognitive-Disabled=true, got %q", r.Header.Get("X-Cognitive-Disabled"))
+		var req ChatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if len(req.ContextDocuments) != 1 || req.ContextDocuments[0].Name != "q3-report.txt" || !strings.Contains(req.ContextDocuments[0].Content, "revenue") {
+			t.Errorf("unexpected context documents: %+v", req.ContextDocuments)
 		}
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(ModelList{Object: "list", Data: []Model{{ID: "hackersera-ai"}}})
+		json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-ctx", Citations: []Citation{{ChunkID: "inline-0", Filename: "q3-report.txt"}}})
 	})
 	defer srv.Close()
 
-	client := NewClient(srv.URL, "my-key").
-		SetUserID("global-user").
-		SetConversationID("global-conv").
-		SetCognitiveDisabled(true)
-
-	// Use ListModels (not Health) because Health doesn't call setHeaders by design
-	_, err := client.ListModels(context.Background())
+	client := NewClient(srv.URL, "test-key")
+	resp, err := client.ChatCompletion(context.Background(), ChatRequest{
+		Model:            ModelDefault,
+		Messages:         []Message{{Role: "user", Content: "What drove growth?"}},
+		ContextDocuments: []InlineDocument{{Name: "q3-report.txt", Content: "Q3 revenue grew 12% on renewals."}},
+	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if len(resp.Citations) != 1 || resp.Citations[0].Filename != "q3-report.txt" {
+		t.Errorf("unexpected citations: %+v", resp.Citations)
+	}
 }
 
-func TestNewClientTrimsTrailingSlash(t *testing.T) {
-	client := NewClient("https://api.example.com/", "key")
-	if client.baseURL != "https://api.example.com" {
-		t.Errorf("expected trimmed URL, got %q", client.baseURL)
+func TestChatRequestValidate(t *testing.T) {
+	msgs := []Message{{Role: "user", Content: "hi"}}
+	cases := []struct {
+		req   ChatRequest
+		field string
+	}{
+		{ChatRequest{Messages: msgs}, "model"},
+		{ChatRequest{Model: ModelDefault}, "messages"},
+		{ChatRequest{Model: ModelDefault, Messages: []Message{{Content: "hi"}}}, "messages[0].role"},
+		{ChatRequest{Model: ModelDefault, Messages: msgs, Temperature: Float64Ptr(2.5)}, "temperature"},
+		{ChatRequest{Model: ModelDefault, Messages: msgs, TopP: Float64Ptr(-0.1)}, "top_p"},
+		{ChatRequest{Model: ModelDefault, Messages: msgs, PresencePenalty: Float64Ptr(3)}, "presence_penalty"},
+		{ChatRequest{Model: ModelDefault, Messages: msgs, MaxTokens: IntPtr(0)}, "max_tokens"},
+		{ChatRequest{Model: ModelDefault, Messages: msgs, MaxTokens: IntPtr(100), MaxCompletionTokens: IntPtr(200)}, "max_tokens"},
+		{ChatRequest{Model: ModelDefault, Messages: msgs, ToolChoice: ToolChoiceRequired()}, "tool_choice"},
+		{ChatRequest{Model: ModelDefault, Messages: msgs, ContextDocuments: []InlineDocument{{Name: "empty.txt", Content: " "}}}, "context_documents[0].content"},
+	}
+	for _, tc := range cases {
+		var vErr *ValidationError
+		if err := tc.req.Validate(); !errors.As(err, &vErr) || vErr.Field != tc.field {
+			t.Errorf("expected ValidationError on %s, got %v", tc.field, err)
+		}
 	}
-}
 
-func TestWithHTTPClient(t *testing.T) {
-	custom := &http.Client{}
-	client := NewClient("http://localhost", "key").WithHTTPClient(custom)
-	if client.httpClient != custom {
-		t.Error("expected custom HTTP client to be set")
+	req := ChatRequest{Model: ModelDefault, Messages: msgs, MaxTokens: IntPtr(100), MaxCompletionTokens: IntPtr(100)}
+	if err := req.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if req.MaxTokens == nil {
+		t.Error("Validate should not modify the request")
 	}
 }
 
-// ─── Models ─────────────────────────────────────────────────────────────────
+func TestChatCompletionValidatesBeforeSending(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not be sent")
+	})
+	defer srv.Close()
 
-func TestListModels(t *testing.T) {
-	expected := ModelList{
-		Object: "list",
-		Data: []Model{
-			{ID: "hackersera-ai", Object: "model", OwnedBy: "hackersera"},
-			{ID: "hackersera-ai-pro", Object: "model", OwnedBy: "hackersera"},
-		},
+	client := NewClient(srv.URL, "test-key")
+	_, err := client.ChatCompletion(context.Background(), ChatRequest{Model: ModelDefault})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Field != "messages" {
+		t.Fatalf("expected ValidationError, got %v", err)
 	}
+}
 
-	srv := newTestServer(t, http.MethodGet, "/v1/models", http.StatusOK, expected)
+func TestMaxTokensCollapsedWhenEqual(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var raw map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&raw)
+		if _, ok := raw["max_tokens"]; ok {
+			t.Errorf("expected max_tokens to be dropped, got %v", raw["max_tokens"])
+		}
+		if raw["max_completion_tokens"] != float64(256) {
+			t.Errorf("expected max_completion_tokens 256, got %v", raw["max_completion_tokens"])
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"chatcmpl-max","choices":[]}`)
+	})
 	defer srv.Close()
 
 	client := NewClient(srv.URL, "test-key")
-	models, err := client.ListModels(context.Background())
+	_, err := client.ChatCompletion(context.Background(), ChatRequest{
+		Model:               ModelDefault,
+		Messages:            []Message{{Role: "user", Content: "hi"}},
+		MaxTokens:           IntPtr(256),
+		MaxCompletionTokens: IntPtr(256),
+	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(models.Data) != 2 {
-		t.Errorf("expected 2 models, got %d", len(models.Data))
+}
+
+func TestChatCompletionWithAllParams(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req ChatRequest
+		json.Unmarshal(body, &req)
+
+		if req.User != "user-1" {
+			t.Errorf("expected user user-1, got %q", req.User)
+		}
+		if req.PresencePenalty == nil || *req.PresencePenalty != 0.5 {
+			t.Errorf("expected presence_penalty 0.5")
+		}
+		if req.FrequencyPenalty == nil || *req.FrequencyPenalty != 0.3 {
+			t.Errorf("expected frequency_penalty 0.3")
+		}
+		if req.Seed == nil || *req.Seed != 42 {
+			t.Errorf("expected seed 42")
+		}
+		if req.ResponseFormat == nil || req.ResponseFormat.Type != "json_object" {
+			t.Errorf("expected response_format json_object")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{
+			ID:      "chatcmpl-params",
+			Choices: []Choice{{Message: Message{Role: "assistant", Content: `{"answer":"ok"}`}}},
+		})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	_, err := client.ChatCompletion(context.Background(), ChatRequest{
+		Model:            ModelDefault,
+		Messages:         []Message{{Role: "user", Content: "test"}},
+		User:             "user-1",
+		PresencePenalty:  Float64Ptr(0.5),
+		FrequencyPenalty: Float64Ptr(0.3),
+		Seed:             IntPtr(42),
+		ResponseFormat:   &ResponseFormat{Type: "json_object"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestChatCompletionPromptCaching(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var raw map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&raw)
+
+		if raw["prompt_cache_key"] != "kb-prefix-v1" {
+			t.Errorf("expected prompt_cache_key, got %v", raw["prompt_cache_key"])
+		}
+		msgs := raw["messages"].([]interface{})
+		system := msgs[0].(map[string]interface{})
+		cc, ok := system["cache_control"].(map[string]interface{})
+		if !ok || cc["type"] != CacheControlEphemeral {
+			t.Errorf("expected cache_control on system message, got %v", system["cache_control"])
+		}
+		if _, ok := msgs[1].(map[string]interface{})["cache_control"]; ok {
+			t.Error("expected no cache_control on user message")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"chatcmpl-cache","choices":[],"usage":{"prompt_tokens":1200,"completion_tokens":10,"total_tokens":1210,"prompt_tokens_details":{"cached_tokens":1024}}}`)
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	resp, err := client.ChatCompletion(context.Background(), ChatRequest{
+		Model: ModelDefault,
+		Messages: []Message{
+			Message{Role: "system", Content: "Long stable policy document..."}.Cacheable(),
+			{Role: "user", Content: "Summarize section 4"},
+		},
+		PromptCacheKey: "kb-prefix-v1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Usage.CachedTokens != 1024 {
+		t.Errorf("expected 1024 cached tokens, got %d", resp.Usage.CachedTokens)
+	}
+}
+
+func TestChatCompletionPrediction(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Prediction == nil || req.Prediction.Type != "content" || req.Prediction.Content != "func main() {}" {
+			t.Errorf("unexpected prediction: %+v", req.Prediction)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"chatcmpl-pred","choices":[],"usage":{"prompt_tokens":20,"completion_tokens":8,"total_tokens":28,"completion_tokens_details":{"accepted_prediction_tokens":6,"rejected_prediction_tokens":1}}}`)
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	resp, err := client.ChatCompletion(context.Background(), ChatRequest{
+		Model:      ModelDefault,
+		Messages:   []Message{{Role: "user", Content: "Rename main to run"}},
+		Prediction: PredictedContent("func main() {}"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	details := resp.Usage.CompletionTokensDetails
+	if details == nil || details.AcceptedPredictionTokens != 6 || details.RejectedPredictionTokens != 1 {
+		t.Errorf("unexpected completion token details: %+v", details)
+	}
+}
+
+func TestChatCompletionLogitBiasAndStop(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.LogitBias["74694"] != -100 {
+			t.Errorf("expected logit_bias for token 74694, got %v", req.LogitBias)
+		}
+		if len(req.Stop) != 2 || req.Stop[0] != "```" || req.Stop[1] != "\n\n" {
+			t.Errorf("expected normalized stop sequences, got %q", req.Stop)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-bias"})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	_, err := client.ChatCompletion(context.Background(), ChatRequest{
+		Model:     ModelDefault,
+		Messages:  []Message{{Role: "user", Content: "List hosts"}},
+		LogitBias: map[string]int{"74694": -100},
+		Stop:      []string{"```", "", "\n\n", "```"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestChatCompletionRejectsInvalidStopAndBias(t *testing.T) {
+	client := NewClient("http://localhost", "test-key")
+	ctx := context.Background()
+
+	_, err := client.ChatCompletion(ctx, ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}, Stop: []string{"a", "b", "c", "d", "e"}})
+	if err == nil || !strings.Contains(err.Error(), "stop sequences") {
+		t.Errorf("expected stop sequence limit error, got %v", err)
+	}
+
+	_, err = client.ChatCompletion(ctx, ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}, LogitBias: map[string]int{"1": 101}})
+	if err == nil || !strings.Contains(err.Error(), "logit_bias") {
+		t.Errorf("expected logit_bias range error, got %v", err)
+	}
+
+	chunks, errs := client.ChatCompletionStream(ctx, ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}, LogitBias: map[string]int{"1": -101}})
+	for range chunks {
+	}
+	if err := <-errs; err == nil {
+		t.Error("expected stream to report validation error")
+	}
+}
+
+func TestChatCompletionConstrainedDecoding(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Regex != `CVE-\d{4}-\d{4,}` {
+			t.Errorf("expected regex constraint, got %q", req.Regex)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-regex"})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	ctx := context.Background()
+	_, err := client.ChatCompletion(ctx, ChatRequest{
+		Model:    ModelDefault,
+		Messages: []Message{{Role: "user", Content: "Which CVE is the xz backdoor?"}},
+		Regex:    `CVE-\d{4}-\d{4,}`,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = client.ChatCompletion(ctx, ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}, Regex: `\d+`, Grammar: `root ::= [0-9]+`})
+	if err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Errorf("expected grammar/regex conflict error, got %v", err)
+	}
+	_, err = client.ChatCompletion(ctx, ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}, Grammar: `root ::= "x"`, ResponseFormat: &ResponseFormat{Type: "json_object"}})
+	if err == nil || !strings.Contains(err.Error(), "response_format") {
+		t.Errorf("expected response_format conflict error, got %v", err)
+	}
+}
+
+func TestChatCompletionServingMetadata(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"chatcmpl-fp","choices":[],"system_fingerprint":"fp_3a9c","backend":"vllm","deployment_id":"ap-south-1a"}`)
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	resp, err := client.ChatCompletion(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}, Seed: IntPtr(7)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.SystemFingerprint != "fp_3a9c" {
+		t.Errorf("expected system_fingerprint fp_3a9c, got %q", resp.SystemFingerprint)
+	}
+	if resp.Backend != "vllm" || resp.DeploymentID != "ap-south-1a" {
+		t.Errorf("unexpected serving metadata: backend=%q deployment=%q", resp.Backend, resp.DeploymentID)
+	}
+}
+
+func TestChatStreamChunkServingMetadata(t *testing.T) {
+	var chunk ChatStreamChunk
+	if err := json.Unmarshal([]byte(`{"id":"s1","choices":[],"system_fingerprint":"fp_3a9c","backend":"vllm"}`), &chunk); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if chunk.SystemFingerprint != "fp_3a9c" || chunk.Backend != "vllm" {
+		t.Errorf("unexpected chunk metadata: %+v", chunk)
+	}
+}
+
+func TestChatCompletionCitationSpans(t *testing.T) {
+	answer := "Rotate keys yearly. Use MFA everywhere — always."
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Include-Citations") != "true" {
+			t.Errorf("expected X-Include-Citations=true, got %q", r.Header.Get("X-Include-Citations"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{
+			ID:      "chatcmpl-cite",
+			Choices: []Choice{{Message: Message{Role: "assistant", Content: answer}}},
+			Citations: []Citation{
+				{ChunkID: "chunk-1", DocumentID: "doc-keys", Filename: "keys.md"},
+				{ChunkID: "chunk-2", DocumentID: "doc-mfa", Filename: "mfa.md"},
+			},
+			Spans: []CitationSpan{
+				{Start: 0, End: 19, ChunkID: "chunk-1"},
+				{Start: 20, End: 48, ChunkID: "chunk-2"},
+			},
+		})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	resp, err := client.ChatCompletionWithOptions(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}, RequestOptions{IncludeCitations: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Citations) != 2 || len(resp.Spans) != 2 {
+		t.Fatalf("expected citations and spans, got %+v / %+v", resp.Citations, resp.Spans)
+	}
+	content := resp.Choices[0].Message.Content.(string)
+	if got := resp.Spans[0].Text(content); got != "Rotate keys yearly." {
+		t.Errorf("unexpected first span text %q", got)
+	}
+	if got := resp.Spans[1].Text(content); got != "Use MFA everywhere — always." {
+		t.Errorf("expected rune offsets across multibyte text, got %q", got)
+	}
+	if got := (CitationSpan{Start: 40, End: 100}).Text(content); got != "" {
+		t.Errorf("expected out-of-range span to return empty text, got %q", got)
+	}
+}
+
+func TestChatCompletionCognitiveTrace(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Include-Cognitive-Trace") != "true" {
+			t.Errorf("expected X-Include-Cognitive-Trace=true, got %q", r.Header.Get("X-Include-Cognitive-Trace"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"chatcmpl-trace","choices":[],"cognitive_trace":{
+			"facts_injected":[{"id":7,"content":"Prod runs on k8s","confidence":0.9}],
+			"preferences_applied":{"tone":"concise"},
+			"chunks_retrieved":[{"chunk_id":"c-1","document_id":"doc-1","score":0.82}],
+			"cache":{"consulted":true,"hit":false,"similarity":0.71},
+			"context_tokens":412}}`)
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	resp, err := client.ChatCompletionWithOptions(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}, RequestOptions{IncludeCognitiveTrace: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	trace := resp.CognitiveTrace
+	if trace == nil {
+		t.Fatal("expected cognitive trace")
+	}
+	if len(trace.FactsInjected) != 1 || trace.FactsInjected[0].ID != 7 {
+		t.Errorf("unexpected facts: %+v", trace.FactsInjected)
+	}
+	if trace.PreferencesApplied["tone"] != "concise" {
+		t.Errorf("unexpected preferences: %+v", trace.PreferencesApplied)
+	}
+	if len(trace.ChunksRetrieved) != 1 || trace.ChunksRetrieved[0].ChunkID != "c-1" {
+		t.Errorf("unexpected chunks: %+v", trace.ChunksRetrieved)
+	}
+	if !trace.Cache.Consulted || trace.Cache.Hit || trace.ContextTokens != 412 {
+		t.Errorf("unexpected cache/tokens: %+v", trace)
+	}
+}
+
+func TestChatCompletionConfidence(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Include-Confidence") != "true" {
+			t.Errorf("expected X-Include-Confidence=true, got %q", r.Header.Get("X-Include-Confidence"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"chatcmpl-conf","choices":[],"confidence":{
+			"score":0.31,"retrieval_score":0.22,"insufficient_context":true,"reason":"low_relevance"}}`)
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	resp, err := client.ChatCompletionWithOptions(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}, RequestOptions{IncludeConfidence: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conf := resp.Confidence
+	if conf == nil {
+		t.Fatal("expected confidence")
+	}
+	if conf.Score != 0.31 || !conf.InsufficientContext || conf.Reason != InsufficientLowRelevance {
+		t.Errorf("unexpected confidence: %+v", conf)
+	}
+	if !resp.LowConfidence(0.2) {
+		t.Error("expected insufficient context to count as low confidence")
+	}
+
+	resp.Confidence = &AnswerConfidence{Score: 0.8}
+	if resp.LowConfidence(0.5) || !resp.LowConfidence(0.9) {
+		t.Error("expected threshold comparison on score")
+	}
+	if (&ChatResponse{}).LowConfidence(0.5) {
+		t.Error("expected no confidence to report false")
+	}
+}
+
+func TestChatCompletionStream(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+
+		chunks := []string{
+			`{"id":"chatcmpl-s1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"role":"assistant","content":"Hello"}}]}`,
+			`{"id":"chatcmpl-s1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{"content":" world"}}]}`,
+			`{"id":"chatcmpl-s1","object":"chat.completion.chunk","choices":[{"index":0,"delta":{},"finish_reason":"stop"}],"usage":{"prompt_tokens":5,"completion_tokens":2,"total_tokens":7}}`,
+		}
+		for _, c := range chunks {
+			fmt.Fprintf(w, "data: %s\n\n", c)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	chunks, errs := client.ChatCompletionStream(context.Background(), ChatRequest{
+		Model:    ModelDefault,
+		Messages: []Message{{Role: "user", Content: "Hi"}},
+	})
+
+	var content strings.Builder
+	for chunk := range chunks {
+		if len(chunk.Choices) > 0 {
+			content.WriteString(chunk.Choices[0].Delta.Content)
+		}
+	}
+
+	// Check for errors
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected stream error: %v", err)
+		}
+	}
+
+	if content.String() != "Hello world" {
+		t.Errorf("expected streamed content %q, got %q", "Hello world", content.String())
+	}
+}
+
+func TestChatCompletionStreamWithOptions(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-User-ID") != "stream-user" {
+			t.Errorf("expected X-User-ID=stream-user, got %q", r.Header.Get("X-User-ID"))
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "data: %s\n\n", `{"id":"s1","choices":[{"index":0,"delta":{"content":"ok"}}]}`)
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	chunks, errs := client.ChatCompletionStreamWithOptions(context.Background(), ChatRequest{
+		Model:    ModelDefault,
+		Messages: []Message{{Role: "user", Content: "test"}},
+	}, RequestOptions{UserID: "stream-user"})
+
+	for range chunks {
+	}
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+// ─── Error Handling ─────────────────────────────────────────────────────────
+
+func TestAPIError(t *testing.T) {
+	errBody := ErrorResponse{
+		Error: ErrorDetail{
+			Message: "Missing Authorization header",
+			Type:    "invalid_request_error",
+			Code:    StringPtr("invalid_api_key"),
+		},
+	}
+
+	srv := newTestServer(t, http.MethodGet, "/v1/models", http.StatusUnauthorized, errBody)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "")
+	_, err := client.ListModels(context.Background())
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+	if apiErr.StatusCode != 401 {
+		t.Errorf("expected status 401, got %d", apiErr.StatusCode)
+	}
+	if apiErr.ErrorBody.Error.Message != "Missing Authorization header" {
+		t.Errorf("expected error message %q, got %q", "Missing Authorization header", apiErr.ErrorBody.Error.Message)
+	}
+	want := "GET /v1/models: 401 invalid_request_error: Missing Authorization header (request ID " + apiErr.ClientRequestID + ")"
+	if apiErr.Error() != want {
+		t.Errorf("expected Error() %q, got %q", want, apiErr.Error())
+	}
+	if apiErr.Method != http.MethodGet || apiErr.Endpoint != "/v1/models" || !strings.Contains(string(apiErr.Body), "Missing Authorization header") {
+		t.Errorf("expected request context and raw body, got %s %s %q", apiErr.Method, apiErr.Endpoint, apiErr.Body)
+	}
+	if apiErr.ErrorBody.Error.Type != "invalid_request_error" {
+		t.Errorf("expected error type %q, got %q", "invalid_request_error", apiErr.ErrorBody.Error.Type)
+	}
+}
+
+func TestAPIErrorNonJSON(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("internal server error"))
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	_, err := client.ListModels(context.Background())
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+	if apiErr.StatusCode != 500 {
+		t.Errorf("expected status 500, got %d", apiErr.StatusCode)
+	}
+	if apiErr.ErrorBody.Error.Type != "unknown_error" {
+		t.Errorf("expected error type unknown_error, got %q", apiErr.ErrorBody.Error.Type)
+	}
+}
+
+func TestAPIErrorUnwrapsReadError(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Promise more body than is sent, so reading it fails.
+		w.Header().Set("Content-Length", "100")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("bad gateway"))
+	})
+	defer srv.Close()
+
+	_, err := NewClient(srv.URL, "test-key").ListModels(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected 502 APIError, got %v", err)
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected the read error to be reachable, got Err %v", apiErr.Err)
+	}
+}
+
+// ─── Client Configuration ───────────────────────────────────────────────────
+
+func TestSetHeaders(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer my-key" {
+			t.Errorf("expected auth header, got %q", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("expected content-type, got %q", r.Header.Get("Content-Type"))
+		}
+		if r.Header.Get("X-User-ID") != "global-user" {
+			t.Errorf("expected X-User-ID=global-user, got %q", r.Header.Get("X-User-ID"))
+		}
+		if r.Header.Get("X-Conversation-ID") != "global-conv" {
+			t.Errorf("expected X-Conversation-ID=global-conv, got %q", r.Header.Get("X-Conversation-ID"))
+		}
+		if r.Header.Get("X-Cognitive-Disabled") != "true" {
+			t.Errorf("expected X-Cognitive-Disabled=true, got %q", r.Header.Get("X-Cognitive-Disabled"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ModelList{Object: "list", Data: []Model{{ID: "hackersera-ai"}}})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "my-key").
+		SetUserID("global-user").
+		SetConversationID("global-conv").
+		SetCognitiveDisabled(true)
+
+	// Use ListModels (not Health) because Health doesn't call setHeaders by design
+	_, err := client.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNamespaceHeader(t *testing.T) {
+	var got []string
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("X-Namespace"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").SetNamespace("tenant-a")
+	ctx := context.Background()
+
+	client.ListFacts(ctx, 0, nil)
+	client.QueryKnowledgeGraph(ctx, "vpn", 0)
+	client.GetProfile(ctx, "user-1")
+	client.ListConversations(ctx, 0)
+	client.ChatCompletionWithOptions(ctx, ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}, RequestOptions{Namespace: "tenant-b"})
+
+	expected := []string{"tenant-a", "tenant-a", "tenant-a", "tenant-a", "tenant-b"}
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected namespaces %v, got %v", expected, got)
+	}
+}
+
+func TestNewClientTrimsTrailingSlash(t *testing.T) {
+	client := NewClient("https://api.example.com/", "key")
+	if client.baseURL != "https://api.example.com" {
+		t.Errorf("expected trimmed URL, got %q", client.baseURL)
+	}
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	custom := &http.Client{}
+	client := NewClient("http://localhost", "key").WithHTTPClient(custom)
+	if client.httpClient != custom {
+		t.Error("expected custom HTTP client to be set")
+	}
+}
+
+// ─── Models ─────────────────────────────────────────────────────────────────
+
+func TestListModels(t *testing.T) {
+	expected := ModelList{
+		Object: "list",
+		Data: []Model{
+			{ID: "hackersera-ai", Object: "model", OwnedBy: "hackersera"},
+			{ID: "hackersera-ai-pro", Object: "model", OwnedBy: "hackersera"},
+		},
+	}
+
+	srv := newTestServer(t, http.MethodGet, "/v1/models", http.StatusOK, expected)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	models, err := client.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(models.Data) != 2 {
+		t.Errorf("expected 2 models, got %d", len(models.Data))
+	}
+	if models.Data[0].ID != "hackersera-ai" {
+		t.Errorf("expected model ID hackersera-ai, got %q", models.Data[0].ID)
+	}
+}
+
+func TestGetModel(t *testing.T) {
+	expected := Model{ID: "hackersera-ai", Object: "model", OwnedBy: "hackersera"}
+
+	srv := newTestServer(t, http.MethodGet, "/v1/models/", http.StatusOK, expected)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	model, err := client.GetModel(context.Background(), "hackersera-ai")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if model.ID != "hackersera-ai" {
+		t.Errorf("expected model ID hackersera-ai, got %q", model.ID)
+	}
+}
+
+// ─── Embeddings ─────────────────────────────────────────────────────────────
+
+func TestCreateEmbedding(t *testing.T) {
+	expected := EmbeddingResponse{
+		Object: "list",
+		Data: []EmbeddingData{
+			{Object: "embedding", Embedding: []float64{0.1, 0.2, 0.3}, Index: 0},
+		},
+		Model: "text-embedding-ada-002",
+		Usage: EmbeddingUsage{PromptTokens: 2, TotalTokens: 2},
+	}
+
+	srv := newTestServer(t, http.MethodPost, "/v1/embeddings", http.StatusOK, expected)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	resp, err := client.CreateEmbedding(context.Background(), EmbeddingRequest{
+		Input: "Hello world",
+		Model: ModelEmbedding,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Data) != 1 {
+		t.Fatalf("expected 1 embedding, got %d", len(resp.Data))
+	}
+	if len(resp.Data[0].Embedding) != 3 {
+		t.Errorf("expected 3 dimensions, got %d", len(resp.Data[0].Embedding))
+	}
+}
+
+func TestCreateEmbeddingWithDimensions(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/embeddings/models/"+ModelEmbedding {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(EmbeddingModelInfo{Model: ModelEmbedding, Dimensions: 1536, SupportedDimensions: []int{768, 256}})
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var raw map[string]interface{}
+		json.Unmarshal(body, &raw)
+
+		if raw["dimensions"] == nil {
+			t.Error("expected dimensions field in request")
+		}
+		if int(raw["dimensions"].(float64)) != 768 {
+			t.Errorf("expected dimensions 768, got %v", raw["dimensions"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(EmbeddingResponse{
+			Object: "list",
+			Data:   []EmbeddingData{{Embedding: make([]float64, 768)}},
+		})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	_, err := client.CreateEmbedding(context.Background(), EmbeddingRequest{
+		Input:      []string{"Hello", "World"},
+		Model:      ModelEmbedding,
+		Dimensions: IntPtr(768),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreateEmbeddingFloat32(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "float32") || strings.Contains(string(body), "Float32") {
+			t.Errorf("expected Float32 not to be sent, got %s", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"object":"list","model":"hackersera-ai-embedding","data":[{"object":"embedding","index":0,"embedding":[0.25,-0.5,1e-3]}],"usage":{"prompt_tokens":2,"total_tokens":2}}`))
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	resp, err := client.CreateEmbedding(context.Background(), EmbeddingRequest{Input: "x", Model: ModelEmbedding, Float32: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := resp.Data[0]
+	if d.Embedding != nil || len(d.Embedding32) != 3 || d.Embedding32[1] != -0.5 || d.Embedding32[2] != float32(1e-3) {
+		t.Errorf("expected float32 vector only, got %+v", d)
+	}
+	if resp.Model != ModelEmbedding || resp.Usage.TotalTokens != 2 {
+		t.Errorf("expected envelope fields decoded, got %+v", resp)
+	}
+	if got := (EmbeddingData{Embedding: []float64{0.5, 2}}).Vector32(); len(got) != 2 || got[1] != 2 {
+		t.Errorf("expected Vector32 to convert Embedding, got %v", got)
+	}
+}
+
+func TestCreateEmbeddingBase64(t *testing.T) {
+	want := []float32{0.5, -1.25, 3}
+	raw := make([]byte, 4*len(want))
+	for i, v := range want {
+		binary.LittleEndian.PutUint32(raw[4*i:], math.Float32bits(v))
+	}
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req EmbeddingRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.EncodingFormat != EmbeddingEncodingBase64 {
+			t.Errorf("expected encoding_format base64, got %q", req.EncodingFormat)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"object":"list","data":[{"object":"embedding","index":0,"embedding":%q}]}`, base64.StdEncoding.EncodeToString(raw))
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	req := EmbeddingRequest{Input: "x", Model: ModelEmbedding, EncodingFormat: EmbeddingEncodingBase64}
+	resp, err := client.CreateEmbedding(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resp.Data[0].Embedding; len(got) != 3 || got[0] != 0.5 || got[1] != -1.25 || got[2] != 3 {
+		t.Errorf("unexpected float64 vector %v", got)
+	}
+
+	req.Float32 = true
+	resp, err = client.CreateEmbedding(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resp.Data[0]; got.Embedding != nil || len(got.Embedding32) != 3 || got.Embedding32[1] != -1.25 {
+		t.Errorf("unexpected float32 vector %+v", got)
+	}
+}
+
+func TestGetEmbeddingInfo(t *testing.T) {
+	expected := EmbeddingModelInfo{Model: ModelEmbedding, Dimensions: 1536, SupportedDimensions: []int{1024, 768}, MaxInputTokens: 8192}
+	srv := newTestServer(t, http.MethodGet, "/v1/embeddings/models/"+ModelEmbedding, http.StatusOK, expected)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	info, err := client.GetEmbeddingInfo(context.Background(), ModelEmbedding)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Dimensions != 1536 || info.MaxInputTokens != 8192 {
+		t.Errorf("unexpected info: %+v", info)
+	}
+	if !info.SupportsDimensions(1536) || !info.SupportsDimensions(768) || info.SupportsDimensions(512) {
+		t.Error("expected only native and listed dimensions to be supported")
+	}
+	flexible := EmbeddingModelInfo{Dimensions: 1024, FlexibleDimensions: true}
+	if !flexible.SupportsDimensions(300) || flexible.SupportsDimensions(2048) || flexible.SupportsDimensions(0) {
+		t.Error("expected flexible model to support sizes up to its native dimensions")
+	}
+}
+
+func TestCreateEmbeddingRejectsUnsupportedDimensions(t *testing.T) {
+	var infoCalls, embedCalls int
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/embeddings/models/" + ModelEmbedding:
+			infoCalls++
+			json.NewEncoder(w).Encode(EmbeddingModelInfo{Model: ModelEmbedding, Dimensions: 1536, SupportedDimensions: []int{768}})
+		case "/v1/embeddings/models/legacy-embedding":
+			infoCalls++
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"message":"not found","type":"not_found"}}`))
+		default:
+			embedCalls++
+			json.NewEncoder(w).Encode(EmbeddingResponse{Object: "list"})
+		}
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	var vErr *ValidationError
+	for i := 0; i < 2; i++ {
+		_, err := client.CreateEmbedding(context.Background(), EmbeddingRequest{Input: "x", Model: ModelEmbedding, Dimensions: IntPtr(384)})
+		if !errors.As(err, &vErr) || vErr.Field != "dimensions" {
+			t.Fatalf("expected dimensions validation error, got %v", err)
+		}
+	}
+	if _, err := client.ReembedAll(context.Background(), ReembedRequest{NewModel: ModelEmbedding, Dimensions: IntPtr(384)}); !errors.As(err, &vErr) {
+		t.Errorf("expected ReembedAll to validate dimensions, got %v", err)
+	}
+	if infoCalls != 1 || embedCalls != 0 {
+		t.Errorf("expected one cached info lookup and no embedding calls, got %d / %d", infoCalls, embedCalls)
+	}
+
+	// Without model info the server decides.
+	for i := 0; i < 2; i++ {
+		if _, err := client.CreateEmbedding(context.Background(), EmbeddingRequest{Input: "x", Model: "legacy-embedding", Dimensions: IntPtr(384)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if infoCalls != 2 || embedCalls != 2 {
+		t.Errorf("expected the missing info to be cached, got %d / %d", infoCalls, embedCalls)
+	}
+}
+
+func TestCreateEmbeddingWithOptions(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-User-ID") != "user-42" {
+			t.Errorf("expected X-User-ID user-42, got %q", r.Header.Get("X-User-ID"))
+		}
+		var req EmbeddingRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.User != "user-42" {
+			t.Errorf("expected user user-42, got %q", req.User)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(EmbeddingResponse{Object: "list"})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	_, err := client.CreateEmbeddingWithOptions(context.Background(), EmbeddingRequest{
+		Input: "Hello",
+		Model: ModelEmbedding,
+		User:  "user-42",
+	}, RequestOptions{UserID: "user-42"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// ─── Audio ──────────────────────────────────────────────────────────────────
+
+func TestTranscribeAudioVerbose(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/transcriptions" {
+			t.Errorf("expected path /v1/audio/transcriptions, got %s", r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("parse multipart: %v", err)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("form file: %v", err)
+		}
+		data, _ := io.ReadAll(file)
+		if string(data) != "ID3 audio" || header.Filename != "call.mp3" {
+			t.Errorf("unexpected file %q / %q", header.Filename, data)
+		}
+		if r.FormValue("language") != "de" || r.FormValue("response_format") != "verbose_json" || r.FormValue("temperature") != "0.2" {
+			t.Errorf("unexpected fields: %v", r.MultipartForm.Value)
+		}
+		if g := r.MultipartForm.Value["timestamp_granularities[]"]; len(g) != 2 || g[0] != "segment" || g[1] != "word" {
+			t.Errorf("unexpected timestamp granularities %v", g)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"task":"transcribe","language":"german","duration":2.5,"text":"Hallo Welt",
+			"segments":[{"id":0,"start":0,"end":2.5,"text":"Hallo Welt","no_speech_prob":0.01}],
+			"words":[{"word":"Hallo","start":0,"end":0.8},{"word":"Welt","start":0.9,"end":1.6}]}`)
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	resp, err := client.TranscribeAudio(context.Background(), AudioRequest{
+		File:                   strings.NewReader("ID3 audio"),
+		Filename:               "call.mp3",
+		Language:               "de",
+		ResponseFormat:         AudioFormatVerboseJSON,
+		Temperature:            Float64Ptr(0.2),
+		TimestampGranularities: []string{TimestampSegment, TimestampWord},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Text != "Hallo Welt" || resp.Duration != 2.5 || len(resp.Segments) != 1 || resp.Segments[0].End != 2.5 {
+		t.Errorf("unexpected transcription: %+v", resp)
+	}
+	if len(resp.Words) != 2 || resp.Words[1].Word != "Welt" || resp.Words[1].Start != 0.9 {
+		t.Errorf("unexpected words: %+v", resp.Words)
+	}
+}
+
+func TestTranslateAudioText(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/translations" {
+			t.Errorf("expected path /v1/audio/translations, got %s", r.URL.Path)
+		}
+		if r.FormValue("response_format") != "srt" {
+			t.Errorf("expected response_format srt, got %q", r.FormValue("response_format"))
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "1\n00:00:00,000 --> 00:00:02,500\nHello world\n")
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	resp, err := client.TranslateAudio(context.Background(), AudioRequest{
+		File:           strings.NewReader("ID3 audio"),
+		Filename:       "call.mp3",
+		ResponseFormat: AudioFormatSRT,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(resp.Text, "Hello world") {
+		t.Errorf("expected subtitle text, got %q", resp.Text)
+	}
+}
+
+func TestAudioRequestValidation(t *testing.T) {
+	client := NewClient("http://unused", "test-key")
+	audio := func() io.Reader { return strings.NewReader("ID3") }
+	cases := map[string]struct {
+		translate bool
+		req       AudioRequest
+	}{
+		"file":                    {req: AudioRequest{Filename: "a.mp3"}},
+		"timestamp_granularities": {req: AudioRequest{File: audio(), TimestampGranularities: []string{TimestampWord}}},
+		"temperature":             {req: AudioRequest{File: audio(), Temperature: Float64Ptr(1.5)}},
+		"language":                {translate: true, req: AudioRequest{File: audio(), Language: "de"}},
+	}
+	for field, tc := range cases {
+		send := client.TranscribeAudio
+		if tc.translate {
+			send = client.TranslateAudio
+		}
+		_, err := send(context.Background(), tc.req)
+		var vErr *ValidationError
+		if !errors.As(err, &vErr) || vErr.Field != field {
+			t.Errorf("%s: expected ValidationError, got %v", field, err)
+		}
+	}
+}
+
+func TestCreateSpeechStreams(t *testing.T) {
+	firstRead := make(chan struct{})
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/speech" {
+			t.Errorf("expected path /v1/audio/speech, got %s", r.URL.Path)
+		}
+		var req SpeechRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Input != "Ticket resolved." || req.Voice != "alloy" || req.ResponseFormat != SpeechFormatOpus || *req.Speed != 1.25 {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		w.Header().Set("Content-Type", "audio/ogg")
+		w.Write([]byte("OggS-part1"))
+		w.(http.Flusher).Flush()
+		<-firstRead
+		w.Write([]byte("-part2"))
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	audio, err := client.CreateSpeech(context.Background(), SpeechRequest{
+		Input:          "Ticket resolved.",
+		Voice:          "alloy",
+		ResponseFormat: SpeechFormatOpus,
+		Speed:          Float64Ptr(1.25),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer audio.Close()
+
+	// The first bytes arrive while the server is still producing audio.
+	buf := make([]byte, len("OggS-part1"))
+	if _, err := io.ReadFull(audio, buf); err != nil || string(buf) != "OggS-part1" {
+		t.Fatalf("expected first chunk, got %q, %v", buf, err)
+	}
+	close(firstRead)
+	rest, _ := io.ReadAll(audio)
+	if string(rest) != "-part2" {
+		t.Errorf("unexpected remaining audio %q", rest)
+	}
+}
+
+func TestCreateSpeechErrors(t *testing.T) {
+	srv := newTestServer(t, http.MethodPost, "/v1/audio/speech", http.StatusBadRequest,
+		ErrorResponse{Error: ErrorDetail{Message: "unknown voice", Type: "invalid_request_error"}})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	var apiErr *APIError
+	if _, err := client.CreateSpeech(context.Background(), SpeechRequest{Input: "hi", Voice: "robot"}); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected APIError, got %v", err)
+	}
+	var vErr *ValidationError
+	if _, err := client.CreateSpeech(context.Background(), SpeechRequest{Input: "hi", Voice: "alloy", Speed: Float64Ptr(5)}); !errors.As(err, &vErr) || vErr.Field != "speed" {
+		t.Errorf("expected speed ValidationError, got %v", err)
+	}
+	if _, err := client.CreateSpeech(context.Background(), SpeechRequest{Voice: "alloy"}); !errors.As(err, &vErr) || vErr.Field != "input" {
+		t.Errorf("expected input ValidationError, got %v", err)
+	}
+}
+
+// ─── Health ─────────────────────────────────────────────────────────────────
+
+func TestHealth(t *testing.T) {
+	expected := HealthResponse{Status: "ok", Version: "1.1.5"}
+
+	srv := newTestServer(t, http.MethodGet, "/health", http.StatusOK, expected)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	health, err := client.Health(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if health.Status != "ok" {
+		t.Errorf("expected status ok, got %q", health.Status)
+	}
+	if health.Version != "1.1.5" {
+		t.Errorf("expected version 1.1.5, got %q", health.Version)
+	}
+}
+
+func TestHealthDegraded(t *testing.T) {
+	expected := HealthResponse{Status: "degraded", Version: "1.1.5"}
+
+	srv := newTestServer(t, http.MethodGet, "/health", http.StatusServiceUnavailable, expected)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	health, err := client.Health(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if health.Status != "degraded" {
+		t.Errorf("expected status degraded, got %q", health.Status)
+	}
+}
+
+// ─── Ready ──────────────────────────────────────────────────────────────────
+
+func TestReady(t *testing.T) {
+	expected := ReadyResponse{
+		Ready:   true,
+		Version: "1.1.5",
+		Checks:  HealthChecks{{Name: "backend", Status: "ok"}, {Name: "database", Status: "ok", LatencyMs: 1.5}},
+	}
+
+	srv := newTestServer(t, http.MethodGet, "/ready", http.StatusOK, expected)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	ready, err := client.Ready(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ready.Ready {
+		t.Error("expected ready=true")
+	}
+	if db, ok := ready.Checks.Get("database"); !ok || !db.OK() || db.Latency() != 1500*time.Microsecond {
+		t.Errorf("unexpected database check: %+v", db)
+	}
+	if failed := ready.Failed(); len(failed) != 0 {
+		t.Errorf("expected no failed checks, got %+v", failed)
+	}
+}
+
+func TestReadyComponentDetails(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"ready":false,"version":"1.2.0","checks":{
+			"vector_store":{"status":"error","latency_ms":5002,"error":"connection refused"},
+			"database":{"status":"ok","latency_ms":3.2},
+			"backend":"ok"}}`))
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	ready, err := client.Ready(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ready.Checks) != 3 || ready.Checks[0].Name != "backend" || ready.Checks[2].Name != "vector_store" {
+		t.Fatalf("expected checks sorted by name, got %+v", ready.Checks)
+	}
+	failed := ready.Failed()
+	if len(failed) != 1 || failed[0].Name != "vector_store" || failed[0].Error != "connection refused" || failed[0].Latency() != 5002*time.Millisecond {
+		t.Errorf("unexpected failed checks: %+v", failed)
+	}
+	if backend, _ := ready.Checks.Get("backend"); !backend.OK() {
+		t.Errorf("expected legacy string status decoded, got %+v", backend)
+	}
+}
+
+func TestHealthComponentChecks(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"degraded","version":"1.2.0","checks":[{"name":"inference","status":"degraded","latency_ms":900}]}`))
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	health, err := client.Health(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	if models.Data[0].ID != "hackersera-ai" {
-		t.Errorf("expected model ID hackersera-ai, got %q", models.Data[0].ID)
+	if failed := health.Checks.Failed(); len(failed) != 1 || failed[0].Name != "inference" || failed[0].LatencyMs != 900 {
+		t.Errorf("unexpected health checks: %+v", health.Checks)
 	}
 }
 
-func TestGetModel(t *testing.T) {
-	expected := Model{ID: "hackersera-ai", Object: "model", OwnedBy: "hackersera"}
+// ─── Documents (RAG) ────────────────────────────────────────────────────────
 
-	srv := newTestServer(t, http.MethodGet, "/v1/models/", http.StatusOK, expected)
+func TestUploadDocument(t *testing.T) {
+	expected := DocumentResponse{
+		ID:       "doc-abc123",
+		Filename: "test.md",
+		Status:   "processing",
+		Tags:     map[string]string{"topic": "test"},
+	}
+
+	srv := newTestServer(t, http.MethodPost, "/v1/documents", http.StatusAccepted, expected)
 	defer srv.Close()
 
 	client := NewClient(srv.URL, "test-key")
-	model, err := client.GetModel(context.Background(), "hackersera-ai")
+	doc, err := client.UploadDocument(context.Background(), DocumentUploadRequest{
+		Content:  "Test content",
+		Filename: "test.md",
+		Tags:     map[string]string{"topic": "test"},
+	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if model.ID != "hackersera-ai" {
-		t.Errorf("expected model ID hackersera-ai, got %q", model.ID)
+	if doc.ID != "doc-abc123" {
+		t.Errorf("expected doc ID doc-abc123, got %q", doc.ID)
+	}
+	if doc.Status != "processing" {
+		t.Errorf("expected status processing, got %q", doc.Status)
 	}
 }
 
-// ─── Embeddings ─────────────────────────────────────────────────────────────
-
-func TestCreateEmbedding(t *testing.T) {
-	expected := EmbeddingResponse{
+func TestUploadDocuments(t *testing.T) {
+	expected := DocumentListResponse{
 		Object: "list",
-		Data: []EmbeddingData{
-			{Object: "embedding", Embedding: []float64{0.1, 0.2, 0.3}, Index: 0},
+		Data: []DocumentResponse{
+			{ID: "doc-1", Filename: "a.md", Status: "processing"},
+			{ID: "doc-2", Filename: "b.md", Status: "processing"},
 		},
-		Model: "text-embedding-ada-002",
-		Usage: EmbeddingUsage{PromptTokens: 2, TotalTokens: 2},
+		Total: 2,
 	}
 
-	srv := newTestServer(t, http.MethodPost, "/v1/embeddings", http.StatusOK, expected)
+	srv := newTestServer(t, http.MethodPost, "/v1/documents", http.StatusAccepted, expected)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	resp, err := client.UploadDocuments(context.Background(), []DocumentUploadRequest{
+		{Content: "Doc 1", Filename: "a.md"},
+		{Content: "Doc 2", Filename: "b.md"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Total != 2 {
+		t.Errorf("expected total 2, got %d", resp.Total)
+	}
+}
+
+func TestUploadDocumentWithCallback(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch DocumentBatchUploadRequest
+		json.NewDecoder(r.Body).Decode(&batch)
+		if len(batch.Documents) != 2 || batch.Documents[1].CallbackURL != "https://ingest.example.com/done" {
+			t.Errorf("expected callback_url on batch documents, got %+v", batch.Documents)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(DocumentListResponse{Object: "list", Total: 2})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	_, err := client.UploadDocuments(context.Background(), []DocumentUploadRequest{
+		{Content: "a", CallbackURL: "https://ingest.example.com/done"},
+		{Content: "b", CallbackURL: "https://ingest.example.com/done"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var event DocumentEvent
+	payload := `{"event":"document.failed","document":{"id":"doc-1","status":"failed","error":"unsupported encoding"},"occurred_at":"2026-01-01T00:00:00Z"}`
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		t.Fatalf("unmarshal event: %v", err)
+	}
+	if event.Event != DocumentEventFailed || event.Document.Error != "unsupported encoding" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
+
+func TestUploadDocumentFile(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/documents/upload" {
+			t.Errorf("expected path /v1/documents/upload, got %s", r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("parse multipart: %v", err)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("form file: %v", err)
+		}
+		data, _ := io.ReadAll(file)
+		if string(data) != "%PDF-1.4 scanned" {
+			t.Errorf("unexpected file content %q", data)
+		}
+		if header.Filename != "evidence.pdf" {
+			t.Errorf("expected filename evidence.pdf, got %q", header.Filename)
+		}
+		if r.FormValue("ocr") != "true" {
+			t.Errorf("expected ocr=true, got %q", r.FormValue("ocr"))
+		}
+		if r.FormValue("ocr_languages") != "eng,deu" {
+			t.Errorf("expected ocr_languages=eng,deu, got %q", r.FormValue("ocr_languages"))
+		}
+		if r.FormValue("tags") != `{"case":"ir-42"}` {
+			t.Errorf("unexpected tags %q", r.FormValue("tags"))
+		}
+		if r.FormValue("callback_url") != "https://ingest.example.com/done" {
+			t.Errorf("unexpected callback_url %q", r.FormValue("callback_url"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(DocumentResponse{ID: "doc-pdf", Filename: "evidence.pdf", Status: "processing"})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	doc, err := client.UploadDocumentFile(context.Background(), DocumentFileUploadRequest{
+		File:         strings.NewReader("%PDF-1.4 scanned"),
+		Filename:     "evidence.pdf",
+		Tags:         map[string]string{"case": "ir-42"},
+		OCR:          true,
+		OCRLanguages: []string{"eng", "deu"},
+		CallbackURL:  "https://ingest.example.com/done",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doc.ID != "doc-pdf" {
+		t.Errorf("expected doc ID doc-pdf, got %q", doc.ID)
+	}
+}
+
+func TestUploadDocumentFileRequiresReader(t *testing.T) {
+	client := NewClient("http://localhost", "test-key")
+	_, err := client.UploadDocumentFile(context.Background(), DocumentFileUploadRequest{Filename: "x.pdf"})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Field != "file" {
+		t.Fatalf("expected file ValidationError, got %v", err)
+	}
+}
+
+func TestListDocuments(t *testing.T) {
+	expected := DocumentListResponse{
+		Object: "list",
+		Data:   []DocumentResponse{{ID: "doc-1", Filename: "test.md", Status: "indexed"}},
+		Total:  1,
+	}
+
+	srv := newTestServer(t, http.MethodGet, "/v1/documents", http.StatusOK, expected)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	docs, err := client.ListDocuments(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if docs.Total != 1 {
+		t.Errorf("expected total 1, got %d", docs.Total)
+	}
+}
+
+func TestGetDocument(t *testing.T) {
+	expected := DocumentResponse{
+		ID:         "doc-abc",
+		Filename:   "test.md",
+		Status:     "indexed",
+		ChunkCount: 5,
+	}
+
+	srv := newTestServer(t, http.MethodGet, "/v1/documents/", http.StatusOK, expected)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	doc, err := client.GetDocument(context.Background(), "doc-abc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doc.ChunkCount != 5 {
+		t.Errorf("expected chunk count 5, got %d", doc.ChunkCount)
+	}
+}
+
+func TestGetDocumentsBatches(t *testing.T) {
+	var batches []int
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/documents/batch-get" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var req DocumentBatchGetRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		batches = append(batches, len(req.IDs))
+
+		var result DocumentBatchGetResponse
+		for _, id := range req.IDs {
+			if id == "doc-missing" {
+				result.NotFound = append(result.NotFound, id)
+				continue
+			}
+			result.Data = append(result.Data, DocumentResponse{ID: id, Status: "indexed"})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	})
+	defer srv.Close()
+
+	ids := make([]string, MaxGetDocumentsBatch+5)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("doc-%d", i)
+	}
+	ids[len(ids)-1] = "doc-missing"
+
+	client := NewClient(srv.URL, "test-key")
+	result, err := client.GetDocuments(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batches) != 2 || batches[0] != MaxGetDocumentsBatch || batches[1] != 5 {
+		t.Errorf("unexpected batch sizes %v", batches)
+	}
+	if len(result.Data) != len(ids)-1 {
+		t.Errorf("expected %d documents, got %d", len(ids)-1, len(result.Data))
+	}
+	if len(result.NotFound) != 1 || result.NotFound[0] != "doc-missing" {
+		t.Errorf("expected doc-missing not found, got %v", result.NotFound)
+	}
+}
+
+func TestGetDocumentsEmpty(t *testing.T) {
+	client := NewClient("http://127.0.0.1:0", "test-key")
+	result, err := client.GetDocuments(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Data) != 0 || len(result.NotFound) != 0 {
+		t.Errorf("expected empty result, got %+v", result)
+	}
+}
+
+func TestDeleteDocument(t *testing.T) {
+	expected := DocumentDeleteResponse{ID: "doc-abc", Deleted: true}
+
+	srv := newTestServer(t, http.MethodDelete, "/v1/documents/", http.StatusOK, expected)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	del, err := client.DeleteDocument(context.Background(), "doc-abc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !del.Deleted {
+		t.Error("expected deleted=true")
+	}
+}
+
+func TestSummarizeDocument(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if r.URL.Path != "/v1/documents/doc-abc/summarize" {
+			t.Errorf("expected path /v1/documents/doc-abc/summarize, got %s", r.URL.Path)
+		}
+		var opts SummaryOptions
+		json.NewDecoder(r.Body).Decode(&opts)
+		if opts.Length != SummaryShort || opts.Focus != "remediation" || !opts.Store {
+			t.Errorf("unexpected options: %+v", opts)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(DocumentSummary{
+			DocumentID: "doc-abc",
+			Summary:    "Patch the VPN appliance.",
+			Length:     SummaryShort,
+			Stored:     true,
+		})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	summary, err := client.SummarizeDocument(context.Background(), "doc-abc", SummaryOptions{
+		Length: SummaryShort,
+		Focus:  "remediation",
+		Store:  true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Summary != "Patch the VPN appliance." {
+		t.Errorf("unexpected summary %q", summary.Summary)
+	}
+	if !summary.Stored {
+		t.Error("expected stored=true")
+	}
+}
+
+func TestListDocumentTags(t *testing.T) {
+	expected := DocumentTagListResponse{Object: "list", Data: []DocumentTag{
+		{Key: "type", Value: "runbook", Count: 12},
+		{Key: "type", Value: "run-book", Count: 2},
+	}}
+	srv := newTestServer(t, http.MethodGet, "/v1/documents/tags", http.StatusOK, expected)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	tags, err := client.ListDocumentTags(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tags.Data) != 2 || tags.Data[0].Count != 12 {
+		t.Errorf("unexpected tags: %+v", tags.Data)
+	}
+}
+
+func TestRenameDocumentTag(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/documents/tags/rename" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		var req TagRenameRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Key != "team" || req.NewKey != "owner" {
+			t.Errorf("unexpected rename request: %+v", req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(TagUpdateResponse{Updated: 5})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	res, err := client.RenameDocumentTag(context.Background(), TagRenameRequest{Key: "team", NewKey: "owner"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Updated != 5 {
+		t.Errorf("expected 5 updated, got %d", res.Updated)
+	}
+
+	if _, err := client.RenameDocumentTag(context.Background(), TagRenameRequest{Key: "team"}); err == nil {
+		t.Error("expected error without a new key or value")
+	}
+}
+
+func TestMergeDocumentTags(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/documents/tags/merge" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var req TagMergeRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Into != "runbook" || len(req.Values) != 2 {
+			t.Errorf("unexpected merge request: %+v", req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(TagUpdateResponse{Updated: 3})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	res, err := client.MergeDocumentTags(context.Background(), TagMergeRequest{
+		Key: "type", Values: []string{"run-book", "Runbook"}, Into: "runbook",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Updated != 3 {
+		t.Errorf("expected 3 updated, got %d", res.Updated)
+	}
+
+	if _, err := client.MergeDocumentTags(context.Background(), TagMergeRequest{Key: "type", Into: "runbook"}); err == nil {
+		t.Error("expected error without values")
+	}
+}
+
+func TestExportKnowledgeBase(t *testing.T) {
+	archive := `{"type":"manifest","version":1,"exported_at":"2026-01-01T00:00:00Z"}
+{"type":"document","document":{"id":"doc-1","filename":"a.md","status":"indexed","chunk_count":1,"created_at":""}}
+{"type":"chunk","chunk_id":"chunk-1","document_id":"doc-1","chunk_index":0,"content":"hello","embedding":[0.1,0.2]}
+`
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/knowledge/export" {
+			t.Errorf("expected path /v1/knowledge/export, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("include_embeddings") != "true" {
+			t.Errorf("expected include_embeddings=true, got %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		fmt.Fprint(w, archive)
+	})
 	defer srv.Close()
 
 	client := NewClient(srv.URL, "test-key")
-	resp, err := client.CreateEmbedding(context.Background(), EmbeddingRequest{
-		Input: "Hello world",
-		Model: ModelEmbedding,
-	})
+	var buf strings.Builder
+	n, err := client.ExportKnowledgeBase(context.Background(), &buf, ExportOptions{IncludeEmbeddings: true})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(resp.Data) != 1 {
-		t.Fatalf("expected 1 embedding, got %d", len(resp.Data))
+	if n != int64(len(archive)) || buf.String() != archive {
+		t.Errorf("expected archive to be copied verbatim, got %d bytes", n)
 	}
-	if len(resp.Data[0].Embedding) != 3 {
-		t.Errorf("expected 3 dimensions, got %d", len(resp.Data[0].Embedding))
+
+	var types []string
+	var chunk ExportRecord
+	err = ReadKnowledgeBaseExport(strings.NewReader(buf.String()), func(rec ExportRecord) error {
+		types = append(types, rec.Type)
+		if rec.Type == ExportRecordChunk {
+			chunk = rec
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if strings.Join(types, ",") != "manifest,document,chunk" {
+		t.Errorf("unexpected record order: %v", types)
+	}
+	if chunk.DocumentID != "doc-1" || len(chunk.Embedding) != 2 {
+		t.Errorf("unexpected chunk record: %+v", chunk)
 	}
 }
 
-func TestCreateEmbeddingWithDimensions(t *testing.T) {
+func TestCreateSnapshot(t *testing.T) {
 	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
-		body, _ := io.ReadAll(r.Body)
-		var raw map[string]interface{}
-		json.Unmarshal(body, &raw)
-
-		if raw["dimensions"] == nil {
-			t.Error("expected dimensions field in request")
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/knowledge/snapshots" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
 		}
-		if int(raw["dimensions"].(float64)) != 768 {
-			t.Errorf("expected dimensions 768, got %v", raw["dimensions"])
+		var req SnapshotCreateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Name != "pre-ingest" {
+			t.Errorf("expected name pre-ingest, got %q", req.Name)
 		}
-
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(EmbeddingResponse{
-			Object: "list",
-			Data:   []EmbeddingData{{Embedding: make([]float64, 768)}},
-		})
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(KnowledgeSnapshot{ID: "snap-1", Name: req.Name, Status: "creating"})
 	})
 	defer srv.Close()
 
 	client := NewClient(srv.URL, "test-key")
-	_, err := client.CreateEmbedding(context.Background(), EmbeddingRequest{
-		Input:      []string{"Hello", "World"},
-		Model:      ModelEmbedding,
-		Dimensions: IntPtr(768),
-	})
+	snap, err := client.CreateSnapshot(context.Background(), "pre-ingest")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if snap.ID != "snap-1" || snap.Status != "creating" {
+		t.Errorf("unexpected snapshot: %+v", snap)
+	}
 }
 
-// ─── Health ─────────────────────────────────────────────────────────────────
-
-func TestHealth(t *testing.T) {
-	expected := HealthResponse{Status: "ok", Version: "1.1.5"}
-
-	srv := newTestServer(t, http.MethodGet, "/health", http.StatusOK, expected)
+func TestListSnapshots(t *testing.T) {
+	expected := SnapshotListResponse{
+		Object: "list",
+		Data:   []KnowledgeSnapshot{{ID: "snap-1", Name: "pre-ingest", Status: "ready", DocumentCount: 120, FactCount: 40}},
+		Total:  1,
+	}
+	srv := newTestServer(t, http.MethodGet, "/v1/knowledge/snapshots", http.StatusOK, expected)
 	defer srv.Close()
 
 	client := NewClient(srv.URL, "test-key")
-	health, err := client.Health(context.Background())
+	snaps, err := client.ListSnapshots(context.Background())
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if health.Status != "ok" {
-		t.Errorf("expected status ok, got %q", health.Status)
-	}
-	if health.Version != "1.1.5" {
-		t.Errorf("expected version 1.1.5, got %q", health.Version)
+	if snaps.Total != 1 || snaps.Data[0].DocumentCount != 120 {
+		t.Errorf("unexpected snapshots: %+v", snaps)
 	}
 }
 
-func TestHealthDegraded(t *testing.T) {
-	expected := HealthResponse{Status: "degraded", Version: "1.1.5"}
-
-	srv := newTestServer(t, http.MethodGet, "/health", http.StatusServiceUnavailable, expected)
+func TestRestoreSnapshot(t *testing.T) {
+	srv := newTestServer(t, http.MethodPost, "/v1/knowledge/snapshots/snap-1/restore", http.StatusAccepted,
+		SnapshotRestoreResponse{SnapshotID: "snap-1", Status: "restoring"})
 	defer srv.Close()
 
 	client := NewClient(srv.URL, "test-key")
-	health, err := client.Health(context.Background())
+	restore, err := client.RestoreSnapshot(context.Background(), "snap-1")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if health.Status != "degraded" {
-		t.Errorf("expected status degraded, got %q", health.Status)
+	if restore.Status != "restoring" {
+		t.Errorf("expected status restoring, got %q", restore.Status)
 	}
 }
 
-// ─── Ready ──────────────────────────────────────────────────────────────────
+func TestReembedAll(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/knowledge/reembed" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var req ReembedRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.NewModel != "hackersera-ai-embedding-v2" || req.BatchSize != 256 {
+			t.Errorf("unexpected reembed request: %+v", req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(ReembedJob{ID: "job-1", Status: JobStatusQueued, NewModel: req.NewModel, TotalChunks: 4000})
+	})
+	defer srv.Close()
 
-func TestReady(t *testing.T) {
-	expected := ReadyResponse{
-		Ready:   true,
-		Version: "1.1.5",
-		Checks:  map[string]string{"backend": "ok", "database": "ok"},
+	client := NewClient(srv.URL, "test-key")
+	job, err := client.ReembedAll(context.Background(), ReembedRequest{NewModel: "hackersera-ai-embedding-v2", BatchSize: 256})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
+	if job.ID != "job-1" || job.Done() || job.Progress() != 0 {
+		t.Errorf("unexpected job: %+v", job)
+	}
+}
 
-	srv := newTestServer(t, http.MethodGet, "/ready", http.StatusOK, expected)
+func TestWaitForReembedJob(t *testing.T) {
+	polls := 0
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/knowledge/reembed/job-1" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		polls++
+		job := ReembedJob{ID: "job-1", Status: JobStatusRunning, TotalChunks: 4, ProcessedChunks: polls * 2}
+		if job.ProcessedChunks >= 4 {
+			job.Status = JobStatusCompleted
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(job)
+	})
 	defer srv.Close()
 
 	client := NewClient(srv.URL, "test-key")
-	ready, err := client.Ready(context.Background())
+	var progress []float64
+	job, err := client.WaitForReembedJob(context.Background(), "job-1", time.Millisecond, func(j *ReembedJob) {
+		progress = append(progress, j.Progress())
+	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !ready.Ready {
-		t.Error("expected ready=true")
+	if job.Status != JobStatusCompleted {
+		t.Errorf("expected completed job, got %q", job.Status)
 	}
-	if ready.Checks["backend"] != "ok" {
-		t.Errorf("expected backend=ok, got %q", ready.Checks["backend"])
+	if len(progress) != 2 || progress[0] != 0.5 || progress[1] != 1 {
+		t.Errorf("unexpected progress sequence: %v", progress)
 	}
 }
 
-// ─── Documents (RAG) ────────────────────────────────────────────────────────
+func TestWaitForReembedJobFailed(t *testing.T) {
+	srv := newTestServer(t, http.MethodGet, "/v1/knowledge/reembed/job-2", http.StatusOK,
+		ReembedJob{ID: "job-2", Status: JobStatusFailed, Error: "dimension mismatch"})
+	defer srv.Close()
 
-func TestUploadDocument(t *testing.T) {
-	expected := DocumentResponse{
-		ID:       "doc-abc123",
-		Filename: "test.md",
-		Status:   "processing",
-		Tags:     map[string]string{"topic": "test"},
+	client := NewClient(srv.URL, "test-key")
+	_, err := client.WaitForReembedJob(context.Background(), "job-2", time.Millisecond, nil)
+	if err == nil || !strings.Contains(err.Error(), "dimension mismatch") {
+		t.Errorf("expected failure error, got %v", err)
 	}
+}
 
-	srv := newTestServer(t, http.MethodPost, "/v1/documents", http.StatusAccepted, expected)
+// ─── Files ──────────────────────────────────────────────────────────────────
+
+func TestUploadFile(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/files" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("form file: %v", err)
+		}
+		data, _ := io.ReadAll(file)
+		if string(data) != `{"custom_id":"1"}` || header.Filename != "batch.jsonl" || r.FormValue("purpose") != FilePurposeBatch {
+			t.Errorf("unexpected upload %q %q purpose=%q", header.Filename, data, r.FormValue("purpose"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(FileObject{ID: "file-1", Object: "file", Bytes: int64(len(data)), Filename: header.Filename, Purpose: FilePurposeBatch})
+	})
 	defer srv.Close()
 
 	client := NewClient(srv.URL, "test-key")
-	doc, err := client.UploadDocument(context.Background(), DocumentUploadRequest{
-		Content:  "Test content",
-		Filename: "test.md",
-		Tags:     map[string]string{"topic": "test"},
+	file, err := client.UploadFile(context.Background(), FileUploadRequest{
+		File:     strings.NewReader(`{"custom_id":"1"}`),
+		Filename: "batch.jsonl",
+		Purpose:  FilePurposeBatch,
 	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if doc.ID != "doc-abc123" {
-		t.Errorf("expected doc ID doc-abc123, got %q", doc.ID)
+	if file.ID != "file-1" || file.Bytes != 17 {
+		t.Errorf("unexpected file: %+v", file)
 	}
-	if doc.Status != "processing" {
-		t.Errorf("expected status processing, got %q", doc.Status)
+	if _, err := client.UploadFile(context.Background(), FileUploadRequest{File: strings.NewReader("x")}); err == nil {
+		t.Error("expected error for missing purpose")
 	}
 }
 
-func TestUploadDocuments(t *testing.T) {
-	expected := DocumentListResponse{
-		Object: "list",
-		Data: []DocumentResponse{
-			{ID: "doc-1", Filename: "a.md", Status: "processing"},
-			{ID: "doc-2", Filename: "b.md", Status: "processing"},
-		},
-		Total: 2,
+func TestUploadFileStreamsReaderErrors(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	file := io.MultiReader(strings.NewReader(`{"custom_id":"1"}`), iotest.ErrReader(errors.New("disk read failed")))
+	_, err := client.UploadFile(context.Background(), FileUploadRequest{File: file, Filename: "batch.jsonl", Purpose: FilePurposeBatch})
+	if err == nil || !strings.Contains(err.Error(), "disk read failed") {
+		t.Fatalf("expected the reader error, got %v", err)
 	}
+}
 
-	srv := newTestServer(t, http.MethodPost, "/v1/documents", http.StatusAccepted, expected)
+func TestListFiles(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/files" || r.URL.Query().Get("purpose") != FilePurposeFineTune {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(FileListResponse{Object: "list", Data: []FileObject{{ID: "file-2", Purpose: FilePurposeFineTune}}})
+	})
 	defer srv.Close()
 
 	client := NewClient(srv.URL, "test-key")
-	resp, err := client.UploadDocuments(context.Background(), []DocumentUploadRequest{
-		{Content: "Doc 1", Filename: "a.md"},
-		{Content: "Doc 2", Filename: "b.md"},
-	})
+	list, err := client.ListFiles(context.Background(), FilePurposeFineTune)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if resp.Total != 2 {
-		t.Errorf("expected total 2, got %d", resp.Total)
+	if len(list.Data) != 1 || list.Data[0].ID != "file-2" {
+		t.Errorf("unexpected list: %+v", list)
 	}
 }
 
-func TestListDocuments(t *testing.T) {
-	expected := DocumentListResponse{
-		Object: "list",
-		Data:   []DocumentResponse{{ID: "doc-1", Filename: "test.md", Status: "indexed"}},
-		Total:  1,
-	}
-
-	srv := newTestServer(t, http.MethodGet, "/v1/documents", http.StatusOK, expected)
+func TestGetFile(t *testing.T) {
+	srv := newTestServer(t, http.MethodGet, "/v1/files/file-1", http.StatusOK, FileObject{ID: "file-1", Filename: "batch.jsonl", Status: "processed"})
 	defer srv.Close()
 
 	client := NewClient(srv.URL, "test-key")
-	docs, err := client.ListDocuments(context.Background())
+	file, err := client.GetFile(context.Background(), "file-1")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if docs.Total != 1 {
-		t.Errorf("expected total 1, got %d", docs.Total)
+	if file.Status != "processed" {
+		t.Errorf("unexpected file: %+v", file)
 	}
 }
 
-func TestGetDocument(t *testing.T) {
-	expected := DocumentResponse{
-		ID:         "doc-abc",
-		Filename:   "test.md",
-		Status:     "indexed",
-		ChunkCount: 5,
-	}
-
-	srv := newTestServer(t, http.MethodGet, "/v1/documents/", http.StatusOK, expected)
+func TestGetFileContent(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/files/file-1/content" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		fmt.Fprint(w, "line one\nline two\n")
+	})
 	defer srv.Close()
 
 	client := NewClient(srv.URL, "test-key")
-	doc, err := client.GetDocument(context.Background(), "doc-abc")
+	content, err := client.GetFileContent(context.Background(), "file-1")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if doc.ChunkCount != 5 {
-		t.Errorf("expected chunk count 5, got %d", doc.ChunkCount)
+	defer content.Close()
+	data, _ := io.ReadAll(content)
+	if string(data) != "line one\nline two\n" {
+		t.Errorf("unexpected content %q", data)
 	}
 }
 
-func TestDeleteDocument(t *testing.T) {
-	expected := DocumentDeleteResponse{ID: "doc-abc", Deleted: true}
-
-	srv := newTestServer(t, http.MethodDelete, "/v1/documents/", http.StatusOK, expected)
+func TestDeleteFile(t *testing.T) {
+	srv := newTestServer(t, http.MethodDelete, "/v1/files/file-1", http.StatusOK, FileDeleteResponse{ID: "file-1", Object: "file", Deleted: true})
 	defer srv.Close()
 
 	client := NewClient(srv.URL, "test-key")
-	del, err := client.DeleteDocument(context.Background(), "doc-abc")
+	resp, err := client.DeleteFile(context.Background(), "file-1")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !del.Deleted {
-		t.Error("expected deleted=true")
+	if !resp.Deleted {
+		t.Error("expected file to be deleted")
 	}
 }
 
@@ -752,6 +2544,86 @@ func TestSearch(t *testing.T) {
 	}
 }
 
+// ─── Language ───────────────────────────────────────────────────────────────
+
+func TestDetectLanguage(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/language/detect" {
+			t.Errorf("expected path /v1/language/detect, got %s", r.URL.Path)
+		}
+		var req LanguageDetectRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Text != "Guten Tag" {
+			t.Errorf("expected text %q, got %q", "Guten Tag", req.Text)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(LanguageDetectResponse{Language: "de", Confidence: 0.98})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	resp, err := client.DetectLanguage(context.Background(), "Guten Tag")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Language != "de" {
+		t.Errorf("expected language de, got %q", resp.Language)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/translate" {
+			t.Errorf("expected path /v1/translate, got %s", r.URL.Path)
+		}
+		var req TranslateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.TargetLang != "en" {
+			t.Errorf("expected target_lang en, got %q", req.TargetLang)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(TranslateResponse{Text: "Good day", SourceLang: "de", TargetLang: "en"})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	resp, err := client.Translate(context.Background(), TranslateRequest{Text: "Guten Tag", TargetLang: "en"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Text != "Good day" {
+		t.Errorf("expected translation %q, got %q", "Good day", resp.Text)
+	}
+	if resp.SourceLang != "de" {
+		t.Errorf("expected source_lang de, got %q", resp.SourceLang)
+	}
+}
+
+func TestTranslateContextHeader(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Translate-Context") != "true" {
+			t.Errorf("expected X-Translate-Context=true, got %q", r.Header.Get("X-Translate-Context"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-tr"})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	_, err := client.ChatCompletionWithOptions(context.Background(), ChatRequest{
+		Model:    ModelDefault,
+		Messages: []Message{{Role: "user", Content: "test"}},
+	}, RequestOptions{TranslateContext: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client = NewClient(srv.URL, "test-key").SetTranslateContext(true)
+	if _, err := client.ChatCompletion(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 // ─── Conversations ──────────────────────────────────────────────────────────
 
 func TestListConversations(t *testing.T) {
@@ -765,27 +2637,84 @@ func TestListConversations(t *testing.T) {
 	}
 
 	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			t.Errorf("expected GET, got %s", r.Method)
-		}
-		if r.URL.Query().Get("limit") != "10" {
-			t.Errorf("expected limit=10, got %q", r.URL.Query().Get("limit"))
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		if r.URL.Query().Get("limit") != "10" {
+			t.Errorf("expected limit=10, got %q", r.URL.Query().Get("limit"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(expected)
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	convos, err := client.ListConversations(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if convos.Total != 2 {
+		t.Errorf("expected total 2, got %d", convos.Total)
+	}
+	if convos.Data[0].Title != "Docker question" {
+		t.Errorf("expected title %q, got %q", "Docker question", convos.Data[0].Title)
+	}
+}
+
+func TestListConversationsByMetadata(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("metadata[ticket_id]") != "SUP-42" || q.Get("metadata[channel]") != "slack" || q.Get("limit") != "5" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ConversationListResponse{Data: []Conversation{
+			{ID: "conv-1", Metadata: map[string]string{"ticket_id": "SUP-42", "channel": "slack"}},
+		}, Total: 1})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	convos, err := client.ListConversationsWithOptions(context.Background(), ConversationListOptions{
+		Limit:    5,
+		Metadata: map[string]string{"ticket_id": "SUP-42", "channel": "slack"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(convos.Data) != 1 || convos.Data[0].Metadata["ticket_id"] != "SUP-42" {
+		t.Errorf("unexpected conversations: %+v", convos.Data)
+	}
+}
+
+func TestChatRequestMetadata(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Metadata["ticket_id"] != "SUP-42" {
+			t.Errorf("expected metadata in request, got %v", req.Metadata)
 		}
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(expected)
+		json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-1"})
 	})
 	defer srv.Close()
 
 	client := NewClient(srv.URL, "test-key")
-	convos, err := client.ListConversations(context.Background(), 10)
-	if err != nil {
+	req := ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}, Metadata: map[string]string{"ticket_id": "SUP-42"}}
+	if _, err := client.ChatCompletion(context.Background(), req); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if convos.Total != 2 {
-		t.Errorf("expected total 2, got %d", convos.Total)
+
+	req.Metadata = map[string]string{"note": strings.Repeat("x", MaxMetadataValueLength+1)}
+	if _, err := client.ChatCompletion(context.Background(), req); err == nil || !strings.Contains(err.Error(), "metadata value") {
+		t.Errorf("expected metadata value length error, got %v", err)
 	}
-	if convos.Data[0].Title != "Docker question" {
-		t.Errorf("expected title %q, got %q", "Docker question", convos.Data[0].Title)
+	req.Metadata = map[string]string{}
+	for i := 0; i <= MaxMetadataPairs; i++ {
+		req.Metadata[fmt.Sprintf("k%d", i)] = "v"
+	}
+	if _, err := client.ChatCompletion(context.Background(), req); err == nil || !strings.Contains(err.Error(), "metadata pairs") {
+		t.Errorf("expected metadata pair count error, got %v", err)
 	}
 }
 
@@ -884,6 +2813,36 @@ func TestDeleteConversation(t *testing.T) {
 	}
 }
 
+func TestAppendConversationTurn(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/conversations/conv-1/turns" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		var req ConversationTurnRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Role != "tool" || req.Metadata["tool_name"] != "whois" {
+			t.Errorf("unexpected turn request: %+v", req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(ConversationTurn{ID: 9, Role: req.Role, Content: req.Content, Metadata: req.Metadata})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	turn, err := client.AppendConversationTurn(context.Background(), "conv-1", ConversationTurnRequest{
+		Role:     "tool",
+		Content:  "whois(example.com)",
+		Metadata: map[string]string{"tool_name": "whois"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if turn.ID != 9 || turn.Metadata["tool_name"] != "whois" {
+		t.Errorf("unexpected turn: %+v", turn)
+	}
+}
+
 // ─── Feedback ───────────────────────────────────────────────────────────────
 
 func TestSubmitFeedback(t *testing.T) {
@@ -1049,6 +3008,83 @@ func TestUpdateProfile(t *testing.T) {
 	}
 }
 
+// ─── User Memories ──────────────────────────────────────────────────────────
+
+func TestRememberForUser(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/memories" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("X-User-ID") != "alice" {
+			t.Errorf("expected X-User-ID alice, got %q", r.Header.Get("X-User-ID"))
+		}
+		var req UserMemoryRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(UserMemory{ID: "mem-1", UserID: "alice", Content: req.Content})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").SetUserID("service-account")
+	memory, err := client.RememberForUser(context.Background(), "alice", UserMemoryRequest{Content: "Prefers Terraform over Pulumi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if memory.ID != "mem-1" || memory.Content != "Prefers Terraform over Pulumi" {
+		t.Errorf("unexpected memory: %+v", memory)
+	}
+}
+
+func TestListUserMemories(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-User-ID") != "alice" {
+			t.Errorf("expected X-User-ID alice, got %q", r.Header.Get("X-User-ID"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(UserMemoryListResponse{Object: "list", Data: []UserMemory{{ID: "mem-1", UserID: "alice"}}, Total: 1})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	list, err := client.ListUserMemories(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.Total != 1 || list.Data[0].ID != "mem-1" {
+		t.Errorf("unexpected list: %+v", list)
+	}
+}
+
+func TestForgetUserMemory(t *testing.T) {
+	srv := newTestServer(t, http.MethodDelete, "/v1/memories/mem-1", http.StatusOK, UserMemoryDeleteResponse{ID: "mem-1", Deleted: true})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	resp, err := client.ForgetUserMemory(context.Background(), "alice", "mem-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Deleted {
+		t.Error("expected memory to be deleted")
+	}
+}
+
+func TestUserMemoriesRequireUserID(t *testing.T) {
+	client := NewClient("http://unused", "test-key").SetUserID("service-account")
+	ctx := context.Background()
+
+	_, remember := client.RememberForUser(ctx, "", UserMemoryRequest{Content: "x"})
+	_, list := client.ListUserMemories(ctx, "")
+	_, forget := client.ForgetUserMemory(ctx, "", "mem-1")
+	for _, err := range []error{remember, list, forget} {
+		var ve *ValidationError
+		if !errors.As(err, &ve) || ve.Field != "user_id" {
+			t.Errorf("expected a user_id validation error, got %v", err)
+		}
+	}
+}
+
 // ─── Knowledge Graph ────────────────────────────────────────────────────────
 
 func TestQueryKnowledgeGraph(t *testing.T) {
@@ -1093,6 +3129,115 @@ func TestQueryKnowledgeGraph(t *testing.T) {
 	}
 }
 
+func TestQueryKnowledgeGraphAsOf(t *testing.T) {
+	asOf := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("query") != "zero trust" {
+			t.Errorf("expected escaped query to round-trip, got %q", q.Get("query"))
+		}
+		if q.Get("as_of") != "2026-07-01T00:00:00Z" {
+			t.Errorf("expected as_of 2026-07-01T00:00:00Z, got %q", q.Get("as_of"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(KnowledgeGraphResponse{Object: "list", AsOf: q.Get("as_of")})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	graph, err := client.QueryKnowledgeGraphAsOf(context.Background(), "zero trust", 0, asOf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if graph.AsOf != "2026-07-01T00:00:00Z" {
+		t.Errorf("expected AsOf in response, got %q", graph.AsOf)
+	}
+}
+
+func TestGetKnowledgeGraphStats(t *testing.T) {
+	var queries []string
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/knowledge/graph/stats" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		queries = append(queries, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"total_nodes":120,"total_edges":300,
+			"nodes_by_type":{"concept":100,"product":20},"edges_by_relation":{"related_to":300},
+			"top_concepts":[{"id":"n1","label":"vpn","type":"concept","hits":42}],
+			"new_concepts":[{"id":"n9","label":"passkeys","type":"concept","hits":5,"first_seen_at":"2026-10-12T08:00:00Z"}]}`)
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	stats, err := client.GetKnowledgeGraphStats(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.TotalNodes != 120 || stats.NodesByType["product"] != 20 || stats.EdgesByRelation["related_to"] != 300 {
+		t.Errorf("unexpected counts: %+v", stats)
+	}
+	if len(stats.TopConcepts) != 1 || stats.TopConcepts[0].Hits != 42 || stats.NewConcepts[0].Label != "passkeys" {
+		t.Errorf("unexpected concepts: %+v / %+v", stats.TopConcepts, stats.NewConcepts)
+	}
+
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := client.GetKnowledgeGraphStatsWithOptions(context.Background(), KnowledgeGraphStatsOptions{Range: TimeRange{From: from}, TopN: 10}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if queries[0] != "" || queries[1] != "from=2026-09-01T00%3A00%3A00Z&top=10" {
+		t.Errorf("unexpected queries %q", queries)
+	}
+}
+
+func TestExtractEntities(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/knowledge/extract" {
+			t.Errorf("expected path /v1/knowledge/extract, got %s", r.URL.Path)
+		}
+		var req EntityExtractionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.DocumentID != "doc-1" || !req.Apply {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(EntityExtractionResponse{
+			DocumentID: "doc-1",
+			Entities: []Entity{
+				{Type: EntityCVE, Value: "CVE-2024-3400", Count: 2, Confidence: 0.99},
+				{Type: EntityProduct, Value: "PAN-OS", Count: 1, Confidence: 0.9},
+			},
+			Keywords: []Keyword{{Term: "command injection", Score: 0.8}},
+			Tags:     map[string]string{"cve": "CVE-2024-3400"},
+		})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	resp, err := client.ExtractEntities(context.Background(), EntityExtractionRequest{DocumentID: "doc-1", Apply: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cves := resp.EntitiesOfType(EntityCVE)
+	if len(cves) != 1 || cves[0].Value != "CVE-2024-3400" {
+		t.Errorf("unexpected CVE entities: %+v", cves)
+	}
+	if len(resp.Keywords) != 1 {
+		t.Errorf("expected 1 keyword, got %d", len(resp.Keywords))
+	}
+}
+
+func TestExtractEntitiesRequiresOneInput(t *testing.T) {
+	client := NewClient("http://localhost", "test-key")
+	var vErr *ValidationError
+	if _, err := client.ExtractEntities(context.Background(), EntityExtractionRequest{}); !errors.As(err, &vErr) || vErr.Field != "text" {
+		t.Errorf("expected ValidationError when neither Text nor DocumentID is set, got %v", err)
+	}
+	if _, err := client.ExtractEntities(context.Background(), EntityExtractionRequest{Text: "x", DocumentID: "doc-1"}); !errors.As(err, &vErr) || vErr.Field != "text" {
+		t.Errorf("expected ValidationError when both Text and DocumentID are set, got %v", err)
+	}
+}
+
 // ─── Learned Facts ──────────────────────────────────────────────────────────
 
 func TestListFacts(t *testing.T) {
@@ -1268,6 +3413,50 @@ func TestUpdateFact(t *testing.T) {
 	}
 }
 
+func TestLinkFactToDocument(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if r.URL.Path != "/v1/knowledge/facts/7/links" {
+			t.Errorf("expected path /v1/knowledge/facts/7/links, got %s", r.URL.Path)
+		}
+		var req FactLinkRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.ChunkID != "chunk-9" {
+			t.Errorf("expected chunk_id chunk-9, got %q", req.ChunkID)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(Fact{
+			ID:      7,
+			Content: "VPN uses port 443",
+			SupportingChunks: []SupportingChunk{
+				{ChunkID: "chunk-9", DocumentID: "doc-2", Filename: "network.md"},
+			},
+		})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	fact, err := client.LinkFactToDocument(context.Background(), 7, FactLinkRequest{ChunkID: "chunk-9"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fact.SupportingChunks) != 1 || fact.SupportingChunks[0].DocumentID != "doc-2" {
+		t.Errorf("unexpected supporting chunks: %+v", fact.SupportingChunks)
+	}
+}
+
+func TestLinkFactToDocumentRequiresTarget(t *testing.T) {
+	client := NewClient("http://localhost", "test-key")
+	_, err := client.LinkFactToDocument(context.Background(), 7, FactLinkRequest{})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || vErr.Field != "document_id" {
+		t.Fatalf("expected ValidationError when no document or chunk is given, got %v", err)
+	}
+}
+
 // ─── Cognitive Intelligence ─────────────────────────────────────────────────
 
 func TestGetCognitiveStats(t *testing.T) {
@@ -1304,6 +3493,27 @@ func TestGetCognitiveStats(t *testing.T) {
 	}
 }
 
+func TestGetCognitiveStatsAsOf(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/cognitive/stats" || r.URL.Query().Get("as_of") != "2026-04-01T00:00:00Z" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(CognitiveStatsResponse{TotalKnowledgeNodes: 40})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	asOf := time.Date(2026, 4, 1, 5, 30, 0, 0, time.FixedZone("IST", 5*3600+1800))
+	stats, err := client.GetCognitiveStatsAsOf(context.Background(), asOf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.TotalKnowledgeNodes != 40 {
+		t.Errorf("expected 40 nodes, got %d", stats.TotalKnowledgeNodes)
+	}
+}
+
 // ─── Usage ──────────────────────────────────────────────────────────────────
 
 func TestGetUsage(t *testing.T) {
@@ -1331,6 +3541,40 @@ func TestGetUsage(t *testing.T) {
 	}
 }
 
+func TestGetUserUsage(t *testing.T) {
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/usage/users/user-42" {
+			t.Errorf("expected path /v1/usage/users/user-42, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("from") != "2026-09-01T00:00:00Z" || r.URL.Query().Get("to") != "2026-10-01T00:00:00Z" {
+			t.Errorf("unexpected range %v", r.URL.Query())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(UserUsage{
+			UserID: "user-42", Requests: 40, Turns: 12, TotalTokens: 9000, AvgLatencyMs: 820,
+			PositiveFeedback: 3, NegativeFeedback: 1,
+		})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	usage, err := client.GetUserUsage(context.Background(), "user-42", TimeRange{From: from, To: to})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if usage.Requests != 40 || usage.TotalTokens != 9000 || usage.FeedbackRatio() != 0.75 {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+	if (&UserUsage{}).FeedbackRatio() != -1 {
+		t.Error("expected -1 ratio without feedback")
+	}
+	if _, err := client.GetUserUsage(context.Background(), "", TimeRange{}); err == nil {
+		t.Error("expected error for empty user ID")
+	}
+}
+
 func TestGetRecentUsage(t *testing.T) {
 	expected := UsageRecentResponse{
 		Object: "list",
@@ -1354,6 +3598,70 @@ func TestGetRecentUsage(t *testing.T) {
 	}
 }
 
+func TestCreateUsageAlert(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/usage/alerts" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var req UsageAlertRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Metric != UsageMetricTokens || req.Threshold != 1_000_000 || req.Window != "24h" {
+			t.Errorf("unexpected alert request: %+v", req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(UsageAlert{ID: "alert-1", Metric: req.Metric, Threshold: req.Threshold, Window: req.Window, WebhookURL: req.WebhookURL, Enabled: true})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	alert, err := client.CreateUsageAlert(context.Background(), UsageAlertRequest{
+		Metric:     UsageMetricTokens,
+		Threshold:  1_000_000,
+		Window:     "24h",
+		WebhookURL: "https://ops.example.com/hooks/usage",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if alert.ID != "alert-1" || !alert.Enabled {
+		t.Errorf("unexpected alert: %+v", alert)
+	}
+}
+
+func TestListUsageAlerts(t *testing.T) {
+	expected := UsageAlertListResponse{
+		Object: "list",
+		Data:   []UsageAlert{{ID: "alert-1", Metric: UsageMetricErrorRate, Threshold: 0.05, Window: "1h"}},
+		Total:  1,
+	}
+	srv := newTestServer(t, http.MethodGet, "/v1/usage/alerts", http.StatusOK, expected)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	alerts, err := client.ListUsageAlerts(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if alerts.Total != 1 || alerts.Data[0].Metric != UsageMetricErrorRate {
+		t.Errorf("unexpected alerts: %+v", alerts)
+	}
+}
+
+func TestDeleteUsageAlert(t *testing.T) {
+	srv := newTestServer(t, http.MethodDelete, "/v1/usage/alerts/alert-1", http.StatusOK, UsageAlertDeleteResponse{ID: "alert-1", Deleted: true})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	del, err := client.DeleteUsageAlert(context.Background(), "alert-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !del.Deleted {
+		t.Error("expected deleted=true")
+	}
+}
+
 // ─── Cache Stats ────────────────────────────────────────────────────────────
 
 func TestGetCacheStats(t *testing.T) {
@@ -1381,6 +3689,73 @@ func TestGetCacheStats(t *testing.T) {
 	}
 }
 
+// ─── Retention ──────────────────────────────────────────────────────────────
+
+func TestSetRetentionPolicy(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/v1/retention/conversations" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var raw map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&raw)
+		if raw["max_age_seconds"] != float64(90*24*3600) {
+			t.Errorf("expected 90 days in seconds, got %v", raw["max_age_seconds"])
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"scope":"conversations","max_age_seconds":7776000,"updated_at":"2026-01-01T00:00:00Z"}`)
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	policy, err := client.SetRetentionPolicy(context.Background(), RetentionPolicy{Scope: RetentionConversations, MaxAge: 90 * 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy.MaxAge != 90*24*time.Hour || policy.UpdatedAt == "" {
+		t.Errorf("unexpected policy: %+v", policy)
+	}
+
+	var vErr *ValidationError
+	if _, err := client.SetRetentionPolicy(context.Background(), RetentionPolicy{MaxAge: time.Hour}); !errors.As(err, &vErr) || vErr.Field != "scope" {
+		t.Errorf("expected scope ValidationError, got %v", err)
+	}
+	if _, err := client.SetRetentionPolicy(context.Background(), RetentionPolicy{Scope: RetentionUsage, MaxAge: -time.Hour}); !errors.As(err, &vErr) || vErr.Field != "max_age_seconds" {
+		t.Errorf("expected max_age_seconds ValidationError, got %v", err)
+	}
+}
+
+func TestListRetentionPolicies(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"object":"list","data":[{"scope":"usage","max_age_seconds":3600},{"scope":"cache","max_age_seconds":60}]}`)
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	list, err := client.ListRetentionPolicies(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list.Data) != 2 || list.Data[0].Scope != RetentionUsage || list.Data[1].MaxAge != time.Minute {
+		t.Errorf("unexpected policies: %+v", list.Data)
+	}
+}
+
+func TestPurgeExpired(t *testing.T) {
+	expected := PurgeResponse{ConversationsDeleted: 12, UsageRecordsDeleted: 340, CacheEntriesDeleted: 5}
+	srv := newTestServer(t, http.MethodPost, "/v1/retention/purge", http.StatusOK, expected)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	result, err := client.PurgeExpired(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *result != expected {
+		t.Errorf("expected %+v, got %+v", expected, *result)
+	}
+}
+
 // ─── Metrics ────────────────────────────────────────────────────────────────
 
 func TestGetMetrics(t *testing.T) {
@@ -0,0 +1,489 @@
+// Command hackersera is an operator CLI for the HackersEra AI API.
+//
+//	hackersera chat                          interactive streaming chat
+//	hackersera docs upload FILE...           upload documents for RAG
+//	hackersera docs list
+//	hackersera docs delete ID...
+//	hackersera search QUERY
+//	hackersera convo list
+//	hackersera convo show ID
+//	hackersera facts [list | add CONTENT]
+//	hackersera usage [-user ID -days N]
+//
+// The API key and base URL are read from HACKERSERA_API_KEY and
+// HACKERSERA_BASE_URL, falling back to the config file given with -config or
+// hackersera/config.json in the user config directory (~/.config on Linux):
+//
+//	{"api_key": "...", "base_url": "https://api-ai.hackersera.com", "model": "hackersera-ai"}
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	sdk "github.com/hackersera-dev-team/hackersera-ai-sdk"
+)
+
+const defaultBaseURL = "https://api-ai.hackersera.com"
+
+const usage = `usage: hackersera [-config FILE] COMMAND [ARGS]
+
+commands:
+  chat [-model M] [-system PROMPT]    interactive streaming chat (/reset, /exit)
+  docs upload [-tag K=V] [-ocr] FILE...
+  docs list
+  docs delete ID...
+  search [-top N] QUERY
+  convo list [-limit N]
+  convo show ID
+  facts [list [-limit N] | add CONTENT]
+  usage [-user ID] [-days N]
+
+environment: HACKERSERA_API_KEY, HACKERSERA_BASE_URL
+`
+
+// config is the CLI configuration file.
+type config struct {
+	APIKey  string `json:"api_key"`
+	BaseURL string `json:"base_url"`
+	Model   string `json:"model"`
+}
+
+// env holds the process inputs, so commands can run against test doubles.
+type env struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+	getenv         func(string) string
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}))
+}
+
+func run(ctx context.Context, args []string, e env) int {
+	fs := flag.NewFlagSet("hackersera", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() { fmt.Fprint(e.stderr, usage) }
+	configPath := fs.String("config", "", "config file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	cfg, err := loadConfig(*configPath, e.getenv)
+	if err != nil {
+		fmt.Fprintln(e.stderr, "hackersera:", err)
+		return 1
+	}
+	if cfg.APIKey == "" {
+		fmt.Fprintln(e.stderr, "hackersera: no API key; set HACKERSERA_API_KEY or api_key in the config file")
+		return 1
+	}
+	client := sdk.NewClient(cfg.BaseURL, cfg.APIKey)
+
+	cmd, rest := fs.Arg(0), fs.Args()[1:]
+	switch cmd {
+	case "chat":
+		err = runChat(ctx, client, cfg, rest, e)
+	case "docs":
+		err = runDocs(ctx, client, rest, e)
+	case "search":
+		err = runSearch(ctx, client, rest, e)
+	case "convo":
+		err = runConvo(ctx, client, rest, e)
+	case "facts":
+		err = runFacts(ctx, client, rest, e)
+	case "usage":
+		err = runUsage(ctx, client, rest, e)
+	default:
+		err = usageError(fmt.Sprintf("unknown command %q", cmd))
+	}
+	if err != nil {
+		var usageErr usageError
+		if errors.As(err, &usageErr) || errors.Is(err, flag.ErrHelp) {
+			if !errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintln(e.stderr, "hackersera:", err)
+			}
+			fmt.Fprint(e.stderr, usage)
+			return 2
+		}
+		fmt.Fprintln(e.stderr, "hackersera:", err)
+		return 1
+	}
+	return 0
+}
+
+// usageError is a malformed command line.
+type usageError string
+
+func (u usageError) Error() string { return string(u) }
+
+// loadConfig reads the config file, if any, and applies the environment over
+// it.
+func loadConfig(path string, getenv func(string) string) (config, error) {
+	explicit := path != ""
+	if !explicit {
+		if dir, err := os.UserConfigDir(); err == nil {
+			path = filepath.Join(dir, "hackersera", "config.json")
+		}
+	}
+
+	var cfg config
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(data, &cfg); err != nil {
+				return cfg, fmt.Errorf("parse %s: %w", path, err)
+			}
+		case explicit || !errors.Is(err, os.ErrNotExist):
+			return cfg, fmt.Errorf("read config: %w", err)
+		}
+	}
+
+	if v := getenv("HACKERSERA_API_KEY"); v != "" {
+		cfg.APIKey = v
+	}
+	if v := getenv("HACKERSERA_BASE_URL"); v != "" {
+		cfg.BaseURL = v
+	} else if v := getenv("HACKERSERA_API_URL"); v != "" {
+		cfg.BaseURL = v
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultBaseURL
+	}
+	if cfg.Model == "" {
+		cfg.Model = sdk.ModelDefault
+	}
+	return cfg, nil
+}
+
+// subFlags returns a flag set for a subcommand that reports errors instead of
+// exiting.
+func subFlags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError(fmt.Sprintf("%s: %v", fs.Name(), err))
+	}
+	return nil
+}
+
+// ─── Chat ───────────────────────────────────────────────────────────────────
+
+func runChat(ctx context.Context, client *sdk.Client, cfg config, args []string, e env) error {
+	fs := subFlags("chat")
+	model := fs.String("model", cfg.Model, "model")
+	system := fs.String("system", "", "system prompt")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	template := sdk.ChatRequest{Model: *model}
+	if *system != "" {
+		template.Messages = []sdk.Message{{Role: "system", Content: *system}}
+	}
+	session := client.NewChatSession(template)
+
+	in := bufio.NewScanner(e.stdin)
+	for {
+		fmt.Fprint(e.stdout, "> ")
+		if !in.Scan() {
+			fmt.Fprintln(e.stdout)
+			return in.Err()
+		}
+		line := strings.TrimSpace(in.Text())
+		switch line {
+		case "":
+			continue
+		case "/exit", "/quit":
+			return nil
+		case "/reset":
+			session = client.NewChatSession(template)
+			fmt.Fprintln(e.stdout, "(new conversation)")
+			continue
+		}
+		if err := streamReply(ctx, session, line, e.stdout); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			fmt.Fprintln(e.stderr, "error:", err)
+		}
+	}
+}
+
+func streamReply(ctx context.Context, session *sdk.ChatSession, line string, w io.Writer) error {
+	stream, err := session.SendStream(ctx, sdk.Message{Role: "user", Content: line})
+	if err != nil {
+		return err
+	}
+	paced := sdk.NewPacedStream(stream, sdk.PaceConfig{WordBoundaries: true})
+	defer paced.Close()
+	for {
+		chunk, err := paced.Next()
+		if err == io.EOF {
+			fmt.Fprintln(w)
+			return nil
+		}
+		if err != nil {
+			fmt.Fprintln(w)
+			return err
+		}
+		for _, choice := range chunk.Choices {
+			fmt.Fprint(w, choice.Delta.Content)
+		}
+	}
+}
+
+// ─── Documents ──────────────────────────────────────────────────────────────
+
+func runDocs(ctx context.Context, client *sdk.Client, args []string, e env) error {
+	if len(args) == 0 {
+		return usageError("docs: missing subcommand")
+	}
+	switch args[0] {
+	case "upload":
+		return runDocsUpload(ctx, client, args[1:], e)
+	case "list":
+		docs, err := client.ListDocuments(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tFILENAME\tSTATUS\tCHUNKS\tCREATED")
+		for _, d := range docs.Data {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", d.ID, d.Filename, d.Status, d.ChunkCount, d.CreatedAt)
+		}
+		return tw.Flush()
+	case "delete":
+		if len(args) < 2 {
+			return usageError("docs delete: missing document ID")
+		}
+		for _, id := range args[1:] {
+			if _, err := client.DeleteDocument(ctx, id); err != nil {
+				return fmt.Errorf("delete %s: %w", id, err)
+			}
+			fmt.Fprintln(e.stdout, "deleted", id)
+		}
+		return nil
+	default:
+		return usageError(fmt.Sprintf("docs: unknown subcommand %q", args[0]))
+	}
+}
+
+// tagFlags collects repeated -tag key=value flags.
+type tagFlags map[string]string
+
+func (t tagFlags) String() string { return fmt.Sprint(map[string]string(t)) }
+
+func (t tagFlags) Set(v string) error {
+	key, value, ok := strings.Cut(v, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("tag %q is not key=value", v)
+	}
+	t[key] = value
+	return nil
+}
+
+func runDocsUpload(ctx context.Context, client *sdk.Client, args []string, e env) error {
+	fs := subFlags("docs upload")
+	tags := tagFlags{}
+	fs.Var(tags, "tag", "key=value tag (repeatable)")
+	ocr := fs.Bool("ocr", false, "run OCR on scanned pages and images")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return usageError("docs upload: missing file")
+	}
+
+	for _, path := range fs.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		doc, err := client.UploadDocumentFile(ctx, sdk.DocumentFileUploadRequest{File: f, Filename: filepath.Base(path), Tags: tags, OCR: *ocr})
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("upload %s: %w", path, err)
+		}
+		fmt.Fprintf(e.stdout, "%s\t%s\t%s\n", doc.ID, doc.Filename, doc.Status)
+	}
+	return nil
+}
+
+func runSearch(ctx context.Context, client *sdk.Client, args []string, e env) error {
+	fs := subFlags("search")
+	top := fs.Int("top", 5, "number of results")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	query := strings.Join(fs.Args(), " ")
+	if query == "" {
+		return usageError("search: missing query")
+	}
+
+	resp, err := client.Search(ctx, sdk.SearchRequest{Query: query, TopK: *top})
+	if err != nil {
+		return err
+	}
+	for i, r := range resp.Data {
+		fmt.Fprintf(e.stdout, "%d. %s (%.3f) [%s]\n   %s\n", i+1, r.Filename, r.Score, r.DocumentID, oneLine(r.Content, 200))
+	}
+	if len(resp.Data) == 0 {
+		fmt.Fprintln(e.stdout, "no results")
+	}
+	return nil
+}
+
+// ─── Conversations ──────────────────────────────────────────────────────────
+
+func runConvo(ctx context.Context, client *sdk.Client, args []string, e env) error {
+	if len(args) == 0 {
+		return usageError("convo: missing subcommand")
+	}
+	switch args[0] {
+	case "list":
+		fs := subFlags("convo list")
+		limit := fs.Int("limit", 20, "number of conversations")
+		if err := parse(fs, args[1:]); err != nil {
+			return err
+		}
+		convos, err := client.ListConversations(ctx, *limit)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tTURNS\tUPDATED\tTITLE")
+		for _, c := range convos.Data {
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", c.ID, c.TurnCount, c.UpdatedAt, oneLine(c.Title, 60))
+		}
+		return tw.Flush()
+	case "show":
+		if len(args) != 2 {
+			return usageError("convo show: want one conversation ID")
+		}
+		convo, err := client.GetConversation(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "%s  %s (%s, %d turns)\n\n", convo.ID, convo.Title, convo.Model, convo.TurnCount)
+		for _, t := range convo.Turns {
+			fmt.Fprintf(e.stdout, "[%s] %s\n\n", t.Role, t.Content)
+		}
+		return nil
+	default:
+		return usageError(fmt.Sprintf("convo: unknown subcommand %q", args[0]))
+	}
+}
+
+// ─── Facts & Usage ──────────────────────────────────────────────────────────
+
+func runFacts(ctx context.Context, client *sdk.Client, args []string, e env) error {
+	sub := "list"
+	if len(args) > 0 {
+		sub, args = args[0], args[1:]
+	}
+	switch sub {
+	case "list":
+		fs := subFlags("facts list")
+		limit := fs.Int("limit", 50, "number of facts")
+		if err := parse(fs, args); err != nil {
+			return err
+		}
+		facts, err := client.ListFacts(ctx, *limit, nil)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tCONF\tVERIFIED\tSOURCE\tCONTENT")
+		for _, f := range facts.Data {
+			fmt.Fprintf(tw, "%d\t%.2f\t%t\t%s\t%s\n", f.ID, f.Confidence, f.Verified, f.Source, oneLine(f.Content, 80))
+		}
+		return tw.Flush()
+	case "add":
+		content := strings.Join(args, " ")
+		if content == "" {
+			return usageError("facts add: missing content")
+		}
+		fact, err := client.CreateFact(ctx, sdk.FactCreateRequest{Content: content, Source: "cli", Confidence: 1, Verified: true})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "created fact %d\n", fact.ID)
+		return nil
+	default:
+		return usageError(fmt.Sprintf("facts: unknown subcommand %q", sub))
+	}
+}
+
+func runUsage(ctx context.Context, client *sdk.Client, args []string, e env) error {
+	fs := subFlags("usage")
+	user := fs.String("user", "", "report one user's usage")
+	days := fs.Int("days", 30, "days covered by -user")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	var byModel []sdk.UsageByModel
+	if *user != "" {
+		u, err := client.GetUserUsage(ctx, *user, sdk.LastDays(*days))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(tw, "user\t%s (last %d days)\n", u.UserID, *days)
+		fmt.Fprintf(tw, "requests\t%d\nturns\t%d\n", u.Requests, u.Turns)
+		fmt.Fprintf(tw, "tokens\t%d (prompt %d, completion %d)\n", u.TotalTokens, u.PromptTokens, u.CompletionTokens)
+		fmt.Fprintf(tw, "avg latency\t%.0f ms\n", u.AvgLatencyMs)
+		fmt.Fprintf(tw, "feedback\t+%d / -%d\n", u.PositiveFeedback, u.NegativeFeedback)
+		byModel = u.ByModel
+	} else {
+		u, err := client.GetUsage(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(tw, "requests\t%d\n", u.TotalRequests)
+		fmt.Fprintf(tw, "tokens\t%d (prompt %d, completion %d)\n", u.TotalTokens, u.PromptTokens, u.CompletionTokens)
+		fmt.Fprintf(tw, "avg latency\t%.0f ms\n", u.AvgLatencyMs)
+		byModel = u.ByModel
+	}
+	if len(byModel) > 0 {
+		fmt.Fprintln(tw, "\nMODEL\tREQUESTS\tTOKENS")
+		for _, m := range byModel {
+			fmt.Fprintf(tw, "%s\t%d\t%d\n", m.Model, m.Requests, m.TotalTokens)
+		}
+	}
+	return tw.Flush()
+}
+
+// oneLine flattens s to a single line of at most n runes.
+func oneLine(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
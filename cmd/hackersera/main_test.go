@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sdk "github.com/hackersera-dev-team/hackersera-ai-sdk"
+)
+
+func runCLI(t *testing.T, vars map[string]string, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr strings.Builder
+	code := run(context.Background(), args, env{
+		stdin:  strings.NewReader(stdin),
+		stdout: &stdout,
+		stderr: &stderr,
+		getenv: func(k string) string { return vars[k] },
+	})
+	return code, stdout.String(), stderr.String()
+}
+
+func TestCommands(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer file-key" {
+			t.Errorf("expected the config file's key, got %q", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/documents":
+			json.NewEncoder(w).Encode(sdk.DocumentListResponse{Data: []sdk.DocumentResponse{{ID: "doc-1", Filename: "runbook.md", Status: "indexed", ChunkCount: 12}}})
+		case "/v1/search":
+			json.NewEncoder(w).Encode(sdk.SearchResponse{Data: []sdk.SearchResult{{DocumentID: "doc-1", Filename: "runbook.md", Content: "Restart the\npump.", Score: 0.91}}})
+		case "/v1/chat/completions":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi th\"}}]}\n\n"+
+				"data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"ere.\"},\"finish_reason\":\"stop\"}]}\n\ndata: [DONE]\n\n")
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{"api_key":"file-key","base_url":"http://unused.invalid"}`), 0o600)
+	vars := map[string]string{"HACKERSERA_BASE_URL": srv.URL}
+
+	code, out, errOut := runCLI(t, vars, "", "-config", path, "docs", "list")
+	if code != 0 || !strings.Contains(out, "doc-1") || !strings.Contains(out, "runbook.md") {
+		t.Errorf("docs list: code %d, out %q, err %q", code, out, errOut)
+	}
+	code, out, _ = runCLI(t, vars, "", "-config", path, "search", "restart", "pump")
+	if code != 0 || !strings.Contains(out, "1. runbook.md (0.910) [doc-1]\n   Restart the pump.") {
+		t.Errorf("search: code %d, out %q", code, out)
+	}
+	code, out, _ = runCLI(t, vars, "hello\n/exit\n", "-config", path, "chat")
+	if code != 0 || !strings.Contains(out, "> Hi there.\n> ") {
+		t.Errorf("chat: code %d, out %q", code, out)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	vars := map[string]string{"HACKERSERA_API_KEY": "k", "HACKERSERA_BASE_URL": "http://unused.invalid"}
+	if code, _, errOut := runCLI(t, vars, "", "-config", filepath.Join(t.TempDir(), "missing.json"), "docs", "list"); code != 1 || !strings.Contains(errOut, "read config") {
+		t.Errorf("expected a missing explicit config to fail, got %d %q", code, errOut)
+	}
+	if code, _, errOut := runCLI(t, vars, "", "bogus"); code != 2 || !strings.Contains(errOut, `unknown command "bogus"`) {
+		t.Errorf("expected a usage error, got %d %q", code, errOut)
+	}
+	if code, _, errOut := runCLI(t, vars, "", "docs", "delete"); code != 2 || !strings.Contains(errOut, "missing document ID") {
+		t.Errorf("expected a usage error, got %d %q", code, errOut)
+	}
+	if code, _, errOut := runCLI(t, map[string]string{}, "", "-config", writeEmptyConfig(t), "usage"); code != 1 || !strings.Contains(errOut, "no API key") {
+		t.Errorf("expected a missing key error, got %d %q", code, errOut)
+	}
+}
+
+func writeEmptyConfig(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{}`), 0o600)
+	return path
+}
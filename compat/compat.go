@@ -0,0 +1,542 @@
+// Package compat mirrors the most used request and response shapes of the
+// OpenAI Go client (github.com/openai/openai-go), so an application written
+// against it can move to HackersEra AI by swapping the import and the
+// constructor. Imported under the name openai, typical code compiles as is:
+//
+//	import openai "github.com/hackersera-dev-team/hackersera-ai-sdk/compat"
+//
+//	client := openai.NewClient(
+//		openai.WithBaseURL("https://api-ai.hackersera.com"),
+//		openai.WithAPIKey(os.Getenv("HACKERSERA_API_KEY")),
+//	)
+//	completion, err := client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
+//		Model: "hackersera-ai",
+//		Messages: []openai.ChatCompletionMessageParamUnion{
+//			openai.SystemMessage("Answer briefly."),
+//			openai.UserMessage("What is RAG?"),
+//		},
+//		Temperature: openai.Float(0.2),
+//	})
+//
+// Only chat completions (plain and streaming, with function tools) and
+// embeddings are covered. Errors are the SDK's, so API failures are
+// *hackeserasdk.APIError rather than *openai.Error. For everything else use
+// Client.SDK, or convert values with ToChatRequest, FromChatResponse,
+// FromChatChunk, ToEmbeddingRequest, and FromEmbeddingResponse.
+package compat
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	sdk "github.com/hackersera-dev-team/hackersera-ai-sdk"
+)
+
+// ─── Client ─────────────────────────────────────────────────────────────────
+
+// Option configures NewClient, like openai-go's option.RequestOption.
+type Option func(*config)
+
+type config struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// WithBaseURL sets the API base URL. Defaults to https://api-ai.hackersera.com.
+func WithBaseURL(url string) Option { return func(c *config) { c.baseURL = url } }
+
+// WithAPIKey sets the API key.
+func WithAPIKey(key string) Option { return func(c *config) { c.apiKey = key } }
+
+// WithHTTPClient sets the http.Client used for requests.
+func WithHTTPClient(hc *http.Client) Option { return func(c *config) { c.httpClient = hc } }
+
+// Client is the openai.Client shape: services hang off Chat and Embeddings.
+type Client struct {
+	Chat       ChatService
+	Embeddings EmbeddingService
+
+	sdk *sdk.Client
+}
+
+// NewClient creates a client from options, like openai.NewClient.
+func NewClient(opts ...Option) Client {
+	cfg := config{baseURL: "https://api-ai.hackersera.com"}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	c := sdk.NewClient(cfg.baseURL, cfg.apiKey)
+	if cfg.httpClient != nil {
+		c.WithHTTPClient(cfg.httpClient)
+	}
+	return Wrap(c)
+}
+
+// Wrap exposes an existing SDK client, with its retries, middleware, and
+// other configuration, through the OpenAI shapes.
+func Wrap(c *sdk.Client) Client {
+	return Client{
+		Chat:       ChatService{Completions: ChatCompletionService{client: c}},
+		Embeddings: EmbeddingService{client: c},
+		sdk:        c,
+	}
+}
+
+// SDK returns the underlying SDK client, for the endpoints this package does
+// not cover.
+func (c Client) SDK() *sdk.Client { return c.sdk }
+
+// ─── Optional Values ────────────────────────────────────────────────────────
+
+// Opt is an optional request field, like openai-go's param.Opt. The zero value
+// is unset and omitted from the request.
+type Opt[T any] struct {
+	Value T
+	set   bool
+}
+
+// Valid reports whether the value is set.
+func (o Opt[T]) Valid() bool { return o.set }
+
+func (o Opt[T]) ptr() *T {
+	if !o.set {
+		return nil
+	}
+	v := o.Value
+	return &v
+}
+
+// String returns a set Opt[string].
+func String(v string) Opt[string] { return Opt[string]{Value: v, set: true} }
+
+// Int returns a set Opt[int64].
+func Int(v int64) Opt[int64] { return Opt[int64]{Value: v, set: true} }
+
+// Float returns a set Opt[float64].
+func Float(v float64) Opt[float64] { return Opt[float64]{Value: v, set: true} }
+
+// Bool returns a set Opt[bool].
+func Bool(v bool) Opt[bool] { return Opt[bool]{Value: v, set: true} }
+
+func intPtr(o Opt[int64]) *int {
+	if !o.set {
+		return nil
+	}
+	v := int(o.Value)
+	return &v
+}
+
+// ─── Chat Completions ───────────────────────────────────────────────────────
+
+// ChatService groups the chat services, like openai.ChatService.
+type ChatService struct {
+	Completions ChatCompletionService
+}
+
+// ChatCompletionService creates chat completions.
+type ChatCompletionService struct {
+	client *sdk.Client
+}
+
+// ChatCompletionNewParams is the request of ChatCompletionService.New.
+type ChatCompletionNewParams struct {
+	Messages            []ChatCompletionMessageParamUnion
+	Model               string
+	MaxTokens           Opt[int64]
+	MaxCompletionTokens Opt[int64]
+	Temperature         Opt[float64]
+	TopP                Opt[float64]
+	PresencePenalty     Opt[float64]
+	FrequencyPenalty    Opt[float64]
+	Seed                Opt[int64]
+	N                   Opt[int64]
+	User                Opt[string]
+	ParallelToolCalls   Opt[bool]
+	Stop                []string
+	Tools               []ChatCompletionToolParam
+}
+
+// ChatCompletionMessageParamUnion is one request message. Build it with
+// SystemMessage, UserMessage, AssistantMessage, ToolMessage, or
+// ChatCompletionMessage.ToParam.
+type ChatCompletionMessageParamUnion struct {
+	msg sdk.Message
+}
+
+// SystemMessage returns a system message.
+func SystemMessage(content string) ChatCompletionMessageParamUnion {
+	return ChatCompletionMessageParamUnion{msg: sdk.Message{Role: "system", Content: content}}
+}
+
+// UserMessage returns a user message.
+func UserMessage(content string) ChatCompletionMessageParamUnion {
+	return ChatCompletionMessageParamUnion{msg: sdk.Message{Role: "user", Content: content}}
+}
+
+// AssistantMessage returns an assistant message.
+func AssistantMessage(content string) ChatCompletionMessageParamUnion {
+	return ChatCompletionMessageParamUnion{msg: sdk.Message{Role: "assistant", Content: content}}
+}
+
+// ToolMessage returns the result of the tool call toolCallID.
+func ToolMessage(content, toolCallID string) ChatCompletionMessageParamUnion {
+	return ChatCompletionMessageParamUnion{msg: sdk.Message{Role: "tool", Content: content, ToolCallID: toolCallID}}
+}
+
+// GetRole returns the message role.
+func (u ChatCompletionMessageParamUnion) GetRole() string { return u.msg.Role }
+
+// ChatCompletionToolParam is a function tool.
+type ChatCompletionToolParam struct {
+	Function FunctionDefinitionParam
+}
+
+// FunctionDefinitionParam describes a function the model may call.
+type FunctionDefinitionParam struct {
+	Name        string
+	Description Opt[string]
+	Parameters  FunctionParameters
+}
+
+// FunctionParameters is the function's JSON Schema.
+type FunctionParameters map[string]any
+
+// ChatCompletion is the response of ChatCompletionService.New.
+type ChatCompletion struct {
+	ID                string
+	Object            string
+	Created           int64
+	Model             string
+	SystemFingerprint string
+	Choices           []ChatCompletionChoice
+	Usage             CompletionUsage
+}
+
+// ChatCompletionChoice is one generated reply.
+type ChatCompletionChoice struct {
+	Index        int64
+	FinishReason string
+	Message      ChatCompletionMessage
+}
+
+// ChatCompletionMessage is a generated assistant message.
+type ChatCompletionMessage struct {
+	Role      string
+	Content   string
+	ToolCalls []ChatCompletionMessageToolCall
+}
+
+// ToParam returns the message for sending back in the history, as it is
+// needed before the results of its tool calls.
+func (m ChatCompletionMessage) ToParam() ChatCompletionMessageParamUnion {
+	msg := sdk.Message{Role: m.Role, Content: m.Content}
+	if msg.Role == "" {
+		msg.Role = "assistant"
+	}
+	for _, tc := range m.ToolCalls {
+		msg.ToolCalls = append(msg.ToolCalls, sdk.ToolCall{
+			ID:       tc.ID,
+			Type:     tc.Type,
+			Function: sdk.FunctionCall{Name: tc.Function.Name, Arguments: tc.Function.Arguments},
+		})
+	}
+	return ChatCompletionMessageParamUnion{msg: msg}
+}
+
+// ChatCompletionMessageToolCall is a function call requested by the model.
+type ChatCompletionMessageToolCall struct {
+	ID       string
+	Type     string
+	Function ChatCompletionMessageToolCallFunction
+}
+
+// ChatCompletionMessageToolCallFunction is the called function and its JSON
+// arguments.
+type ChatCompletionMessageToolCallFunction struct {
+	Name      string
+	Arguments string
+}
+
+// CompletionUsage is the token usage of a completion.
+type CompletionUsage struct {
+	PromptTokens     int64
+	CompletionTokens int64
+	TotalTokens      int64
+}
+
+// New creates a chat completion.
+func (s ChatCompletionService) New(ctx context.Context, params ChatCompletionNewParams) (*ChatCompletion, error) {
+	resp, err := s.client.ChatCompletion(ctx, ToChatRequest(params))
+	if err != nil {
+		return nil, err
+	}
+	return FromChatResponse(resp), nil
+}
+
+// NewStreaming starts a streaming chat completion. As in openai-go, errors
+// opening the stream are reported by the stream's Err.
+func (s ChatCompletionService) NewStreaming(ctx context.Context, params ChatCompletionNewParams) *ChatCompletionStream {
+	stream, err := s.client.StreamChat(ctx, ToChatRequest(params))
+	return &ChatCompletionStream{stream: stream, err: err}
+}
+
+// ToChatRequest converts OpenAI-shaped params to an SDK chat request.
+func ToChatRequest(p ChatCompletionNewParams) sdk.ChatRequest {
+	req := sdk.ChatRequest{
+		Model:               p.Model,
+		MaxTokens:           intPtr(p.MaxTokens),
+		MaxCompletionTokens: intPtr(p.MaxCompletionTokens),
+		Temperature:         p.Temperature.ptr(),
+		TopP:                p.TopP.ptr(),
+		PresencePenalty:     p.PresencePenalty.ptr(),
+		FrequencyPenalty:    p.FrequencyPenalty.ptr(),
+		Seed:                intPtr(p.Seed),
+		N:                   intPtr(p.N),
+		User:                p.User.Value,
+		ParallelToolCalls:   p.ParallelToolCalls.ptr(),
+		Stop:                p.Stop,
+	}
+	for _, m := range p.Messages {
+		req.Messages = append(req.Messages, m.msg)
+	}
+	for _, t := range p.Tools {
+		var params interface{}
+		if t.Function.Parameters != nil {
+			params = map[string]any(t.Function.Parameters)
+		}
+		req.Tools = append(req.Tools, sdk.Tool{
+			Type:     sdk.ToolTypeFunction,
+			Function: sdk.ToolFunction{Name: t.Function.Name, Description: t.Function.Description.Value, Parameters: params},
+		})
+	}
+	return req
+}
+
+// FromChatResponse converts an SDK chat response to the OpenAI shape.
+func FromChatResponse(r *sdk.ChatResponse) *ChatCompletion {
+	out := &ChatCompletion{
+		ID:                r.ID,
+		Object:            r.Object,
+		Created:           r.Created,
+		Model:             r.Model,
+		SystemFingerprint: r.SystemFingerprint,
+		Usage:             fromUsage(r.Usage),
+	}
+	for _, ch := range r.Choices {
+		msg := ChatCompletionMessage{Role: ch.Message.Role, Content: ch.Message.ContentAsText()}
+		for _, tc := range ch.Message.ToolCalls {
+			msg.ToolCalls = append(msg.ToolCalls, ChatCompletionMessageToolCall{
+				ID:       tc.ID,
+				Type:     tc.Type,
+				Function: ChatCompletionMessageToolCallFunction{Name: tc.Function.Name, Arguments: tc.Function.Arguments},
+			})
+		}
+		out.Choices = append(out.Choices, ChatCompletionChoice{Index: int64(ch.Index), FinishReason: ch.FinishReason, Message: msg})
+	}
+	return out
+}
+
+func fromUsage(u sdk.Usage) CompletionUsage {
+	return CompletionUsage{PromptTokens: int64(u.PromptTokens), CompletionTokens: int64(u.CompletionTokens), TotalTokens: int64(u.TotalTokens)}
+}
+
+// ─── Chat Completion Streams ────────────────────────────────────────────────
+
+// ChatCompletionChunk is one streamed chunk.
+type ChatCompletionChunk struct {
+	ID      string
+	Object  string
+	Created int64
+	Model   string
+	Choices []ChatCompletionChunkChoice
+	// Usage is zero unless the server reported usage on this chunk.
+	Usage CompletionUsage
+}
+
+// ChatCompletionChunkChoice is the increment of one choice.
+type ChatCompletionChunkChoice struct {
+	Index int64
+	Delta ChatCompletionChunkChoiceDelta
+	// FinishReason is empty until the choice's last chunk.
+	FinishReason string
+}
+
+// ChatCompletionChunkChoiceDelta is the new content of a chunk.
+type ChatCompletionChunkChoiceDelta struct {
+	Role      string
+	Content   string
+	ToolCalls []ChatCompletionChunkChoiceDeltaToolCall
+}
+
+// ChatCompletionChunkChoiceDeltaToolCall is a fragment of a streamed tool call.
+type ChatCompletionChunkChoiceDeltaToolCall struct {
+	Index    int64
+	ID       string
+	Type     string
+	Function ChatCompletionChunkChoiceDeltaToolCallFunction
+}
+
+// ChatCompletionChunkChoiceDeltaToolCallFunction is a fragment of a streamed
+// function call.
+type ChatCompletionChunkChoiceDeltaToolCallFunction struct {
+	Name      string
+	Arguments string
+}
+
+// ChatCompletionStream iterates a streaming completion like openai-go's
+// ssestream.Stream: call Next until it returns false, then check Err. Always
+// Close it.
+//
+//	stream := client.Chat.Completions.NewStreaming(ctx, params)
+//	defer stream.Close()
+//	for stream.Next() {
+//		chunk := stream.Current()
+//		if len(chunk.Choices) > 0 {
+//			fmt.Print(chunk.Choices[0].Delta.Content)
+//		}
+//	}
+//	if err := stream.Err(); err != nil {
+//		return err
+//	}
+type ChatCompletionStream struct {
+	stream  *sdk.ChatStream
+	current ChatCompletionChunk
+	err     error
+}
+
+// Next advances to the next chunk and reports whether there is one.
+func (s *ChatCompletionStream) Next() bool {
+	if s.err != nil || s.stream == nil {
+		return false
+	}
+	chunk, err := s.stream.Next()
+	if err != nil {
+		if err != io.EOF {
+			s.err = err
+		}
+		s.stream = nil
+		return false
+	}
+	s.current = FromChatChunk(chunk)
+	return true
+}
+
+// Current returns the chunk read by the last Next.
+func (s *ChatCompletionStream) Current() ChatCompletionChunk { return s.current }
+
+// Err returns the error that ended the stream, or nil if it ended normally.
+func (s *ChatCompletionStream) Err() error { return s.err }
+
+// Close releases the stream's connection. It is safe to call more than once.
+func (s *ChatCompletionStream) Close() error {
+	if s.stream == nil {
+		return nil
+	}
+	return s.stream.Close()
+}
+
+// FromChatChunk converts an SDK stream chunk to the OpenAI shape.
+func FromChatChunk(c sdk.ChatStreamChunk) ChatCompletionChunk {
+	out := ChatCompletionChunk{ID: c.ID, Object: c.Object, Created: c.Created, Model: c.Model}
+	if c.Usage != nil {
+		out.Usage = fromUsage(*c.Usage)
+	}
+	for _, ch := range c.Choices {
+		choice := ChatCompletionChunkChoice{
+			Index: int64(ch.Index),
+			Delta: ChatCompletionChunkChoiceDelta{Role: ch.Delta.Role, Content: ch.Delta.Content},
+		}
+		if ch.FinishReason != nil {
+			choice.FinishReason = *ch.FinishReason
+		}
+		for _, tc := range ch.Delta.ToolCalls {
+			choice.Delta.ToolCalls = append(choice.Delta.ToolCalls, ChatCompletionChunkChoiceDeltaToolCall{
+				Index:    int64(tc.Index),
+				ID:       tc.ID,
+				Type:     tc.Type,
+				Function: ChatCompletionChunkChoiceDeltaToolCallFunction{Name: tc.Function.Name, Arguments: tc.Function.Arguments},
+			})
+		}
+		out.Choices = append(out.Choices, choice)
+	}
+	return out
+}
+
+// ─── Embeddings ─────────────────────────────────────────────────────────────
+
+// EmbeddingService creates embeddings.
+type EmbeddingService struct {
+	client *sdk.Client
+}
+
+// EmbeddingNewParams is the request of EmbeddingService.New.
+type EmbeddingNewParams struct {
+	Input      EmbeddingNewParamsInputUnion
+	Model      string
+	Dimensions Opt[int64]
+	User       Opt[string]
+}
+
+// EmbeddingNewParamsInputUnion is the text or texts to embed; set one field.
+type EmbeddingNewParamsInputUnion struct {
+	OfString         Opt[string]
+	OfArrayOfStrings []string
+}
+
+// CreateEmbeddingResponse is the response of EmbeddingService.New.
+type CreateEmbeddingResponse struct {
+	Object string
+	Model  string
+	Data   []Embedding
+	Usage  CreateEmbeddingResponseUsage
+}
+
+// Embedding is one vector, in input order by Index.
+type Embedding struct {
+	Object    string
+	Index     int64
+	Embedding []float64
+}
+
+// CreateEmbeddingResponseUsage is the token usage of an embedding request.
+type CreateEmbeddingResponseUsage struct {
+	PromptTokens int64
+	TotalTokens  int64
+}
+
+// New creates embeddings.
+func (s EmbeddingService) New(ctx context.Context, params EmbeddingNewParams) (*CreateEmbeddingResponse, error) {
+	resp, err := s.client.CreateEmbedding(ctx, ToEmbeddingRequest(params))
+	if err != nil {
+		return nil, err
+	}
+	return FromEmbeddingResponse(resp), nil
+}
+
+// ToEmbeddingRequest converts OpenAI-shaped params to an SDK embedding
+// request.
+func ToEmbeddingRequest(p EmbeddingNewParams) sdk.EmbeddingRequest {
+	req := sdk.EmbeddingRequest{Model: p.Model, Dimensions: intPtr(p.Dimensions), User: p.User.Value}
+	if p.Input.OfString.Valid() {
+		req.Input = p.Input.OfString.Value
+	} else {
+		req.Input = p.Input.OfArrayOfStrings
+	}
+	return req
+}
+
+// FromEmbeddingResponse converts an SDK embedding response to the OpenAI
+// shape.
+func FromEmbeddingResponse(r *sdk.EmbeddingResponse) *CreateEmbeddingResponse {
+	out := &CreateEmbeddingResponse{
+		Object: r.Object,
+		Model:  r.Model,
+		Usage:  CreateEmbeddingResponseUsage{PromptTokens: int64(r.Usage.PromptTokens), TotalTokens: int64(r.Usage.TotalTokens)},
+	}
+	for _, d := range r.Data {
+		out.Data = append(out.Data, Embedding{Object: d.Object, Index: int64(d.Index), Embedding: d.Embedding})
+	}
+	return out
+}
@@ -0,0 +1,133 @@
+package compat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sdk "github.com/hackersera-dev-team/hackersera-ai-sdk"
+)
+
+func TestChatCompletionsNew(t *testing.T) {
+	var got sdk.ChatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" || r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("unexpected request %s %v", r.URL.Path, r.Header)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"chatcmpl-1","object":"chat.completion","model":"hackersera-ai",
+			"choices":[{"index":0,"finish_reason":"tool_calls","message":{"role":"assistant","content":null,
+			"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Pune\"}"}}]}}],
+			"usage":{"prompt_tokens":12,"completion_tokens":5,"total_tokens":17}}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient(WithBaseURL(srv.URL), WithAPIKey("test-key"))
+	completion, err := client.Chat.Completions.New(context.Background(), ChatCompletionNewParams{
+		Model:       sdk.ModelDefault,
+		Messages:    []ChatCompletionMessageParamUnion{SystemMessage("Be brief."), UserMessage("Weather in Pune?")},
+		Temperature: Float(0.2),
+		MaxTokens:   Int(64),
+		Tools: []ChatCompletionToolParam{{Function: FunctionDefinitionParam{
+			Name:       "get_weather",
+			Parameters: FunctionParameters{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}},
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got.Messages) != 2 || got.Messages[1].Content != "Weather in Pune?" || got.Temperature == nil || *got.Temperature != 0.2 || got.MaxTokens == nil || *got.MaxTokens != 64 {
+		t.Errorf("unexpected request: %+v", got)
+	}
+	if got.TopP != nil || got.Seed != nil {
+		t.Errorf("expected unset options omitted, got %+v", got)
+	}
+	if len(got.Tools) != 1 || got.Tools[0].Type != sdk.ToolTypeFunction || got.Tools[0].Function.Name != "get_weather" {
+		t.Errorf("unexpected tools: %+v", got.Tools)
+	}
+
+	choice := completion.Choices[0]
+	if choice.FinishReason != "tool_calls" || len(choice.Message.ToolCalls) != 1 || choice.Message.ToolCalls[0].Function.Arguments != `{"city":"Pune"}` {
+		t.Errorf("unexpected choice: %+v", choice)
+	}
+	if completion.Usage.TotalTokens != 17 {
+		t.Errorf("unexpected usage: %+v", completion.Usage)
+	}
+
+	// The reply goes back into the history before the tool result.
+	next := ToChatRequest(ChatCompletionNewParams{Messages: []ChatCompletionMessageParamUnion{choice.Message.ToParam(), ToolMessage("31°C", "call_1")}})
+	if m := next.Messages[0]; m.Role != "assistant" || len(m.ToolCalls) != 1 || m.ToolCalls[0].ID != "call_1" {
+		t.Errorf("unexpected assistant param: %+v", m)
+	}
+	if m := next.Messages[1]; m.Role != "tool" || m.ToolCallID != "call_1" {
+		t.Errorf("unexpected tool param: %+v", m)
+	}
+}
+
+func TestChatCompletionsNewStreaming(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"c1\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":\"Hel\"}}]}\n\n"+
+			"data: {\"id\":\"c1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"lo\"},\"finish_reason\":\"stop\"}]}\n\n"+
+			"data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	client := Wrap(sdk.NewClient(srv.URL, "test-key"))
+	stream := client.Chat.Completions.NewStreaming(context.Background(), ChatCompletionNewParams{Model: sdk.ModelDefault, Messages: []ChatCompletionMessageParamUnion{UserMessage("hi")}})
+	defer stream.Close()
+
+	var text strings.Builder
+	var finish string
+	for stream.Next() {
+		chunk := stream.Current()
+		text.WriteString(chunk.Choices[0].Delta.Content)
+		finish = chunk.Choices[0].FinishReason
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text.String() != "Hello" || finish != "stop" {
+		t.Errorf("unexpected stream result %q / %q", text.String(), finish)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"message":"bad key","type":"authentication_error"}}`)
+	}))
+	defer failing.Close()
+	stream = NewClient(WithBaseURL(failing.URL)).Chat.Completions.NewStreaming(context.Background(), ChatCompletionNewParams{Model: sdk.ModelDefault, Messages: []ChatCompletionMessageParamUnion{UserMessage("hi")}})
+	var apiErr *sdk.APIError
+	if stream.Next() || !errors.As(stream.Err(), &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected the open error from Err, got %v", stream.Err())
+	}
+}
+
+func TestEmbeddingsNew(t *testing.T) {
+	var got sdk.EmbeddingRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"object":"list","model":"hackersera-embed","data":[{"object":"embedding","index":0,"embedding":[0.5,-0.25]}],"usage":{"prompt_tokens":3,"total_tokens":3}}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient(WithBaseURL(srv.URL), WithAPIKey("test-key"))
+	resp, err := client.Embeddings.New(context.Background(), EmbeddingNewParams{Model: sdk.ModelEmbedding, Input: EmbeddingNewParamsInputUnion{OfString: String("hello")}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Input != "hello" || got.Dimensions != nil {
+		t.Errorf("unexpected request: %+v", got)
+	}
+	if len(resp.Data) != 1 || resp.Data[0].Embedding[1] != -0.25 || resp.Usage.PromptTokens != 3 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
@@ -0,0 +1,77 @@
+package hackeserasdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ─── Per-Model Concurrency ──────────────────────────────────────────────────
+
+// ErrTooManyInFlight is returned when a model's in-flight limit is reached and
+// the client is configured with ConcurrencyReject.
+var ErrTooManyInFlight = errors.New("too many in-flight requests")
+
+// ConcurrencyPolicy decides what happens when a model's in-flight limit is reached.
+type ConcurrencyPolicy int
+
+const (
+	// ConcurrencyQueue waits for a free slot until the request context is done.
+	ConcurrencyQueue ConcurrencyPolicy = iota
+	// ConcurrencyReject fails immediately with ErrTooManyInFlight.
+	ConcurrencyReject
+)
+
+type modelLimiter struct {
+	policy ConcurrencyPolicy
+	slots  map[string]chan struct{}
+}
+
+// acquire takes a slot for model, returning a func that releases it.
+// Models without a configured limit are not restricted.
+func (l *modelLimiter) acquire(ctx context.Context, model string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	sem, ok := l.slots[model]
+	if !ok {
+		return func() {}, nil
+	}
+
+	release := func() { <-sem }
+	select {
+	case sem <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	if l.policy == ConcurrencyReject {
+		return nil, fmt.Errorf("%w: model %s (limit %d)", ErrTooManyInFlight, model, cap(sem))
+	}
+	select {
+	case sem <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// WithModelConcurrency caps the number of in-flight chat and embedding requests per
+// model, so a heavy workload on one model cannot starve others sharing the client.
+// Streams hold their slot until the stream ends. Models missing from limits, or with
+// a limit <= 0, are unrestricted. Call it before the client is shared between goroutines.
+//
+//	client.WithModelConcurrency(map[string]int{
+//		hackeserasdk.ModelPro:  4,
+//		hackeserasdk.ModelLite: 64,
+//	}, hackeserasdk.ConcurrencyQueue)
+func (c *Client) WithModelConcurrency(limits map[string]int, policy ConcurrencyPolicy) *Client {
+	l := &modelLimiter{policy: policy, slots: make(map[string]chan struct{}, len(limits))}
+	for model, n := range limits {
+		if n > 0 {
+			l.slots[model] = make(chan struct{}, n)
+		}
+	}
+	c.limiter = l
+	return c
+}
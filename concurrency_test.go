@@ -0,0 +1,96 @@
+package hackeserasdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// blockPro holds pro requests until unblock is closed.
+func blockPro(started chan<- struct{}, unblock <-chan struct{}) func(ChatRequest) {
+	return func(req ChatRequest) {
+		if req.Model == ModelPro {
+			started <- struct{}{}
+			<-unblock
+		}
+	}
+}
+
+func TestModelConcurrencyReject(t *testing.T) {
+	started := make(chan struct{}, 1)
+	unblock := make(chan struct{})
+	srv := newScriptedServer(t)
+	srv.onChat = blockPro(started, unblock)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").
+		WithModelConcurrency(map[string]int{ModelPro: 1}, ConcurrencyReject)
+	ctx := context.Background()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.ChatCompletion(ctx, ChatRequest{Model: ModelPro, Messages: []Message{{Role: "user", Content: "hi"}}})
+		done <- err
+	}()
+	<-started
+
+	_, err := client.ChatCompletion(ctx, ChatRequest{Model: ModelPro, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if !errors.Is(err, ErrTooManyInFlight) {
+		t.Errorf("expected ErrTooManyInFlight, got %v", err)
+	}
+
+	if _, err := client.ChatCompletion(ctx, ChatRequest{Model: ModelLite, Messages: []Message{{Role: "user", Content: "hi"}}}); err != nil {
+		t.Errorf("expected unlimited model to proceed, got %v", err)
+	}
+
+	close(unblock)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error from first request: %v", err)
+	}
+
+	// The slot is released once the first request completes.
+	go func() { <-started }()
+	if _, err := client.ChatCompletion(ctx, ChatRequest{Model: ModelPro, Messages: []Message{{Role: "user", Content: "hi"}}}); err != nil {
+		t.Errorf("expected slot to be free after completion, got %v", err)
+	}
+}
+
+func TestModelConcurrencyQueue(t *testing.T) {
+	started := make(chan struct{}, 2)
+	unblock := make(chan struct{})
+	srv := newScriptedServer(t)
+	srv.onChat = blockPro(started, unblock)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").
+		WithModelConcurrency(map[string]int{ModelPro: 1}, ConcurrencyQueue)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.ChatCompletion(context.Background(), ChatRequest{Model: ModelPro, Messages: []Message{{Role: "user", Content: "hi"}}})
+		done <- err
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := client.ChatCompletion(ctx, ChatRequest{Model: ModelPro, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected queued request to time out waiting for a slot, got %v", err)
+	}
+
+	queued := make(chan error, 1)
+	go func() {
+		_, err := client.ChatCompletion(context.Background(), ChatRequest{Model: ModelPro, Messages: []Message{{Role: "user", Content: "hi"}}})
+		queued <- err
+	}()
+
+	close(unblock)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := <-queued; err != nil {
+		t.Errorf("expected queued request to run after slot frees, got %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package hackeserasdk
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ─── Multimodal Content ─────────────────────────────────────────────────────
+
+// Content part types.
+const (
+	ContentTypeText     = "text"
+	ContentTypeImageURL = "image_url"
+)
+
+// Image detail levels for ImageURLContent. Low is cheaper in tokens; high lets
+// the model read small text in screenshots.
+const (
+	ImageDetailAuto = "auto"
+	ImageDetailLow  = "low"
+	ImageDetailHigh = "high"
+)
+
+// TextContent returns a text content part.
+func TextContent(text string) ContentPart {
+	return ContentPart{Type: ContentTypeText, Text: text}
+}
+
+// ImageURLContent returns an image content part for an http(s) or data URL.
+// detail is one of the ImageDetail constants, or "" for the server default.
+func ImageURLContent(url, detail string) ContentPart {
+	return ContentPart{Type: ContentTypeImageURL, ImageURL: &ImageURL{URL: url, Detail: detail}}
+}
+
+// ImageFromFile reads an image and returns it as a base64 data URL content part.
+// The MIME type is sniffed from the file contents, falling back to the extension.
+func ImageFromFile(path string) (ContentPart, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ContentPart{}, fmt.Errorf("read image: %w", err)
+	}
+	mimeType := http.DetectContentType(data)
+	if !strings.HasPrefix(mimeType, "image/") {
+		mimeType = mime.TypeByExtension(strings.ToLower(filepath.Ext(path)))
+	}
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	if !strings.HasPrefix(mimeType, "image/") {
+		return ContentPart{}, fmt.Errorf("%s is not a recognized image type", filepath.Base(path))
+	}
+	url := "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+	return ImageURLContent(url, ""), nil
+}
+
+// Parts returns the message content as content parts, like ContentAsParts.
+func (m Message) Parts() []ContentPart {
+	return m.ContentAsParts()
+}
+
+// ContentAsParts returns the message content as content parts, whatever form
+// it is held in: a string or *string becomes a single text part, a ContentPart or
+// []ContentPart is returned as is, and generic JSON values (a map or a slice
+// of maps, as built by hand or decoded into interface{}) are converted through
+// their JSON form. It returns nil for empty or null content and for values
+// that are not content parts.
+func (m Message) ContentAsParts() []ContentPart {
+	switch content := m.Content.(type) {
+	case nil:
+		return nil
+	case string:
+		if content == "" {
+			return nil
+		}
+		return []ContentPart{TextContent(content)}
+	case *string:
+		if content == nil || *content == "" {
+			return nil
+		}
+		return []ContentPart{TextContent(*content)}
+	case []ContentPart:
+		return content
+	case ContentPart:
+		return []ContentPart{content}
+	case *ContentPart:
+		if content == nil {
+			return nil
+		}
+		return []ContentPart{*content}
+	}
+
+	data, err := json.Marshal(m.Content)
+	if err != nil {
+		return nil
+	}
+	var parts []ContentPart
+	if err := json.Unmarshal(data, &parts); err == nil {
+		return parts
+	}
+	var part ContentPart
+	if err := json.Unmarshal(data, &part); err == nil && part.Type != "" {
+		return []ContentPart{part}
+	}
+	return nil
+}
+
+// ContentAsText returns the message's text: string content as is, or the
+// text parts of multipart content concatenated. Images and null content
+// contribute nothing.
+func (m Message) ContentAsText() string {
+	switch content := m.Content.(type) {
+	case string:
+		return content
+	case *string:
+		if content == nil {
+			return ""
+		}
+		return *content
+	}
+	var text strings.Builder
+	for _, p := range m.ContentAsParts() {
+		if p.Type == ContentTypeText || p.Type == "" {
+			text.WriteString(p.Text)
+		}
+	}
+	return text.String()
+}
+
+// NormalizeContent rewrites Content in place to one of the forms the SDK
+// builds and decodes: nil, a string, or []ContentPart. Content that is not
+// recognizable as text or content parts, such as a number, is left unchanged.
+func (m *Message) NormalizeContent() {
+	switch content := m.Content.(type) {
+	case nil, string, []ContentPart:
+		return
+	case *string:
+		if content == nil {
+			m.Content = nil
+		} else {
+			m.Content = *content
+		}
+		return
+	}
+	if parts := m.ContentAsParts(); parts != nil {
+		m.Content = parts
+	}
+}
+
+// UnmarshalJSON decodes content arrays into []ContentPart, so a decoded message
+// carries the same Content type it would be built with. String content stays a
+// string.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	type messageAlias Message
+	aux := struct {
+		*messageAlias
+		Content json.RawMessage `json:"content"`
+	}{messageAlias: (*messageAlias)(m)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	raw := bytes.TrimSpace(aux.Content)
+	switch {
+	case len(raw) == 0 || bytes.Equal(raw, []byte("null")):
+		m.Content = nil
+	case raw[0] == '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return err
+		}
+		m.Content = s
+	case raw[0] == '[':
+		var parts []ContentPart
+		if err := json.Unmarshal(raw, &parts); err != nil {
+			return fmt.Errorf("decode content parts: %w", err)
+		}
+		m.Content = parts
+	default:
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		m.Content = v
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package hackeserasdk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestContentConstructors(t *testing.T) {
+	msg := Message{Role: "user", Content: []ContentPart{
+		TextContent("What is in this screenshot?"),
+		ImageURLContent("https://example.com/s.png", ImageDetailHigh),
+	}}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	want := `{"role":"user","content":[{"type":"text","text":"What is in this screenshot?"},` +
+		`{"type":"image_url","image_url":{"url":"https://example.com/s.png","detail":"high"}}]}`
+	if string(data) != want {
+		t.Errorf("unexpected JSON:\n%s", data)
+	}
+}
+
+func TestMessageContentRoundTrip(t *testing.T) {
+	var msg Message
+	err := json.Unmarshal([]byte(`{"role":"assistant","content":[{"type":"text","text":"a cat"},{"type":"image_url","image_url":{"url":"data:image/png;base64,AA=="}}]}`), &msg)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	parts, ok := msg.Content.([]ContentPart)
+	if !ok || len(parts) != 2 || parts[1].ImageURL.URL != "data:image/png;base64,AA==" {
+		t.Fatalf("expected []ContentPart content, got %#v", msg.Content)
+	}
+	if len(msg.Parts()) != 2 {
+		t.Errorf("expected Parts to return content parts, got %+v", msg.Parts())
+	}
+
+	if err := json.Unmarshal([]byte(`{"role":"user","content":"hi","name":"alice"}`), &msg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if msg.Content != "hi" || msg.Name != "alice" {
+		t.Errorf("expected string content and other fields decoded, got %+v", msg)
+	}
+	if parts := msg.Parts(); len(parts) != 1 || parts[0].Text != "hi" {
+		t.Errorf("expected string content as a single text part, got %+v", parts)
+	}
+
+	if err := json.Unmarshal([]byte(`{"role":"assistant","content":null,"tool_calls":[{"id":"c1","type":"function","function":{"name":"f","arguments":"{}"}}]}`), &msg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if msg.Content != nil || len(msg.ToolCalls) != 1 {
+		t.Errorf("expected nil content with tool calls, got %+v", msg)
+	}
+}
+
+func TestMessageContentNormalization(t *testing.T) {
+	generic := Message{Role: "user", Content: []interface{}{
+		map[string]interface{}{"type": "text", "text": "Compare "},
+		map[string]interface{}{"type": "image_url", "image_url": map[string]interface{}{"url": "https://example.com/a.png"}},
+		map[string]interface{}{"type": "text", "text": "these"},
+	}}
+	if got := generic.ContentAsText(); got != "Compare these" {
+		t.Errorf("unexpected text %q", got)
+	}
+	if parts := generic.ContentAsParts(); len(parts) != 3 || parts[1].ImageURL == nil || parts[1].ImageURL.URL != "https://example.com/a.png" {
+		t.Errorf("expected generic parts converted, got %+v", parts)
+	}
+	generic.NormalizeContent()
+	if _, ok := generic.Content.([]ContentPart); !ok {
+		t.Errorf("expected NormalizeContent to produce []ContentPart, got %T", generic.Content)
+	}
+
+	single := Message{Content: map[string]interface{}{"type": "text", "text": "one part"}}
+	if got := single.ContentAsText(); got != "one part" {
+		t.Errorf("expected a single part object to convert, got %q", got)
+	}
+	text := "plain"
+	ptr := Message{Content: &text}
+	if got := ptr.ContentAsText(); got != "plain" {
+		t.Errorf("expected *string text, got %q", got)
+	}
+	if parts := ptr.ContentAsParts(); len(parts) != 1 || parts[0].Text != "plain" {
+		t.Errorf("expected *string as one text part, got %+v", parts)
+	}
+	ptr.NormalizeContent()
+	if ptr.Content != "plain" {
+		t.Errorf("expected *string to normalize to string, got %#v", ptr.Content)
+	}
+
+	for _, m := range []Message{{}, {Content: ""}, {Content: 42}} {
+		if m.ContentAsText() != "" || m.ContentAsParts() != nil {
+			t.Errorf("expected no content from %#v", m.Content)
+		}
+	}
+	for _, content := range []interface{}{42, map[string]interface{}{"text": "no type"}} {
+		odd := Message{Content: content}
+		odd.NormalizeContent()
+		if fmt.Sprint(odd.Content) != fmt.Sprint(content) {
+			t.Errorf("expected unrecognized content left unchanged, got %#v", odd.Content)
+		}
+	}
+}
+
+func TestImageFromFile(t *testing.T) {
+	dir := t.TempDir()
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	path := filepath.Join(dir, "shot.bin")
+	os.WriteFile(path, png, 0o600)
+
+	part, err := ImageFromFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+	if part.Type != ContentTypeImageURL || part.ImageURL.URL != want {
+		t.Errorf("unexpected part: %+v", part)
+	}
+
+	text := filepath.Join(dir, "notes.txt")
+	os.WriteFile(text, []byte("not an image"), 0o600)
+	if _, err := ImageFromFile(text); err == nil || !strings.Contains(err.Error(), "not a recognized image") {
+		t.Errorf("expected unrecognized image error, got %v", err)
+	}
+	if _, err := ImageFromFile(filepath.Join(dir, "missing.png")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
@@ -0,0 +1,189 @@
+package hackeserasdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ─── Degraded Mode ──────────────────────────────────────────────────────────
+
+// ErrUploadQueued is returned by UploadDocument when the server is degraded and
+// the DegradedPolicy queues uploads. The upload is sent once the server is healthy.
+var ErrUploadQueued = errors.New("document upload queued until the server is healthy")
+
+// defaultHealthCheckInterval is how often the health status is refreshed in degraded mode.
+const defaultHealthCheckInterval = 30 * time.Second
+
+// DegradedPolicy configures what the client changes while Health reports
+// status "degraded".
+type DegradedPolicy struct {
+	// PreferLiteModel sends chat requests to ModelLite.
+	PreferLiteModel bool
+	// DisableCognitive sets X-Cognitive-Disabled on every request.
+	DisableCognitive bool
+	// Timeout replaces the HTTP client timeout for non-streaming requests (0 keeps it).
+	Timeout time.Duration
+	// QueueUploads holds UploadDocument calls until the server is healthy again.
+	// Requests made with a WithUrgent context are never queued.
+	QueueUploads bool
+	// CheckInterval is how often health is refreshed before chat and upload
+	// requests. Defaults to 30s.
+	CheckInterval time.Duration
+	// OnUploadFlushed, if set, is called for each queued upload once it is sent.
+	OnUploadFlushed func(req DocumentUploadRequest, resp *DocumentResponse, err error)
+}
+
+type degradedMode struct {
+	policy DegradedPolicy
+
+	mu       sync.Mutex
+	degraded bool
+	checked  time.Time
+	queue    []DocumentUploadRequest
+}
+
+// WithDegradedMode makes the client follow policy while the server reports
+// status "degraded". Health is checked lazily, at most once per CheckInterval,
+// before chat and upload requests; every call to Health also updates the state.
+//
+//	client.WithDegradedMode(hackeserasdk.DegradedPolicy{
+//		PreferLiteModel:  true,
+//		DisableCognitive: true,
+//		QueueUploads:     true,
+//	})
+func (c *Client) WithDegradedMode(policy DegradedPolicy) *Client {
+	if policy.CheckInterval <= 0 {
+		policy.CheckInterval = defaultHealthCheckInterval
+	}
+	c.degraded = &degradedMode{policy: policy}
+	return c
+}
+
+// Degraded reports whether the last observed health status was "degraded".
+// It is always false without WithDegradedMode.
+func (c *Client) Degraded() bool {
+	if c.degraded == nil {
+		return false
+	}
+	c.degraded.mu.Lock()
+	defer c.degraded.mu.Unlock()
+	return c.degraded.degraded
+}
+
+// QueuedUploads returns the number of uploads waiting for the server to recover.
+func (c *Client) QueuedUploads() int {
+	if c.degraded == nil {
+		return 0
+	}
+	c.degraded.mu.Lock()
+	defer c.degraded.mu.Unlock()
+	return len(c.degraded.queue)
+}
+
+// FlushQueuedUploads sends all queued uploads now, regardless of health, and
+// returns the first error. OnUploadFlushed is called for each upload.
+func (c *Client) FlushQueuedUploads(ctx context.Context) error {
+	if c.degraded == nil {
+		return nil
+	}
+	c.degraded.mu.Lock()
+	queue := c.degraded.queue
+	c.degraded.queue = nil
+	c.degraded.mu.Unlock()
+
+	var firstErr error
+	ctx = WithUrgent(ctx)
+	for _, req := range queue {
+		resp, err := c.UploadDocument(ctx, req)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if c.degraded.policy.OnUploadFlushed != nil {
+			c.degraded.policy.OnUploadFlushed(req, resp, err)
+		}
+	}
+	return firstErr
+}
+
+type urgentKey struct{}
+
+// WithUrgent returns a context whose requests bypass degraded-mode upload queueing.
+func WithUrgent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, urgentKey{}, true)
+}
+
+func isUrgent(ctx context.Context) bool {
+	urgent, _ := ctx.Value(urgentKey{}).(bool)
+	return urgent
+}
+
+// observeHealth records a health status. Recovering from degraded flushes the
+// upload queue in the background.
+func (c *Client) observeHealth(status string) {
+	if c.degraded == nil {
+		return
+	}
+	c.degraded.mu.Lock()
+	wasDegraded := c.degraded.degraded
+	c.degraded.degraded = status == "degraded"
+	c.degraded.checked = time.Now()
+	flush := wasDegraded && !c.degraded.degraded && len(c.degraded.queue) > 0
+	c.degraded.mu.Unlock()
+
+	if flush {
+		go c.FlushQueuedUploads(context.Background())
+	}
+}
+
+// checkDegraded refreshes the health status if it is stale and reports whether
+// the server is degraded. A failed health check keeps the previous state.
+func (c *Client) checkDegraded(ctx context.Context) bool {
+	m := c.degraded
+	if m == nil {
+		return false
+	}
+	m.mu.Lock()
+	stale := time.Since(m.checked) >= m.policy.CheckInterval
+	if stale {
+		// Claim the check so concurrent requests don't all probe health.
+		m.checked = time.Now()
+	}
+	m.mu.Unlock()
+
+	if stale {
+		c.Health(ctx)
+	}
+	return c.Degraded()
+}
+
+// degradeChat applies the chat parts of the degraded policy to req.
+func (c *Client) degradeChat(ctx context.Context, req *ChatRequest) {
+	if c.checkDegraded(ctx) && c.degraded.policy.PreferLiteModel {
+		req.Model = ModelLite
+	}
+}
+
+// queueUpload queues req if the policy holds uploads while degraded. Nothing is
+// queued after Shutdown, which has already flushed the queue.
+func (c *Client) queueUpload(ctx context.Context, req DocumentUploadRequest) bool {
+	if c.degraded == nil || !c.degraded.policy.QueueUploads || isUrgent(ctx) || c.shutdown.isClosed() || !c.checkDegraded(ctx) {
+		return false
+	}
+	c.degraded.mu.Lock()
+	c.degraded.queue = append(c.degraded.queue, req)
+	c.degraded.mu.Unlock()
+	return true
+}
+
+// degradedHTTPClient returns the HTTP client to use for non-streaming requests.
+func (c *Client) degradedHTTPClient() *http.Client {
+	if c.degraded == nil || c.degraded.policy.Timeout <= 0 || !c.Degraded() {
+		return c.httpClient
+	}
+	hc := *c.httpClient
+	hc.Timeout = c.degraded.policy.Timeout
+	return &hc
+}
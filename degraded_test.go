@@ -0,0 +1,105 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newDegradedServer reports status from /health and accepts uploads.
+func newDegradedServer(t *testing.T, status *atomic.Value) *scriptedServer {
+	t.Helper()
+	return newScriptedServer(t).
+		handle("/health", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(HealthResponse{Status: status.Load().(string)})
+		}).
+		handle("/v1/documents", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(DocumentResponse{ID: "doc-1", Status: "processing"})
+		})
+}
+
+func TestDegradedModeChat(t *testing.T) {
+	var status atomic.Value
+	status.Store("degraded")
+	srv := newDegradedServer(t, &status)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithDegradedMode(DegradedPolicy{
+		PreferLiteModel:  true,
+		DisableCognitive: true,
+		CheckInterval:    time.Hour,
+	})
+	ctx := context.Background()
+
+	resp, err := client.ChatCompletion(ctx, ChatRequest{Model: ModelPro, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cognitive := srv.last("/v1/chat/completions").Header.Get("X-Cognitive-Disabled"); resp.Model != ModelLite || cognitive != "true" {
+		t.Errorf("expected lite model with cognitive disabled, got model %q cognitive-disabled %q", resp.Model, cognitive)
+	}
+	if !client.Degraded() {
+		t.Error("expected client to report degraded")
+	}
+
+	status.Store("ok")
+	if _, err := client.Health(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err = client.ChatCompletion(ctx, ChatRequest{Model: ModelPro, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cognitive := srv.last("/v1/chat/completions").Header.Get("X-Cognitive-Disabled"); resp.Model != ModelPro || cognitive != "" {
+		t.Errorf("expected normal behavior once healthy, got model %q cognitive-disabled %q", resp.Model, cognitive)
+	}
+}
+
+func TestDegradedModeQueuesUploads(t *testing.T) {
+	var status atomic.Value
+	status.Store("degraded")
+	srv := newDegradedServer(t, &status)
+	defer srv.Close()
+
+	flushed := make(chan error, 1)
+	client := NewClient(srv.URL, "test-key").WithDegradedMode(DegradedPolicy{
+		QueueUploads:  true,
+		CheckInterval: time.Hour,
+		OnUploadFlushed: func(req DocumentUploadRequest, resp *DocumentResponse, err error) {
+			flushed <- err
+		},
+	})
+	ctx := context.Background()
+
+	_, err := client.UploadDocument(ctx, DocumentUploadRequest{Content: "runbook", Filename: "a.md"})
+	if !errors.Is(err, ErrUploadQueued) {
+		t.Fatalf("expected ErrUploadQueued, got %v", err)
+	}
+	if _, err := client.UploadDocument(WithUrgent(ctx), DocumentUploadRequest{Content: "incident", Filename: "b.md"}); err != nil {
+		t.Fatalf("expected urgent upload to be sent, got %v", err)
+	}
+	if client.QueuedUploads() != 1 || srv.count("/v1/documents") != 1 {
+		t.Fatalf("expected 1 queued and 1 sent upload, got %d / %d", client.QueuedUploads(), srv.count("/v1/documents"))
+	}
+
+	status.Store("ok")
+	client.Health(ctx)
+	select {
+	case err := <-flushed:
+		if err != nil {
+			t.Errorf("unexpected flush error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected queued upload to flush after recovery")
+	}
+	if client.QueuedUploads() != 0 || srv.count("/v1/documents") != 2 {
+		t.Errorf("expected queue to drain, got %d queued / %d sent", client.QueuedUploads(), srv.count("/v1/documents"))
+	}
+}
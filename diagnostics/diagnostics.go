@@ -0,0 +1,426 @@
+// Package diagnostics runs the HackersEra AI deployment verification suite
+// against a live API, so operators can embed it in their own health tooling.
+//
+//	report := diagnostics.RunSuite(ctx, client, diagnostics.Options{})
+//	for _, s := range report.Steps {
+//		fmt.Printf("%2d. %-24s %s %s\n", s.Number, s.Name, s.Status, s.Detail)
+//	}
+//	if !report.OK() {
+//		os.Exit(1)
+//	}
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/hackersera-dev-team/hackersera-ai-sdk"
+)
+
+// Step statuses.
+const (
+	StatusPass = "pass"
+	StatusFail = "fail"
+	StatusSkip = "skip"
+)
+
+// Options configures RunSuite.
+type Options struct {
+	// UserID is the identity used for chat, profile, and feedback steps.
+	// Defaults to "diagnostics-user".
+	UserID string
+	// ReadOnly skips every step that writes data (chat and streaming, which
+	// create conversations, document upload, feedback, profile update, facts,
+	// and the cleanup deletes).
+	ReadOnly bool
+	// IndexTimeout bounds the wait for the uploaded test document to be indexed.
+	// Defaults to 5 seconds. A document still processing does not fail the step.
+	IndexTimeout time.Duration
+	// OnStep, if set, is called as each step finishes, e.g. to print progress.
+	OnStep func(StepResult)
+}
+
+// StepResult is the outcome of one verification step.
+type StepResult struct {
+	Number   int           `json:"number"`
+	Name     string        `json:"name"`
+	Status   string        `json:"status"`
+	Detail   string        `json:"detail,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Report is the outcome of RunSuite.
+type Report struct {
+	Steps    []StepResult  `json:"steps"`
+	Passed   int           `json:"passed"`
+	Failed   int           `json:"failed"`
+	Skipped  int           `json:"skipped"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// OK reports whether no step failed.
+func (r *Report) OK() bool { return r.Failed == 0 }
+
+// skipError marks a step that could not run, e.g. because an earlier step
+// produced nothing to act on.
+type skipError string
+
+func (e skipError) Error() string { return string(e) }
+
+// suite carries state between steps.
+type suite struct {
+	client *sdk.Client
+	opts   Options
+
+	conversationID string
+	doc            *sdk.DocumentResponse
+	fact           *sdk.Fact
+}
+
+type step struct {
+	name   string
+	writes bool
+	run    func(ctx context.Context, s *suite) (string, error)
+}
+
+// RunSuite executes the 28-step deployment verification: health and readiness,
+// models, chat and streaming, embeddings, documents and search, feedback,
+// conversations, profiles, facts, the knowledge graph, usage, cache, and
+// metrics, then deletes the document and conversation it created. Steps run in
+// order and a failure does not stop the suite; steps that depend on an earlier
+// result are skipped when it is missing.
+func RunSuite(ctx context.Context, client *sdk.Client, opts Options) *Report {
+	if opts.UserID == "" {
+		opts.UserID = "diagnostics-user"
+	}
+	if opts.IndexTimeout <= 0 {
+		opts.IndexTimeout = 5 * time.Second
+	}
+
+	s := &suite{client: client, opts: opts}
+	report := &Report{}
+	start := time.Now()
+
+	for i, st := range steps {
+		result := StepResult{Number: i + 1, Name: st.name}
+		began := time.Now()
+		if st.writes && opts.ReadOnly {
+			result.Status, result.Detail = StatusSkip, "read-only run"
+		} else {
+			detail, err := st.run(ctx, s)
+			result.Detail = detail
+			switch err.(type) {
+			case nil:
+				result.Status = StatusPass
+			case skipError:
+				result.Status, result.Detail = StatusSkip, err.Error()
+			default:
+				result.Status, result.Error = StatusFail, err.Error()
+			}
+		}
+		result.Duration = time.Since(began)
+
+		switch result.Status {
+		case StatusPass:
+			report.Passed++
+		case StatusFail:
+			report.Failed++
+		default:
+			report.Skipped++
+		}
+		report.Steps = append(report.Steps, result)
+		if opts.OnStep != nil {
+			opts.OnStep(result)
+		}
+	}
+
+	report.Duration = time.Since(start)
+	return report
+}
+
+var steps = []step{
+	{name: "Health", run: func(ctx context.Context, s *suite) (string, error) {
+		health, err := s.client.Health(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("status %s, version %s", health.Status, health.Version), nil
+	}},
+	{name: "Readiness", run: func(ctx context.Context, s *suite) (string, error) {
+		ready, err := s.client.Ready(ctx)
+		if err != nil {
+			return "", err
+		}
+		if !ready.Ready {
+			var failed []string
+			for _, c := range ready.Failed() {
+				failed = append(failed, c.Name+"="+c.Status)
+			}
+			return "", fmt.Errorf("not ready: %s", strings.Join(failed, ", "))
+		}
+		return fmt.Sprintf("%d checks ok", len(ready.Checks)), nil
+	}},
+	{name: "List models", run: func(ctx context.Context, s *suite) (string, error) {
+		models, err := s.client.ListModels(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d models", len(models.Data)), nil
+	}},
+	{name: "Get model", run: func(ctx context.Context, s *suite) (string, error) {
+		model, err := s.client.GetModel(ctx, sdk.ModelDefault)
+		if err != nil {
+			return "", err
+		}
+		return model.ID, nil
+	}},
+	{name: "Chat completion", writes: true, run: func(ctx context.Context, s *suite) (string, error) {
+		resp, err := s.client.ChatCompletionWithOptions(ctx, sdk.ChatRequest{
+			Model:    sdk.ModelDefault,
+			Messages: []sdk.Message{{Role: "user", Content: "Say 'Hello from HackersEra AI!' and nothing else."}},
+			User:     s.opts.UserID,
+		}, sdk.RequestOptions{UserID: s.opts.UserID})
+		if err != nil {
+			return "", err
+		}
+		if len(resp.Choices) == 0 {
+			return "", fmt.Errorf("no choices in response")
+		}
+		s.conversationID = resp.ConversationID
+		return fmt.Sprintf("conversation %s, %d tokens", resp.ConversationID, resp.Usage.TotalTokens), nil
+	}},
+	{name: "Streaming", writes: true, run: func(ctx context.Context, s *suite) (string, error) {
+		stream, err := s.client.StreamChat(ctx, sdk.ChatRequest{
+			Model:    sdk.ModelDefault,
+			Messages: []sdk.Message{{Role: "user", Content: "Count from 1 to 3, one number per line."}},
+		})
+		if err != nil {
+			return "", err
+		}
+		defer stream.Close()
+		chunks := 0
+		for {
+			if _, err := stream.Next(); err != nil {
+				if stream.Err() != nil {
+					return "", stream.Err()
+				}
+				break
+			}
+			chunks++
+		}
+		return fmt.Sprintf("%d chunks", chunks), nil
+	}},
+	{name: "Embeddings", run: func(ctx context.Context, s *suite) (string, error) {
+		emb, err := s.client.CreateEmbedding(ctx, sdk.EmbeddingRequest{Input: "Hello world", Model: sdk.ModelEmbedding})
+		if err != nil {
+			return "", err
+		}
+		if len(emb.Data) == 0 {
+			return "", fmt.Errorf("no embeddings in response")
+		}
+		return fmt.Sprintf("%d dimensions", len(emb.Data[0].Embedding)), nil
+	}},
+	{name: "Document upload", writes: true, run: func(ctx context.Context, s *suite) (string, error) {
+		doc, err := s.client.UploadDocument(ctx, sdk.DocumentUploadRequest{
+			Content:  "HackersEra is an Indian cybersecurity company founded in 2018 that provides AI-powered penetration testing and vulnerability assessment services.",
+			Filename: "diagnostics-doc.txt",
+			Tags:     map[string]string{"test": "deployment"},
+		})
+		if err != nil {
+			return "", err
+		}
+		s.doc = doc
+
+		deadline := time.Now().Add(s.opts.IndexTimeout)
+		for time.Now().Before(deadline) {
+			d, err := s.client.GetDocument(ctx, doc.ID)
+			if err == nil && d.Status == "indexed" {
+				return fmt.Sprintf("%s indexed (%d chunks)", doc.ID, d.ChunkCount), nil
+			}
+			if err == nil && d.Status == "failed" {
+				return "", fmt.Errorf("indexing %s failed: %s", doc.ID, d.Error)
+			}
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+		return fmt.Sprintf("%s still processing", doc.ID), nil
+	}},
+	{name: "List documents", run: func(ctx context.Context, s *suite) (string, error) {
+		docs, err := s.client.ListDocuments(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d documents", docs.Total), nil
+	}},
+	{name: "Search", run: func(ctx context.Context, s *suite) (string, error) {
+		results, err := s.client.Search(ctx, sdk.SearchRequest{Query: "cybersecurity penetration testing"})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d results", results.Total), nil
+	}},
+	{name: "Feedback", writes: true, run: func(ctx context.Context, s *suite) (string, error) {
+		if s.conversationID == "" {
+			return "", skipError("no conversation ID available")
+		}
+		fb, err := s.client.SubmitFeedback(ctx, sdk.FeedbackRequest{
+			ConversationID: s.conversationID,
+			Rating:         1,
+			Comment:        "Deployment diagnostics feedback",
+		})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("feedback %d", fb.ID), nil
+	}},
+	{name: "List conversations", run: func(ctx context.Context, s *suite) (string, error) {
+		convos, err := s.client.ListConversations(ctx, 5)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d conversations", convos.Total), nil
+	}},
+	{name: "Get conversation", run: func(ctx context.Context, s *suite) (string, error) {
+		if s.conversationID == "" {
+			return "", skipError("no conversation ID available")
+		}
+		detail, err := s.client.GetConversation(ctx, s.conversationID)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d turns", detail.TurnCount), nil
+	}},
+	{name: "Search conversations", run: func(ctx context.Context, s *suite) (string, error) {
+		results, err := s.client.SearchConversations(ctx, "Hello", 5)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d results", results.Total), nil
+	}},
+	{name: "Get profile", run: func(ctx context.Context, s *suite) (string, error) {
+		profile, err := s.client.GetProfile(ctx, s.opts.UserID)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d queries", profile.TotalQueries), nil
+	}},
+	{name: "Update profile", writes: true, run: func(ctx context.Context, s *suite) (string, error) {
+		profile, err := s.client.UpdateProfile(ctx, s.opts.UserID, sdk.ProfileUpdateRequest{
+			DisplayName: "Diagnostics User",
+			Preferences: map[string]string{"detail_level": "concise"},
+		})
+		if err != nil {
+			return "", err
+		}
+		return profile.DisplayName, nil
+	}},
+	{name: "Create fact", writes: true, run: func(ctx context.Context, s *suite) (string, error) {
+		fact, err := s.client.CreateFact(ctx, sdk.FactCreateRequest{
+			Content:    "HackersEra SDK supports Go, Python, and Node.js",
+			Source:     "manual",
+			Confidence: 0.9,
+			Verified:   true,
+		})
+		if err != nil {
+			return "", err
+		}
+		s.fact = fact
+		return fmt.Sprintf("fact %d", fact.ID), nil
+	}},
+	{name: "Batch create facts", writes: true, run: func(ctx context.Context, s *suite) (string, error) {
+		facts, err := s.client.CreateFacts(ctx, []sdk.FactCreateRequest{
+			{Content: "Go SDK uses zero external dependencies", Source: "docs", Confidence: 0.95},
+			{Content: "API is OpenAI-compatible", Source: "docs", Confidence: 0.99, Verified: true},
+		})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d facts", facts.Total), nil
+	}},
+	{name: "List facts", run: func(ctx context.Context, s *suite) (string, error) {
+		facts, err := s.client.ListFacts(ctx, 10, nil)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d facts", facts.Total), nil
+	}},
+	{name: "Update fact", writes: true, run: func(ctx context.Context, s *suite) (string, error) {
+		if s.fact == nil {
+			return "", skipError("no fact created")
+		}
+		fact, err := s.client.UpdateFact(ctx, s.fact.ID, sdk.FactUpdateRequest{
+			Verified:   sdk.BoolPtr(true),
+			Confidence: sdk.Float64Ptr(0.99),
+		})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("fact %d confidence %.2f", fact.ID, fact.Confidence), nil
+	}},
+	{name: "Knowledge graph", run: func(ctx context.Context, s *suite) (string, error) {
+		graph, err := s.client.QueryKnowledgeGraph(ctx, "cybersecurity", 10)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d nodes, %d edges", graph.Total, len(graph.Edges)), nil
+	}},
+	{name: "Cognitive stats", run: func(ctx context.Context, s *suite) (string, error) {
+		stats, err := s.client.GetCognitiveStats(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d conversations, %d facts", stats.TotalConversations, stats.TotalLearnedFacts), nil
+	}},
+	{name: "Usage", run: func(ctx context.Context, s *suite) (string, error) {
+		usage, err := s.client.GetUsage(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d requests, %d tokens", usage.TotalRequests, usage.TotalTokens), nil
+	}},
+	{name: "Recent usage", run: func(ctx context.Context, s *suite) (string, error) {
+		recent, err := s.client.GetRecentUsage(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d records", recent.Count), nil
+	}},
+	{name: "Cache stats", run: func(ctx context.Context, s *suite) (string, error) {
+		stats, err := s.client.GetCacheStats(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d entries, %d hits", stats.ActiveEntries, stats.TotalHits), nil
+	}},
+	{name: "Metrics", run: func(ctx context.Context, s *suite) (string, error) {
+		metrics, err := s.client.GetMetrics(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d bytes", len(metrics)), nil
+	}},
+	{name: "Delete document", writes: true, run: func(ctx context.Context, s *suite) (string, error) {
+		if s.doc == nil {
+			return "", skipError("no document uploaded")
+		}
+		if _, err := s.client.DeleteDocument(ctx, s.doc.ID); err != nil {
+			return "", err
+		}
+		return s.doc.ID, nil
+	}},
+	{name: "Delete conversation", writes: true, run: func(ctx context.Context, s *suite) (string, error) {
+		if s.conversationID == "" {
+			return "", skipError("no conversation ID available")
+		}
+		if _, err := s.client.DeleteConversation(ctx, s.conversationID); err != nil {
+			return "", err
+		}
+		return s.conversationID, nil
+	}},
+}
@@ -0,0 +1,136 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sdk "github.com/hackersera-dev-team/hackersera-ai-sdk"
+)
+
+// newFakeBackend answers every suite endpoint with a minimal successful body.
+// Paths listed in failing return 500.
+func newFakeBackend(t *testing.T, failing map[string]bool, hits map[string]int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		hits[key]++
+		if failing[r.URL.Path] {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error":{"message":"boom","type":"server_error"}}`)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+		switch {
+		case path == "/ready":
+			json.NewEncoder(w).Encode(sdk.ReadyResponse{Ready: true})
+		case path == "/metrics":
+			fmt.Fprint(w, "requests_total 1\n")
+		case path == "/v1/chat/completions" && isStreamRequest(r):
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"1\"}}]}\n\ndata: [DONE]\n\n")
+		case path == "/v1/chat/completions":
+			json.NewEncoder(w).Encode(sdk.ChatResponse{
+				ConversationID: "conv-1",
+				Choices:        []sdk.Choice{{Message: sdk.Message{Role: "assistant", Content: "Hello"}}},
+			})
+		case path == "/v1/embeddings":
+			json.NewEncoder(w).Encode(sdk.EmbeddingResponse{Data: []sdk.EmbeddingData{{Embedding: []float64{0.1, 0.2}}}})
+		case path == "/v1/documents" && r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(sdk.DocumentResponse{ID: "doc-1", Status: "processing"})
+		case strings.HasPrefix(path, "/v1/documents/") && r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(sdk.DocumentResponse{ID: "doc-1", Status: "indexed", ChunkCount: 1})
+		case path == "/v1/feedback":
+			json.NewEncoder(w).Encode(sdk.FeedbackResponse{ID: 3, Rating: 1})
+		case path == "/v1/knowledge/facts" && r.Method == http.MethodPost:
+			json.NewEncoder(w).Encode(sdk.Fact{ID: 7})
+		case strings.HasPrefix(path, "/v1/knowledge/facts/") && r.Method != http.MethodPost:
+			json.NewEncoder(w).Encode(sdk.Fact{ID: 7, Confidence: 0.99})
+		default:
+			fmt.Fprint(w, `{"id":"x","status":"ok","deleted":true}`)
+		}
+	}))
+}
+
+func isStreamRequest(r *http.Request) bool {
+	var req sdk.ChatRequest
+	json.NewDecoder(r.Body).Decode(&req)
+	return req.Stream
+}
+
+func TestRunSuite(t *testing.T) {
+	hits := map[string]int{}
+	srv := newFakeBackend(t, nil, hits)
+	defer srv.Close()
+
+	var seen []StepResult
+	report := RunSuite(context.Background(), sdk.NewClient(srv.URL, "test-key"), Options{
+		OnStep: func(s StepResult) { seen = append(seen, s) },
+	})
+
+	if len(report.Steps) != 28 || len(seen) != 28 {
+		t.Fatalf("expected 28 steps, got %d (%d reported)", len(report.Steps), len(seen))
+	}
+	if !report.OK() || report.Passed != 28 {
+		for _, s := range report.Steps {
+			if s.Status != StatusPass {
+				t.Logf("%d. %s: %s %s %s", s.Number, s.Name, s.Status, s.Detail, s.Error)
+			}
+		}
+		t.Fatalf("expected all steps to pass, got %d passed, %d failed, %d skipped", report.Passed, report.Failed, report.Skipped)
+	}
+	if hits["DELETE /v1/documents/doc-1"] != 1 || hits["DELETE /v1/conversations/conv-1"] != 1 {
+		t.Errorf("expected cleanup of created document and conversation, got %v", hits)
+	}
+}
+
+func TestRunSuiteFailuresAndSkips(t *testing.T) {
+	hits := map[string]int{}
+	srv := newFakeBackend(t, map[string]bool{"/v1/chat/completions": true}, hits)
+	defer srv.Close()
+
+	report := RunSuite(context.Background(), sdk.NewClient(srv.URL, "test-key"), Options{})
+	if report.OK() {
+		t.Fatal("expected failed report")
+	}
+
+	byName := map[string]StepResult{}
+	for _, s := range report.Steps {
+		byName[s.Name] = s
+	}
+	if s := byName["Chat completion"]; s.Status != StatusFail || !strings.Contains(s.Error, "boom") {
+		t.Errorf("expected chat failure, got %+v", s)
+	}
+	for _, name := range []string{"Feedback", "Get conversation", "Delete conversation"} {
+		if s := byName[name]; s.Status != StatusSkip {
+			t.Errorf("expected %s to be skipped without a conversation, got %+v", name, s)
+		}
+	}
+	if s := byName["Embeddings"]; s.Status != StatusPass {
+		t.Errorf("expected later steps to keep running, got %+v", s)
+	}
+}
+
+func TestRunSuiteReadOnly(t *testing.T) {
+	hits := map[string]int{}
+	srv := newFakeBackend(t, nil, hits)
+	defer srv.Close()
+
+	report := RunSuite(context.Background(), sdk.NewClient(srv.URL, "test-key"), Options{ReadOnly: true})
+	// The 10 write steps, plus Get conversation, which has no conversation.
+	if !report.OK() || report.Skipped != 11 {
+		t.Errorf("expected 11 steps skipped, got %d skipped, %d failed", report.Skipped, report.Failed)
+	}
+	for key := range hits {
+		if strings.HasPrefix(key, "DELETE ") || key == "POST /v1/documents" || key == "POST /v1/knowledge/facts" || key == "POST /v1/chat/completions" {
+			t.Errorf("expected no writes in read-only mode, got %s", key)
+		}
+	}
+}
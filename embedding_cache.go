@@ -0,0 +1,246 @@
+package hackeserasdk
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ─── Embedding Cache ────────────────────────────────────────────────────────
+
+const defaultEmbeddingCacheEntries = 10000
+
+// EmbeddingCacheStore holds cached vectors in their encoded form. Implement it
+// over Redis or a database to share the cache between processes; the default
+// is an in-memory LRU (NewLRUEmbeddingStore). Errors are the store's concern:
+// a failed Get is a miss and a failed Set is dropped.
+type EmbeddingCacheStore interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	// Set stores value for ttl; a ttl of 0 means no expiry.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration)
+}
+
+// EmbeddingCacheConfig configures WithEmbeddingCache.
+type EmbeddingCacheConfig struct {
+	// MaxEntries bounds the default in-memory store. Defaults to 10000.
+	MaxEntries int
+	// TTL expires entries, for example after a model is retrained under the
+	// same name. Zero keeps entries until evicted.
+	TTL time.Duration
+	// Store replaces the in-memory store; MaxEntries is then unused.
+	Store EmbeddingCacheStore
+}
+
+type embeddingCache struct {
+	store EmbeddingCacheStore
+	ttl   time.Duration
+}
+
+// WithEmbeddingCache caches embeddings per input text, keyed by a hash of the
+// model, Dimensions, and text, so re-embedding unchanged texts skips the
+// network and costs no tokens. When only some inputs of a batch are cached,
+// just the misses are sent; Usage then counts only them. Requests whose Input
+// is not a string or []string are not cached.
+func (c *Client) WithEmbeddingCache(cfg EmbeddingCacheConfig) *Client {
+	store := cfg.Store
+	if store == nil {
+		store = NewLRUEmbeddingStore(cfg.MaxEntries)
+	}
+	c.embeddingCache = &embeddingCache{store: store, ttl: cfg.TTL}
+	return c
+}
+
+// embeddingInputs returns the texts of a string or []string Input.
+func embeddingInputs(input interface{}) ([]string, bool) {
+	switch v := input.(type) {
+	case string:
+		return []string{v}, true
+	case []string:
+		return v, true
+	default:
+		return nil, false
+	}
+}
+
+func embeddingCacheKey(req EmbeddingRequest, input string) string {
+	h := sha256.New()
+	h.Write([]byte(req.Model))
+	h.Write([]byte{0})
+	if req.Dimensions != nil {
+		h.Write([]byte(strconv.Itoa(*req.Dimensions)))
+	}
+	h.Write([]byte{0})
+	h.Write([]byte(input))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// createEmbeddingCached serves inputs from the cache and embeds the rest in a
+// single request.
+func (c *Client) createEmbeddingCached(ctx context.Context, req EmbeddingRequest, opts RequestOptions, inputs []string) (*EmbeddingResponse, error) {
+	cache := c.embeddingCache
+	out := &EmbeddingResponse{Object: "list", Model: req.Model, Data: make([]EmbeddingData, len(inputs))}
+	keys := make([]string, len(inputs))
+	var missing []int
+	for i, input := range inputs {
+		keys[i] = embeddingCacheKey(req, input)
+		out.Data[i] = EmbeddingData{Object: "embedding", Index: i}
+		if value, ok := cache.store.Get(ctx, keys[i]); ok && decodeCachedVector(value, req.Float32, &out.Data[i]) {
+			continue
+		}
+		missing = append(missing, i)
+	}
+	if len(missing) == 0 {
+		return out, nil
+	}
+
+	batch := make([]string, len(missing))
+	for j, i := range missing {
+		batch[j] = inputs[i]
+	}
+	sent := req
+	sent.Input = batch
+	resp, err := c.createEmbedding(ctx, sent, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range resp.Data {
+		if d.Index < 0 || d.Index >= len(missing) {
+			continue
+		}
+		i := missing[d.Index]
+		d.Index = i
+		out.Data[i] = d
+		cache.store.Set(ctx, keys[i], encodeCachedVector(d), cache.ttl)
+	}
+	if resp.Model != "" {
+		out.Model = resp.Model
+	}
+	out.Usage = resp.Usage
+	out.RateLimit = resp.RateLimit
+	return out, nil
+}
+
+// encodeCachedVector packs a vector as a width byte (4 or 8) followed by
+// little-endian floats, so float32 vectors cost half as much to store.
+func encodeCachedVector(d EmbeddingData) []byte {
+	if d.Embedding32 != nil {
+		b := make([]byte, 1+4*len(d.Embedding32))
+		b[0] = 4
+		for i, v := range d.Embedding32 {
+			binary.LittleEndian.PutUint32(b[1+4*i:], math.Float32bits(v))
+		}
+		return b
+	}
+	b := make([]byte, 1+8*len(d.Embedding))
+	b[0] = 8
+	for i, v := range d.Embedding {
+		binary.LittleEndian.PutUint64(b[1+8*i:], math.Float64bits(v))
+	}
+	return b
+}
+
+// decodeCachedVector unpacks value into d in the form the request asked for.
+// It reports false for a malformed value, which is treated as a miss.
+func decodeCachedVector(value []byte, asFloat32 bool, d *EmbeddingData) bool {
+	if len(value) == 0 || (value[0] != 4 && value[0] != 8) || (len(value)-1)%int(value[0]) != 0 {
+		return false
+	}
+	width, body := int(value[0]), value[1:]
+	n := len(body) / width
+	at := func(i int) float64 {
+		if width == 4 {
+			return float64(math.Float32frombits(binary.LittleEndian.Uint32(body[4*i:])))
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(body[8*i:]))
+	}
+	if asFloat32 {
+		d.Embedding32 = make([]float32, n)
+		for i := range d.Embedding32 {
+			d.Embedding32[i] = float32(at(i))
+		}
+	} else {
+		d.Embedding = make([]float64, n)
+		for i := range d.Embedding {
+			d.Embedding[i] = at(i)
+		}
+	}
+	return true
+}
+
+// LRUEmbeddingStore is the in-memory EmbeddingCacheStore used by default: it
+// evicts the least recently used entry once full.
+type LRUEmbeddingStore struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List
+	entries map[string]*list.Element
+	now     func() time.Time
+}
+
+type lruEmbeddingEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRUEmbeddingStore returns a store holding up to maxEntries vectors;
+// maxEntries <= 0 defaults to 10000.
+func NewLRUEmbeddingStore(maxEntries int) *LRUEmbeddingStore {
+	if maxEntries <= 0 {
+		maxEntries = defaultEmbeddingCacheEntries
+	}
+	return &LRUEmbeddingStore{max: maxEntries, order: list.New(), entries: map[string]*list.Element{}, now: time.Now}
+}
+
+// Get implements EmbeddingCacheStore.
+func (s *LRUEmbeddingStore) Get(ctx context.Context, key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*lruEmbeddingEntry)
+	if !e.expires.IsZero() && !s.now().Before(e.expires) {
+		s.order.Remove(el)
+		delete(s.entries, key)
+		return nil, false
+	}
+	s.order.MoveToFront(el)
+	return e.value, true
+}
+
+// Set implements EmbeddingCacheStore.
+func (s *LRUEmbeddingStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var expires time.Time
+	if ttl > 0 {
+		expires = s.now().Add(ttl)
+	}
+	if el, ok := s.entries[key]; ok {
+		el.Value = &lruEmbeddingEntry{key: key, value: value, expires: expires}
+		s.order.MoveToFront(el)
+		return
+	}
+	s.entries[key] = s.order.PushFront(&lruEmbeddingEntry{key: key, value: value, expires: expires})
+	for s.order.Len() > s.max {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*lruEmbeddingEntry).key)
+	}
+}
+
+// Len returns the number of stored entries, including expired ones not yet
+// evicted.
+func (s *LRUEmbeddingStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
@@ -0,0 +1,96 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newEmbeddingServer embeds each input as [len(input), index] and records the
+// batches it receives.
+func newEmbeddingServer(t *testing.T, batches *[][]string) *scriptedServer {
+	t.Helper()
+	return newScriptedServer(t).handle("/v1/embeddings", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		*batches = append(*batches, req.Input)
+		resp := EmbeddingResponse{Object: "list", Model: ModelEmbedding, Usage: EmbeddingUsage{TotalTokens: len(req.Input)}}
+		for i, in := range req.Input {
+			resp.Data = append(resp.Data, EmbeddingData{Object: "embedding", Index: i, Embedding: []float64{float64(len(in)), float64(i)}})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	})
+}
+
+func TestEmbeddingCache(t *testing.T) {
+	var batches [][]string
+	srv := newEmbeddingServer(t, &batches)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithEmbeddingCache(EmbeddingCacheConfig{})
+	ctx := context.Background()
+	if _, err := client.CreateEmbedding(ctx, EmbeddingRequest{Model: ModelEmbedding, Input: []string{"a", "bb"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := client.CreateEmbedding(ctx, EmbeddingRequest{Model: ModelEmbedding, Input: []string{"bb", "ccc", "a"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batches) != 2 || len(batches[1]) != 1 || batches[1][0] != "ccc" {
+		t.Fatalf("expected only the uncached input to be sent, got %v", batches)
+	}
+	for i, want := range []float64{2, 3, 1} {
+		d := resp.Data[i]
+		if d.Index != i || d.Embedding[0] != want {
+			t.Errorf("data %d: expected the vector for its own input, got %+v", i, d)
+		}
+	}
+	if resp.Usage.TotalTokens != 1 {
+		t.Errorf("expected usage for the sent input only, got %+v", resp.Usage)
+	}
+
+	resp, err = client.CreateEmbedding(ctx, EmbeddingRequest{Model: ModelEmbedding, Input: "ccc", Float32: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batches) != 2 || resp.Data[0].Embedding32[0] != 3 || resp.Data[0].Embedding != nil {
+		t.Errorf("expected a cache hit in float32 form, got %+v after %d requests", resp.Data, len(batches))
+	}
+
+	// Model and Dimensions are part of the key.
+	srv.handle("/v1/embeddings/models/"+ModelEmbedding, http.NotFound)
+	client.CreateEmbedding(ctx, EmbeddingRequest{Model: ModelEmbedding, Input: "a", Dimensions: IntPtr(2)})
+	if len(batches) != 3 {
+		t.Errorf("expected a different Dimensions to miss, got %d requests", len(batches))
+	}
+}
+
+func TestLRUEmbeddingStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewLRUEmbeddingStore(2)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	store.Set(ctx, "a", []byte("1"), 0)
+	store.Set(ctx, "b", []byte("2"), time.Minute)
+	store.Get(ctx, "a")
+	store.Set(ctx, "c", []byte("3"), 0)
+	if _, ok := store.Get(ctx, "b"); ok {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+	if _, ok := store.Get(ctx, "a"); !ok || store.Len() != 2 {
+		t.Errorf("expected a and c kept, got %d entries", store.Len())
+	}
+
+	store.Set(ctx, "d", []byte("4"), time.Minute)
+	now = now.Add(2 * time.Minute)
+	if _, ok := store.Get(ctx, "d"); ok {
+		t.Error("expected an expired entry to miss")
+	}
+}
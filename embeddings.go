@@ -0,0 +1,135 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+)
+
+// ─── Embedding Dimensions ───────────────────────────────────────────────────
+
+// embeddingInfoCache holds GetEmbeddingInfo results per model for dimension
+// checks. A nil entry records a server without the info endpoint.
+type embeddingInfoCache struct {
+	mu     sync.Mutex
+	models map[string]*EmbeddingModelInfo
+}
+
+// checkDimensions rejects a Dimensions value model cannot produce, so a vector
+// store schema that disagrees with the model fails before anything is embedded.
+// Models the server has no info for are not checked.
+func (c *Client) checkDimensions(ctx context.Context, model string, dims *int) error {
+	if dims == nil {
+		return nil
+	}
+	if *dims < 1 {
+		return invalidf("dimensions", "dimensions must be at least 1, got %d", *dims)
+	}
+	if model == "" {
+		return nil
+	}
+
+	c.embeddingInfo.mu.Lock()
+	info, cached := c.embeddingInfo.models[model]
+	c.embeddingInfo.mu.Unlock()
+	if !cached {
+		fetched, err := c.GetEmbeddingInfo(ctx, model)
+		var apiErr *APIError
+		switch {
+		case err == nil:
+			info = fetched
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+			// Unknown model or older server: leave the check to the server.
+		case ctx.Err() != nil:
+			return err
+		default:
+			return nil
+		}
+		c.embeddingInfo.mu.Lock()
+		if c.embeddingInfo.models == nil {
+			c.embeddingInfo.models = map[string]*EmbeddingModelInfo{}
+		}
+		c.embeddingInfo.models[model] = info
+		c.embeddingInfo.mu.Unlock()
+	}
+
+	if info != nil && !info.SupportsDimensions(*dims) {
+		return invalidf("dimensions", "model %s cannot produce %d dimensions (native %d, supported %v)", model, *dims, info.Dimensions, info.SupportedDimensions)
+	}
+	return nil
+}
+
+// ─── Vector Decoding ────────────────────────────────────────────────────────
+
+// decodeEmbeddings decodes an embeddings response. Each vector is either a
+// JSON array or, for EmbeddingEncodingBase64, a base64 string of little-endian
+// float32 values. With asFloat32 the vectors are stored in Embedding32, so no
+// float64 copy is ever allocated.
+func decodeEmbeddings(r io.Reader, asFloat32 bool, out *EmbeddingResponse) error {
+	var wire struct {
+		EmbeddingResponse
+		Data []struct {
+			Object    string          `json:"object"`
+			Embedding json.RawMessage `json:"embedding"`
+			Index     int             `json:"index"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(r).Decode(&wire); err != nil {
+		return err
+	}
+	*out = wire.EmbeddingResponse
+	out.Data = make([]EmbeddingData, len(wire.Data))
+	for i, d := range wire.Data {
+		data := EmbeddingData{Object: d.Object, Index: d.Index}
+		var err error
+		switch {
+		case len(d.Embedding) > 0 && d.Embedding[0] == '"':
+			data.Embedding, data.Embedding32, err = decodeBase64Vector(d.Embedding, asFloat32)
+		case asFloat32:
+			err = json.Unmarshal(d.Embedding, &data.Embedding32)
+		default:
+			err = json.Unmarshal(d.Embedding, &data.Embedding)
+		}
+		if err != nil {
+			return fmt.Errorf("embedding %d: %w", d.Index, err)
+		}
+		out.Data[i] = data
+	}
+	return nil
+}
+
+// decodeBase64Vector decodes a JSON string holding base64 little-endian
+// float32 values into one of the two vector forms.
+func decodeBase64Vector(raw json.RawMessage, asFloat32 bool) ([]float64, []float32, error) {
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		return nil, nil, err
+	}
+	b, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode base64: %w", err)
+	}
+	if len(b)%4 != 0 {
+		return nil, nil, fmt.Errorf("base64 vector is %d bytes, not a multiple of 4", len(b))
+	}
+	n := len(b) / 4
+	if asFloat32 {
+		v := make([]float32, n)
+		for i := range v {
+			v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+		}
+		return nil, v, nil
+	}
+	v := make([]float64, n)
+	for i := range v {
+		v[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:])))
+	}
+	return v, nil, nil
+}
@@ -0,0 +1,206 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// ─── Event Streams ──────────────────────────────────────────────────────────
+
+// Stream event types sent by the events endpoint.
+const (
+	EventMessageStart   = "message_start"
+	EventReasoningDelta = "reasoning_delta"
+	EventContentDelta   = "content_delta"
+	EventToolCallDelta  = "tool_call_delta"
+	EventMessageEnd     = "message_end"
+	EventError          = "error"
+)
+
+// StreamEvent is one typed event of an EventStream. Which fields are set
+// depends on Type.
+type StreamEvent struct {
+	Type string `json:"type"`
+	// Index is the choice the event belongs to.
+	Index int `json:"index,omitempty"`
+
+	// ID and Model are set on message_start.
+	ID    string `json:"id,omitempty"`
+	Model string `json:"model,omitempty"`
+	// Delta is the text of a reasoning_delta or content_delta.
+	Delta string `json:"delta,omitempty"`
+	// ToolCall is the fragment of a tool_call_delta. Combine fragments with a
+	// ToolCallAccumulator.
+	ToolCall *ToolCallDelta `json:"tool_call,omitempty"`
+	// FinishReason, Usage, and Safety are set on message_end.
+	FinishReason string             `json:"finish_reason,omitempty"`
+	Usage        *Usage             `json:"usage,omitempty"`
+	Safety       *SafetyAnnotations `json:"safety,omitempty"`
+	// Error is set on error events.
+	Error *ErrorDetail `json:"error,omitempty"`
+
+	// Data is the raw event payload, for event types this SDK does not model.
+	Data json.RawMessage `json:"-"`
+}
+
+// StreamError is an error event sent after the stream started.
+type StreamError struct {
+	Detail ErrorDetail
+}
+
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("stream error (%s): %s", e.Detail.Type, e.Detail.Message)
+}
+
+// EventStream reads a chat completion as typed events, so a UI can tell
+// reasoning, content, and tool call phases apart instead of only receiving
+// text deltas. Its life cycle is ChatStream's: always Close it. An EventStream
+// is not safe for concurrent use.
+//
+//	events, err := client.StreamEvents(ctx, req)
+//	if err != nil {
+//		return err
+//	}
+//	defer events.Close()
+//	for {
+//		ev, err := events.Next()
+//		if err == io.EOF {
+//			break
+//		}
+//		if err != nil {
+//			return err
+//		}
+//		switch ev.Type {
+//		case hackeserasdk.EventReasoningDelta:
+//			ui.Thinking(ev.Delta)
+//		case hackeserasdk.EventContentDelta:
+//			ui.Write(ev.Delta)
+//		case hackeserasdk.EventToolCallDelta:
+//			ui.ToolProgress(ev.ToolCall)
+//		}
+//	}
+type EventStream struct {
+	body    io.ReadCloser
+	scanner *sseScanner
+	release func()
+	// malformed is ChatStream.malformed.
+	malformed func(payload string, err error) error
+
+	err       error
+	closeOnce sync.Once
+}
+
+// StreamEvents sends a streaming chat completion to the events endpoint and
+// returns the open stream. Errors before the first event are returned here.
+func (c *Client) StreamEvents(ctx context.Context, req ChatRequest) (*EventStream, error) {
+	return c.openEventStream(ctx, req, nil)
+}
+
+// StreamEventsWithOptions is StreamEvents with per-request options.
+func (c *Client) StreamEventsWithOptions(ctx context.Context, req ChatRequest, opts RequestOptions) (*EventStream, error) {
+	return c.openEventStream(ctx, req, &opts)
+}
+
+func (c *Client) openEventStream(ctx context.Context, req ChatRequest, opts *RequestOptions) (*EventStream, error) {
+	body, release, err := c.postStream(ctx, "/v1/responses", req, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &EventStream{body: body, scanner: c.newSSEScanner(body), release: release,
+		malformed: func(payload string, err error) error { return c.malformedChunk(ctx, payload, err) }}, nil
+}
+
+// Next returns the next event. Events of unknown types are returned with Data
+// set. It returns io.EOF when the stream ends normally and a *StreamError
+// after an error event; any error ends the stream and is also reported by Err.
+func (s *EventStream) Next() (StreamEvent, error) {
+	if s.err != nil {
+		return StreamEvent{}, s.err
+	}
+
+	var eventType string
+	var data strings.Builder
+	for {
+		more := s.scanner.Scan()
+		line := s.scanner.Text()
+		switch {
+		case !more:
+			// Dispatch an event left unterminated by the end of the body.
+		case strings.HasPrefix(line, "event:"):
+			eventType = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+			continue
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			continue
+		case line != "":
+			// Comments and fields this client does not use.
+			continue
+		}
+
+		// A blank line dispatches the event.
+		payload := data.String()
+		data.Reset()
+		typ := eventType
+		eventType = ""
+		if payload == "[DONE]" {
+			return StreamEvent{}, s.finish(io.EOF)
+		}
+		var ev StreamEvent
+		if payload != "" {
+			if err := json.Unmarshal([]byte(payload), &ev); err != nil {
+				if perr := s.malformed(payload, err); perr != nil {
+					return StreamEvent{}, s.finish(perr)
+				}
+			} else {
+				if ev.Type == "" {
+					ev.Type = typ
+				}
+				ev.Data = json.RawMessage(payload)
+				if ev.Type == EventError && ev.Error != nil {
+					return ev, s.finish(&StreamError{Detail: *ev.Error})
+				}
+				return ev, nil
+			}
+		}
+		if !more {
+			break
+		}
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		return StreamEvent{}, s.finish(streamReadError(err, s.scanner))
+	}
+	return StreamEvent{}, s.finish(io.EOF)
+}
+
+// Err returns the error that ended the stream, or nil if it ended normally or
+// is still open.
+func (s *EventStream) Err() error {
+	if s.err == io.EOF {
+		return nil
+	}
+	return s.err
+}
+
+// Close releases the stream's connection. It is safe to call more than once.
+func (s *EventStream) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		err = s.body.Close()
+		s.release()
+	})
+	return err
+}
+
+func (s *EventStream) finish(err error) error {
+	s.err = err
+	s.Close()
+	return err
+}
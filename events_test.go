@@ -0,0 +1,103 @@
+package hackeserasdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func newEventServer(t *testing.T, body string) *scriptedServer {
+	t.Helper()
+	return newScriptedServer(t).handle("/v1/responses", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, body)
+	})
+}
+
+func TestStreamEvents(t *testing.T) {
+	srv := newEventServer(t, ": keep-alive\n\n"+
+		"event: message_start\ndata: {\"id\":\"resp-1\",\"model\":\"hackersera-ai-pro\"}\n\n"+
+		"data: {\"type\":\"reasoning_delta\",\"delta\":\"Checking the order.\"}\n\n"+
+		"data: {\"type\":\"tool_call_delta\",\"tool_call\":{\"index\":0,\"id\":\"call_1\",\"type\":\"function\",\"function\":{\"name\":\"lookup\",\"arguments\":\"{\\\"id\\\":\"}}}\n\n"+
+		"data: {\"type\":\"tool_call_delta\",\"tool_call\":{\"index\":0,\"function\":{\"arguments\":\"7}\"}}}\n\n"+
+		"data: {\"type\":\"content_delta\",\"delta\":\"Line one\"}\n\n"+
+		"event: citation\ndata: {\"source\":\"kb\"}\n\n"+
+		"data: {\"type\":\"message_end\",\"finish_reason\":\"tool_calls\",\"usage\":{\"total_tokens\":42}}\n\n"+
+		"data: [DONE]\n\n")
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	events, err := client.StreamEvents(context.Background(), ChatRequest{Model: ModelPro, Messages: []Message{{Role: "user", Content: "where is order 7?"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer events.Close()
+
+	var types []string
+	var acc ToolCallAccumulator
+	var end StreamEvent
+	for {
+		ev, err := events.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		types = append(types, ev.Type)
+		switch ev.Type {
+		case EventMessageStart:
+			if ev.ID != "resp-1" || ev.Model != ModelPro {
+				t.Errorf("unexpected message_start: %+v", ev)
+			}
+		case EventToolCallDelta:
+			acc.Add(Delta{ToolCalls: []ToolCallDelta{*ev.ToolCall}})
+		case "citation":
+			if string(ev.Data) != `{"source":"kb"}` {
+				t.Errorf("expected raw payload for unknown event, got %s", ev.Data)
+			}
+		case EventMessageEnd:
+			end = ev
+		}
+	}
+
+	want := []string{EventMessageStart, EventReasoningDelta, EventToolCallDelta, EventToolCallDelta, EventContentDelta, "citation", EventMessageEnd}
+	if fmt.Sprint(types) != fmt.Sprint(want) {
+		t.Errorf("expected events %v, got %v", want, types)
+	}
+	if calls := acc.ToolCalls(); len(calls) != 1 || calls[0].Function.Arguments != `{"id":7}` {
+		t.Errorf("unexpected tool calls: %+v", calls)
+	}
+	if end.FinishReason != "tool_calls" || end.Usage == nil || end.Usage.TotalTokens != 42 {
+		t.Errorf("unexpected message_end: %+v", end)
+	}
+}
+
+func TestStreamEventsError(t *testing.T) {
+	srv := newEventServer(t, "data: {\"type\":\"content_delta\",\"delta\":\"Par\"}\n\n"+
+		"data: {\"type\":\"error\",\"error\":{\"message\":\"backend restarted\",\"type\":\"server_error\"}}")
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	events, err := client.StreamEvents(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer events.Close()
+
+	if ev, err := events.Next(); err != nil || ev.Delta != "Par" {
+		t.Fatalf("unexpected first event %+v / %v", ev, err)
+	}
+	// The error event is not followed by a blank line; the end of the body dispatches it.
+	_, err = events.Next()
+	var streamErr *StreamError
+	if !errors.As(err, &streamErr) || streamErr.Detail.Message != "backend restarted" {
+		t.Fatalf("expected StreamError, got %v", err)
+	}
+	if !errors.As(events.Err(), &streamErr) {
+		t.Errorf("expected Err to report the stream error, got %v", events.Err())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	sdk "github.com/hackersera-dev-team/hackersera-ai-sdk"
@@ -301,6 +302,29 @@ done:
 		fmt.Printf("Total requests: %d, Total tokens: %d\n\n", usage.TotalRequests, usage.TotalTokens)
 	}
 
+	// ─── Files ───────────────────────────────────────────────────────────
+	fmt.Println("=== Files ===")
+	batchInput := `{"custom_id":"req-1","method":"POST","url":"/v1/chat/completions","body":{"model":"hackersera-ai","messages":[{"role":"user","content":"Hello"}]}}` + "\n"
+	file, err := client.UploadFile(ctx, sdk.FileUploadRequest{
+		File:     strings.NewReader(batchInput),
+		Filename: "batch-input.jsonl",
+		Purpose:  sdk.FilePurposeBatch,
+	})
+	if err != nil {
+		log.Printf("Upload file failed: %v\n\n", err)
+	} else {
+		fmt.Printf("Uploaded %s (%d bytes, purpose: %s)\n", file.ID, file.Bytes, file.Purpose)
+		if files, err := client.ListFiles(ctx, sdk.FilePurposeBatch); err == nil {
+			fmt.Printf("Batch files: %d\n", len(files.Data))
+		}
+		if delFile, err := client.DeleteFile(ctx, file.ID); err != nil {
+			log.Printf("Delete file failed: %v\n", err)
+		} else {
+			fmt.Printf("Deleted file %s: %v\n", delFile.ID, delFile.Deleted)
+		}
+		fmt.Println()
+	}
+
 	// ─── Cleanup: Delete Document ────────────────────────────────────────
 	fmt.Println("=== Cleanup ===")
 	del, err := client.DeleteDocument(ctx, doc.ID)
@@ -0,0 +1,269 @@
+package hackeserasdk
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ─── A/B Experiments ────────────────────────────────────────────────────────
+
+const defaultExperimentConversations = 10000
+
+// Variant is one arm of an Experiment. Zero-valued fields leave the
+// corresponding request field untouched.
+type Variant struct {
+	Name string
+	// Weight is the relative share of traffic routed to this variant.
+	Weight       float64
+	Model        string
+	Temperature  *float64
+	SystemPrompt string
+}
+
+// VariantStats aggregates traffic, usage, and feedback for a single variant.
+type VariantStats struct {
+	Variant          string
+	Requests         int
+	Errors           int
+	PromptTokens     int
+	CompletionTokens int
+	TotalLatency     time.Duration
+	PositiveFeedback int
+	NegativeFeedback int
+}
+
+// AvgLatency returns the mean latency of successful requests.
+func (s VariantStats) AvgLatency() time.Duration {
+	ok := s.Requests - s.Errors
+	if ok <= 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(ok)
+}
+
+// Experiment routes chat completions across weighted variants, tags each request
+// with the chosen variant (X-Experiment header), and aggregates usage and feedback
+// per variant so configurations can be compared on real traffic.
+//
+//	exp, _ := hackeserasdk.NewExperiment(client, "pro-vs-default",
+//		hackeserasdk.Variant{Name: "control", Weight: 0.9},
+//		hackeserasdk.Variant{Name: "pro", Weight: 0.1, Model: hackeserasdk.ModelPro},
+//	)
+//	resp, variant, err := exp.ChatCompletion(ctx, req, hackeserasdk.RequestOptions{UserID: "u-1"})
+type Experiment struct {
+	Name string
+	// MaxConversations bounds how many conversations are remembered for
+	// feedback attribution; the least recently used are forgotten first, and
+	// feedback on them is no longer counted. Defaults to 10000.
+	MaxConversations int
+
+	client      *Client
+	variants    []Variant
+	totalWeight float64
+
+	mu            sync.Mutex
+	rnd           *rand.Rand
+	stats         map[string]*VariantStats
+	conversations map[string]*list.Element
+	recent        *list.List // of experimentConversation, most recent first
+}
+
+type experimentConversation struct {
+	id, variant string
+}
+
+// NewExperiment creates an experiment over the given variants.
+// Variant names must be unique and weights positive.
+func NewExperiment(client *Client, name string, variants ...Variant) (*Experiment, error) {
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("experiment %q: at least one variant is required", name)
+	}
+
+	exp := &Experiment{
+		Name:          name,
+		client:        client,
+		variants:      variants,
+		rnd:           rand.New(rand.NewSource(time.Now().UnixNano())),
+		stats:         make(map[string]*VariantStats, len(variants)),
+		conversations: make(map[string]*list.Element),
+		recent:        list.New(),
+	}
+	for _, v := range variants {
+		if v.Name == "" {
+			return nil, fmt.Errorf("experiment %q: variant name is required", name)
+		}
+		if v.Weight <= 0 {
+			return nil, fmt.Errorf("experiment %q: variant %q must have a positive weight", name, v.Name)
+		}
+		if _, dup := exp.stats[v.Name]; dup {
+			return nil, fmt.Errorf("experiment %q: duplicate variant %q", name, v.Name)
+		}
+		exp.stats[v.Name] = &VariantStats{Variant: v.Name}
+		exp.totalWeight += v.Weight
+	}
+
+	return exp, nil
+}
+
+// Assign picks a variant. A non-empty key (typically the user ID) always maps to
+// the same variant, so a user sees a consistent configuration; an empty key
+// picks a variant at random.
+func (e *Experiment) Assign(key string) Variant {
+	var point float64
+	if key != "" {
+		h := fnv.New64a()
+		h.Write([]byte(e.Name + "\x00" + key))
+		point = float64(h.Sum64()%1_000_000) / 1_000_000
+	} else {
+		e.mu.Lock()
+		point = e.rnd.Float64()
+		e.mu.Unlock()
+	}
+
+	target := point * e.totalWeight
+	for _, v := range e.variants {
+		if target < v.Weight {
+			return v
+		}
+		target -= v.Weight
+	}
+	return e.variants[len(e.variants)-1]
+}
+
+// Apply returns a copy of req with the variant's overrides applied.
+// The caller's Messages slice is never modified.
+func (v Variant) Apply(req ChatRequest) ChatRequest {
+	if v.Model != "" {
+		req.Model = v.Model
+	}
+	if v.Temperature != nil {
+		req.Temperature = v.Temperature
+	}
+	if v.SystemPrompt != "" {
+		msgs := make([]Message, 0, len(req.Messages)+1)
+		if len(req.Messages) > 0 && req.Messages[0].Role == "system" {
+			msgs = append(msgs, Message{Role: "system", Content: v.SystemPrompt})
+			msgs = append(msgs, req.Messages[1:]...)
+		} else {
+			msgs = append(msgs, Message{Role: "system", Content: v.SystemPrompt})
+			msgs = append(msgs, req.Messages...)
+		}
+		req.Messages = msgs
+	}
+	return req
+}
+
+// ChatCompletion assigns a variant (keyed by opts.UserID, falling back to req.User),
+// applies it to req, and sends the request. The returned conversation is remembered
+// so later feedback via SubmitFeedback is attributed to the same variant.
+func (e *Experiment) ChatCompletion(ctx context.Context, req ChatRequest, opts RequestOptions) (*ChatResponse, Variant, error) {
+	key := opts.UserID
+	if key == "" {
+		key = req.User
+	}
+	variant := e.Assign(key)
+
+	headers := make(map[string]string, len(opts.Headers)+1)
+	for k, v := range opts.Headers {
+		headers[k] = v
+	}
+	headers["X-Experiment"] = e.Name + "/" + variant.Name
+	opts.Headers = headers
+
+	start := time.Now()
+	resp, err := e.client.ChatCompletionWithOptions(ctx, variant.Apply(req), opts)
+	elapsed := time.Since(start)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	stats := e.stats[variant.Name]
+	stats.Requests++
+	if err != nil {
+		stats.Errors++
+		return nil, variant, err
+	}
+	stats.TotalLatency += elapsed
+	stats.PromptTokens += resp.Usage.PromptTokens
+	stats.CompletionTokens += resp.Usage.CompletionTokens
+	if resp.ConversationID != "" {
+		e.remember(resp.ConversationID, variant.Name)
+	}
+
+	return resp, variant, nil
+}
+
+// SubmitFeedback forwards feedback to the API and, when the conversation was
+// served by this experiment, counts the rating toward its variant.
+func (e *Experiment) SubmitFeedback(ctx context.Context, req FeedbackRequest) (*FeedbackResponse, error) {
+	resp, err := e.client.SubmitFeedback(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if name, ok := e.lookup(req.ConversationID); ok {
+		switch {
+		case req.Rating > 0:
+			e.stats[name].PositiveFeedback++
+		case req.Rating < 0:
+			e.stats[name].NegativeFeedback++
+		}
+	}
+
+	return resp, nil
+}
+
+// VariantOf returns the variant that served a conversation, if known.
+func (e *Experiment) VariantOf(conversationID string) (string, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.lookup(conversationID)
+}
+
+// remember records the variant serving a conversation, evicting the least
+// recently used conversations beyond MaxConversations. e.mu must be held.
+func (e *Experiment) remember(conversationID, variant string) {
+	if el, ok := e.conversations[conversationID]; ok {
+		el.Value = experimentConversation{id: conversationID, variant: variant}
+		e.recent.MoveToFront(el)
+		return
+	}
+	e.conversations[conversationID] = e.recent.PushFront(experimentConversation{id: conversationID, variant: variant})
+
+	limit := e.MaxConversations
+	if limit <= 0 {
+		limit = defaultExperimentConversations
+	}
+	for e.recent.Len() > limit {
+		oldest := e.recent.Back()
+		e.recent.Remove(oldest)
+		delete(e.conversations, oldest.Value.(experimentConversation).id)
+	}
+}
+
+// lookup returns the variant serving a conversation. e.mu must be held.
+func (e *Experiment) lookup(conversationID string) (string, bool) {
+	el, ok := e.conversations[conversationID]
+	if !ok {
+		return "", false
+	}
+	e.recent.MoveToFront(el)
+	return el.Value.(experimentConversation).variant, true
+}
+
+// Results returns a snapshot of per-variant statistics in variant order.
+func (e *Experiment) Results() []VariantStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]VariantStats, 0, len(e.variants))
+	for _, v := range e.variants {
+		out = append(out, *e.stats[v.Name])
+	}
+	return out
+}
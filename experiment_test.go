@@ -0,0 +1,150 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestNewExperimentValidation(t *testing.T) {
+	client := NewClient("http://localhost", "key")
+	if _, err := NewExperiment(client, "e"); err == nil {
+		t.Error("expected error for no variants")
+	}
+	if _, err := NewExperiment(client, "e", Variant{Name: "a", Weight: 0}); err == nil {
+		t.Error("expected error for non-positive weight")
+	}
+	if _, err := NewExperiment(client, "e", Variant{Name: "a", Weight: 1}, Variant{Name: "a", Weight: 1}); err == nil {
+		t.Error("expected error for duplicate variant")
+	}
+}
+
+func TestExperimentAssignDeterministic(t *testing.T) {
+	exp, err := NewExperiment(NewClient("http://localhost", "key"), "model-test",
+		Variant{Name: "control", Weight: 1},
+		Variant{Name: "pro", Weight: 1, Model: ModelPro},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first := exp.Assign("user-42").Name
+	for i := 0; i < 10; i++ {
+		if got := exp.Assign("user-42").Name; got != first {
+			t.Fatalf("expected stable assignment %q, got %q", first, got)
+		}
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i < 200; i++ {
+		seen[exp.Assign(string(rune('a'+i%26))+string(rune('0'+i/26))).Name] = true
+	}
+	if !seen["control"] || !seen["pro"] {
+		t.Errorf("expected both variants to receive traffic, got %v", seen)
+	}
+}
+
+func TestVariantApply(t *testing.T) {
+	original := []Message{{Role: "system", Content: "old"}, {Role: "user", Content: "hi"}}
+	req := ChatRequest{Model: ModelDefault, Messages: original}
+
+	out := Variant{Model: ModelLite, Temperature: Float64Ptr(0.1), SystemPrompt: "new"}.Apply(req)
+	if out.Model != ModelLite {
+		t.Errorf("expected model override, got %q", out.Model)
+	}
+	if out.Temperature == nil || *out.Temperature != 0.1 {
+		t.Error("expected temperature override")
+	}
+	if len(out.Messages) != 2 || out.Messages[0].Content != "new" {
+		t.Errorf("expected system prompt replaced, got %+v", out.Messages)
+	}
+	if original[0].Content != "old" {
+		t.Error("expected caller messages to be left untouched")
+	}
+
+	out = Variant{SystemPrompt: "sys"}.Apply(ChatRequest{Messages: []Message{{Role: "user", Content: "hi"}}})
+	if len(out.Messages) != 2 || out.Messages[0].Role != "system" {
+		t.Errorf("expected system prompt prepended, got %+v", out.Messages)
+	}
+}
+
+func TestExperimentChatCompletionAndFeedback(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/chat/completions":
+			if r.Header.Get("X-Experiment") != "only-pro/pro" {
+				t.Errorf("expected X-Experiment header, got %q", r.Header.Get("X-Experiment"))
+			}
+			var req ChatRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Model != ModelPro {
+				t.Errorf("expected variant model %q, got %q", ModelPro, req.Model)
+			}
+			json.NewEncoder(w).Encode(ChatResponse{
+				ID:             "chatcmpl-exp",
+				ConversationID: "conv-exp",
+				Usage:          Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+			})
+		case "/v1/feedback":
+			json.NewEncoder(w).Encode(FeedbackResponse{ID: 1, ConversationID: "conv-exp", Rating: -1})
+		}
+	})
+	defer srv.Close()
+
+	exp, err := NewExperiment(NewClient(srv.URL, "key"), "only-pro", Variant{Name: "pro", Weight: 1, Model: ModelPro})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	_, variant, err := exp.ChatCompletion(ctx, ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}, RequestOptions{UserID: "u-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if variant.Name != "pro" {
+		t.Errorf("expected variant pro, got %q", variant.Name)
+	}
+	if name, ok := exp.VariantOf("conv-exp"); !ok || name != "pro" {
+		t.Errorf("expected conversation tagged with pro, got %q", name)
+	}
+
+	if _, err := exp.SubmitFeedback(ctx, FeedbackRequest{ConversationID: "conv-exp", Rating: -1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results := exp.Results()
+	if len(results) != 1 {
+		t.Fatalf("expected 1 variant result, got %d", len(results))
+	}
+	r := results[0]
+	if r.Requests != 1 || r.PromptTokens != 10 || r.CompletionTokens != 5 || r.NegativeFeedback != 1 {
+		t.Errorf("unexpected stats: %+v", r)
+	}
+}
+
+func TestExperimentBoundsConversations(t *testing.T) {
+	exp, err := NewExperiment(NewClient("http://localhost", "key"), "bounded", Variant{Name: "a", Weight: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exp.MaxConversations = 2
+
+	exp.remember("conv-1", "a")
+	exp.remember("conv-2", "a")
+	exp.VariantOf("conv-1") // conv-2 is now the least recently used
+	exp.remember("conv-3", "a")
+
+	if _, ok := exp.VariantOf("conv-2"); ok {
+		t.Error("expected the least recently used conversation to be evicted")
+	}
+	for _, id := range []string{"conv-1", "conv-3"} {
+		if _, ok := exp.VariantOf(id); !ok {
+			t.Errorf("expected %s to be remembered", id)
+		}
+	}
+	if len(exp.conversations) != 2 || exp.recent.Len() != 2 {
+		t.Errorf("expected 2 remembered conversations, got %d", len(exp.conversations))
+	}
+}
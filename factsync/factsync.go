@@ -0,0 +1,279 @@
+// Package factsync keeps learned facts in step with an authoritative external
+// system (a CSV export, a database table, a CMDB API). Each sync diffs the
+// source's records against the facts it created earlier, creates and updates
+// facts to match, expires facts whose record is gone, and reports what
+// changed.
+//
+//	src := factsync.NewCSVSource("cmdb", f, "host", "description")
+//	report, err := factsync.Sync(ctx, client, src, factsync.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Printf("%d created, %d updated, %d expired, %d failed\n",
+//		len(report.Created), len(report.Updated), len(report.Expired), len(report.Failed))
+//
+// Facts are matched to records through their Source field, which Sync sets to
+// "<source name>:<record ID>". Facts with any other Source, including those
+// learned from conversations, are never touched.
+package factsync
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	sdk "github.com/hackersera-dev-team/hackersera-ai-sdk"
+)
+
+// Record is one authoritative fact from an external system.
+type Record struct {
+	// ID identifies the record in its source and must be stable across syncs.
+	ID      string
+	Content string
+}
+
+// Source lists the current records of an external system.
+type Source interface {
+	// Name prefixes the Source of every fact synced from this source. It must
+	// not change between syncs, or earlier facts are expired and recreated.
+	Name() string
+	Records(ctx context.Context) ([]Record, error)
+}
+
+// Options configures Sync.
+type Options struct {
+	// DryRun computes the report without changing any fact.
+	DryRun bool
+	// ListLimit caps how many facts are read to find earlier synced ones.
+	// Defaults to 10000. Sync fails rather than run on a partial list, since
+	// facts it cannot see would be created again and never expired.
+	ListLimit int
+}
+
+// Change is one fact created, updated, expired, or left unchanged.
+type Change struct {
+	RecordID string
+	// FactID is 0 for a fact a dry run would create.
+	FactID  int
+	Content string
+	// Previous is the content before an update.
+	Previous string
+}
+
+// Failure is a record or fact Sync could not reconcile.
+type Failure struct {
+	RecordID string
+	FactID   int
+	Err      error
+}
+
+// Report is the reconciliation result of a Sync.
+type Report struct {
+	Source    string
+	DryRun    bool
+	Created   []Change
+	Updated   []Change
+	Expired   []Change
+	Unchanged []Change
+	Failed    []Failure
+}
+
+// OK reports whether every record and fact was reconciled.
+func (r *Report) OK() bool { return len(r.Failed) == 0 }
+
+// Sync reconciles the facts of src with its current records. Records without
+// a fact are created as verified facts with confidence 1; facts whose record
+// changed are updated and re-verified; facts whose record is gone are expired
+// by marking them unverified with confidence 0, since facts cannot be
+// deleted. Per-record failures are collected in the report; the returned
+// error is for failures that stop the sync (reading the source or the facts).
+func Sync(ctx context.Context, client *sdk.Client, src Source, opts Options) (*Report, error) {
+	if opts.ListLimit <= 0 {
+		opts.ListLimit = 10000
+	}
+	report := &Report{Source: src.Name(), DryRun: opts.DryRun}
+
+	records, err := src.Records(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read source %s: %w", src.Name(), err)
+	}
+	facts, err := client.ListFacts(ctx, opts.ListLimit, nil)
+	if err != nil {
+		return nil, fmt.Errorf("list facts: %w", err)
+	}
+	if facts.Total > len(facts.Data) {
+		return nil, fmt.Errorf("list facts: %d of %d facts returned; raise ListLimit", len(facts.Data), facts.Total)
+	}
+
+	prefix := src.Name() + ":"
+	existing := map[string]sdk.Fact{}
+	for _, f := range facts.Data {
+		if id, ok := strings.CutPrefix(f.Source, prefix); ok {
+			existing[id] = f
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, rec := range records {
+		if seen[rec.ID] {
+			report.Failed = append(report.Failed, Failure{RecordID: rec.ID, Err: errors.New("duplicate record ID")})
+			continue
+		}
+		seen[rec.ID] = true
+		if rec.ID == "" || strings.TrimSpace(rec.Content) == "" {
+			report.Failed = append(report.Failed, Failure{RecordID: rec.ID, Err: errors.New("record needs an ID and content")})
+			continue
+		}
+
+		fact, ok := existing[rec.ID]
+		change := Change{RecordID: rec.ID, FactID: fact.ID, Content: rec.Content}
+		switch {
+		case !ok:
+			if !opts.DryRun {
+				created, err := client.CreateFact(ctx, sdk.FactCreateRequest{Content: rec.Content, Source: prefix + rec.ID, Confidence: 1, Verified: true})
+				if err != nil {
+					report.Failed = append(report.Failed, Failure{RecordID: rec.ID, Err: err})
+					continue
+				}
+				change.FactID = created.ID
+			}
+			report.Created = append(report.Created, change)
+		case fact.Content != rec.Content || !fact.Verified:
+			change.Previous = fact.Content
+			if !opts.DryRun {
+				_, err := client.UpdateFact(ctx, fact.ID, sdk.FactUpdateRequest{Content: sdk.StringPtr(rec.Content), Confidence: sdk.Float64Ptr(1), Verified: sdk.BoolPtr(true)})
+				if err != nil {
+					report.Failed = append(report.Failed, Failure{RecordID: rec.ID, FactID: fact.ID, Err: err})
+					continue
+				}
+			}
+			report.Updated = append(report.Updated, change)
+		default:
+			report.Unchanged = append(report.Unchanged, change)
+		}
+	}
+
+	stale := make([]string, 0, len(existing))
+	for id, f := range existing {
+		if !seen[id] && (f.Verified || f.Confidence > 0) {
+			stale = append(stale, id)
+		}
+	}
+	sort.Strings(stale)
+	for _, id := range stale {
+		fact := existing[id]
+		if !opts.DryRun {
+			_, err := client.UpdateFact(ctx, fact.ID, sdk.FactUpdateRequest{Confidence: sdk.Float64Ptr(0), Verified: sdk.BoolPtr(false)})
+			if err != nil {
+				report.Failed = append(report.Failed, Failure{RecordID: id, FactID: fact.ID, Err: err})
+				continue
+			}
+		}
+		report.Expired = append(report.Expired, Change{RecordID: id, FactID: fact.ID, Content: fact.Content})
+	}
+	return report, nil
+}
+
+// ─── Sources ────────────────────────────────────────────────────────────────
+
+type csvSource struct {
+	name              string
+	r                 io.Reader
+	idColumn, content string
+}
+
+// NewCSVSource reads records from CSV with a header row, taking the record ID
+// and content from the named columns. The reader is consumed by the first
+// sync.
+func NewCSVSource(name string, r io.Reader, idColumn, contentColumn string) Source {
+	return &csvSource{name: name, r: r, idColumn: idColumn, content: contentColumn}
+}
+
+func (s *csvSource) Name() string { return s.name }
+
+func (s *csvSource) Records(ctx context.Context) ([]Record, error) {
+	rows, err := csv.NewReader(s.r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read csv: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	idCol, contentCol := -1, -1
+	for i, h := range rows[0] {
+		switch strings.TrimSpace(h) {
+		case s.idColumn:
+			idCol = i
+		case s.content:
+			contentCol = i
+		}
+	}
+	if idCol < 0 || contentCol < 0 {
+		return nil, fmt.Errorf("csv header needs columns %q and %q", s.idColumn, s.content)
+	}
+	records := make([]Record, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		records = append(records, Record{ID: row[idCol], Content: row[contentCol]})
+	}
+	return records, nil
+}
+
+type querySource struct {
+	name  string
+	db    *sql.DB
+	query string
+	args  []any
+}
+
+// NewQuerySource reads records from a database query returning two columns:
+// the record ID and the content.
+func NewQuerySource(name string, db *sql.DB, query string, args ...any) Source {
+	return &querySource{name: name, db: db, query: query, args: args}
+}
+
+func (s *querySource) Name() string { return s.name }
+
+func (s *querySource) Records(ctx context.Context) ([]Record, error) {
+	rows, err := s.db.QueryContext(ctx, s.query, s.args...)
+	if err != nil {
+		return nil, fmt.Errorf("query records: %w", err)
+	}
+	defer rows.Close()
+	var records []Record
+	for rows.Next() {
+		var rec Record
+		if err := rows.Scan(&rec.ID, &rec.Content); err != nil {
+			return nil, fmt.Errorf("scan record: %w", err)
+		}
+		records = append(records, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query records: %w", err)
+	}
+	return records, nil
+}
+
+type funcSource struct {
+	name string
+	fn   func(context.Context) ([]Record, error)
+}
+
+// SourceFunc adapts a function to a Source, for APIs such as a CMDB that need
+// their own client and paging:
+//
+//	src := factsync.SourceFunc("cmdb", func(ctx context.Context) ([]factsync.Record, error) {
+//		hosts, err := cmdb.ListHosts(ctx)
+//		...
+//	})
+func SourceFunc(name string, fn func(context.Context) ([]Record, error)) Source {
+	return &funcSource{name: name, fn: fn}
+}
+
+func (s *funcSource) Name() string { return s.name }
+
+func (s *funcSource) Records(ctx context.Context) ([]Record, error) { return s.fn(ctx) }
@@ -0,0 +1,191 @@
+package factsync
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	sdk "github.com/hackersera-dev-team/hackersera-ai-sdk"
+)
+
+// factStore is a fake facts backend.
+type factStore struct {
+	mu    sync.Mutex
+	facts map[int]*sdk.Fact
+	next  int
+	fail  map[int]bool
+}
+
+func newFactStore(t *testing.T, facts ...sdk.Fact) (*factStore, *httptest.Server) {
+	t.Helper()
+	s := &factStore{facts: map[int]*sdk.Fact{}, next: 100, fail: map[int]bool{}}
+	for i := range facts {
+		f := facts[i]
+		s.facts[f.ID] = &f
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/knowledge/facts":
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			list := sdk.FactListResponse{Object: "list", Total: len(s.facts)}
+			for _, f := range s.facts {
+				if limit > 0 && len(list.Data) == limit {
+					break
+				}
+				list.Data = append(list.Data, *f)
+			}
+			json.NewEncoder(w).Encode(list)
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/knowledge/facts":
+			var req sdk.FactCreateRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			s.next++
+			f := &sdk.Fact{ID: s.next, Content: req.Content, Source: req.Source, Confidence: req.Confidence, Verified: req.Verified}
+			s.facts[f.ID] = f
+			json.NewEncoder(w).Encode(f)
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/v1/knowledge/facts/"):
+			id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/v1/knowledge/facts/"))
+			f, ok := s.facts[id]
+			if !ok || s.fail[id] {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"error":{"message":"update failed","type":"server_error"}}`))
+				return
+			}
+			var req sdk.FactUpdateRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Content != nil {
+				f.Content = *req.Content
+			}
+			if req.Confidence != nil {
+				f.Confidence = *req.Confidence
+			}
+			if req.Verified != nil {
+				f.Verified = *req.Verified
+			}
+			json.NewEncoder(w).Encode(f)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	return s, srv
+}
+
+func TestSync(t *testing.T) {
+	store, srv := newFactStore(t,
+		sdk.Fact{ID: 1, Content: "web-1 runs nginx", Source: "cmdb:web-1", Confidence: 1, Verified: true},
+		sdk.Fact{ID: 2, Content: "db-1 runs Postgres 14", Source: "cmdb:db-1", Confidence: 1, Verified: true},
+		sdk.Fact{ID: 3, Content: "old-1 is the mail relay", Source: "cmdb:old-1", Confidence: 1, Verified: true},
+		sdk.Fact{ID: 4, Content: "Users prefer dark mode", Source: "conversation", Confidence: 0.7},
+	)
+	defer srv.Close()
+	client := sdk.NewClient(srv.URL, "test-key")
+
+	csvData := "host,description\n" +
+		"web-1,web-1 runs nginx\n" +
+		"db-1,db-1 runs Postgres 16\n" +
+		"cache-1,cache-1 runs Redis\n"
+	report, err := Sync(context.Background(), client, NewCSVSource("cmdb", strings.NewReader(csvData), "host", "description"), Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.OK() || len(report.Created) != 1 || len(report.Updated) != 1 || len(report.Expired) != 1 || len(report.Unchanged) != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if u := report.Updated[0]; u.FactID != 2 || u.Previous != "db-1 runs Postgres 14" || store.facts[2].Content != "db-1 runs Postgres 16" {
+		t.Errorf("unexpected update: %+v", u)
+	}
+	if c := report.Created[0]; c.RecordID != "cache-1" || store.facts[c.FactID].Source != "cmdb:cache-1" || !store.facts[c.FactID].Verified {
+		t.Errorf("unexpected create: %+v", c)
+	}
+	if old := store.facts[3]; report.Expired[0].FactID != 3 || old.Verified || old.Confidence != 0 {
+		t.Errorf("expected old-1 expired, got %+v", old)
+	}
+	if learned := store.facts[4]; learned.Confidence != 0.7 {
+		t.Errorf("expected facts from other sources untouched, got %+v", learned)
+	}
+
+	// A second sync finds nothing to do; the expired fact stays expired.
+	again, err := Sync(context.Background(), client, SourceFunc("cmdb", func(context.Context) ([]Record, error) {
+		return []Record{{"web-1", "web-1 runs nginx"}, {"db-1", "db-1 runs Postgres 16"}, {"cache-1", "cache-1 runs Redis"}}, nil
+	}), Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(again.Unchanged) != 3 || len(again.Created)+len(again.Updated)+len(again.Expired) != 0 {
+		t.Errorf("expected an idempotent second sync, got %+v", again)
+	}
+}
+
+func TestSyncDryRunAndFailures(t *testing.T) {
+	store, srv := newFactStore(t,
+		sdk.Fact{ID: 1, Content: "a", Source: "hr:1", Confidence: 1, Verified: true},
+		sdk.Fact{ID: 2, Content: "b", Source: "hr:2", Confidence: 1, Verified: true},
+	)
+	defer srv.Close()
+	client := sdk.NewClient(srv.URL, "test-key")
+	src := SourceFunc("hr", func(context.Context) ([]Record, error) {
+		return []Record{{"1", "a changed"}, {"3", "c"}, {"3", "c again"}}, nil
+	})
+
+	report, err := Sync(context.Background(), client, src, Options{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Created) != 1 || report.Created[0].FactID != 0 || len(report.Updated) != 1 || len(report.Expired) != 1 {
+		t.Errorf("unexpected dry-run report: %+v", report)
+	}
+	if len(report.Failed) != 1 || report.Failed[0].RecordID != "3" {
+		t.Errorf("expected the duplicate record reported, got %+v", report.Failed)
+	}
+	if store.facts[1].Content != "a" || len(store.facts) != 2 {
+		t.Error("expected a dry run to change nothing")
+	}
+
+	store.fail[2] = true
+	report, err = Sync(context.Background(), client, src, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.OK() || len(report.Expired) != 0 || report.Failed[len(report.Failed)-1].FactID != 2 {
+		t.Errorf("expected the failed expiry reported, got %+v", report)
+	}
+}
+
+func TestCSVSourceMissingColumn(t *testing.T) {
+	src := NewCSVSource("x", strings.NewReader("id,text\n1,a\n"), "id", "content")
+	if _, err := src.Records(context.Background()); err == nil || !strings.Contains(err.Error(), `"content"`) {
+		t.Errorf("expected a missing column error, got %v", err)
+	}
+}
+
+func TestSyncRefusesTruncatedFactList(t *testing.T) {
+	store, srv := newFactStore(t,
+		sdk.Fact{ID: 1, Content: "web-1 runs nginx", Source: "cmdb:web-1", Confidence: 1, Verified: true},
+		sdk.Fact{ID: 2, Content: "web-2 runs nginx", Source: "cmdb:web-2", Confidence: 1, Verified: true},
+		sdk.Fact{ID: 3, Content: "web-3 runs nginx", Source: "cmdb:web-3", Confidence: 1, Verified: true},
+	)
+	defer srv.Close()
+
+	src := SourceFunc("cmdb", func(context.Context) ([]Record, error) {
+		return []Record{
+			{ID: "web-1", Content: "web-1 runs nginx"},
+			{ID: "web-2", Content: "web-2 runs nginx"},
+			{ID: "web-3", Content: "web-3 runs nginx"},
+		}, nil
+	})
+	_, err := Sync(context.Background(), sdk.NewClient(srv.URL, "test-key"), src, Options{ListLimit: 2})
+	if err == nil || !strings.Contains(err.Error(), "ListLimit") {
+		t.Fatalf("expected a truncation error, got %v", err)
+	}
+	if len(store.facts) != 3 {
+		t.Errorf("expected no facts created from a partial list, got %d", len(store.facts))
+	}
+}
@@ -0,0 +1,186 @@
+package hackeserasdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ─── Feedback Replay ────────────────────────────────────────────────────────
+
+// ReplayMetadataKey is the ChatRequest.Metadata key EvaluateFeedback tags
+// replayed requests with, set to the original conversation ID, so replays can
+// be told apart from real traffic.
+const ReplayMetadataKey = "feedback_replay"
+
+// FeedbackEvalConfig is the candidate configuration EvaluateFeedback replays
+// conversations against.
+type FeedbackEvalConfig struct {
+	// Candidate overrides the model, temperature, or system prompt of each
+	// replayed request. The zero value replays with the original model.
+	Candidate Variant
+	// Options are sent with every replayed request, e.g. retrieval headers or
+	// a Namespace. ConversationID is ignored: each replay starts fresh.
+	Options RequestOptions
+	// Prepare, if set, adjusts each request after Candidate is applied, for
+	// settings Variant does not cover (ContextDocuments, MaxTokens, ...).
+	Prepare func(ChatRequest) ChatRequest
+	// Match reports whether a replayed answer satisfies the correction.
+	// Defaults to MatchCorrection.
+	Match func(answer, correction string) bool
+}
+
+// FeedbackEvalResult is the outcome of replaying one piece of feedback.
+type FeedbackEvalResult struct {
+	Feedback FeedbackRequest
+	// Question is the user message the rated answer replied to.
+	Question string
+	// Original is the rated answer; Answer is the candidate's.
+	Original string
+	Answer   string
+	Matched  bool
+	// Err is set when the conversation could not be loaded or replayed.
+	Err error
+}
+
+// FeedbackEvalReport collects the results of EvaluateFeedback.
+type FeedbackEvalReport struct {
+	Results []FeedbackEvalResult
+	// Matched and Failed count results whose answer matched the correction
+	// and results with an Err. Skipped counts feedback that was not negative
+	// or had no correction.
+	Matched int
+	Failed  int
+	Skipped int
+}
+
+// MatchRate returns the share of replayed results that matched, or 0 when
+// nothing was replayed.
+func (r *FeedbackEvalReport) MatchRate() float64 {
+	if len(r.Results) == 0 {
+		return 0
+	}
+	return float64(r.Matched) / float64(len(r.Results))
+}
+
+// EvaluateFeedback turns negative feedback into a regression suite: for each
+// feedback with a negative Rating and a Correction, it loads the conversation,
+// replays the history up to the rated answer with cfg's candidate settings,
+// and checks whether the new answer matches the correction. TurnID selects the
+// rated answer; without one, the last assistant turn is used. Feedback is read
+// from the caller (the API does not list it), typically the requests it
+// submitted with SubmitFeedback.
+//
+//	report := client.EvaluateFeedback(ctx, negative, hackeserasdk.FeedbackEvalConfig{
+//		Candidate: hackeserasdk.Variant{Model: hackeserasdk.ModelPro},
+//	})
+//	fmt.Printf("%d/%d corrections now matched\n", report.Matched, len(report.Results))
+//
+// Each replay is a new conversation tagged with ReplayMetadataKey.
+func (c *Client) EvaluateFeedback(ctx context.Context, feedback []FeedbackRequest, cfg FeedbackEvalConfig) *FeedbackEvalReport {
+	if cfg.Match == nil {
+		cfg.Match = MatchCorrection
+	}
+	cfg.Options.ConversationID = ""
+
+	report := &FeedbackEvalReport{}
+	for _, fb := range feedback {
+		if fb.Rating >= 0 || strings.TrimSpace(fb.Correction) == "" {
+			report.Skipped++
+			continue
+		}
+		res := c.replayFeedback(ctx, fb, cfg)
+		switch {
+		case res.Err != nil:
+			report.Failed++
+		case res.Matched:
+			report.Matched++
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report
+}
+
+func (c *Client) replayFeedback(ctx context.Context, fb FeedbackRequest, cfg FeedbackEvalConfig) FeedbackEvalResult {
+	res := FeedbackEvalResult{Feedback: fb}
+	convo, err := c.GetConversation(ctx, fb.ConversationID)
+	if err != nil {
+		res.Err = fmt.Errorf("load conversation: %w", err)
+		return res
+	}
+
+	rated := -1
+	for i, turn := range convo.Turns {
+		if turn.Role == "assistant" && (fb.TurnID == 0 || turn.ID == fb.TurnID) {
+			rated = i
+		}
+	}
+	if rated < 0 {
+		res.Err = fmt.Errorf("conversation %s has no rated assistant turn", fb.ConversationID)
+		return res
+	}
+	res.Original = convo.Turns[rated].Content
+
+	req := ChatRequest{Model: convo.Turns[rated].Model, Metadata: map[string]string{ReplayMetadataKey: fb.ConversationID}}
+	if req.Model == "" {
+		req.Model = convo.Model
+	}
+	for _, turn := range convo.Turns[:rated] {
+		req.Messages = append(req.Messages, Message{Role: turn.Role, Content: turn.Content})
+		if turn.Role == "user" {
+			res.Question = turn.Content
+		}
+	}
+	req = cfg.Candidate.Apply(req)
+	if cfg.Prepare != nil {
+		req = cfg.Prepare(req)
+	}
+
+	resp, err := c.ChatCompletionWithOptions(ctx, req, cfg.Options)
+	if err != nil {
+		res.Err = fmt.Errorf("replay: %w", err)
+		return res
+	}
+	if len(resp.Choices) > 0 {
+		res.Answer = resp.Choices[0].Message.ContentAsText()
+	}
+	res.Matched = cfg.Match(res.Answer, fb.Correction)
+	return res
+}
+
+// MatchCorrection is the default FeedbackEvalConfig.Match. Ignoring case and
+// punctuation, it accepts an answer that contains the correction, or that
+// contains at least 80% of the correction's distinct words.
+func MatchCorrection(answer, correction string) bool {
+	a, c := matchWords(answer), matchWords(correction)
+	if len(c) == 0 {
+		return false
+	}
+	if strings.Contains(" "+strings.Join(a, " ")+" ", " "+strings.Join(c, " ")+" ") {
+		return true
+	}
+	have := make(map[string]bool, len(a))
+	for _, w := range a {
+		have[w] = true
+	}
+	want := map[string]bool{}
+	hits := 0
+	for _, w := range c {
+		if want[w] {
+			continue
+		}
+		want[w] = true
+		if have[w] {
+			hits++
+		}
+	}
+	return float64(hits) >= 0.8*float64(len(want))
+}
+
+// matchWords lowercases s and splits it into runs of letters and digits.
+func matchWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,66 @@
+package hackeserasdk
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEvaluateFeedback(t *testing.T) {
+	srv := newScriptedServer(t,
+		assistantReply("The staging cluster runs in eu-west-1."),
+		assistantReply("I am not sure."),
+	).handle("/v1/conversations/conv-1", jsonHandler(ConversationDetail{ID: "conv-1", Model: ModelDefault, Turns: []ConversationTurn{
+		{ID: 1, Role: "user", Content: "Where is staging?"},
+		{ID: 2, Role: "assistant", Content: "us-east-1", Model: ModelDefault},
+		{ID: 3, Role: "user", Content: "And prod?"},
+		{ID: 4, Role: "assistant", Content: "eu-central-1", Model: ModelDefault},
+	}})).handle("/v1/conversations/conv-2", jsonHandler(ConversationDetail{ID: "conv-2", Turns: []ConversationTurn{
+		{ID: 1, Role: "user", Content: "Who owns billing?", Model: ModelLite},
+		{ID: 2, Role: "assistant", Content: "Nobody.", Model: ModelLite},
+	}}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	report := client.EvaluateFeedback(context.Background(), []FeedbackRequest{
+		{ConversationID: "conv-1", TurnID: 2, Rating: -1, Correction: "Staging runs in eu-west-1"},
+		{ConversationID: "conv-2", Rating: -1, Correction: "The payments team owns billing."},
+		{ConversationID: "conv-1", Rating: 1},
+		{ConversationID: "conv-1", Rating: -1},
+	}, FeedbackEvalConfig{Candidate: Variant{Model: ModelPro, SystemPrompt: "Be precise."}})
+
+	if report.Matched != 1 || report.Failed != 0 || report.Skipped != 2 || len(report.Results) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	if r := report.Results[0]; !r.Matched || r.Question != "Where is staging?" || r.Original != "us-east-1" {
+		t.Errorf("first result = %+v", r)
+	}
+	if report.Results[1].Matched {
+		t.Errorf("unmatched answer counted as matched")
+	}
+
+	chats := srv.chats()
+	// The replay stops before the rated turn and uses the candidate settings.
+	if got := chats[0]; len(got.Messages) != 2 || got.Messages[0].Content != "Be precise." || got.Model != ModelPro ||
+		got.Metadata[ReplayMetadataKey] != "conv-1" {
+		t.Errorf("replayed request = %+v", got)
+	}
+	if srv.last("/v1/chat/completions").Header.Get("X-Conversation-ID") != "" {
+		t.Errorf("replay continued the original conversation")
+	}
+}
+
+func TestMatchCorrection(t *testing.T) {
+	for _, tc := range []struct {
+		answer, correction string
+		want               bool
+	}{
+		{"Staging runs in EU-West-1, behind the VPN.", "staging runs in eu-west-1", true},
+		{"In eu-west-1 the staging cluster runs.", "The staging cluster runs in eu-west-1", true},
+		{"Staging runs in us-east-1.", "Staging runs in eu-west-1", false},
+		{"anything", "", false},
+	} {
+		if got := MatchCorrection(tc.answer, tc.correction); got != tc.want {
+			t.Errorf("MatchCorrection(%q, %q) = %v", tc.answer, tc.correction, got)
+		}
+	}
+}
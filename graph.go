@@ -0,0 +1,171 @@
+package hackeserasdk
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+)
+
+// ─── Knowledge Graph Export ─────────────────────────────────────────────────
+
+// D3Graph is a knowledge graph in the node-link format consumed by d3-force
+// and most graph front ends.
+type D3Graph struct {
+	Nodes  []D3Node `json:"nodes"`
+	Links  []D3Link `json:"links"`
+	Groups []string `json:"groups"`
+}
+
+// D3Node is a graph node. Group is the node type and Value its hit count.
+// Degree, Radius, X, and Y are layout hints: X and Y place each group on its
+// own sector of a unit circle so the force simulation starts from a stable,
+// clustered position instead of random noise.
+type D3Node struct {
+	ID     string  `json:"id"`
+	Label  string  `json:"label"`
+	Group  string  `json:"group"`
+	Value  int     `json:"value"`
+	Degree int     `json:"degree"`
+	Radius float64 `json:"radius"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+}
+
+// D3Link is a graph edge. Value is the edge weight.
+type D3Link struct {
+	Source   string  `json:"source"`
+	Target   string  `json:"target"`
+	Value    float64 `json:"value"`
+	Relation string  `json:"relation,omitempty"`
+}
+
+// ToD3 converts the graph response to D3 node-link form.
+// Edges that reference nodes missing from the response are dropped,
+// since d3-force rejects links to unknown nodes.
+func (g *KnowledgeGraphResponse) ToD3() *D3Graph {
+	known := make(map[string]bool, len(g.Data))
+	for _, n := range g.Data {
+		known[n.ID] = true
+	}
+
+	degree := make(map[string]int, len(g.Data))
+	links := make([]D3Link, 0, len(g.Edges))
+	for _, e := range g.Edges {
+		if !known[e.FromID] || !known[e.ToID] {
+			continue
+		}
+		degree[e.FromID]++
+		degree[e.ToID]++
+		links = append(links, D3Link{
+			Source:   e.FromID,
+			Target:   e.ToID,
+			Value:    e.Weight,
+			Relation: e.Relation,
+		})
+	}
+
+	groupSet := make(map[string]bool)
+	for _, n := range g.Data {
+		groupSet[n.Type] = true
+	}
+	groups := make([]string, 0, len(groupSet))
+	for grp := range groupSet {
+		groups = append(groups, grp)
+	}
+	sort.Strings(groups)
+	groupIndex := make(map[string]int, len(groups))
+	for i, grp := range groups {
+		groupIndex[grp] = i
+	}
+
+	// Count members per group so each node gets its own slot within the sector.
+	groupSize := make(map[string]int, len(groups))
+	for _, n := range g.Data {
+		groupSize[n.Type]++
+	}
+	groupSlot := make(map[string]int, len(groups))
+
+	nodes := make([]D3Node, 0, len(g.Data))
+	sector := 2 * math.Pi / float64(max(len(groups), 1))
+	for _, n := range g.Data {
+		slot := groupSlot[n.Type]
+		groupSlot[n.Type]++
+		angle := sector*float64(groupIndex[n.Type]) + sector*(float64(slot)+0.5)/float64(groupSize[n.Type])
+
+		nodes = append(nodes, D3Node{
+			ID:     n.ID,
+			Label:  n.Label,
+			Group:  n.Type,
+			Value:  n.HitCount,
+			Degree: degree[n.ID],
+			Radius: 4 + 2*math.Sqrt(float64(n.HitCount)),
+			X:      math.Round(math.Cos(angle)*1000) / 1000,
+			Y:      math.Round(math.Sin(angle)*1000) / 1000,
+		})
+	}
+
+	return &D3Graph{Nodes: nodes, Links: links, Groups: groups}
+}
+
+// ToD3JSON returns the graph encoded as D3 node-link JSON.
+func (g *KnowledgeGraphResponse) ToD3JSON() ([]byte, error) {
+	return json.Marshal(g.ToD3())
+}
+
+// ─── Knowledge Graph Diff ───────────────────────────────────────────────────
+
+// GraphDiff lists what changed between two knowledge graph snapshots.
+// Edges are matched by endpoints and relation; weight changes are not reported.
+type GraphDiff struct {
+	AddedNodes   []KnowledgeNode
+	RemovedNodes []KnowledgeNode
+	AddedEdges   []KnowledgeEdge
+	RemovedEdges []KnowledgeEdge
+}
+
+// Empty reports whether the two graphs had the same nodes and edges.
+func (d *GraphDiff) Empty() bool {
+	return len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 && len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0
+}
+
+// DiffKnowledgeGraphs compares two graph responses, typically the same query run
+// with QueryKnowledgeGraphAsOf at two points in time.
+func DiffKnowledgeGraphs(before, after *KnowledgeGraphResponse) *GraphDiff {
+	edgeKey := func(e KnowledgeEdge) string { return e.FromID + "\x00" + e.Relation + "\x00" + e.ToID }
+
+	diff := &GraphDiff{}
+	beforeNodes := make(map[string]bool, len(before.Data))
+	for _, n := range before.Data {
+		beforeNodes[n.ID] = true
+	}
+	afterNodes := make(map[string]bool, len(after.Data))
+	for _, n := range after.Data {
+		afterNodes[n.ID] = true
+		if !beforeNodes[n.ID] {
+			diff.AddedNodes = append(diff.AddedNodes, n)
+		}
+	}
+	for _, n := range before.Data {
+		if !afterNodes[n.ID] {
+			diff.RemovedNodes = append(diff.RemovedNodes, n)
+		}
+	}
+
+	beforeEdges := make(map[string]bool, len(before.Edges))
+	for _, e := range before.Edges {
+		beforeEdges[edgeKey(e)] = true
+	}
+	afterEdges := make(map[string]bool, len(after.Edges))
+	for _, e := range after.Edges {
+		afterEdges[edgeKey(e)] = true
+		if !beforeEdges[edgeKey(e)] {
+			diff.AddedEdges = append(diff.AddedEdges, e)
+		}
+	}
+	for _, e := range before.Edges {
+		if !afterEdges[edgeKey(e)] {
+			diff.RemovedEdges = append(diff.RemovedEdges, e)
+		}
+	}
+	return diff
+}
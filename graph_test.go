@@ -0,0 +1,105 @@
+package hackeserasdk
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestKnowledgeGraphToD3(t *testing.T) {
+	graph := &KnowledgeGraphResponse{
+		Data: []KnowledgeNode{
+			{ID: "n1", Label: "SQL Injection", Type: "vulnerability", HitCount: 16},
+			{ID: "n2", Label: "OWASP", Type: "organization", HitCount: 4},
+			{ID: "n3", Label: "XSS", Type: "vulnerability", HitCount: 0},
+		},
+		Edges: []KnowledgeEdge{
+			{ID: 1, FromID: "n1", ToID: "n2", Relation: "listed_by", Weight: 0.9},
+			{ID: 2, FromID: "n3", ToID: "n2", Relation: "listed_by", Weight: 0.5},
+			{ID: 3, FromID: "n1", ToID: "missing", Relation: "related_to", Weight: 0.1},
+		},
+	}
+
+	d3 := graph.ToD3()
+	if len(d3.Nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(d3.Nodes))
+	}
+	if len(d3.Links) != 2 {
+		t.Fatalf("expected dangling edge to be dropped, got %d links", len(d3.Links))
+	}
+	if d3.Links[0].Source != "n1" || d3.Links[0].Target != "n2" || d3.Links[0].Value != 0.9 {
+		t.Errorf("unexpected first link: %+v", d3.Links[0])
+	}
+	if len(d3.Groups) != 2 || d3.Groups[0] != "organization" || d3.Groups[1] != "vulnerability" {
+		t.Errorf("expected sorted groups, got %v", d3.Groups)
+	}
+
+	n1 := d3.Nodes[0]
+	if n1.Group != "vulnerability" || n1.Value != 16 {
+		t.Errorf("unexpected node mapping: %+v", n1)
+	}
+	if n1.Degree != 1 {
+		t.Errorf("expected degree 1 for n1, got %d", n1.Degree)
+	}
+	if d3.Nodes[1].Degree != 2 {
+		t.Errorf("expected degree 2 for n2, got %d", d3.Nodes[1].Degree)
+	}
+	if n1.Radius != 12 {
+		t.Errorf("expected radius 12 for hit count 16, got %f", n1.Radius)
+	}
+	if d3.Nodes[0].X == d3.Nodes[2].X && d3.Nodes[0].Y == d3.Nodes[2].Y {
+		t.Error("expected nodes in the same group to get distinct positions")
+	}
+}
+
+func TestKnowledgeGraphToD3JSON(t *testing.T) {
+	graph := &KnowledgeGraphResponse{
+		Data:  []KnowledgeNode{{ID: "n1", Label: "Go", Type: "language", HitCount: 1}},
+		Edges: []KnowledgeEdge{},
+	}
+
+	data, err := graph.ToD3JSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var raw map[string]interface{}
+	json.Unmarshal(data, &raw)
+
+	nodes := raw["nodes"].([]interface{})
+	node := nodes[0].(map[string]interface{})
+	for _, key := range []string{"id", "label", "group", "value"} {
+		if _, ok := node[key]; !ok {
+			t.Errorf("expected node key %q", key)
+		}
+	}
+	if links, ok := raw["links"].([]interface{}); !ok || len(links) != 0 {
+		t.Errorf("expected empty links array, got %v", raw["links"])
+	}
+}
+
+func TestDiffKnowledgeGraphs(t *testing.T) {
+	before := &KnowledgeGraphResponse{
+		Data:  []KnowledgeNode{{ID: "a"}, {ID: "b"}},
+		Edges: []KnowledgeEdge{{FromID: "a", ToID: "b", Relation: "related"}},
+	}
+	after := &KnowledgeGraphResponse{
+		Data: []KnowledgeNode{{ID: "a"}, {ID: "c"}},
+		Edges: []KnowledgeEdge{
+			{FromID: "a", ToID: "c", Relation: "related"},
+		},
+	}
+
+	diff := DiffKnowledgeGraphs(before, after)
+	if len(diff.AddedNodes) != 1 || diff.AddedNodes[0].ID != "c" {
+		t.Errorf("expected node c added, got %+v", diff.AddedNodes)
+	}
+	if len(diff.RemovedNodes) != 1 || diff.RemovedNodes[0].ID != "b" {
+		t.Errorf("expected node b removed, got %+v", diff.RemovedNodes)
+	}
+	if len(diff.AddedEdges) != 1 || len(diff.RemovedEdges) != 1 {
+		t.Errorf("expected one edge added and removed, got %+v / %+v", diff.AddedEdges, diff.RemovedEdges)
+	}
+	if diff.Empty() || !DiffKnowledgeGraphs(after, after).Empty() {
+		t.Error("unexpected Empty result")
+	}
+}
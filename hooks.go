@@ -0,0 +1,137 @@
+package hackeserasdk
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// ─── Hooks ──────────────────────────────────────────────────────────────────
+
+// RequestInfo is the sanitized metadata of a request attempt passed to hooks.
+type RequestInfo struct {
+	Method          string
+	Path            string
+	ClientRequestID string
+	// Header is a copy of the request headers with Authorization, cookies,
+	// and signatures redacted.
+	Header http.Header
+}
+
+// ResponseInfo describes the outcome of a request attempt.
+type ResponseInfo struct {
+	Request RequestInfo
+	// StatusCode is 0 when the attempt failed without a response; Err is then
+	// the transport error.
+	StatusCode int
+	Latency    time.Duration
+	Err        error
+}
+
+// RetryInfo describes a retry WithRetry is about to make.
+type RetryInfo struct {
+	Request RequestInfo
+	// Attempt is the attempt that failed, starting at 1.
+	Attempt int
+	// Reason is the status code ("503") or "transport".
+	Reason string
+	Delay  time.Duration
+}
+
+// Hooks are lifecycle callbacks for observability and auditing. Nil fields
+// are skipped. Hooks run synchronously on the calling goroutine, so they
+// should be fast and must not block; a slow hook delays the request.
+type Hooks struct {
+	// OnRequest runs before every attempt is sent, retries included.
+	OnRequest func(ctx context.Context, info RequestInfo)
+	// OnResponse runs when an attempt returns a response or fails. For a
+	// stream it runs when the headers arrive, not when the stream ends.
+	OnResponse func(ctx context.Context, info ResponseInfo)
+	// OnRetry runs before WithRetry waits to retry.
+	OnRetry func(ctx context.Context, info RetryInfo)
+	// OnStreamChunk runs for every chunk a ChatStream returns.
+	OnStreamChunk func(ctx context.Context, chunk ChatStreamChunk)
+	// OnStreamParseError runs for every stream payload that is not valid
+	// JSON, whether or not WithStrictStreaming ends the stream on it.
+	OnStreamParseError func(ctx context.Context, err *StreamParseError)
+}
+
+// WithHooks registers lifecycle hooks. Each call adds a set, so independent
+// layers (metrics, audit) can register their own; sets run in the order
+// added. Like Use, call it before the client is shared between goroutines.
+//
+//	client.WithHooks(hackeserasdk.Hooks{
+//		OnResponse: func(ctx context.Context, info hackeserasdk.ResponseInfo) {
+//			metrics.Observe(info.Request.Path, info.StatusCode, info.Latency)
+//		},
+//	})
+func (c *Client) WithHooks(h Hooks) *Client {
+	c.hooks = append(c.hooks, h)
+	return c
+}
+
+// hookSet is the registered Hooks; its methods are no-ops when empty.
+type hookSet []Hooks
+
+// wrap runs OnRequest and OnResponse around each attempt sent through next.
+func (hs hookSet) wrap(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		ctx := req.Context()
+		info := requestInfo(req)
+		for _, h := range hs {
+			if h.OnRequest != nil {
+				h.OnRequest(ctx, info)
+			}
+		}
+
+		start := time.Now()
+		resp, err := next(req)
+		out := ResponseInfo{Request: info, Latency: time.Since(start), Err: err}
+		if resp != nil {
+			out.StatusCode = resp.StatusCode
+		}
+		for _, h := range hs {
+			if h.OnResponse != nil {
+				h.OnResponse(ctx, out)
+			}
+		}
+		return resp, err
+	}
+}
+
+func (hs hookSet) retry(req *http.Request, attempt int, reason string, delay time.Duration) {
+	if len(hs) == 0 {
+		return
+	}
+	info := RetryInfo{Request: requestInfo(req), Attempt: attempt, Reason: reason, Delay: delay}
+	for _, h := range hs {
+		if h.OnRetry != nil {
+			h.OnRetry(req.Context(), info)
+		}
+	}
+}
+
+func (hs hookSet) streamChunk(ctx context.Context, chunk ChatStreamChunk) {
+	for _, h := range hs {
+		if h.OnStreamChunk != nil {
+			h.OnStreamChunk(ctx, chunk)
+		}
+	}
+}
+
+func (hs hookSet) streamParseError(ctx context.Context, err *StreamParseError) {
+	for _, h := range hs {
+		if h.OnStreamParseError != nil {
+			h.OnStreamParseError(ctx, err)
+		}
+	}
+}
+
+func requestInfo(req *http.Request) RequestInfo {
+	return RequestInfo{
+		Method:          req.Method,
+		Path:            req.URL.Path,
+		ClientRequestID: req.Header.Get(HeaderClientRequestID),
+		Header:          redactHeaders(req.Header),
+	}
+}
@@ -0,0 +1,89 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithHooks(t *testing.T) {
+	attempts := 0
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-hooks"})
+	})
+	defer srv.Close()
+
+	var events []string
+	var responses []ResponseInfo
+	client := NewClient(srv.URL, "secret-key").
+		WithRetry(RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond}).
+		WithHooks(Hooks{
+			OnRequest: func(ctx context.Context, info RequestInfo) {
+				events = append(events, "request")
+				if info.Header.Get("Authorization") != "[REDACTED]" || info.ClientRequestID == "" {
+					t.Errorf("unsanitized request info: %+v", info)
+				}
+			},
+			OnResponse: func(ctx context.Context, info ResponseInfo) {
+				events = append(events, "response")
+				responses = append(responses, info)
+			},
+			OnRetry: func(ctx context.Context, info RetryInfo) {
+				events = append(events, "retry:"+info.Reason)
+			},
+		}).
+		WithHooks(Hooks{OnResponse: func(context.Context, ResponseInfo) { events = append(events, "audit") }})
+
+	if _, err := client.ChatCompletion(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"request", "response", "audit", "retry:429", "request", "response", "audit"}
+	if len(events) != len(want) {
+		t.Fatalf("events = %v, want %v", events, want)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Fatalf("events = %v, want %v", events, want)
+		}
+	}
+	if responses[0].StatusCode != 429 || responses[1].StatusCode != 200 || responses[1].Request.Path != "/v1/chat/completions" {
+		t.Errorf("responses = %+v", responses)
+	}
+}
+
+func TestWithHooksStreamChunks(t *testing.T) {
+	srv := newScriptedServer(t).withSSE(
+		`{"choices":[{"index":0,"delta":{"content":"Hel"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"lo"}}]}`,
+	)
+	defer srv.Close()
+
+	var text string
+	client := NewClient(srv.URL, "test-key").WithHooks(Hooks{
+		OnStreamChunk: func(ctx context.Context, chunk ChatStreamChunk) { text += chunk.Choices[0].Delta.Content },
+	})
+	stream, err := client.StreamChat(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+	for {
+		if _, err := stream.Next(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if text != "Hello" {
+		t.Errorf("hooked chunks = %q", text)
+	}
+}
@@ -0,0 +1,179 @@
+package hackeserasdk
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ─── Prompt-Injection Screening ─────────────────────────────────────────────
+
+// InjectionAction is what an InjectionScreener does with content that matches
+// an injection pattern.
+type InjectionAction int
+
+const (
+	// InjectionFlag keeps the content unchanged and reports the findings.
+	InjectionFlag InjectionAction = iota
+	// InjectionStrip replaces each matched span with InjectionRedacted.
+	InjectionStrip
+	// InjectionBlock withholds the content entirely.
+	InjectionBlock
+)
+
+// InjectionRedacted replaces matched spans under InjectionStrip.
+const InjectionRedacted = "[removed: possible prompt injection]"
+
+// InjectionPattern is a named pattern an InjectionScreener looks for.
+type InjectionPattern struct {
+	Name   string
+	Regexp *regexp.Regexp
+}
+
+// DefaultInjectionPatterns covers instruction overrides, attempts to extract
+// the system prompt, forged chat-role markers, and markdown images whose URL
+// carries a query string, the usual channel for exfiltrating conversation data
+// when a client renders the reply.
+var DefaultInjectionPatterns = []InjectionPattern{
+	{Name: "ignore_instructions", Regexp: regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override)\s+(all\s+|any\s+)?(of\s+)?(the\s+|your\s+)?(previous|prior|above|earlier|preceding|system)\s+(instructions|prompts?|rules|directions|messages)`)},
+	{Name: "role_override", Regexp: regexp.MustCompile(`(?i)\b(you\s+are\s+now\s+(a|an|in)\b|new\s+instructions\s*:|from\s+now\s+on,?\s+you\s+(will|must|are))`)},
+	{Name: "prompt_extraction", Regexp: regexp.MustCompile(`(?i)\b(reveal|print|show|repeat|output|leak)\s+(me\s+)?(your|the)\s+(system\s+prompt|hidden\s+prompt|initial\s+instructions|instructions)`)},
+	{Name: "role_marker", Regexp: regexp.MustCompile(`(?im)(^\s*(system|assistant)\s*:|<\|im_start\|>|<\|system\|>|\[/?INST\])`)},
+	{Name: "exfiltration_url", Regexp: regexp.MustCompile(`!\[[^\]]*\]\(\s*https?://[^)\s]*\?[^)\s]+\)`)},
+}
+
+// InjectionFinding is one pattern match in screened content.
+type InjectionFinding struct {
+	Pattern string `json:"pattern"`
+	Match   string `json:"match"`
+	// Offset is the byte offset of Match in the original content.
+	Offset int `json:"offset"`
+}
+
+// ScreenResult is the outcome of screening one piece of content.
+type ScreenResult struct {
+	// Content is the content to use: unchanged, stripped, or empty if blocked.
+	Content  string
+	Findings []InjectionFinding
+	Blocked  bool
+}
+
+// InjectionScreener scans retrieved chunks and tool outputs for prompt-injection
+// patterns before they are placed into messages. The zero value flags matches of
+// DefaultInjectionPatterns. Screening is pattern-based and catches common
+// attacks, not all of them; treat it as one layer alongside tool permissions.
+//
+//	screener := &hackeserasdk.InjectionScreener{Action: hackeserasdk.InjectionStrip}
+//	results, findings := screener.ScreenSearchResults(search.Data)
+type InjectionScreener struct {
+	Action InjectionAction
+	// Patterns replaces DefaultInjectionPatterns when set.
+	Patterns []InjectionPattern
+	// AllowedHosts are hosts (and their subdomains) whose URLs are not reported,
+	// e.g. the organization's own image CDN.
+	AllowedHosts []string
+}
+
+// Screen scans content and applies the screener's action to any matches.
+func (s *InjectionScreener) Screen(content string) ScreenResult {
+	findings := s.find(content)
+	if len(findings) == 0 {
+		return ScreenResult{Content: content}
+	}
+	switch s.Action {
+	case InjectionBlock:
+		return ScreenResult{Findings: findings, Blocked: true}
+	case InjectionStrip:
+		return ScreenResult{Content: strip(content, findings), Findings: findings}
+	default:
+		return ScreenResult{Content: content, Findings: findings}
+	}
+}
+
+// ScreenSearchResults screens the content of each result. Blocked results are
+// dropped and stripped ones carry the stripped content; the input is not modified.
+func (s *InjectionScreener) ScreenSearchResults(results []SearchResult) ([]SearchResult, []InjectionFinding) {
+	out := make([]SearchResult, 0, len(results))
+	var all []InjectionFinding
+	for _, r := range results {
+		screened := s.Screen(r.Content)
+		all = append(all, screened.Findings...)
+		if screened.Blocked {
+			continue
+		}
+		r.Content = screened.Content
+		out = append(out, r)
+	}
+	return out, all
+}
+
+func (s *InjectionScreener) find(content string) []InjectionFinding {
+	patterns := s.Patterns
+	if patterns == nil {
+		patterns = DefaultInjectionPatterns
+	}
+	var findings []InjectionFinding
+	for _, p := range patterns {
+		for _, loc := range p.Regexp.FindAllStringIndex(content, -1) {
+			match := content[loc[0]:loc[1]]
+			if s.allowed(match) {
+				continue
+			}
+			findings = append(findings, InjectionFinding{Pattern: p.Name, Match: match, Offset: loc[0]})
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Offset < findings[j].Offset })
+	return findings
+}
+
+// allowed reports whether every URL in match points at an allowed host. Matches
+// without URLs are never allowed.
+func (s *InjectionScreener) allowed(match string) bool {
+	if len(s.AllowedHosts) == 0 {
+		return false
+	}
+	urls := urlPattern.FindAllString(match, -1)
+	if len(urls) == 0 {
+		return false
+	}
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil || !s.hostAllowed(u.Hostname()) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *InjectionScreener) hostAllowed(host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range s.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+var urlPattern = regexp.MustCompile(`https?://[^)\s]+`)
+
+// strip replaces the matched spans, merging overlapping matches.
+func strip(content string, findings []InjectionFinding) string {
+	var b strings.Builder
+	pos := 0
+	for _, f := range findings {
+		end := f.Offset + len(f.Match)
+		if end <= pos {
+			continue
+		}
+		if f.Offset >= pos {
+			b.WriteString(content[pos:f.Offset])
+			b.WriteString(InjectionRedacted)
+		}
+		pos = end
+	}
+	b.WriteString(content[pos:])
+	return b.String()
+}
@@ -0,0 +1,131 @@
+package hackeserasdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ─── Lazy Initialization ────────────────────────────────────────────────────
+
+// lazyInitRetryInterval is how long a failed validation is reused before the
+// next request tries again, so a backend that is still starting is not flooded.
+const lazyInitRetryInterval = 5 * time.Second
+
+// InitError is returned by requests on a NewLazyClient client whose one-time
+// validation failed. Step is "ready" or "list models".
+type InitError struct {
+	Step string
+	Err  error
+}
+
+func (e *InitError) Error() string {
+	return fmt.Sprintf("client initialization failed at %s: %v", e.Step, e.Err)
+}
+
+func (e *InitError) Unwrap() error { return e.Err }
+
+type lazyInit struct {
+	mu       sync.Mutex
+	done     bool
+	err      error
+	failedAt time.Time
+	models   *ModelList
+}
+
+type lazyInitKey struct{}
+
+// NewLazyClient creates a client that validates the backend on first use rather
+// than at construction, for services that build clients at boot before the
+// backend is up. The first request runs Ready and ListModels; success is cached
+// for the client's lifetime. A failure is returned as an *InitError and reused
+// for a few seconds before the next request retries. Health is never gated, so
+// liveness probes keep working while the backend starts.
+func NewLazyClient(baseURL, apiKey string) *Client {
+	c := NewClient(baseURL, apiKey)
+	c.lazy = &lazyInit{}
+	return c
+}
+
+// Initialize runs the lazy client's validation now, e.g. from a readiness probe,
+// and returns its result. It returns nil for clients not created with NewLazyClient.
+func (c *Client) Initialize(ctx context.Context) error {
+	return c.ensureInit(ctx)
+}
+
+// InitializedModels returns the model list fetched during validation, or nil if
+// the client is not lazy or has not been validated yet.
+func (c *Client) InitializedModels() *ModelList {
+	if c.lazy == nil {
+		return nil
+	}
+	c.lazy.mu.Lock()
+	defer c.lazy.mu.Unlock()
+	return c.lazy.models
+}
+
+// ensureInit validates a lazy client once. Validation requests are marked on the
+// context so they pass through without recursing.
+func (c *Client) ensureInit(ctx context.Context) error {
+	if c.lazy == nil || ctx.Value(lazyInitKey{}) != nil {
+		return nil
+	}
+	l := c.lazy
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.done {
+		return nil
+	}
+	if l.err != nil && time.Since(l.failedAt) < lazyInitRetryInterval {
+		return l.err
+	}
+
+	models, err := c.validate(context.WithValue(ctx, lazyInitKey{}, true))
+	if err != nil {
+		// A caller giving up says nothing about the backend, so only real
+		// failures are reused by other requests.
+		if ctx.Err() == nil {
+			l.err, l.failedAt = err, time.Now()
+		}
+		return err
+	}
+	l.done, l.err, l.models = true, nil, models
+	return nil
+}
+
+func (c *Client) validate(ctx context.Context) (*ModelList, error) {
+	ready, err := c.Ready(ctx)
+	if err != nil {
+		return nil, &InitError{Step: "ready", Err: err}
+	}
+	if !ready.Ready {
+		return nil, &InitError{Step: "ready", Err: fmt.Errorf("server not ready: %s", failingChecks(ready.Checks))}
+	}
+
+	models, err := c.ListModels(ctx)
+	if err != nil {
+		return nil, &InitError{Step: "list models", Err: err}
+	}
+	if len(models.Data) == 0 {
+		return nil, &InitError{Step: "list models", Err: fmt.Errorf("no models available")}
+	}
+	return models, nil
+}
+
+// failingChecks lists the readiness checks not reporting "ok".
+func failingChecks(checks HealthChecks) string {
+	var failing []string
+	for _, c := range checks.Failed() {
+		desc := c.Name + "=" + c.Status
+		if c.Error != "" {
+			desc += " (" + c.Error + ")"
+		}
+		failing = append(failing, desc)
+	}
+	if len(failing) == 0 {
+		return "no failing checks reported"
+	}
+	return strings.Join(failing, ", ")
+}
@@ -0,0 +1,114 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newLazyBackend reports ready from /ready once ready is set.
+func newLazyBackend(t *testing.T, ready *atomic.Bool) *scriptedServer {
+	t.Helper()
+	return newScriptedServer(t).
+		handle("/ready", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if !ready.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				json.NewEncoder(w).Encode(ReadyResponse{Checks: HealthChecks{{Name: "database", Status: "ok"}, {Name: "inference", Status: "starting"}}})
+				return
+			}
+			json.NewEncoder(w).Encode(ReadyResponse{Ready: true})
+		}).
+		handle("/v1/models", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(ModelList{Data: []Model{{ID: ModelDefault}}})
+		}).
+		handle("/health", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(HealthResponse{Status: "ok"})
+		})
+}
+
+func TestLazyClientValidatesOnce(t *testing.T) {
+	var ready atomic.Bool
+	ready.Store(true)
+	srv := newLazyBackend(t, &ready)
+	defer srv.Close()
+
+	client := NewLazyClient(srv.URL, "test-key")
+	if srv.count("/ready") != 0 {
+		t.Fatal("expected no requests at construction")
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := client.ChatCompletion(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if srv.count("/ready") != 1 || srv.count("/v1/models") != 1 || srv.count("/v1/chat/completions") != 3 {
+		t.Errorf("expected one validation and three completions, got %d / %d / %d", srv.count("/ready"), srv.count("/v1/models"), srv.count("/v1/chat/completions"))
+	}
+	if models := client.InitializedModels(); models == nil || models.Data[0].ID != ModelDefault {
+		t.Errorf("expected cached model list, got %+v", models)
+	}
+}
+
+func TestLazyClientInitError(t *testing.T) {
+	var ready atomic.Bool
+	srv := newLazyBackend(t, &ready)
+	defer srv.Close()
+
+	client := NewLazyClient(srv.URL, "test-key")
+	_, err := client.ChatCompletion(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	var initErr *InitError
+	if !errors.As(err, &initErr) {
+		t.Fatalf("expected InitError, got %v", err)
+	}
+	if initErr.Step != "ready" || !strings.Contains(err.Error(), "inference=starting") {
+		t.Errorf("unexpected init error: %v", err)
+	}
+
+	if err := client.Initialize(context.Background()); !errors.As(err, &initErr) {
+		t.Errorf("expected cached failure, got %v", err)
+	}
+	if srv.count("/ready") != 1 || srv.count("/v1/chat/completions") != 0 {
+		t.Errorf("expected failure to be reused without new requests, got %d ready / %d completions", srv.count("/ready"), srv.count("/v1/chat/completions"))
+	}
+
+	if _, err := client.Health(context.Background()); err != nil {
+		t.Errorf("expected health to bypass initialization, got %v", err)
+	}
+
+	// Expire the cached failure and let the backend come up.
+	ready.Store(true)
+	client.lazy.failedAt = client.lazy.failedAt.Add(-lazyInitRetryInterval)
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Errorf("expected initialization to succeed after retry, got %v", err)
+	}
+}
+
+func TestInitializeNonLazyClient(t *testing.T) {
+	if err := NewClient("http://unused", "test-key").Initialize(context.Background()); err != nil {
+		t.Errorf("expected nil for a regular client, got %v", err)
+	}
+}
+
+func TestLazyClientDoesNotCacheCanceledInit(t *testing.T) {
+	var ready atomic.Bool
+	ready.Store(true)
+	srv := newLazyBackend(t, &ready)
+	defer srv.Close()
+
+	client := NewLazyClient(srv.URL, "test-key")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.Initialize(ctx); err == nil {
+		t.Fatal("expected error for canceled context")
+	}
+	if err := client.Initialize(context.Background()); err != nil {
+		t.Errorf("expected a canceled attempt not to be cached, got %v", err)
+	}
+}
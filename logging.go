@@ -0,0 +1,164 @@
+package hackeserasdk
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// ─── Logging ────────────────────────────────────────────────────────────────
+
+// maxLoggedBody caps the request body included in logs by WithLogContent.
+const maxLoggedBody = 4096
+
+// redactedHeaders are logged as "[REDACTED]".
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", HeaderSignature}
+
+type clientLogger struct {
+	l       *slog.Logger
+	content bool
+}
+
+// WithLogger logs the client's HTTP traffic to l:
+//
+//   - Debug: each request attempt starting and finishing, streams opening and
+//     finishing.
+//   - Info: retries, with the reason and backoff.
+//   - Warn: failed attempts (transport errors and 4xx/5xx statuses), streams
+//     failing, and responses that could not be parsed (a malformed stream
+//     chunk, a non-JSON error body).
+//
+// Records carry the method, path, and X-Client-Request-ID and are logged with
+// the request's context. Request headers are logged at Debug with
+// Authorization, cookies, and signatures redacted. Message content is never
+// logged unless enabled with WithLogContent.
+//
+//	client := hackeserasdk.NewClient(baseURL, apiKey).
+//		WithLogger(slog.Default().With("component", "hackersera"))
+func (c *Client) WithLogger(l *slog.Logger) *Client {
+	content := c.logger != nil && c.logger.content
+	c.logger = &clientLogger{l: l, content: content}
+	return c
+}
+
+// WithLogContent includes request bodies (up to 4 KB) and streamed completion
+// text in the WithLogger records. It is off by default, since prompts and
+// answers may hold personal or confidential data.
+func (c *Client) WithLogContent(enabled bool) *Client {
+	if c.logger == nil {
+		c.logger = &clientLogger{l: slog.New(discardHandler{})}
+	}
+	c.logger.content = enabled
+	return c
+}
+
+// wrap logs each attempt sent through next.
+func (l *clientLogger) wrap(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		ctx := req.Context()
+		attrs := requestAttrs(req)
+		if l.l.Enabled(ctx, slog.LevelDebug) {
+			start := append(attrs[:len(attrs):len(attrs)], slog.Any("headers", redactHeaders(req.Header)))
+			if l.content {
+				if body, err := readRequestBody(req); err == nil && len(body) > 0 {
+					if len(body) > maxLoggedBody {
+						body = body[:maxLoggedBody]
+					}
+					start = append(start, slog.String("body", string(body)))
+				}
+			}
+			l.l.LogAttrs(ctx, slog.LevelDebug, "request started", start...)
+		}
+
+		began := time.Now()
+		resp, err := next(req)
+		attrs = append(attrs, slog.Duration("duration", time.Since(began)))
+		switch {
+		case err != nil:
+			l.l.LogAttrs(ctx, slog.LevelWarn, "request failed", append(attrs, slog.String("error", err.Error()))...)
+		case resp.StatusCode >= 400:
+			l.l.LogAttrs(ctx, slog.LevelWarn, "request failed", append(attrs, slog.Int("status", resp.StatusCode))...)
+		default:
+			l.l.LogAttrs(ctx, slog.LevelDebug, "request finished", append(attrs, slog.Int("status", resp.StatusCode))...)
+		}
+		return resp, err
+	}
+}
+
+func (l *clientLogger) retry(req *http.Request, attempt int, reason string, delay time.Duration) {
+	if l == nil {
+		return
+	}
+	l.l.LogAttrs(req.Context(), slog.LevelInfo, "retrying request", append(requestAttrs(req),
+		slog.Int("attempt", attempt), slog.String("reason", reason), slog.Duration("delay", delay))...)
+}
+
+// malformed reports a response part that could not be parsed.
+func (l *clientLogger) malformed(ctx context.Context, msg string, err error, attrs ...slog.Attr) {
+	if l == nil {
+		return
+	}
+	l.l.LogAttrs(ctx, slog.LevelWarn, msg, append(attrs, slog.String("error", err.Error()))...)
+}
+
+// streamOpened and streamEnded log a ChatStream's lifecycle; err is io.EOF
+// for a stream that ended normally and nil for one closed before its end.
+func (l *clientLogger) streamOpened(ctx context.Context, path string) {
+	if l == nil {
+		return
+	}
+	l.l.LogAttrs(ctx, slog.LevelDebug, "stream opened", slog.String("path", path))
+}
+
+func (l *clientLogger) streamEnded(ctx context.Context, s *ChatStream, err error) {
+	if l == nil {
+		return
+	}
+	st := s.Stats()
+	attrs := []slog.Attr{
+		slog.Duration("ttft", st.TimeToFirstToken),
+		slog.Duration("duration", st.Total),
+		slog.Int("completion_tokens", st.CompletionTokens),
+		slog.Float64("tokens_per_second", st.TokensPerSecond),
+	}
+	if l.content {
+		attrs = append(attrs, slog.String("content", s.text.String()))
+	}
+	switch {
+	case err == nil:
+		l.l.LogAttrs(ctx, slog.LevelDebug, "stream closed early", attrs...)
+	case err == io.EOF:
+		l.l.LogAttrs(ctx, slog.LevelDebug, "stream finished", attrs...)
+	default:
+		l.l.LogAttrs(ctx, slog.LevelWarn, "stream failed", append(attrs, slog.String("error", err.Error()))...)
+	}
+}
+
+func requestAttrs(req *http.Request) []slog.Attr {
+	attrs := []slog.Attr{slog.String("method", req.Method), slog.String("path", req.URL.Path)}
+	if id := req.Header.Get(HeaderClientRequestID); id != "" {
+		attrs = append(attrs, slog.String("client_request_id", id))
+	}
+	return attrs
+}
+
+// redactHeaders returns a copy of h with credentials replaced.
+func redactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range redactedHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, "[REDACTED]")
+		}
+	}
+	return out
+}
+
+// discardHandler drops every record, for WithLogContent before WithLogger.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
@@ -0,0 +1,103 @@
+package hackeserasdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// logRecords decodes the JSON lines written by a slog.JSONHandler.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var out []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("bad log line %q: %v", line, err)
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+func TestWithLogger(t *testing.T) {
+	attempts := 0
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("upstream down"))
+			return
+		}
+		json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-log"})
+	})
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	client := NewClient(srv.URL, "secret-key").
+		WithRetry(RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond}).
+		WithLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	req := ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "my account number is 1234"}}}
+	if _, err := client.ChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var msgs []string
+	for _, rec := range logRecords(t, &buf) {
+		msgs = append(msgs, rec["msg"].(string))
+	}
+	want := "request started,request failed,retrying request,request started,request finished"
+	if got := strings.Join(msgs, ","); got != want {
+		t.Errorf("records = %s, want %s", got, want)
+	}
+	if out := buf.String(); strings.Contains(out, "secret-key") || strings.Contains(out, "1234") || !strings.Contains(out, "[REDACTED]") {
+		t.Errorf("log leaks credentials or content:\n%s", out)
+	}
+
+	// Content is logged only when enabled.
+	buf.Reset()
+	client.WithLogContent(true)
+	if _, err := client.ChatCompletion(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "my account number is 1234") {
+		t.Errorf("expected request body in log:\n%s", buf.String())
+	}
+}
+
+func TestWithLoggerStream(t *testing.T) {
+	srv := newScriptedServer(t).withSSE(
+		`{"choices":[{"index":0,"delta":{"content":"Hello"}}]}`,
+		`not json`,
+	)
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	client := NewClient(srv.URL, "test-key").WithLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+	stream, err := client.StreamChat(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for {
+		if _, err := stream.Next(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Only Warn and above reach an Info handler.
+	recs := logRecords(t, &buf)
+	if len(recs) != 1 || recs[0]["msg"] != "malformed stream chunk" || recs[0]["level"] != "WARN" {
+		t.Errorf("records = %v", recs)
+	}
+}
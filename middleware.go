@@ -0,0 +1,51 @@
+package hackeserasdk
+
+import "net/http"
+
+// ─── Middleware ─────────────────────────────────────────────────────────────
+
+// RoundTripFunc sends a single HTTP request.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the function that sends each HTTP request. It can inspect or
+// mutate the request (headers, auth refresh), observe the response or error
+// (logging, metrics), or short-circuit the call.
+//
+//	client.Use(func(next hackeserasdk.RoundTripFunc) hackeserasdk.RoundTripFunc {
+//		return func(req *http.Request) (*http.Response, error) {
+//			start := time.Now()
+//			resp, err := next(req)
+//			log.Printf("%s %s in %v", req.Method, req.URL.Path, time.Since(start))
+//			return resp, err
+//		}
+//	})
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Use appends middleware to the client. Middleware runs on every HTTP request the
+// client sends, streaming and health checks included, and on every retry
+// attempt. The first middleware added is the outermost. Call Use before the
+// client is shared between goroutines.
+func (c *Client) Use(mw ...Middleware) *Client {
+	c.middleware = append(c.middleware, mw...)
+	return c
+}
+
+// roundTrip returns hc.Do wrapped in the client's middleware chain. Request
+// signing is innermost, so it signs the request as middleware left it, and
+// WithLogger and WithHooks see the request as sent.
+func (c *Client) roundTrip(hc *http.Client) RoundTripFunc {
+	send := RoundTripFunc(hc.Do)
+	if c.logger != nil {
+		send = c.logger.wrap(send)
+	}
+	if len(c.hooks) > 0 {
+		send = c.hooks.wrap(send)
+	}
+	if c.signing != nil {
+		send = c.signing.wrap(send)
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		send = c.middleware[i](send)
+	}
+	return send
+}
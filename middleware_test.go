@@ -0,0 +1,76 @@
+package hackeserasdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer refreshed" {
+			t.Errorf("expected middleware to replace auth, got %q", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("X-Tenant") != "acme" {
+			t.Errorf("expected X-Tenant acme, got %q", r.Header.Get("X-Tenant"))
+		}
+		if strings.HasSuffix(r.URL.Path, "/chat/completions") {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hi\"}}]}\n\ndata: [DONE]\n\n")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"object":"list","data":[]}`)
+	})
+	defer srv.Close()
+
+	var order []string
+	client := NewClient(srv.URL, "stale").Use(
+		func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, "outer:"+req.URL.Path)
+				req.Header.Set("Authorization", "Bearer refreshed")
+				return next(req)
+			}
+		},
+		func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, "inner")
+				req.Header.Set("X-Tenant", "acme")
+				return next(req)
+			}
+		},
+	)
+	ctx := context.Background()
+
+	if _, err := client.ListModels(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chunks, errs := client.ChatCompletionStream(ctx, ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	for range chunks {
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+
+	want := []string{"outer:/v1/models", "inner", "outer:/v1/chat/completions", "inner"}
+	if fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Errorf("expected %v, got %v", want, order)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	blocked := errors.New("blocked by policy")
+	client := NewClient("http://localhost:1", "key").Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return nil, blocked
+		}
+	})
+	_, err := client.ListModels(context.Background())
+	if !errors.Is(err, blocked) {
+		t.Errorf("expected middleware error, got %v", err)
+	}
+}
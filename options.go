@@ -0,0 +1,105 @@
+package hackeserasdk
+
+import "context"
+
+// ─── Context Request Options ────────────────────────────────────────────────
+
+type requestOptionsKey struct{}
+
+// WithRequestOptions returns a context that makes every SDK call made with it
+// send opts, including endpoints without a *WithOptions variant (search, documents,
+// facts, feedback, conversations). Options already on ctx are kept unless opts
+// overrides them; Headers are merged. Options passed to a *WithOptions method
+// are applied on top.
+//
+//	ctx = hackeserasdk.WithRequestOptions(ctx, hackeserasdk.RequestOptions{UserID: userID})
+//	results, err := client.Search(ctx, req) // attributed to userID
+func WithRequestOptions(ctx context.Context, opts RequestOptions) context.Context {
+	if base, ok := RequestOptionsFromContext(ctx); ok {
+		opts = mergeOptions(base, opts)
+	}
+	return context.WithValue(ctx, requestOptionsKey{}, opts)
+}
+
+// RequestOptionsFromContext returns the options set with WithRequestOptions.
+func RequestOptionsFromContext(ctx context.Context) (RequestOptions, bool) {
+	opts, ok := ctx.Value(requestOptionsKey{}).(RequestOptions)
+	return opts, ok
+}
+
+// mergeOptions returns base with the non-zero fields of over applied.
+func mergeOptions(base, over RequestOptions) RequestOptions {
+	merged := over
+	if merged.UserID == "" {
+		merged.UserID = base.UserID
+	}
+	if merged.ConversationID == "" {
+		merged.ConversationID = base.ConversationID
+	}
+	if merged.Namespace == "" {
+		merged.Namespace = base.Namespace
+	}
+	if merged.ClientRequestID == "" {
+		merged.ClientRequestID = base.ClientRequestID
+	}
+	if merged.Timeout == 0 {
+		merged.Timeout = base.Timeout
+	}
+	merged.CognitiveDisabled = base.CognitiveDisabled || over.CognitiveDisabled
+	merged.TranslateContext = base.TranslateContext || over.TranslateContext
+	merged.IncludeCitations = base.IncludeCitations || over.IncludeCitations
+	merged.IncludeCognitiveTrace = base.IncludeCognitiveTrace || over.IncludeCognitiveTrace
+	merged.IncludeConfidence = base.IncludeConfidence || over.IncludeConfidence
+
+	if merged.Temperature == nil {
+		merged.Temperature = base.Temperature
+	}
+	if merged.TopP == nil {
+		merged.TopP = base.TopP
+	}
+	if merged.MaxTokens == nil {
+		merged.MaxTokens = base.MaxTokens
+	}
+	if merged.Seed == nil {
+		merged.Seed = base.Seed
+	}
+
+	if len(base.Headers) > 0 {
+		merged.Headers = make(map[string]string, len(base.Headers)+len(over.Headers))
+		for k, v := range base.Headers {
+			merged.Headers[k] = v
+		}
+		for k, v := range over.Headers {
+			merged.Headers[k] = v
+		}
+	}
+	return merged
+}
+
+// applyOverrides applies the sampling overrides of the context options, then of
+// opts (if not nil), to req.
+func applyOverrides(ctx context.Context, req *ChatRequest, opts *RequestOptions) {
+	if ctxOpts, ok := RequestOptionsFromContext(ctx); ok {
+		ctxOpts.overrideRequest(req)
+	}
+	if opts != nil {
+		opts.overrideRequest(req)
+	}
+}
+
+func (o *RequestOptions) overrideRequest(req *ChatRequest) {
+	if o.Temperature != nil {
+		req.Temperature = o.Temperature
+	}
+	if o.TopP != nil {
+		req.TopP = o.TopP
+	}
+	if o.MaxTokens != nil {
+		// The override replaces either token limit rather than conflicting with it.
+		req.MaxTokens = o.MaxTokens
+		req.MaxCompletionTokens = nil
+	}
+	if o.Seed != nil {
+		req.Seed = o.Seed
+	}
+}
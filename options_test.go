@@ -0,0 +1,100 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithRequestOptions(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-User-ID") != "user-7" {
+			t.Errorf("expected X-User-ID user-7, got %q", r.Header.Get("X-User-ID"))
+		}
+		if r.Header.Get("X-Conversation-ID") != "conv-7" {
+			t.Errorf("expected X-Conversation-ID conv-7, got %q", r.Header.Get("X-Conversation-ID"))
+		}
+		if r.Header.Get("X-Team") != "red" || r.Header.Get("X-Trace") != "abc" {
+			t.Errorf("expected merged headers, got %v", r.Header)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(FeedbackResponse{ID: 1})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").SetUserID("default-user")
+	ctx := WithRequestOptions(context.Background(), RequestOptions{UserID: "user-7", Headers: map[string]string{"X-Team": "red"}})
+	ctx = WithRequestOptions(ctx, RequestOptions{ConversationID: "conv-7", Headers: map[string]string{"X-Trace": "abc"}})
+
+	if _, err := client.SubmitFeedback(ctx, FeedbackRequest{ConversationID: "conv-7", Rating: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	opts, ok := RequestOptionsFromContext(ctx)
+	if !ok || opts.UserID != "user-7" || opts.ConversationID != "conv-7" {
+		t.Errorf("expected merged options, got %+v", opts)
+	}
+}
+
+func TestWithRequestOptionsNamespaceScopesSearchCache(t *testing.T) {
+	calls := 0
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SearchResponse{Object: "list"})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithSearchCache(time.Minute)
+	ctx := WithRequestOptions(context.Background(), RequestOptions{Namespace: "tenant-a"})
+	for i := 0; i < 2; i++ {
+		if _, err := client.Search(ctx, SearchRequest{Query: "vpn"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected repeated namespaced search to hit the cache, got %d calls", calls)
+	}
+	if _, err := client.Search(context.Background(), SearchRequest{Query: "vpn"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected un-namespaced search not to see tenant-a's entry, got %d calls", calls)
+	}
+}
+
+func TestRequestOptionsOverrideSampling(t *testing.T) {
+	var got []ChatRequest
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		got = append(got, req)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-override"})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	proto := ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}, Temperature: Float64Ptr(0.2), MaxTokens: IntPtr(100)}
+	ctx := context.Background()
+
+	if _, err := client.ChatCompletionWithOptions(ctx, proto, RequestOptions{Temperature: Float64Ptr(0.9), MaxTokens: IntPtr(2000)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctxWithSeed := WithRequestOptions(ctx, RequestOptions{Seed: IntPtr(7)})
+	if _, err := client.ChatCompletion(ctxWithSeed, proto); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if *got[0].Temperature != 0.9 || *got[0].MaxTokens != 2000 {
+		t.Errorf("expected overrides applied, got temperature %v max_tokens %v", *got[0].Temperature, *got[0].MaxTokens)
+	}
+	if *got[1].Temperature != 0.2 || got[1].Seed == nil || *got[1].Seed != 7 {
+		t.Errorf("expected prototype values with context seed, got %+v", got[1])
+	}
+	if *proto.Temperature != 0.2 || *proto.MaxTokens != 100 || proto.Seed != nil {
+		t.Error("expected prototype request to be unchanged")
+	}
+}
@@ -0,0 +1,463 @@
+package hackeserasdk
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ─── Offline Outbox ─────────────────────────────────────────────────────────
+
+// HeaderIdempotencyKey carries the key that lets the server recognise a
+// repeated mutation and apply it only once.
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// Outbox operation kinds.
+const (
+	OutboxUploadDocument = "upload_document"
+	OutboxSubmitFeedback = "submit_feedback"
+	OutboxCreateFact     = "create_fact"
+)
+
+const defaultOutboxReplayInterval = 30 * time.Second
+
+// ErrQueued matches the *QueuedError returned by a mutation the outbox
+// queued, with errors.Is.
+var ErrQueued = errors.New("queued in the outbox until the API is reachable")
+
+// QueuedError is returned by UploadDocument, SubmitFeedback, and CreateFact
+// when the API was unreachable and the call was queued in the outbox.
+type QueuedError struct {
+	// OpID identifies the queued OutboxOp; it is also its idempotency key.
+	OpID string
+	// Cause is the error that made the API unreachable.
+	Cause error
+}
+
+func (e *QueuedError) Error() string {
+	return fmt.Sprintf("%v (op %s): %v", ErrQueued, e.OpID, e.Cause)
+}
+
+func (e *QueuedError) Is(target error) bool { return target == ErrQueued }
+
+func (e *QueuedError) Unwrap() error { return e.Cause }
+
+// OutboxOp is a queued mutation.
+type OutboxOp struct {
+	// ID is unique per operation and is sent as its Idempotency-Key, on the
+	// first attempt and on every replay.
+	ID       string          `json:"id"`
+	Kind     string          `json:"kind"`
+	Payload  json.RawMessage `json:"payload"`
+	Enqueued time.Time       `json:"enqueued"`
+	// Options are the RequestOptions of the original call's context.
+	Options   *RequestOptions `json:"options,omitempty"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error,omitempty"`
+}
+
+// OutboxStore persists queued operations. Implementations must be safe for
+// concurrent use; NewFileOutboxStore is the durable default.
+type OutboxStore interface {
+	// Put adds op, or replaces the op with the same ID.
+	Put(op OutboxOp) error
+	// List returns the queued ops, oldest first.
+	List() ([]OutboxOp, error)
+	Delete(id string) error
+}
+
+// OutboxConfig configures WithOutbox.
+type OutboxConfig struct {
+	// Store holds the queue. Defaults to an in-memory store, which does not
+	// survive a restart; use NewFileOutboxStore on devices that may reboot.
+	Store OutboxStore
+	// ReplayInterval is how often the queue is retried in the background.
+	// Defaults to 30s; a negative value disables background replays, leaving
+	// ReplayOutbox to the caller.
+	ReplayInterval time.Duration
+	// OnReplayed, if set, is called for each op leaving the queue: with a nil
+	// error once it was applied, or with the server's error when it was
+	// rejected and dropped.
+	OnReplayed func(op OutboxOp, err error)
+}
+
+type outbox struct {
+	cfg     OutboxConfig
+	pending atomic.Int64
+	kick    chan struct{}
+	stop    chan struct{}
+	stopped sync.Once
+	mu      sync.Mutex // held during a replay
+}
+
+// WithOutbox queues document uploads, feedback, and fact creation while the
+// API is unreachable, for devices with intermittent connectivity. Such a call
+// returns a *QueuedError (errors.Is(err, ErrQueued)) instead of failing, and
+// the operation is replayed once requests succeed again or on the next
+// ReplayInterval, oldest first. Each operation carries an Idempotency-Key so
+// an attempt that reached the server before the connection dropped is not
+// applied twice. Only transport errors and 502/504 responses count as
+// unreachable; other errors are returned as usual. Operations already in the
+// store, e.g. from before a restart, are replayed too. Shutdown stops the
+// background replays.
+//
+//	store, err := hackeserasdk.NewFileOutboxStore("/var/lib/appliance/outbox")
+//	if err != nil {
+//		return err
+//	}
+//	client := hackeserasdk.NewClient(baseURL, apiKey).
+//		WithOutbox(hackeserasdk.OutboxConfig{Store: store})
+func (c *Client) WithOutbox(cfg OutboxConfig) *Client {
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryOutboxStore()
+	}
+	if cfg.ReplayInterval == 0 {
+		cfg.ReplayInterval = defaultOutboxReplayInterval
+	}
+	o := &outbox{cfg: cfg, kick: make(chan struct{}, 1), stop: make(chan struct{})}
+	if ops, err := cfg.Store.List(); err == nil {
+		o.pending.Store(int64(len(ops)))
+	}
+	c.outbox = o
+	go c.replayLoop(o)
+	c.OnShutdown(func(context.Context) error {
+		o.stopped.Do(func() { close(o.stop) })
+		return nil
+	})
+	return c
+}
+
+// OutboxPending returns the number of queued operations.
+func (c *Client) OutboxPending() int {
+	if c.outbox == nil {
+		return 0
+	}
+	return int(c.outbox.pending.Load())
+}
+
+type idempotencyKeyKey struct{}
+
+// WithIdempotencyKey returns a context whose requests carry key as their
+// Idempotency-Key header.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+func idempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyKey{}).(string)
+	return key, ok && key != ""
+}
+
+func newIdempotencyKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("op_%d", time.Now().UnixNano())
+	}
+	return "op_" + hex.EncodeToString(b[:])
+}
+
+// sendViaOutbox sends req with send, queueing it as kind if the API is
+// unreachable.
+func sendViaOutbox[Req, Resp any](c *Client, ctx context.Context, kind string, req Req, send func(context.Context, Req) (*Resp, error)) (*Resp, error) {
+	key, ok := idempotencyKeyFromContext(ctx)
+	if !ok {
+		key = newIdempotencyKey()
+		ctx = WithIdempotencyKey(ctx, key)
+	}
+	resp, err := send(ctx, req)
+	if err == nil || ctx.Err() != nil || !apiUnreachable(err) {
+		return resp, err
+	}
+
+	payload, merr := json.Marshal(req)
+	if merr != nil {
+		return nil, err
+	}
+	op := OutboxOp{ID: key, Kind: kind, Payload: payload, Enqueued: time.Now().UTC(), Attempts: 1, LastError: err.Error()}
+	if opts, ok := RequestOptionsFromContext(ctx); ok {
+		op.Options = &opts
+	}
+	if perr := c.outbox.cfg.Store.Put(op); perr != nil {
+		return nil, errors.Join(err, fmt.Errorf("queue in outbox: %w", perr))
+	}
+	c.outbox.pending.Add(1)
+	return nil, &QueuedError{OpID: key, Cause: err}
+}
+
+// apiUnreachable reports whether err means the API could not be reached, as
+// opposed to the server rejecting the request.
+func apiUnreachable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusBadGateway || apiErr.StatusCode == http.StatusGatewayTimeout
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && !errors.Is(err, context.Canceled)
+}
+
+// outboxRetryable reports whether a replay that failed with err should stay
+// queued: the API is still unreachable or asked to be retried later. Anything
+// else, such as a 4xx or an undecodable op, would fail the same way every time.
+func outboxRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// ReplayOutbox sends the queued operations in order. It stops at the first
+// operation that still cannot be sent, which stays queued, and returns its
+// error. Operations the server rejects are dropped and reported to
+// OnReplayed. Concurrent calls wait for each other.
+func (c *Client) ReplayOutbox(ctx context.Context) error {
+	o := c.outbox
+	if o == nil {
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	ops, err := o.cfg.Store.List()
+	if err != nil {
+		return fmt.Errorf("list outbox: %w", err)
+	}
+	o.pending.Store(int64(len(ops)))
+	for _, op := range ops {
+		err := c.replayOp(ctx, op)
+		if err != nil && outboxRetryable(err) {
+			op.Attempts++
+			op.LastError = err.Error()
+			o.cfg.Store.Put(op)
+			return err
+		}
+		if derr := o.cfg.Store.Delete(op.ID); derr != nil {
+			return fmt.Errorf("delete outbox op %s: %w", op.ID, derr)
+		}
+		o.pending.Add(-1)
+		if o.cfg.OnReplayed != nil {
+			o.cfg.OnReplayed(op, err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) replayOp(ctx context.Context, op OutboxOp) error {
+	ctx = WithIdempotencyKey(ctx, op.ID)
+	if op.Options != nil {
+		ctx = WithRequestOptions(ctx, *op.Options)
+	}
+	decode := func(v any) error {
+		if err := json.Unmarshal(op.Payload, v); err != nil {
+			return fmt.Errorf("decode outbox op %s: %w", op.ID, err)
+		}
+		return nil
+	}
+	switch op.Kind {
+	case OutboxUploadDocument:
+		var req DocumentUploadRequest
+		if err := decode(&req); err != nil {
+			return err
+		}
+		_, err := c.uploadDocument(ctx, req)
+		return err
+	case OutboxSubmitFeedback:
+		var req FeedbackRequest
+		if err := decode(&req); err != nil {
+			return err
+		}
+		_, err := c.submitFeedback(ctx, req)
+		return err
+	case OutboxCreateFact:
+		var req FactCreateRequest
+		if err := decode(&req); err != nil {
+			return err
+		}
+		_, err := c.createFact(ctx, req)
+		return err
+	default:
+		return fmt.Errorf("unknown outbox op kind %q", op.Kind)
+	}
+}
+
+// replayLoop replays the queue on every ReplayInterval and whenever a request
+// succeeds, until Shutdown.
+func (c *Client) replayLoop(o *outbox) {
+	var tick <-chan time.Time
+	if o.cfg.ReplayInterval > 0 {
+		t := time.NewTicker(o.cfg.ReplayInterval)
+		defer t.Stop()
+		tick = t.C
+	}
+	for {
+		select {
+		case <-o.stop:
+			return
+		case <-tick:
+		case <-o.kick:
+		}
+		if o.pending.Load() > 0 {
+			c.ReplayOutbox(context.Background())
+		}
+	}
+}
+
+// observeOutbox wakes the replay loop after a response, which shows the API
+// is reachable again.
+func (c *Client) observeOutbox(resp *http.Response) {
+	o := c.outbox
+	if o == nil || resp == nil || resp.StatusCode >= 500 || o.pending.Load() == 0 {
+		return
+	}
+	select {
+	case o.kick <- struct{}{}:
+	default:
+	}
+}
+
+// ─── Outbox Stores ──────────────────────────────────────────────────────────
+
+// MemoryOutboxStore is an in-memory OutboxStore.
+type MemoryOutboxStore struct {
+	mu  sync.Mutex
+	ops map[string]OutboxOp
+}
+
+// NewMemoryOutboxStore returns an empty in-memory store.
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{ops: map[string]OutboxOp{}}
+}
+
+// Put implements OutboxStore.
+func (s *MemoryOutboxStore) Put(op OutboxOp) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ops[op.ID] = op
+	return nil
+}
+
+// List implements OutboxStore.
+func (s *MemoryOutboxStore) List() ([]OutboxOp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ops := make([]OutboxOp, 0, len(s.ops))
+	for _, op := range s.ops {
+		ops = append(ops, op)
+	}
+	sortOutboxOps(ops)
+	return ops, nil
+}
+
+// Delete implements OutboxStore.
+func (s *MemoryOutboxStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.ops, id)
+	return nil
+}
+
+// FileOutboxStore keeps each queued op in its own JSON file in a directory.
+// Files are written to a temporary name, synced, and renamed into place, so a
+// crash or power loss leaves either the old or the new op, never a torn one.
+type FileOutboxStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileOutboxStore returns a store in dir, creating it if needed.
+func NewFileOutboxStore(dir string) (*FileOutboxStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create outbox dir: %w", err)
+	}
+	return &FileOutboxStore{dir: dir}, nil
+}
+
+func (s *FileOutboxStore) path(id string) string {
+	return filepath.Join(s.dir, url.PathEscape(id)+".json")
+}
+
+// Put implements OutboxStore.
+func (s *FileOutboxStore) Put(op OutboxOp) error {
+	data, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp, err := os.CreateTemp(s.dir, ".op-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(op.ID))
+}
+
+// List implements OutboxStore. Files that cannot be decoded are skipped.
+func (s *FileOutboxStore) List() ([]OutboxOp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var ops []OutboxOp
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var op OutboxOp
+		if json.Unmarshal(data, &op) == nil && op.ID != "" {
+			ops = append(ops, op)
+		}
+	}
+	sortOutboxOps(ops)
+	return ops, nil
+}
+
+// Delete implements OutboxStore.
+func (s *FileOutboxStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(s.path(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func sortOutboxOps(ops []OutboxOp) {
+	sort.Slice(ops, func(i, j int) bool {
+		if !ops[i].Enqueued.Equal(ops[j].Enqueued) {
+			return ops[i].Enqueued.Before(ops[j].Enqueued)
+		}
+		return ops[i].ID < ops[j].ID
+	})
+}
@@ -0,0 +1,169 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyTransport fails every request with a transport error while down.
+type flakyTransport struct {
+	down atomic.Bool
+}
+
+func (t *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.down.Load() {
+		return nil, errors.New("network is unreachable")
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func jsonHandler(v interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+	}
+}
+
+func TestOutboxQueuesAndReplaysAcrossRestart(t *testing.T) {
+	srv := newScriptedServer(t).
+		handle("/v1/documents", jsonHandler(DocumentResponse{ID: "doc-1", Status: "processing"})).
+		handle("/v1/knowledge/facts", jsonHandler(Fact{ID: 7, Content: "pump 3 replaced"}))
+	defer srv.Close()
+	dir := t.TempDir()
+	link := &flakyTransport{}
+	link.down.Store(true)
+
+	store, err := NewFileOutboxStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(srv.URL, "test-key").
+		WithHTTPClient(&http.Client{Transport: link}).
+		WithOutbox(OutboxConfig{Store: store, ReplayInterval: -1})
+	_, err = client.UploadDocument(context.Background(), DocumentUploadRequest{Content: "Site log", Filename: "log.txt"})
+	var queued *QueuedError
+	if !errors.As(err, &queued) || !errors.Is(err, ErrQueued) {
+		t.Fatalf("expected the upload queued, got %v", err)
+	}
+	ctx := WithRequestOptions(context.Background(), RequestOptions{UserID: "tech-9"})
+	if _, err := client.CreateFact(ctx, FactCreateRequest{Content: "pump 3 replaced"}); !errors.Is(err, ErrQueued) {
+		t.Fatalf("expected the fact queued, got %v", err)
+	}
+	if client.OutboxPending() != 2 {
+		t.Fatalf("expected 2 pending ops, got %d", client.OutboxPending())
+	}
+	client.Shutdown(context.Background())
+
+	// A new process finds the queue on disk.
+	store, _ = NewFileOutboxStore(dir)
+	var replayed []OutboxOp
+	client = NewClient(srv.URL, "test-key").
+		WithOutbox(OutboxConfig{Store: store, ReplayInterval: -1, OnReplayed: func(op OutboxOp, err error) {
+			if err != nil {
+				t.Errorf("unexpected replay error for %s: %v", op.Kind, err)
+			}
+			replayed = append(replayed, op)
+		}})
+	defer client.Shutdown(context.Background())
+	if client.OutboxPending() != 2 {
+		t.Fatalf("expected 2 pending ops after restart, got %d", client.OutboxPending())
+	}
+	if err := client.ReplayOutbox(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(replayed) != 2 || replayed[0].Kind != OutboxUploadDocument || replayed[1].Kind != OutboxCreateFact {
+		t.Fatalf("expected both ops replayed in order, got %+v", replayed)
+	}
+	if key := srv.last("/v1/documents").Header.Get(HeaderIdempotencyKey); key != queued.OpID {
+		t.Errorf("expected the replay to carry idempotency key %q, got %q", queued.OpID, key)
+	}
+	if user := srv.last("/v1/knowledge/facts").Header.Get("X-User-ID"); user != "tech-9" {
+		t.Errorf("expected the original request options replayed, got user %q", user)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 || client.OutboxPending() != 0 {
+		t.Errorf("expected an empty outbox, got %d files", len(entries))
+	}
+}
+
+func TestOutboxReplayDropsRejectedOps(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	srv := newScriptedServer(t).handle("/v1/feedback", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+		w.Write([]byte(`{"error":{"message":"nope","type":"invalid_request_error"}}`))
+	})
+	defer srv.Close()
+	link := &flakyTransport{}
+	link.down.Store(true)
+
+	var mu sync.Mutex
+	var dropped error
+	client := NewClient(srv.URL, "test-key").
+		WithHTTPClient(&http.Client{Transport: link}).
+		WithOutbox(OutboxConfig{ReplayInterval: -1, OnReplayed: func(op OutboxOp, err error) {
+			mu.Lock()
+			dropped = err
+			mu.Unlock()
+		}})
+	defer client.Shutdown(context.Background())
+	if _, err := client.SubmitFeedback(context.Background(), FeedbackRequest{ConversationID: "c1", Rating: 1}); !errors.Is(err, ErrQueued) {
+		t.Fatalf("expected the feedback queued, got %v", err)
+	}
+
+	// Server errors keep the op queued; it is not sent again as a new op either.
+	link.down.Store(false)
+	if err := client.ReplayOutbox(context.Background()); err == nil || client.OutboxPending() != 1 {
+		t.Fatalf("expected the op kept after a 503, got %v with %d pending", err, client.OutboxPending())
+	}
+	status.Store(http.StatusBadRequest)
+	if err := client.ReplayOutbox(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	var apiErr *APIError
+	if client.OutboxPending() != 0 || !errors.As(dropped, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected the rejected op dropped and reported, got %v", dropped)
+	}
+
+	// Rejections are not queued in the first place.
+	if _, err := client.SubmitFeedback(context.Background(), FeedbackRequest{ConversationID: "c1", Rating: 1}); err == nil || errors.Is(err, ErrQueued) {
+		t.Errorf("expected a 400 returned directly, got %v", err)
+	}
+}
+
+func TestOutboxReplaysWhenConnectivityReturns(t *testing.T) {
+	srv := newScriptedServer(t, assistantReply("ok")).
+		handle("/v1/knowledge/facts", jsonHandler(Fact{ID: 1}))
+	defer srv.Close()
+	link := &flakyTransport{}
+	link.down.Store(true)
+
+	client := NewClient(srv.URL, "test-key").
+		WithHTTPClient(&http.Client{Transport: link}).
+		WithOutbox(OutboxConfig{ReplayInterval: -1})
+	defer client.Shutdown(context.Background())
+	if _, err := client.CreateFact(context.Background(), FactCreateRequest{Content: "x"}); !errors.Is(err, ErrQueued) {
+		t.Fatalf("expected the fact queued, got %v", err)
+	}
+
+	link.down.Store(false)
+	if _, err := client.ChatCompletion(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for client.OutboxPending() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if client.OutboxPending() != 0 || srv.count("/v1/knowledge/facts") != 1 {
+		t.Errorf("expected the queued fact sent after a successful request, got %d pending", client.OutboxPending())
+	}
+}
@@ -0,0 +1,232 @@
+package hackeserasdk
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ─── Stream Pacing ──────────────────────────────────────────────────────────
+
+// maxHeldBytes bounds the text held back waiting for a word boundary, so runs
+// without spaces (URLs, CJK text) still flow.
+const maxHeldBytes = 64
+
+// PaceConfig configures NewPacedStream.
+type PaceConfig struct {
+	// TokensPerSecond caps the delivery rate, estimated with EstimateTokens.
+	// Bursts from the backend are split into words and spread out; a slower
+	// backend is not sped up. Zero delivers chunks as soon as they are ready.
+	TokensPerSecond float64
+	// WordBoundaries holds back a trailing partial word until the rest of it
+	// arrives, so no delta ends mid-word.
+	WordBoundaries bool
+}
+
+// PacedStream smooths a ChatStream for display: it can align deltas to word
+// boundaries and cap the delivery rate, for terminal and chat UIs where the
+// backend's raw chunking shows as bursts of partial words. Only content deltas
+// are reshaped; role, tool call, finish, safety, and usage data pass through
+// in order. Chunks with several choices are split into one chunk per choice.
+// A PacedStream is not safe for concurrent use.
+//
+//	paced := hackeserasdk.NewPacedStream(stream, hackeserasdk.PaceConfig{
+//		TokensPerSecond: 40,
+//		WordBoundaries:  true,
+//	})
+//	defer paced.Close()
+//	for {
+//		chunk, err := paced.Next()
+//		if err == io.EOF {
+//			break
+//		}
+//		...
+//	}
+type PacedStream struct {
+	stream *ChatStream
+	cfg    PaceConfig
+
+	queue   []ChatStreamChunk
+	held    map[int]string
+	last    ChatStreamChunk
+	srcErr  error
+	start   time.Time
+	emitted float64
+	sleep   func(time.Duration)
+	now     func() time.Time
+}
+
+// NewPacedStream wraps stream. The paced stream takes ownership; close it
+// instead of stream.
+func NewPacedStream(stream *ChatStream, cfg PaceConfig) *PacedStream {
+	return &PacedStream{stream: stream, cfg: cfg, held: map[int]string{}, sleep: time.Sleep, now: time.Now}
+}
+
+// Next returns the next chunk, waiting as needed to keep to the rate. It
+// returns io.EOF once the stream ended normally and all held text was
+// delivered; other errors are the ChatStream's.
+func (p *PacedStream) Next() (ChatStreamChunk, error) {
+	for len(p.queue) == 0 {
+		if p.srcErr != nil {
+			return ChatStreamChunk{}, p.srcErr
+		}
+		chunk, err := p.stream.Next()
+		if err != nil {
+			p.srcErr = err
+			if err == io.EOF {
+				p.flushHeld()
+			}
+			continue
+		}
+		p.last = chunk
+		p.reshape(chunk)
+	}
+	out := p.queue[0]
+	p.queue = p.queue[1:]
+	p.wait(out)
+	return out, nil
+}
+
+// Err returns the error that ended the stream, or nil if it ended normally or
+// is still open.
+func (p *PacedStream) Err() error { return p.stream.Err() }
+
+// Stats returns the underlying stream's timing; pacing delays are not counted.
+func (p *PacedStream) Stats() StreamStats { return p.stream.Stats() }
+
+// Close closes the underlying stream. Held text is discarded.
+func (p *PacedStream) Close() error { return p.stream.Close() }
+
+// reshape queues the deliverable parts of chunk, one chunk per choice.
+func (p *PacedStream) reshape(chunk ChatStreamChunk) {
+	if len(chunk.Choices) == 0 {
+		p.queue = append(p.queue, chunk)
+		return
+	}
+	for i, choice := range chunk.Choices {
+		text := p.held[choice.Index] + choice.Delta.Content
+		ready, rest := text, ""
+		if p.cfg.WordBoundaries && choice.FinishReason == nil {
+			ready, rest = cutLastWord(text)
+		}
+		p.held[choice.Index] = rest
+
+		head := chunk
+		if i > 0 {
+			// Usage belongs to the chunk, not each choice; deliver it once.
+			head.Usage = nil
+		}
+		p.queueText(head, choice, ready)
+	}
+}
+
+// queueText queues text for choice, split into words when pacing. Role and
+// tool calls go on the first piece; finish reason and safety on the last.
+func (p *PacedStream) queueText(chunk ChatStreamChunk, choice ChunkChoice, text string) {
+	pieces := []string{text}
+	if p.cfg.TokensPerSecond > 0 && text != "" {
+		pieces = splitWords(text)
+	}
+	for j, piece := range pieces {
+		c := choice
+		c.Delta = Delta{Content: piece}
+		if j == 0 {
+			c.Delta.Role, c.Delta.ToolCalls = choice.Delta.Role, choice.Delta.ToolCalls
+		}
+		if j < len(pieces)-1 {
+			c.FinishReason, c.Safety = nil, nil
+		}
+		if c.Delta.Content == "" && c.Delta.Role == "" && c.Delta.ToolCalls == nil && c.FinishReason == nil && c.Safety == nil && chunk.Usage == nil {
+			continue
+		}
+		out := chunk
+		out.Choices = []ChunkChoice{c}
+		if j > 0 {
+			out.Usage = nil
+		}
+		p.queue = append(p.queue, out)
+	}
+}
+
+// flushHeld queues the text still held back when the stream ends without a
+// finish reason.
+func (p *PacedStream) flushHeld() {
+	indexes := make([]int, 0, len(p.held))
+	for index, text := range p.held {
+		if text != "" {
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Ints(indexes)
+	for _, index := range indexes {
+		chunk := p.last
+		chunk.Usage = nil
+		p.queueText(chunk, ChunkChoice{Index: index}, p.held[index])
+		delete(p.held, index)
+	}
+}
+
+// wait sleeps until out may be delivered. Time spent waiting on the backend
+// earns no credit, so a stall is not followed by a burst.
+func (p *PacedStream) wait(out ChatStreamChunk) {
+	if p.cfg.TokensPerSecond <= 0 || len(out.Choices) == 0 {
+		return
+	}
+	tokens := float64(EstimateTokens(out.Choices[0].Delta.Content))
+	if tokens == 0 {
+		return
+	}
+	now := p.now()
+	if p.start.IsZero() {
+		p.start = now
+	}
+	due := p.start.Add(time.Duration(p.emitted / p.cfg.TokensPerSecond * float64(time.Second)))
+	if d := due.Sub(now); d > 0 {
+		p.sleep(d)
+	} else {
+		p.start = now.Add(-time.Duration(p.emitted / p.cfg.TokensPerSecond * float64(time.Second)))
+	}
+	p.emitted += tokens
+}
+
+// cutLastWord splits text before its trailing partial word. Text ending in
+// whitespace, or holding more than maxHeldBytes after the last space, is all
+// ready.
+func cutLastWord(text string) (ready, rest string) {
+	if text == "" {
+		return "", ""
+	}
+	if r, _ := utf8.DecodeLastRuneInString(text); unicode.IsSpace(r) {
+		return text, ""
+	}
+	i := strings.LastIndexFunc(text, unicode.IsSpace)
+	if i < 0 {
+		if len(text) > maxHeldBytes {
+			return text, ""
+		}
+		return "", text
+	}
+	_, size := utf8.DecodeRuneInString(text[i:])
+	if len(text)-(i+size) > maxHeldBytes {
+		return text, ""
+	}
+	return text[:i+size], text[i+size:]
+}
+
+// splitWords splits text into words, each keeping the whitespace after it.
+func splitWords(text string) []string {
+	var words []string
+	start, inSpace := 0, false
+	for i, r := range text {
+		space := unicode.IsSpace(r)
+		if inSpace && !space {
+			words = append(words, text[start:i])
+			start = i
+		}
+		inSpace = space
+	}
+	return append(words, text[start:])
+}
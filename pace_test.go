@@ -0,0 +1,90 @@
+package hackeserasdk
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readPaced(t *testing.T, p *PacedStream) []ChatStreamChunk {
+	t.Helper()
+	defer p.Close()
+	var chunks []ChatStreamChunk
+	for {
+		chunk, err := p.Next()
+		if err == io.EOF {
+			return chunks
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+}
+
+func TestPacedStreamWordBoundaries(t *testing.T) {
+	srv := newScriptedServer(t).withSSE(
+		`{"choices":[{"index":0,"delta":{"role":"assistant","content":"Hel"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"lo wor"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"ld, how"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":" are you"}}]}`,
+		`{"choices":[{"index":0,"delta":{},"finish_reason":"stop"}],"usage":{"total_tokens":9}}`,
+	)
+	defer srv.Close()
+
+	stream, err := NewClient(srv.URL, "test-key").StreamChat(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chunks := readPaced(t, NewPacedStream(stream, PaceConfig{WordBoundaries: true}))
+
+	var deltas []string
+	for _, c := range chunks {
+		deltas = append(deltas, c.Choices[0].Delta.Content)
+	}
+	want := []string{"", "Hello ", "world, ", "how are ", "you"}
+	if strings.Join(deltas, "|") != strings.Join(want, "|") {
+		t.Fatalf("expected deltas %q, got %q", want, deltas)
+	}
+	if chunks[0].Choices[0].Delta.Role != "assistant" {
+		t.Errorf("expected the role passed through, got %+v", chunks[0])
+	}
+	last := chunks[len(chunks)-1]
+	if last.Choices[0].FinishReason == nil || *last.Choices[0].FinishReason != "stop" || last.Usage == nil || last.Usage.TotalTokens != 9 {
+		t.Errorf("expected the finish chunk to carry the last word, got %+v", last)
+	}
+}
+
+func TestPacedStreamRate(t *testing.T) {
+	srv := newScriptedServer(t).withSSE(
+		`{"choices":[{"index":0,"delta":{"content":"one two three four five"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":" six and a partial wor"}}]}`,
+	)
+	defer srv.Close()
+
+	stream, err := NewClient(srv.URL, "test-key").StreamChat(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "count"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	paced := NewPacedStream(stream, PaceConfig{TokensPerSecond: 10, WordBoundaries: true})
+	clock := time.Unix(0, 0)
+	var slept time.Duration
+	paced.now = func() time.Time { return clock }
+	paced.sleep = func(d time.Duration) { slept += d; clock = clock.Add(d) }
+
+	chunks := readPaced(t, paced)
+	var text strings.Builder
+	for _, c := range chunks {
+		text.WriteString(c.Choices[0].Delta.Content)
+	}
+	// Ten words one by one; the partial word is flushed at the end of the stream.
+	if len(chunks) != 10 || text.String() != "one two three four five six and a partial wor" {
+		t.Fatalf("unexpected chunks (%d): %q", len(chunks), text.String())
+	}
+	// Every word but the last waits for the tokens before it: 11 at 10/s.
+	if slept != 1100*time.Millisecond {
+		t.Errorf("expected 1.1s of waiting, slept %v", slept)
+	}
+}
@@ -0,0 +1,161 @@
+package hackeserasdk
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ─── Profile-Driven Defaults ────────────────────────────────────────────────
+
+// Profile preference keys read by WithProfileDefaults.
+const (
+	// PreferenceDetailLevel is "concise" or "detailed".
+	PreferenceDetailLevel = "detail_level"
+	// PreferenceLanguage is the natural language replies should be written in,
+	// as a name or code (e.g. "German", "de").
+	PreferenceLanguage = "language"
+)
+
+// Max tokens applied for each detail level when the request leaves MaxTokens unset.
+const (
+	conciseMaxTokens  = 512
+	detailedMaxTokens = 4096
+)
+
+type profileDefaults struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[profileKey]profileEntry
+}
+
+// profileKey scopes cached preferences to a namespace, since the same user ID
+// may belong to different tenants.
+type profileKey struct {
+	namespace string
+	userID    string
+}
+
+type profileEntry struct {
+	prefs   map[string]string
+	fetched time.Time
+}
+
+// WithProfileDefaults personalizes chat requests from the user's profile. The
+// profile of the request's user (opts.UserID, then context options, then
+// SetUserID) in the request's namespace is fetched once and cached for ttl (0
+// caches until UpdateProfile changes it). Its preferences then fill in request
+// defaults:
+//
+//   - detail_level "concise" or "detailed" sets MaxTokens, if unset, and adds a
+//     matching system prompt hint.
+//   - language adds an instruction to reply in that language.
+//
+// Profile lookups that fail are ignored; the request is sent unpersonalized.
+func (c *Client) WithProfileDefaults(ttl time.Duration) *Client {
+	c.profileDefaults = &profileDefaults{ttl: ttl, entries: make(map[profileKey]profileEntry)}
+	return c
+}
+
+// preferences returns the cached preferences for key, fetching them if needed.
+// ctx must carry key's namespace.
+func (c *Client) preferences(ctx context.Context, key profileKey) map[string]string {
+	pd := c.profileDefaults
+	pd.mu.Lock()
+	entry, ok := pd.entries[key]
+	pd.mu.Unlock()
+	if ok && (pd.ttl <= 0 || time.Since(entry.fetched) < pd.ttl) {
+		return entry.prefs
+	}
+
+	profile, err := c.GetProfile(ctx, key.userID)
+	if err != nil {
+		return entry.prefs
+	}
+	pd.mu.Lock()
+	pd.entries[key] = profileEntry{prefs: profile.Preferences, fetched: time.Now()}
+	pd.mu.Unlock()
+	return profile.Preferences
+}
+
+// forgetProfile drops userID's cached preferences in every namespace.
+func (c *Client) forgetProfile(userID string) {
+	if c.profileDefaults == nil {
+		return
+	}
+	c.profileDefaults.mu.Lock()
+	for key := range c.profileDefaults.entries {
+		if key.userID == userID {
+			delete(c.profileDefaults.entries, key)
+		}
+	}
+	c.profileDefaults.mu.Unlock()
+}
+
+func overrideProfileKey(key profileKey, opts RequestOptions) profileKey {
+	if opts.Namespace != "" {
+		key.namespace = opts.Namespace
+	}
+	if opts.UserID != "" {
+		key.userID = opts.UserID
+	}
+	return key
+}
+
+// applyProfileDefaults personalizes req for the resolved user and namespace,
+// each taken from opts (the per-call options, if any), then context options,
+// then the client.
+func (c *Client) applyProfileDefaults(ctx context.Context, req *ChatRequest, opts *RequestOptions) {
+	if c.profileDefaults == nil {
+		return
+	}
+	key := profileKey{namespace: c.namespace, userID: c.userID}
+	if ctxOpts, ok := RequestOptionsFromContext(ctx); ok {
+		key = overrideProfileKey(key, ctxOpts)
+	}
+	if opts != nil {
+		key = overrideProfileKey(key, *opts)
+		if opts.Namespace != "" {
+			ctx = WithRequestOptions(ctx, RequestOptions{Namespace: opts.Namespace})
+		}
+	}
+	if key.userID == "" {
+		return
+	}
+
+	prefs := c.preferences(ctx, key)
+	var hints []string
+	switch strings.ToLower(prefs[PreferenceDetailLevel]) {
+	case "concise", "brief":
+		if req.MaxTokens == nil && req.MaxCompletionTokens == nil {
+			req.MaxTokens = IntPtr(conciseMaxTokens)
+		}
+		hints = append(hints, "Keep answers concise.")
+	case "detailed":
+		if req.MaxTokens == nil && req.MaxCompletionTokens == nil {
+			req.MaxTokens = IntPtr(detailedMaxTokens)
+		}
+		hints = append(hints, "Give detailed, thorough answers.")
+	}
+	if lang := prefs[PreferenceLanguage]; lang != "" {
+		hints = append(hints, "Reply in "+lang+".")
+	}
+	if len(hints) == 0 {
+		return
+	}
+
+	hint := strings.Join(hints, " ")
+	msgs := make([]Message, 0, len(req.Messages)+1)
+	if len(req.Messages) > 0 && req.Messages[0].Role == "system" {
+		if text, ok := req.Messages[0].Content.(string); ok {
+			first := req.Messages[0]
+			first.Content = text + "\n\n" + hint
+			req.Messages = append(append(msgs, first), req.Messages[1:]...)
+			return
+		}
+	}
+	msgs = append(msgs, Message{Role: "system", Content: hint})
+	req.Messages = append(msgs, req.Messages...)
+}
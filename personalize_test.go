@@ -0,0 +1,114 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestProfileDefaults(t *testing.T) {
+	profileFetches := 0
+	var last ChatRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/profile", func(w http.ResponseWriter, r *http.Request) {
+		profileFetches++
+		if r.Header.Get("X-User-ID") != "user-9" {
+			t.Errorf("expected profile lookup for user-9, got %q", r.Header.Get("X-User-ID"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(UserProfile{
+			UserID:      "user-9",
+			Preferences: map[string]string{PreferenceDetailLevel: "concise", PreferenceLanguage: "German"},
+		})
+	})
+	mux.HandleFunc("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		last = ChatRequest{}
+		json.NewDecoder(r.Body).Decode(&last)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-profile"})
+	})
+	srv := newTestServerFunc(mux.ServeHTTP)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithProfileDefaults(time.Hour)
+	ctx := context.Background()
+	msgs := []Message{{Role: "system", Content: "You are a SOC assistant."}, {Role: "user", Content: "hi"}}
+
+	if _, err := client.ChatCompletionWithOptions(ctx, ChatRequest{Model: ModelDefault, Messages: msgs}, RequestOptions{UserID: "user-9"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if last.MaxTokens == nil || *last.MaxTokens != conciseMaxTokens {
+		t.Errorf("expected concise max_tokens, got %v", last.MaxTokens)
+	}
+	if len(last.Messages) != 2 || last.Messages[0].Content != "You are a SOC assistant.\n\nKeep answers concise. Reply in German." {
+		t.Errorf("expected hint appended to system prompt, got %+v", last.Messages)
+	}
+	if msgs[0].Content != "You are a SOC assistant." {
+		t.Error("expected caller's messages not to be modified")
+	}
+
+	_, err := client.ChatCompletionWithOptions(ctx, ChatRequest{
+		Model:     ModelDefault,
+		Messages:  []Message{{Role: "user", Content: "hi"}},
+		MaxTokens: IntPtr(50),
+	}, RequestOptions{UserID: "user-9"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *last.MaxTokens != 50 || len(last.Messages) != 2 || last.Messages[0].Role != "system" {
+		t.Errorf("expected explicit max_tokens kept and a new system hint, got %+v", last)
+	}
+	if profileFetches != 1 {
+		t.Errorf("expected profile to be fetched once, got %d", profileFetches)
+	}
+
+	if _, err := client.ChatCompletion(ctx, ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if last.MaxTokens != nil || len(last.Messages) != 1 {
+		t.Errorf("expected no personalization without a user, got %+v", last)
+	}
+}
+
+func TestProfileDefaultsScopedByNamespace(t *testing.T) {
+	var fetchedFor []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/profile", func(w http.ResponseWriter, r *http.Request) {
+		ns := r.Header.Get("X-Namespace")
+		fetchedFor = append(fetchedFor, ns)
+		level := "concise"
+		if ns == "tenant-b" {
+			level = "detailed"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(UserProfile{UserID: "user-9", Preferences: map[string]string{PreferenceDetailLevel: level}})
+	})
+	var last ChatRequest
+	mux.HandleFunc("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		last = ChatRequest{}
+		json.NewDecoder(r.Body).Decode(&last)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-profile"})
+	})
+	srv := newTestServerFunc(mux.ServeHTTP)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").SetNamespace("tenant-a").WithProfileDefaults(time.Hour)
+	ctx := context.Background()
+	req := ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}
+
+	client.ChatCompletionWithOptions(ctx, req, RequestOptions{UserID: "user-9"})
+	if last.MaxTokens == nil || *last.MaxTokens != conciseMaxTokens {
+		t.Errorf("expected tenant-a concise max_tokens, got %v", last.MaxTokens)
+	}
+	client.ChatCompletionWithOptions(ctx, req, RequestOptions{UserID: "user-9", Namespace: "tenant-b"})
+	if last.MaxTokens == nil || *last.MaxTokens != detailedMaxTokens {
+		t.Errorf("expected tenant-b detailed max_tokens, got %v", last.MaxTokens)
+	}
+	client.ChatCompletionWithOptions(ctx, req, RequestOptions{UserID: "user-9"})
+	if len(fetchedFor) != 2 || fetchedFor[0] != "tenant-a" || fetchedFor[1] != "tenant-b" {
+		t.Errorf("expected one fetch per namespace, got %v", fetchedFor)
+	}
+}
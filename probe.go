@@ -0,0 +1,94 @@
+package hackeserasdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ─── Synthetic Probe ────────────────────────────────────────────────────────
+
+// ProbeCheck is the outcome of a single probe step.
+type ProbeCheck struct {
+	Latency time.Duration
+	Err     error
+}
+
+// OK reports whether the step succeeded.
+func (p ProbeCheck) OK() bool { return p.Err == nil }
+
+// ProbeResult holds the end-to-end latencies measured by Probe.
+type ProbeResult struct {
+	Chat      ProbeCheck
+	Embedding ProbeCheck
+	Search    ProbeCheck
+	// Stream.Latency is the full stream duration; TimeToFirstToken is the delay
+	// until the first chunk carrying content arrived.
+	Stream           ProbeCheck
+	TimeToFirstToken time.Duration
+}
+
+// Healthy reports whether every probe step succeeded.
+func (r *ProbeResult) Healthy() bool {
+	return r.Chat.OK() && r.Embedding.OK() && r.Search.OK() && r.Stream.OK()
+}
+
+// Probe runs a minimal chat completion, embedding, search, and streaming chat
+// against the API and measures each one end to end. It is a synthetic health
+// check that exercises the real request path, unlike Ready which only reports
+// dependency status.
+//
+// Chat probes use the lite model with a one-token budget and cognitive processing
+// disabled, so they do not create conversations or skew user profiles.
+// The returned error joins the errors of all failed steps; the result is always
+// populated.
+func (c *Client) Probe(ctx context.Context) (*ProbeResult, error) {
+	result := &ProbeResult{}
+	chatReq := ChatRequest{
+		Model:     ModelLite,
+		Messages:  []Message{{Role: "user", Content: "ping"}},
+		MaxTokens: IntPtr(1),
+	}
+	opts := RequestOptions{CognitiveDisabled: true}
+
+	start := time.Now()
+	_, err := c.ChatCompletionWithOptions(ctx, chatReq, opts)
+	result.Chat = ProbeCheck{Latency: time.Since(start), Err: err}
+
+	start = time.Now()
+	_, err = c.CreateEmbedding(ctx, EmbeddingRequest{Input: "ping", Model: ModelEmbedding})
+	result.Embedding = ProbeCheck{Latency: time.Since(start), Err: err}
+
+	// A unique query keeps the client-side search cache from answering the probe.
+	start = time.Now()
+	_, err = c.Search(ctx, SearchRequest{Query: fmt.Sprintf("probe %d", start.UnixNano()), TopK: 1})
+	result.Search = ProbeCheck{Latency: time.Since(start), Err: err}
+
+	start = time.Now()
+	chunks, errs := c.ChatCompletionStreamWithOptions(ctx, chatReq, opts)
+	for chunk := range chunks {
+		if result.TimeToFirstToken == 0 && len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+			result.TimeToFirstToken = time.Since(start)
+		}
+	}
+	err = <-errs
+	result.Stream = ProbeCheck{Latency: time.Since(start), Err: err}
+
+	var failed []error
+	for _, step := range []struct {
+		name  string
+		check ProbeCheck
+	}{
+		{"chat", result.Chat},
+		{"embedding", result.Embedding},
+		{"search", result.Search},
+		{"stream", result.Stream},
+	} {
+		if step.check.Err != nil {
+			failed = append(failed, fmt.Errorf("probe %s: %w", step.name, step.check.Err))
+		}
+	}
+
+	return result, errors.Join(failed...)
+}
@@ -0,0 +1,83 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newProbeServer(t *testing.T, failSearch bool) *scriptedServer {
+	t.Helper()
+	return newScriptedServer(t).
+		withSSE(
+			`{"choices":[{"index":0,"delta":{"role":"assistant"}}]}`,
+			`{"choices":[{"index":0,"delta":{"content":"p"}}]}`,
+		).
+		handle("/v1/embeddings", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(EmbeddingResponse{Data: []EmbeddingData{{Embedding: []float64{0.1}}}})
+		}).
+		handle("/v1/search", func(w http.ResponseWriter, r *http.Request) {
+			if failSearch {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"error":{"message":"pgvector down","type":"server_error"}}`))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(SearchResponse{Object: "list"})
+		})
+}
+
+// checkProbeChats reports probe chat requests that did not use the lite model
+// with cognitive processing disabled.
+func checkProbeChats(t *testing.T, srv *scriptedServer) {
+	t.Helper()
+	for _, req := range srv.chats() {
+		if req.Model != ModelLite {
+			t.Errorf("expected probe to use %q, got %q", ModelLite, req.Model)
+		}
+	}
+	if srv.last("/v1/chat/completions").Header.Get("X-Cognitive-Disabled") != "true" {
+		t.Errorf("expected probe to disable cognitive processing")
+	}
+}
+
+func TestProbe(t *testing.T) {
+	srv := newProbeServer(t, false)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	result, err := client.Probe(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Healthy() {
+		t.Errorf("expected healthy probe, got %+v", result)
+	}
+	if result.Chat.Latency <= 0 || result.Embedding.Latency <= 0 || result.Search.Latency <= 0 || result.Stream.Latency <= 0 {
+		t.Errorf("expected all latencies to be measured, got %+v", result)
+	}
+	if result.TimeToFirstToken <= 0 || result.TimeToFirstToken > result.Stream.Latency {
+		t.Errorf("expected time to first token within stream duration, got %v / %v", result.TimeToFirstToken, result.Stream.Latency)
+	}
+	checkProbeChats(t, srv)
+}
+
+func TestProbeReportsFailedSteps(t *testing.T) {
+	srv := newProbeServer(t, true)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	result, err := client.Probe(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "probe search") {
+		t.Fatalf("expected search failure in error, got %v", err)
+	}
+	if result.Healthy() || result.Search.OK() {
+		t.Error("expected search step to be marked failed")
+	}
+	if !result.Chat.OK() || !result.Embedding.OK() || !result.Stream.OK() {
+		t.Error("expected other steps to succeed")
+	}
+}
@@ -0,0 +1,160 @@
+package hackeserasdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ─── Conversation Promotion ─────────────────────────────────────────────────
+
+// Tags set on documents created by PromoteConversationToDocument.
+const (
+	PromotedSourceTag         = "source"
+	PromotedConversationIDTag = "conversation_id"
+)
+
+const defaultPromoteSummaryPrompt = "Rewrite the support conversation below as a short knowledge-base " +
+	"entry: state the problem and the resolution. Leave out greetings, names, and anything not needed " +
+	"to solve the problem again. Reply with the entry only."
+
+// PromoteOptions controls how a conversation becomes a knowledge-base document.
+type PromoteOptions struct {
+	// Filename defaults to "conversation-<id>.md".
+	Filename string
+	// Tags are added to the document, next to the source and conversation_id tags.
+	Tags map[string]string
+	// Summarize prepends a model-written summary of the thread.
+	Summarize bool
+	// SummaryModel is the model used when Summarize is set. Defaults to ModelDefault.
+	SummaryModel string
+	// SummaryPrompt overrides the instruction given to the summary model.
+	SummaryPrompt string
+	// SummaryOnly uploads the summary without the cleaned transcript. Implies Summarize.
+	SummaryOnly bool
+}
+
+// PromoteConversationToDocument turns a resolved conversation into a knowledge-base
+// document so later chats can retrieve the answer. Only user and assistant turns
+// are kept; whitespace is normalized and empty turns are dropped. The document is
+// tagged with source "conversation" and the conversation ID, and uploaded with
+// UploadDocument.
+func (c *Client) PromoteConversationToDocument(ctx context.Context, conversationID string, opts PromoteOptions) (*DocumentResponse, error) {
+	conv, err := c.GetConversation(ctx, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("get conversation: %w", err)
+	}
+
+	transcript := promotedTranscript(conv.Turns)
+	if transcript == "" {
+		return nil, fmt.Errorf("conversation %s has no user or assistant turns", conversationID)
+	}
+
+	var doc strings.Builder
+	title := strings.TrimSpace(conv.Title)
+	if title == "" {
+		title = "Conversation " + conv.ID
+	}
+	fmt.Fprintf(&doc, "# %s\n\n", title)
+
+	if opts.Summarize || opts.SummaryOnly {
+		summary, err := c.summarizeConversation(ctx, transcript, opts)
+		if err != nil {
+			return nil, fmt.Errorf("summarize conversation: %w", err)
+		}
+		fmt.Fprintf(&doc, "## Summary\n\n%s\n", summary)
+		if !opts.SummaryOnly {
+			doc.WriteString("\n")
+		}
+	}
+	if !opts.SummaryOnly {
+		fmt.Fprintf(&doc, "## Transcript\n\n%s", transcript)
+	}
+
+	tags := make(map[string]string, len(opts.Tags)+2)
+	for k, v := range opts.Tags {
+		tags[k] = v
+	}
+	tags[PromotedSourceTag] = "conversation"
+	tags[PromotedConversationIDTag] = conv.ID
+
+	filename := opts.Filename
+	if filename == "" {
+		filename = "conversation-" + conv.ID + ".md"
+	}
+
+	return c.UploadDocument(ctx, DocumentUploadRequest{
+		Content:  doc.String(),
+		Filename: filename,
+		Tags:     tags,
+	})
+}
+
+// promotedTranscript renders the user and assistant turns as Markdown.
+func promotedTranscript(turns []ConversationTurn) string {
+	var b strings.Builder
+	for _, t := range turns {
+		var speaker string
+		switch t.Role {
+		case "user":
+			speaker = "User"
+		case "assistant":
+			speaker = "Assistant"
+		default:
+			continue
+		}
+		content := cleanTurn(t.Content)
+		if content == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "**%s:** %s\n\n", speaker, content)
+	}
+	return b.String()
+}
+
+// cleanTurn trims trailing spaces from each line and collapses runs of blank lines.
+func cleanTurn(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	out := lines[:0]
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			if blank {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// summarizeConversation asks the summary model for a knowledge-base entry.
+func (c *Client) summarizeConversation(ctx context.Context, transcript string, opts PromoteOptions) (string, error) {
+	model := opts.SummaryModel
+	if model == "" {
+		model = ModelDefault
+	}
+	prompt := opts.SummaryPrompt
+	if prompt == "" {
+		prompt = defaultPromoteSummaryPrompt
+	}
+
+	resp, err := c.ChatCompletionWithOptions(ctx, ChatRequest{
+		Model: model,
+		Messages: []Message{
+			{Role: "system", Content: prompt},
+			{Role: "user", Content: transcript},
+		},
+	}, RequestOptions{CognitiveDisabled: true})
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("empty summary response")
+	}
+	return strings.TrimSpace(messageText(resp.Choices[0].Message)), nil
+}
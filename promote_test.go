@@ -0,0 +1,88 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newPromoteServer(t *testing.T, uploaded *DocumentUploadRequest) *scriptedServer {
+	t.Helper()
+	return newScriptedServer(t, assistantReply("Fix: disable adapter power saving.")).
+		handle("/v1/conversations/conv-7", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(ConversationDetail{
+				ID:    "conv-7",
+				Title: "VPN drops after sleep",
+				Turns: []ConversationTurn{
+					{Role: "system", Content: "You are a support agent."},
+					{Role: "user", Content: "My VPN drops after sleep.  \n\n\n\nAny fix?"},
+					{Role: "assistant", Content: "Disable power saving on the adapter."},
+					{Role: "user", Content: "   "},
+				},
+			})
+		}).
+		handle("/v1/documents", func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(uploaded)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(DocumentResponse{ID: "doc-1", Status: "processing"})
+		})
+}
+
+func TestPromoteConversationToDocument(t *testing.T) {
+	var uploaded DocumentUploadRequest
+	srv := newPromoteServer(t, &uploaded)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	doc, err := client.PromoteConversationToDocument(context.Background(), "conv-7", PromoteOptions{
+		Tags: map[string]string{"product": "vpn"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doc.ID != "doc-1" {
+		t.Errorf("expected doc-1, got %s", doc.ID)
+	}
+	if n := srv.count("/v1/chat/completions"); n != 0 {
+		t.Errorf("expected no summary request, got %d", n)
+	}
+
+	want := "# VPN drops after sleep\n\n## Transcript\n\n" +
+		"**User:** My VPN drops after sleep.\n\nAny fix?\n\n" +
+		"**Assistant:** Disable power saving on the adapter.\n\n"
+	if uploaded.Content != want {
+		t.Errorf("unexpected content:\n%q", uploaded.Content)
+	}
+	if uploaded.Filename != "conversation-conv-7.md" {
+		t.Errorf("expected default filename, got %s", uploaded.Filename)
+	}
+	if uploaded.Tags["source"] != "conversation" || uploaded.Tags["conversation_id"] != "conv-7" || uploaded.Tags["product"] != "vpn" {
+		t.Errorf("unexpected tags: %v", uploaded.Tags)
+	}
+}
+
+func TestPromoteConversationSummaryOnly(t *testing.T) {
+	var uploaded DocumentUploadRequest
+	srv := newPromoteServer(t, &uploaded)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	if _, err := client.PromoteConversationToDocument(context.Background(), "conv-7", PromoteOptions{SummaryOnly: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chats := srv.chats()
+	if len(chats) != 1 {
+		t.Fatalf("expected 1 summary request, got %d", len(chats))
+	}
+	if !strings.Contains(messageText(chats[0].Messages[1]), "Disable power saving") {
+		t.Errorf("expected transcript in summary request, got %v", chats[0].Messages[1].Content)
+	}
+	want := "# VPN drops after sleep\n\n## Summary\n\nFix: disable adapter power saving.\n"
+	if uploaded.Content != want {
+		t.Errorf("unexpected content:\n%q", uploaded.Content)
+	}
+}
@@ -0,0 +1,105 @@
+package hackeserasdk
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ─── Rate Limits ────────────────────────────────────────────────────────────
+
+// RateLimitInfo is the rate limit state reported by X-RateLimit-* response headers.
+// Counts are -1 when the server did not send them.
+type RateLimitInfo struct {
+	LimitRequests     int
+	RemainingRequests int
+	// ResetRequests is how long until the request budget is replenished.
+	ResetRequests time.Duration
+
+	LimitTokens     int
+	RemainingTokens int
+	ResetTokens     time.Duration
+}
+
+// parseRateLimit reads X-RateLimit-{Limit,Remaining,Reset}-{Requests,Tokens}.
+// The unsuffixed X-RateLimit-{Limit,Remaining,Reset} headers are read as the
+// request budget. It returns nil if no rate limit headers are present.
+func parseRateLimit(h http.Header) *RateLimitInfo {
+	if h.Get("X-RateLimit-Limit-Requests") == "" && h.Get("X-RateLimit-Limit") == "" &&
+		h.Get("X-RateLimit-Limit-Tokens") == "" && h.Get("X-RateLimit-Remaining-Requests") == "" &&
+		h.Get("X-RateLimit-Remaining") == "" && h.Get("X-RateLimit-Remaining-Tokens") == "" {
+		return nil
+	}
+	first := func(names ...string) string {
+		for _, n := range names {
+			if v := h.Get(n); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	return &RateLimitInfo{
+		LimitRequests:     rateLimitCount(first("X-RateLimit-Limit-Requests", "X-RateLimit-Limit")),
+		RemainingRequests: rateLimitCount(first("X-RateLimit-Remaining-Requests", "X-RateLimit-Remaining")),
+		ResetRequests:     rateLimitReset(first("X-RateLimit-Reset-Requests", "X-RateLimit-Reset")),
+		LimitTokens:       rateLimitCount(h.Get("X-RateLimit-Limit-Tokens")),
+		RemainingTokens:   rateLimitCount(h.Get("X-RateLimit-Remaining-Tokens")),
+		ResetTokens:       rateLimitReset(h.Get("X-RateLimit-Reset-Tokens")),
+	}
+}
+
+func rateLimitCount(v string) int {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// rateLimitReset accepts a Go duration ("6m0s"), seconds ("30"), or a Unix timestamp.
+func rateLimitReset(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if d, err := time.ParseDuration(v); err == nil {
+		return d
+	}
+	secs, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0
+	}
+	// Values this large are absolute Unix times rather than relative seconds.
+	if secs > 1_000_000_000 {
+		return time.Until(time.Unix(secs, 0))
+	}
+	return time.Duration(secs) * time.Second
+}
+
+type rateLimitTracker struct {
+	mu   sync.Mutex
+	last *RateLimitInfo
+}
+
+func (t *rateLimitTracker) record(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	if info := parseRateLimit(resp.Header); info != nil {
+		t.mu.Lock()
+		t.last = info
+		t.mu.Unlock()
+	}
+}
+
+// RateLimit returns the most recent rate limit state reported by the server on
+// any endpoint, or nil if none has been seen. Use it to throttle before hitting 429s.
+func (c *Client) RateLimit() *RateLimitInfo {
+	c.rateLimit.mu.Lock()
+	defer c.rateLimit.mu.Unlock()
+	if c.rateLimit.last == nil {
+		return nil
+	}
+	info := *c.rateLimit.last
+	return &info
+}
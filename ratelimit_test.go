@@ -0,0 +1,71 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimitHeaders(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit-Requests", "600")
+		w.Header().Set("X-RateLimit-Remaining-Requests", "599")
+		w.Header().Set("X-RateLimit-Reset-Requests", "100ms")
+		w.Header().Set("X-RateLimit-Limit-Tokens", "150000")
+		w.Header().Set("X-RateLimit-Remaining-Tokens", "149000")
+		w.Header().Set("X-RateLimit-Reset-Tokens", "6m0s")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-rl"})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	if client.RateLimit() != nil {
+		t.Error("expected no rate limit info before any request")
+	}
+	resp, err := client.ChatCompletion(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := RateLimitInfo{
+		LimitRequests: 600, RemainingRequests: 599, ResetRequests: 100 * time.Millisecond,
+		LimitTokens: 150000, RemainingTokens: 149000, ResetTokens: 6 * time.Minute,
+	}
+	if resp.RateLimit == nil || *resp.RateLimit != want {
+		t.Errorf("expected %+v, got %+v", want, resp.RateLimit)
+	}
+	if got := client.RateLimit(); got == nil || *got != want {
+		t.Errorf("expected client to track last rate limit, got %+v", got)
+	}
+}
+
+func TestAPIErrorRetryAfter(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "12")
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "12")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"message":"rate limited","type":"rate_limit_error"}}`))
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	_, err := client.ListModels(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if apiErr.RetryAfter != 12*time.Second {
+		t.Errorf("expected RetryAfter 12s, got %v", apiErr.RetryAfter)
+	}
+	if apiErr.RateLimit == nil || apiErr.RateLimit.RemainingRequests != 0 || apiErr.RateLimit.ResetRequests != 12*time.Second {
+		t.Errorf("unexpected rate limit info: %+v", apiErr.RateLimit)
+	}
+	if apiErr.RateLimit.LimitTokens != -1 {
+		t.Errorf("expected missing token limit to be -1, got %d", apiErr.RateLimit.LimitTokens)
+	}
+}
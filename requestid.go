@@ -0,0 +1,57 @@
+package hackeserasdk
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// ─── Client Request IDs ─────────────────────────────────────────────────────
+
+// HeaderClientRequestID is the header carrying the caller-side request ID.
+// It is sent on every authenticated request and echoed on ChatResponse and APIError,
+// so a single user action can be traced across services and HackersEra support.
+const HeaderClientRequestID = "X-Client-Request-ID"
+
+// HeaderRequestID is the request ID header understood by the API and most
+// gateways. The client sends its X-Client-Request-ID value in it too, and
+// reads the ID the server assigned back from the response: that ID is the
+// RequestID of the call's UsageRecord, echoed as ChatResponse.RequestID and
+// APIError.RequestID.
+const HeaderRequestID = "X-Request-ID"
+
+// setRequestID sets both request ID headers to id.
+func setRequestID(h http.Header, id string) {
+	h.Set(HeaderClientRequestID, id)
+	h.Set(HeaderRequestID, id)
+}
+
+type clientRequestIDKey struct{}
+
+// WithClientRequestID returns a context that makes every SDK call made with it
+// send id as X-Client-Request-ID instead of a generated one.
+func WithClientRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, clientRequestIDKey{}, id)
+}
+
+// ClientRequestIDFromContext returns the request ID set with WithClientRequestID.
+func ClientRequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(clientRequestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// newClientRequestID generates a random 128-bit request ID.
+func newClientRequestID() string {
+	return randomID("req_")
+}
+
+// randomID returns prefix followed by 128 random bits in hex, or "" if the
+// system random source fails.
+func randomID(prefix string) string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	return prefix + hex.EncodeToString(b[:])
+}
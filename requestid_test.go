@@ -0,0 +1,113 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClientRequestIDGenerated(t *testing.T) {
+	var seen []string
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get(HeaderClientRequestID))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-rid"})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	resp, err := client.ChatCompletion(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.ChatCompletion(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+
+	if len(seen) != 2 || !strings.HasPrefix(seen[0], "req_") {
+		t.Fatalf("expected generated request IDs, got %v", seen)
+	}
+	if seen[0] == seen[1] {
+		t.Error("expected a fresh request ID per call")
+	}
+	if resp.ClientRequestID != seen[0] {
+		t.Errorf("expected response to echo %q, got %q", seen[0], resp.ClientRequestID)
+	}
+}
+
+func TestClientRequestIDFromContextAndOptions(t *testing.T) {
+	var got string
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(HeaderClientRequestID)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-rid"})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	ctx := WithClientRequestID(context.Background(), "action-123")
+
+	if _, err := client.ListModels(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "action-123" {
+		t.Errorf("expected context request ID, got %q", got)
+	}
+
+	resp, err := client.ChatCompletionWithOptions(ctx, ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}, RequestOptions{ClientRequestID: "opt-456"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "opt-456" || resp.ClientRequestID != "opt-456" {
+		t.Errorf("expected options to override context, sent %q, echoed %q", got, resp.ClientRequestID)
+	}
+}
+
+func TestClientRequestIDOnAPIError(t *testing.T) {
+	srv := newTestServer(t, http.MethodGet, "/v1/models", http.StatusInternalServerError, ErrorResponse{
+		Error: ErrorDetail{Message: "boom", Type: "server_error"},
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	_, err := client.ListModels(WithClientRequestID(context.Background(), "trace-me"))
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+	if apiErr.ClientRequestID != "trace-me" {
+		t.Errorf("expected error to carry request ID, got %q", apiErr.ClientRequestID)
+	}
+}
+
+func TestServerRequestIDEchoed(t *testing.T) {
+	var sent string
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = r.Header.Get(HeaderRequestID)
+		w.Header().Set(HeaderRequestID, "srv-"+sent)
+		if r.URL.Path == "/v1/usage" {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: ErrorDetail{Message: "forbidden"}})
+			return
+		}
+		json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-rid"})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	ctx := WithClientRequestID(context.Background(), "action-1")
+	resp, err := client.ChatCompletion(ctx, ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent != "action-1" || resp.RequestID != "srv-action-1" {
+		t.Errorf("sent %q, echoed %q", sent, resp.RequestID)
+	}
+
+	_, err = client.GetUsage(ctx)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RequestID != "srv-action-1" {
+		t.Errorf("expected APIError with server request ID, got %#v", err)
+	}
+}
@@ -0,0 +1,313 @@
+package hackeserasdk
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// ─── Resource Clients ───────────────────────────────────────────────────────
+
+// The resource clients group the Client's methods by API area, so each area
+// is discoverable on its own and can be swapped for a fake behind a small
+// interface in tests:
+//
+//	type documentStore interface {
+//		Upload(ctx context.Context, req hackeserasdk.DocumentUploadRequest) (*hackeserasdk.DocumentResponse, error)
+//		Delete(ctx context.Context, docID string) (*hackeserasdk.DocumentDeleteResponse, error)
+//	}
+//
+//	var docs documentStore = client.Documents
+//
+// Each method is the flat Client method named in its comment, with the same
+// behavior; both forms share the client's configuration and middleware.
+
+// ChatClient covers chat completions, streams, sessions, and tool runs.
+// Use it as Client.Chat.
+type ChatClient struct {
+	client *Client
+}
+
+// DocumentsClient covers the RAG knowledge base documents and search.
+// Use it as Client.Documents.
+type DocumentsClient struct {
+	client *Client
+}
+
+// ConversationsClient covers stored conversations and their feedback.
+// Use it as Client.Conversations.
+type ConversationsClient struct {
+	client *Client
+}
+
+// KnowledgeClient covers the knowledge graph, learned facts, snapshots, and re-embedding.
+// Use it as Client.Knowledge.
+type KnowledgeClient struct {
+	client *Client
+}
+
+// UsageClient covers usage statistics and alerts.
+// Use it as Client.Usage.
+type UsageClient struct {
+	client *Client
+}
+
+// Completion is Client.ChatCompletion.
+func (ch *ChatClient) Completion(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	return ch.client.ChatCompletion(ctx, req)
+}
+
+// CompletionWithOptions is Client.ChatCompletionWithOptions.
+func (ch *ChatClient) CompletionWithOptions(ctx context.Context, req ChatRequest, opts RequestOptions) (*ChatResponse, error) {
+	return ch.client.ChatCompletionWithOptions(ctx, req, opts)
+}
+
+// Stream is Client.StreamChat.
+func (ch *ChatClient) Stream(ctx context.Context, req ChatRequest) (*ChatStream, error) {
+	return ch.client.StreamChat(ctx, req)
+}
+
+// StreamWithOptions is Client.StreamChatWithOptions.
+func (ch *ChatClient) StreamWithOptions(ctx context.Context, req ChatRequest, opts RequestOptions) (*ChatStream, error) {
+	return ch.client.StreamChatWithOptions(ctx, req, opts)
+}
+
+// StreamEvents is Client.StreamEvents.
+func (ch *ChatClient) StreamEvents(ctx context.Context, req ChatRequest) (*EventStream, error) {
+	return ch.client.StreamEvents(ctx, req)
+}
+
+// StreamEventsWithOptions is Client.StreamEventsWithOptions.
+func (ch *ChatClient) StreamEventsWithOptions(ctx context.Context, req ChatRequest, opts RequestOptions) (*EventStream, error) {
+	return ch.client.StreamEventsWithOptions(ctx, req, opts)
+}
+
+// NewSession is Client.NewChatSession.
+func (ch *ChatClient) NewSession(template ChatRequest) *ChatSession {
+	return ch.client.NewChatSession(template)
+}
+
+// RunTools is Client.RunTools.
+func (ch *ChatClient) RunTools(ctx context.Context, req ChatRequest, runner *ToolRunner) (*ToolRun, error) {
+	return ch.client.RunTools(ctx, req, runner)
+}
+
+// Upload is Client.UploadDocument.
+func (d *DocumentsClient) Upload(ctx context.Context, req DocumentUploadRequest) (*DocumentResponse, error) {
+	return d.client.UploadDocument(ctx, req)
+}
+
+// UploadBatch is Client.UploadDocuments.
+func (d *DocumentsClient) UploadBatch(ctx context.Context, docs []DocumentUploadRequest) (*DocumentListResponse, error) {
+	return d.client.UploadDocuments(ctx, docs)
+}
+
+// UploadFile is Client.UploadDocumentFile.
+func (d *DocumentsClient) UploadFile(ctx context.Context, req DocumentFileUploadRequest) (*DocumentResponse, error) {
+	return d.client.UploadDocumentFile(ctx, req)
+}
+
+// List is Client.ListDocuments.
+func (d *DocumentsClient) List(ctx context.Context) (*DocumentListResponse, error) {
+	return d.client.ListDocuments(ctx)
+}
+
+// Get is Client.GetDocument.
+func (d *DocumentsClient) Get(ctx context.Context, docID string) (*DocumentResponse, error) {
+	return d.client.GetDocument(ctx, docID)
+}
+
+// GetMany is Client.GetDocuments.
+func (d *DocumentsClient) GetMany(ctx context.Context, ids []string) (*DocumentBatchGetResponse, error) {
+	return d.client.GetDocuments(ctx, ids)
+}
+
+// Delete is Client.DeleteDocument.
+func (d *DocumentsClient) Delete(ctx context.Context, docID string) (*DocumentDeleteResponse, error) {
+	return d.client.DeleteDocument(ctx, docID)
+}
+
+// Summarize is Client.SummarizeDocument.
+func (d *DocumentsClient) Summarize(ctx context.Context, docID string, opts SummaryOptions) (*DocumentSummary, error) {
+	return d.client.SummarizeDocument(ctx, docID, opts)
+}
+
+// ListTags is Client.ListDocumentTags.
+func (d *DocumentsClient) ListTags(ctx context.Context) (*DocumentTagListResponse, error) {
+	return d.client.ListDocumentTags(ctx)
+}
+
+// RenameTag is Client.RenameDocumentTag.
+func (d *DocumentsClient) RenameTag(ctx context.Context, req TagRenameRequest) (*TagUpdateResponse, error) {
+	return d.client.RenameDocumentTag(ctx, req)
+}
+
+// MergeTags is Client.MergeDocumentTags.
+func (d *DocumentsClient) MergeTags(ctx context.Context, req TagMergeRequest) (*TagUpdateResponse, error) {
+	return d.client.MergeDocumentTags(ctx, req)
+}
+
+// Search is Client.Search.
+func (d *DocumentsClient) Search(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
+	return d.client.Search(ctx, req)
+}
+
+// List is Client.ListConversations.
+func (cv *ConversationsClient) List(ctx context.Context, limit int) (*ConversationListResponse, error) {
+	return cv.client.ListConversations(ctx, limit)
+}
+
+// ListWithOptions is Client.ListConversationsWithOptions.
+func (cv *ConversationsClient) ListWithOptions(ctx context.Context, opts ConversationListOptions) (*ConversationListResponse, error) {
+	return cv.client.ListConversationsWithOptions(ctx, opts)
+}
+
+// Get is Client.GetConversation.
+func (cv *ConversationsClient) Get(ctx context.Context, conversationID string) (*ConversationDetail, error) {
+	return cv.client.GetConversation(ctx, conversationID)
+}
+
+// Search is Client.SearchConversations.
+func (cv *ConversationsClient) Search(ctx context.Context, query string, limit int) (*ConversationSearchResponse, error) {
+	return cv.client.SearchConversations(ctx, query, limit)
+}
+
+// Delete is Client.DeleteConversation.
+func (cv *ConversationsClient) Delete(ctx context.Context, conversationID string) (*ConversationDeleteResponse, error) {
+	return cv.client.DeleteConversation(ctx, conversationID)
+}
+
+// AppendTurn is Client.AppendConversationTurn.
+func (cv *ConversationsClient) AppendTurn(ctx context.Context, conversationID string, req ConversationTurnRequest) (*ConversationTurn, error) {
+	return cv.client.AppendConversationTurn(ctx, conversationID, req)
+}
+
+// SubmitFeedback is Client.SubmitFeedback.
+func (cv *ConversationsClient) SubmitFeedback(ctx context.Context, req FeedbackRequest) (*FeedbackResponse, error) {
+	return cv.client.SubmitFeedback(ctx, req)
+}
+
+// PromoteToDocument is Client.PromoteConversationToDocument.
+func (cv *ConversationsClient) PromoteToDocument(ctx context.Context, conversationID string, opts PromoteOptions) (*DocumentResponse, error) {
+	return cv.client.PromoteConversationToDocument(ctx, conversationID, opts)
+}
+
+// QueryGraph is Client.QueryKnowledgeGraph.
+func (k *KnowledgeClient) QueryGraph(ctx context.Context, query string, limit int) (*KnowledgeGraphResponse, error) {
+	return k.client.QueryKnowledgeGraph(ctx, query, limit)
+}
+
+// QueryGraphAsOf is Client.QueryKnowledgeGraphAsOf.
+func (k *KnowledgeClient) QueryGraphAsOf(ctx context.Context, query string, limit int, asOf time.Time) (*KnowledgeGraphResponse, error) {
+	return k.client.QueryKnowledgeGraphAsOf(ctx, query, limit, asOf)
+}
+
+// GraphStats is Client.GetKnowledgeGraphStats.
+func (k *KnowledgeClient) GraphStats(ctx context.Context) (*KnowledgeGraphStats, error) {
+	return k.client.GetKnowledgeGraphStats(ctx)
+}
+
+// GraphStatsWithOptions is Client.GetKnowledgeGraphStatsWithOptions.
+func (k *KnowledgeClient) GraphStatsWithOptions(ctx context.Context, opts KnowledgeGraphStatsOptions) (*KnowledgeGraphStats, error) {
+	return k.client.GetKnowledgeGraphStatsWithOptions(ctx, opts)
+}
+
+// ExtractEntities is Client.ExtractEntities.
+func (k *KnowledgeClient) ExtractEntities(ctx context.Context, req EntityExtractionRequest) (*EntityExtractionResponse, error) {
+	return k.client.ExtractEntities(ctx, req)
+}
+
+// ListFacts is Client.ListFacts.
+func (k *KnowledgeClient) ListFacts(ctx context.Context, limit int, verified *bool) (*FactListResponse, error) {
+	return k.client.ListFacts(ctx, limit, verified)
+}
+
+// CreateFact is Client.CreateFact.
+func (k *KnowledgeClient) CreateFact(ctx context.Context, req FactCreateRequest) (*Fact, error) {
+	return k.client.CreateFact(ctx, req)
+}
+
+// CreateFacts is Client.CreateFacts.
+func (k *KnowledgeClient) CreateFacts(ctx context.Context, facts []FactCreateRequest) (*FactListResponse, error) {
+	return k.client.CreateFacts(ctx, facts)
+}
+
+// UpdateFact is Client.UpdateFact.
+func (k *KnowledgeClient) UpdateFact(ctx context.Context, factID int, req FactUpdateRequest) (*Fact, error) {
+	return k.client.UpdateFact(ctx, factID, req)
+}
+
+// LinkFactToDocument is Client.LinkFactToDocument.
+func (k *KnowledgeClient) LinkFactToDocument(ctx context.Context, factID int, req FactLinkRequest) (*Fact, error) {
+	return k.client.LinkFactToDocument(ctx, factID, req)
+}
+
+// Export is Client.ExportKnowledgeBase.
+func (k *KnowledgeClient) Export(ctx context.Context, w io.Writer, opts ExportOptions) (int64, error) {
+	return k.client.ExportKnowledgeBase(ctx, w, opts)
+}
+
+// CreateSnapshot is Client.CreateSnapshot.
+func (k *KnowledgeClient) CreateSnapshot(ctx context.Context, name string) (*KnowledgeSnapshot, error) {
+	return k.client.CreateSnapshot(ctx, name)
+}
+
+// ListSnapshots is Client.ListSnapshots.
+func (k *KnowledgeClient) ListSnapshots(ctx context.Context) (*SnapshotListResponse, error) {
+	return k.client.ListSnapshots(ctx)
+}
+
+// RestoreSnapshot is Client.RestoreSnapshot.
+func (k *KnowledgeClient) RestoreSnapshot(ctx context.Context, snapshotID string) (*SnapshotRestoreResponse, error) {
+	return k.client.RestoreSnapshot(ctx, snapshotID)
+}
+
+// ReembedAll is Client.ReembedAll.
+func (k *KnowledgeClient) ReembedAll(ctx context.Context, req ReembedRequest) (*ReembedJob, error) {
+	return k.client.ReembedAll(ctx, req)
+}
+
+// GetReembedJob is Client.GetReembedJob.
+func (k *KnowledgeClient) GetReembedJob(ctx context.Context, jobID string) (*ReembedJob, error) {
+	return k.client.GetReembedJob(ctx, jobID)
+}
+
+// WaitForReembedJob is Client.WaitForReembedJob.
+func (k *KnowledgeClient) WaitForReembedJob(ctx context.Context, jobID string, interval time.Duration, onProgress func(*ReembedJob)) (*ReembedJob, error) {
+	return k.client.WaitForReembedJob(ctx, jobID, interval, onProgress)
+}
+
+// Get is Client.GetUsage.
+func (u *UsageClient) Get(ctx context.Context) (*UsageResponse, error) {
+	return u.client.GetUsage(ctx)
+}
+
+// GetUser is Client.GetUserUsage.
+func (u *UsageClient) GetUser(ctx context.Context, userID string, r TimeRange) (*UserUsage, error) {
+	return u.client.GetUserUsage(ctx, userID, r)
+}
+
+// Recent is Client.GetRecentUsage.
+func (u *UsageClient) Recent(ctx context.Context) (*UsageRecentResponse, error) {
+	return u.client.GetRecentUsage(ctx)
+}
+
+// CreateAlert is Client.CreateUsageAlert.
+func (u *UsageClient) CreateAlert(ctx context.Context, req UsageAlertRequest) (*UsageAlert, error) {
+	return u.client.CreateUsageAlert(ctx, req)
+}
+
+// ListAlerts is Client.ListUsageAlerts.
+func (u *UsageClient) ListAlerts(ctx context.Context) (*UsageAlertListResponse, error) {
+	return u.client.ListUsageAlerts(ctx)
+}
+
+// DeleteAlert is Client.DeleteUsageAlert.
+func (u *UsageClient) DeleteAlert(ctx context.Context, alertID string) (*UsageAlertDeleteResponse, error) {
+	return u.client.DeleteUsageAlert(ctx, alertID)
+}
+
+// CacheStats is Client.GetCacheStats.
+func (u *UsageClient) CacheStats(ctx context.Context) (*CacheStatsResponse, error) {
+	return u.client.GetCacheStats(ctx)
+}
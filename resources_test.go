@@ -0,0 +1,46 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestResourceClients(t *testing.T) {
+	srv := newScriptedServer(t, assistantReply("hello")).
+		handle("/v1/documents/doc-1", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(DocumentDeleteResponse{ID: "doc-1", Deleted: true})
+		})
+	defer srv.Close()
+
+	var sent []string
+	client := NewClient(srv.URL, "test-key").Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			sent = append(sent, req.URL.Path)
+			return next(req)
+		}
+	})
+
+	resp, err := client.Chat.Completion(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Choices[0].Message.ContentAsText() != "hello" {
+		t.Errorf("unexpected reply: %+v", resp.Choices[0].Message)
+	}
+
+	// A sub-client satisfies a caller's narrow interface, so tests can fake one area.
+	var docs interface {
+		Delete(ctx context.Context, docID string) (*DocumentDeleteResponse, error)
+	} = client.Documents
+	del, err := docs.Delete(context.Background(), "doc-1")
+	if err != nil || !del.Deleted {
+		t.Fatalf("unexpected delete result %+v / %v", del, err)
+	}
+
+	if len(sent) != 2 || sent[0] != "/v1/chat/completions" || sent[1] != "/v1/documents/doc-1" {
+		t.Errorf("expected both calls through the client's middleware, got %v", sent)
+	}
+}
@@ -0,0 +1,176 @@
+package hackeserasdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ─── Retries ────────────────────────────────────────────────────────────────
+
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 30 * time.Second
+)
+
+// RetryConfig configures automatic retries of transient failures.
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts, including the first. Values
+	// below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry; it doubles on each retry.
+	// Defaults to 500ms.
+	BaseDelay time.Duration
+	// MaxDelay caps a single backoff. A Retry-After longer than MaxDelay ends
+	// the retries instead of being waited out. Defaults to 30s.
+	MaxDelay time.Duration
+}
+
+// WithRetry retries non-streaming requests that fail transiently, using
+// exponential backoff with jitter. GET, HEAD, PUT, and DELETE requests are
+// retried on a transport error, 429, 500, 502, 503, or 504. Other methods (chat
+// completions, uploads, fact creation) may already have taken effect after a
+// transport error or 5xx, so they are retried only on 429 and 503, where the
+// server refused the request before doing any work; mark a context with
+// WithIdempotent to retry them like a GET. A Retry-After header on 429 and 503
+// responses is honored when it asks for a longer wait, up to MaxDelay; a longer
+// Retry-After ends the retries, and the caller gets the APIError with
+// RetryAfter set. Every attempt of a request carries the same
+// X-Client-Request-ID. Streaming requests and Health are never retried.
+//
+//	client := hackeserasdk.NewClient(baseURL, apiKey).
+//		WithRetry(hackeserasdk.RetryConfig{MaxAttempts: 5, BaseDelay: time.Second})
+func (c *Client) WithRetry(cfg RetryConfig) *Client {
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = defaultRetryBaseDelay
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = defaultRetryMaxDelay
+	}
+	c.retry = &cfg
+	return c
+}
+
+type idempotentKey struct{}
+
+// WithIdempotent returns a context whose requests are retried on every transient
+// failure regardless of method, for calls the caller knows are safe to repeat.
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// idempotent reports whether req may be re-sent after it possibly took effect.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	v, _ := req.Context().Value(idempotentKey{}).(bool)
+	return v
+}
+
+// doRetry sends req with hc, retrying transient failures per the retry config.
+func (c *Client) doRetry(hc *http.Client, req *http.Request) (*http.Response, error) {
+	if c.configErr != nil {
+		return nil, c.configErr
+	}
+	cfg := c.retry
+	for attempt := 1; ; attempt++ {
+		resp, err := c.doWith(hc, req)
+
+		reason := retryReason(req, resp, err)
+		if cfg == nil || reason == "" || attempt >= cfg.MaxAttempts || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+
+		delay := cfg.backoff(attempt)
+		if resp != nil {
+			after := retryAfter(resp)
+			if after > cfg.MaxDelay {
+				// Waiting that long would stall the call; the caller gets the
+				// APIError with RetryAfter set and decides.
+				return resp, err
+			}
+			if after > delay {
+				delay = after
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		c.logger.retry(req, attempt, reason, delay)
+		c.hooks.retry(req, attempt, reason, delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		c.stats.recordRetry(reason)
+	}
+}
+
+// backoff returns the delay before retry number attempt (1-based): exponential
+// growth from BaseDelay, capped at MaxDelay, with "equal jitter" so that clients
+// failing together do not retry in lockstep.
+func (cfg *RetryConfig) backoff(attempt int) time.Duration {
+	d := cfg.BaseDelay
+	for i := 1; i < attempt && d < cfg.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > cfg.MaxDelay {
+		d = cfg.MaxDelay
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// retryReason returns why an attempt should be retried, or "" if it should not.
+// Failures after which the request may have been processed are retried only for
+// idempotent requests.
+func retryReason(req *http.Request, resp *http.Response, err error) string {
+	ctx := req.Context()
+	if err != nil {
+		if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || !idempotent(req) {
+			return ""
+		}
+		return "transport"
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return strconv.Itoa(resp.StatusCode)
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		if idempotent(req) {
+			return strconv.Itoa(resp.StatusCode)
+		}
+	}
+	return ""
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) time.Duration {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
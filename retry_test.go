@@ -0,0 +1,141 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryTransientFailures(t *testing.T) {
+	attempts := 0
+	var ids []string
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		ids = append(ids, r.Header.Get(HeaderClientRequestID))
+		body, _ := io.ReadAll(r.Body)
+		if len(body) == 0 {
+			t.Errorf("attempt %d: expected request body to be replayed", attempts)
+		}
+		switch attempts {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-retry"})
+		}
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithRetry(RetryConfig{MaxAttempts: 5, BaseDelay: time.Millisecond})
+	resp, err := client.ChatCompletion(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.ID != "chatcmpl-retry" || attempts != 3 {
+		t.Errorf("expected success on third attempt, got %q after %d", resp.ID, attempts)
+	}
+	if ids[0] == "" || ids[0] != ids[1] || ids[1] != ids[2] {
+		t.Errorf("expected the same client request ID on every attempt, got %v", ids)
+	}
+
+	stats := client.Stats()
+	if stats.Retries != 2 || stats.RetryReasons["503"] != 1 || stats.RetryReasons["429"] != 1 {
+		t.Errorf("unexpected retry stats: %+v", stats)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	attempts := 0
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`{"error":{"message":"upstream down","type":"server_error"}}`))
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithRetry(RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	_, err := client.ListModels(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected final 502 APIError, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetrySkipsClientErrors(t *testing.T) {
+	attempts := 0
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"message":"bad","type":"invalid_request_error"}}`))
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithRetry(RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	client.ListModels(context.Background())
+	if attempts != 1 {
+		t.Errorf("expected 400 not to be retried, got %d attempts", attempts)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	cfg := RetryConfig{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt, max := range map[int]time.Duration{1: 100 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second} {
+		d := cfg.backoff(attempt)
+		if d < max/2 || d > max {
+			t.Errorf("attempt %d: expected delay in [%v, %v], got %v", attempt, max/2, max, d)
+		}
+	}
+}
+
+func TestRetryNonIdempotentOnlyWhenRefused(t *testing.T) {
+	attempts := 0
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`{"error":{"message":"upstream down","type":"server_error"}}`))
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithRetry(RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	client.CreateFact(context.Background(), FactCreateRequest{Content: "Port 22 is open"})
+	if attempts != 1 {
+		t.Errorf("expected POST not to be retried on 502, got %d attempts", attempts)
+	}
+
+	attempts = 0
+	client.CreateFact(WithIdempotent(context.Background()), FactCreateRequest{Content: "Port 22 is open"})
+	if attempts != 3 {
+		t.Errorf("expected WithIdempotent POST to be retried, got %d attempts", attempts)
+	}
+}
+
+func TestRetryAfterBeyondMaxDelay(t *testing.T) {
+	attempts := 0
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"message":"slow down","type":"rate_limit_error"}}`))
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithRetry(RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second})
+	start := time.Now()
+	_, err := client.ListModels(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter != time.Hour {
+		t.Fatalf("expected 429 APIError with RetryAfter 1h, got %v", err)
+	}
+	if attempts != 1 || time.Since(start) > time.Second {
+		t.Errorf("expected no retry wait, got %d attempts in %v", attempts, time.Since(start))
+	}
+}
@@ -0,0 +1,250 @@
+package hackeserasdk
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ─── JSON Schema ────────────────────────────────────────────────────────────
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+	byteSliceType  = reflect.TypeOf([]byte(nil))
+)
+
+// SchemaFor returns the JSON Schema of T, for use as tool parameters or a
+// response format. Struct fields are named by their json tag and skipped when
+// tagged "-". A field is required unless its json tag has omitempty or it is a
+// pointer. The description and enum (comma-separated) struct tags are copied
+// into the schema:
+//
+//	type ScanParams struct {
+//		Host    string `json:"host" description:"Hostname or IP to scan"`
+//		Profile string `json:"profile,omitempty" enum:"quick,full"`
+//	}
+//
+// Interface fields accept any value. Recursive types are cut off at the first
+// repetition with an unconstrained schema.
+func SchemaFor[T any]() map[string]interface{} {
+	return schemaOf(reflect.TypeOf((*T)(nil)).Elem(), map[reflect.Type]bool{})
+}
+
+func schemaOf(t reflect.Type, seen map[reflect.Type]bool) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return map[string]interface{}{}
+	case byteSliceType:
+		return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaOf(t.Elem(), seen)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaOf(t.Elem(), seen)}
+	case reflect.Struct:
+		if seen[t] {
+			return map[string]interface{}{}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		return structSchema(t, seen)
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func structSchema(t reflect.Type, seen map[reflect.Type]bool) map[string]interface{} {
+	props := map[string]interface{}{}
+	required := []string{}
+	for _, f := range dominantFields(collectFields(t, 0, seen, nil)) {
+		props[f.name] = f.prop
+		if f.required {
+			required = append(required, f.name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// schemaField is a candidate property: a field of the struct or of one of
+// its embedded structs, depth levels down.
+type schemaField struct {
+	name     string
+	depth    int
+	tagged   bool
+	required bool
+	prop     map[string]interface{}
+}
+
+// collectFields appends t's fields to fields in declaration order, flattening
+// embedded structs the way encoding/json does.
+func collectFields(t reflect.Type, depth int, seen map[reflect.Type]bool, fields []schemaField) []schemaField {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				if !f.IsExported() {
+					// encoding/json cannot set fields through an unexported pointer.
+					continue
+				}
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				// A type embedding itself contributes its fields once, as in encoding/json.
+				if !seen[ft] {
+					seen[ft] = true
+					fields = collectFields(ft, depth+1, seen, fields)
+					delete(seen, ft)
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		tagged := name != ""
+		if !tagged {
+			name = f.Name
+		}
+
+		prop := schemaOf(f.Type, seen)
+		if desc := f.Tag.Get("description"); desc != "" {
+			prop["description"] = desc
+		}
+		if enum := f.Tag.Get("enum"); enum != "" {
+			values := strings.Split(enum, ",")
+			for i := range values {
+				values[i] = strings.TrimSpace(values[i])
+			}
+			prop["enum"] = values
+		}
+		fields = append(fields, schemaField{
+			name:     name,
+			depth:    depth,
+			tagged:   tagged,
+			required: !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Ptr,
+			prop:     prop,
+		})
+	}
+	return fields
+}
+
+// dominantFields applies encoding/json's rules to fields sharing a name: the
+// shallowest wins, a tagged field beats untagged ones at the same depth, and
+// any other tie drops the name entirely. Order of first appearance is kept.
+func dominantFields(fields []schemaField) []schemaField {
+	byName := map[string][]schemaField{}
+	var names []string
+	for _, f := range fields {
+		if _, ok := byName[f.name]; !ok {
+			names = append(names, f.name)
+		}
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	out := make([]schemaField, 0, len(names))
+	for _, name := range names {
+		var best []schemaField
+		for _, f := range byName[name] {
+			switch {
+			case len(best) == 0 || f.depth < best[0].depth:
+				best = []schemaField{f}
+			case f.depth == best[0].depth:
+				best = append(best, f)
+			}
+		}
+		if len(best) > 1 {
+			var tagged []schemaField
+			for _, f := range best {
+				if f.tagged {
+					tagged = append(tagged, f)
+				}
+			}
+			if len(tagged) != 1 {
+				continue
+			}
+			best = tagged
+		}
+		out = append(out, best[0])
+	}
+	return out
+}
+
+// strictCompatible reports whether schema can be sent with strict json_schema
+// enforcement. The schema is inspected in its JSON form, so any value that
+// marshals to a schema object is accepted.
+func strictCompatible(schema interface{}) bool {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return false
+	}
+	var node interface{}
+	if err := json.Unmarshal(data, &node); err != nil {
+		return false
+	}
+	return strictNode(node)
+}
+
+func strictNode(node interface{}) bool {
+	obj, ok := node.(map[string]interface{})
+	if !ok || len(obj) == 0 {
+		return false
+	}
+	if obj["type"] == "object" {
+		if ap, ok := obj["additionalProperties"].(bool); !ok || ap {
+			return false
+		}
+		props, _ := obj["properties"].(map[string]interface{})
+		required := map[string]bool{}
+		if list, ok := obj["required"].([]interface{}); ok {
+			for _, name := range list {
+				if s, ok := name.(string); ok {
+					required[s] = true
+				}
+			}
+		}
+		for name, prop := range props {
+			if !required[name] || !strictNode(prop) {
+				return false
+			}
+		}
+	}
+	if items, ok := obj["items"]; ok && !strictNode(items) {
+		return false
+	}
+	return true
+}
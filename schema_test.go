@@ -0,0 +1,136 @@
+package hackeserasdk
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type schemaBase struct {
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type schemaParams struct {
+	schemaBase
+	Host     string            `json:"host" description:"Hostname or IP"`
+	Ports    []int             `json:"ports,omitempty"`
+	Profile  string            `json:"profile" enum:"quick, full"`
+	Timeout  *float64          `json:"timeout"`
+	Since    time.Time         `json:"since,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Internal string            `json:"-"`
+	Next     *schemaParams     `json:"next,omitempty"`
+	hidden   int
+}
+
+func TestSchemaFor(t *testing.T) {
+	got := SchemaFor[schemaParams]()
+	data, _ := json.Marshal(got)
+	var schema map[string]interface{}
+	json.Unmarshal(data, &schema)
+
+	props := schema["properties"].(map[string]interface{})
+	if len(props) != 8 {
+		t.Errorf("expected 8 properties, got %v", props)
+	}
+	if _, ok := props["Internal"]; ok {
+		t.Error("expected json:\"-\" field to be skipped")
+	}
+	host := props["host"].(map[string]interface{})
+	if host["type"] != "string" || host["description"] != "Hostname or IP" {
+		t.Errorf("unexpected host schema: %v", host)
+	}
+	if ports := props["ports"].(map[string]interface{}); ports["type"] != "array" || ports["items"].(map[string]interface{})["type"] != "integer" {
+		t.Errorf("unexpected ports schema: %v", ports)
+	}
+	if enum := props["profile"].(map[string]interface{})["enum"]; !reflect.DeepEqual(enum, []interface{}{"quick", "full"}) {
+		t.Errorf("unexpected enum: %v", enum)
+	}
+	if props["since"].(map[string]interface{})["format"] != "date-time" {
+		t.Errorf("expected date-time format, got %v", props["since"])
+	}
+	if _, ok := props["namespace"]; !ok {
+		t.Error("expected embedded struct fields to be flattened")
+	}
+	if next := props["next"].(map[string]interface{}); len(next) != 0 {
+		t.Errorf("expected recursive field to be unconstrained, got %v", next)
+	}
+
+	required := schema["required"].([]interface{})
+	if !reflect.DeepEqual(required, []interface{}{"host", "profile"}) {
+		t.Errorf("expected host and profile required, got %v", required)
+	}
+}
+
+func TestSchemaForScalars(t *testing.T) {
+	if s := SchemaFor[bool](); s["type"] != "boolean" {
+		t.Errorf("expected boolean, got %v", s)
+	}
+	if s := SchemaFor[[]byte](); s["type"] != "string" {
+		t.Errorf("expected base64 string for []byte, got %v", s)
+	}
+	if s := SchemaFor[interface{}](); len(s) != 0 {
+		t.Errorf("expected empty schema for interface{}, got %v", s)
+	}
+}
+
+type schemaSelfEmbed struct {
+	*schemaSelfEmbed
+	X int `json:"x"`
+}
+
+func TestSchemaForSelfEmbedding(t *testing.T) {
+	s := SchemaFor[schemaSelfEmbed]()
+	props := s["properties"].(map[string]interface{})
+	if len(props) != 1 || props["x"] == nil {
+		t.Errorf("expected only x, got %v", props)
+	}
+}
+
+type schemaIdentified struct {
+	ID   string `json:"id" description:"base ID"`
+	Kind string
+}
+
+type schemaOther struct {
+	Kind  string
+	Owner string
+}
+
+type schemaTaggedOwner struct {
+	Owner string `json:"Owner"`
+}
+
+type schemaEmbedding struct {
+	schemaIdentified
+	schemaOther
+	schemaTaggedOwner
+	ID string `json:"id" description:"outer ID"`
+}
+
+func TestSchemaForEmbeddedFieldDominance(t *testing.T) {
+	s := SchemaFor[schemaEmbedding]()
+	props := s["properties"].(map[string]interface{})
+
+	// The outer id shadows the embedded one; Kind conflicts at the same depth
+	// and is dropped; the tagged Owner beats the untagged one.
+	if len(props) != 2 || props["id"].(map[string]interface{})["description"] != "outer ID" || props["Owner"] == nil {
+		t.Errorf("unexpected properties: %v", props)
+	}
+	if required := s["required"].([]string); len(required) != 2 || required[0] != "id" || required[1] != "Owner" {
+		t.Errorf("expected each name required once, got %v", required)
+	}
+
+	data, _ := json.Marshal(schemaEmbedding{})
+	var encoded map[string]interface{}
+	json.Unmarshal(data, &encoded)
+	for name := range encoded {
+		if props[name] == nil {
+			t.Errorf("json.Marshal emits %q, missing from the schema", name)
+		}
+	}
+	if len(encoded) != len(props) {
+		t.Errorf("schema has %v, json.Marshal emits %v", props, encoded)
+	}
+}
@@ -0,0 +1,701 @@
+// Package sdktest is an in-memory fake of the HackersEra AI API for
+// integration tests of applications built on the SDK. It serves chat
+// completions (scripted or echoed, streaming included), documents, keyword
+// search over them, conversations, and learned facts over httptest, so code
+// under test talks to a real HTTP server through a real *hackeserasdk.Client.
+//
+//	srv := sdktest.NewServer()
+//	defer srv.Close()
+//	srv.Reply("Restart the pump, then check the pressure.")
+//	srv.AddDocument("runbook.md", "To restart the pump, hold the reset button.", nil)
+//
+//	app := myapp.New(srv.Client())
+//	...
+//	if got := srv.ChatRequests(); len(got) != 1 {
+//		t.Fatalf("expected one chat request, got %d", len(got))
+//	}
+//
+// The fake keeps state per Server and is safe for concurrent use. Endpoints it
+// does not implement answer 404 with an API error body.
+package sdktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/hackersera-dev-team/hackersera-ai-sdk"
+)
+
+// APIKey is the key Client authenticates with. The server accepts any key.
+const APIKey = "sdktest-key"
+
+// Server is a fake API server.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	replies   []sdk.Message
+	replyFunc func(sdk.ChatRequest) sdk.Message
+	failures  map[string][]failure
+	chats     []sdk.ChatRequest
+	docs      []*document
+	convos    []*sdk.ConversationDetail
+	facts     []*sdk.Fact
+	nextID    int
+	now       func() time.Time
+}
+
+type document struct {
+	sdk.DocumentResponse
+	content string
+}
+
+type failure struct {
+	status  int
+	message string
+}
+
+// NewServer starts a fake server with no documents, conversations, or facts.
+func NewServer() *Server {
+	s := &Server{failures: map[string][]failure{}, now: time.Now}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", s.health)
+	mux.HandleFunc("GET /ready", s.ready)
+	mux.HandleFunc("GET /v1/models", s.listModels)
+	mux.HandleFunc("POST /v1/chat/completions", s.chat)
+	mux.HandleFunc("POST /v1/documents", s.uploadDocuments)
+	mux.HandleFunc("POST /v1/documents/upload", s.uploadDocumentFile)
+	mux.HandleFunc("GET /v1/documents", s.listDocuments)
+	mux.HandleFunc("GET /v1/documents/{id}", s.getDocument)
+	mux.HandleFunc("DELETE /v1/documents/{id}", s.deleteDocument)
+	mux.HandleFunc("POST /v1/search", s.search)
+	mux.HandleFunc("GET /v1/conversations", s.listConversations)
+	mux.HandleFunc("GET /v1/conversations/{id}", s.getConversation)
+	mux.HandleFunc("DELETE /v1/conversations/{id}", s.deleteConversation)
+	mux.HandleFunc("GET /v1/knowledge/facts", s.listFacts)
+	mux.HandleFunc("POST /v1/knowledge/facts", s.createFacts)
+	mux.HandleFunc("PUT /v1/knowledge/facts/{id}", s.updateFact)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "not_found", "sdktest: "+r.Method+" "+r.URL.Path+" is not implemented")
+	})
+	s.Server = httptest.NewServer(s.failOrServe(mux))
+	return s
+}
+
+// Client returns an SDK client for the server. Each call returns a new client.
+func (s *Server) Client() *sdk.Client {
+	return sdk.NewClient(s.URL, APIKey)
+}
+
+// ─── Scripting ──────────────────────────────────────────────────────────────
+
+// Reply queues assistant replies, one per chat completion in order. Once the
+// queue is empty, replies come from the ReplyFunc, or echo the last user
+// message.
+func (s *Server) Reply(contents ...string) *Server {
+	for _, c := range contents {
+		s.ReplyMessage(sdk.Message{Role: "assistant", Content: c})
+	}
+	return s
+}
+
+// ReplyMessage queues a reply message, e.g. one with ToolCalls.
+func (s *Server) ReplyMessage(msg sdk.Message) *Server {
+	if msg.Role == "" {
+		msg.Role = "assistant"
+	}
+	s.mu.Lock()
+	s.replies = append(s.replies, msg)
+	s.mu.Unlock()
+	return s
+}
+
+// ReplyFunc answers chat completions the queue does not cover.
+func (s *Server) ReplyFunc(fn func(req sdk.ChatRequest) sdk.Message) *Server {
+	s.mu.Lock()
+	s.replyFunc = fn
+	s.mu.Unlock()
+	return s
+}
+
+// Fail makes the next request to path fail with status and message, for
+// testing error handling. Calls queue up: each failure is used once.
+func (s *Server) Fail(path string, status int, message string) *Server {
+	s.mu.Lock()
+	s.failures[path] = append(s.failures[path], failure{status: status, message: message})
+	s.mu.Unlock()
+	return s
+}
+
+// ChatRequests returns the chat completion requests received so far.
+func (s *Server) ChatRequests() []sdk.ChatRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]sdk.ChatRequest(nil), s.chats...)
+}
+
+// ─── Seeding & Inspection ───────────────────────────────────────────────────
+
+// AddDocument stores an indexed document and returns its ID. Paragraphs
+// (separated by blank lines) become its search chunks.
+func (s *Server) AddDocument(filename, content string, tags map[string]string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addDocument(filename, content, tags).ID
+}
+
+// Documents returns the stored documents.
+func (s *Server) Documents() []sdk.DocumentResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]sdk.DocumentResponse, len(s.docs))
+	for i, d := range s.docs {
+		out[i] = d.DocumentResponse
+	}
+	return out
+}
+
+// AddFact stores a fact and returns it.
+func (s *Server) AddFact(req sdk.FactCreateRequest) sdk.Fact {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.addFact(req)
+}
+
+// Facts returns the stored facts.
+func (s *Server) Facts() []sdk.Fact {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]sdk.Fact, len(s.facts))
+	for i, f := range s.facts {
+		out[i] = *f
+	}
+	return out
+}
+
+// Conversations returns the stored conversations with their turns.
+func (s *Server) Conversations() []sdk.ConversationDetail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]sdk.ConversationDetail, len(s.convos))
+	for i, c := range s.convos {
+		out[i] = *c
+		out[i].Turns = append([]sdk.ConversationTurn(nil), c.Turns...)
+	}
+	return out
+}
+
+func (s *Server) id(prefix string) string {
+	s.nextID++
+	return prefix + "-" + strconv.Itoa(s.nextID)
+}
+
+func (s *Server) timestamp() string { return s.now().UTC().Format(time.RFC3339) }
+
+func (s *Server) addDocument(filename, content string, tags map[string]string) *document {
+	d := &document{content: content, DocumentResponse: sdk.DocumentResponse{
+		ID:         s.id("doc"),
+		Filename:   filename,
+		Status:     "indexed",
+		ChunkCount: len(chunks(content)),
+		Tags:       tags,
+		CreatedAt:  s.timestamp(),
+	}}
+	s.docs = append(s.docs, d)
+	return d
+}
+
+func (s *Server) addFact(req sdk.FactCreateRequest) *sdk.Fact {
+	s.nextID++
+	f := &sdk.Fact{ID: s.nextID, Content: req.Content, Source: req.Source, Confidence: req.Confidence, Verified: req.Verified, CreatedAt: s.timestamp()}
+	s.facts = append(s.facts, f)
+	return f
+}
+
+// ─── Handlers ───────────────────────────────────────────────────────────────
+
+func (s *Server) failOrServe(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		queue := s.failures[r.URL.Path]
+		var f *failure
+		if len(queue) > 0 {
+			f = &queue[0]
+			s.failures[r.URL.Path] = queue[1:]
+		}
+		s.mu.Unlock()
+		if f != nil {
+			writeError(w, f.status, errorType(f.status), f.message)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, sdk.HealthResponse{Status: "ok", Version: "sdktest"})
+}
+
+func (s *Server) ready(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, sdk.ReadyResponse{Ready: true, Version: "sdktest"})
+}
+
+func (s *Server) listModels(w http.ResponseWriter, r *http.Request) {
+	list := sdk.ModelList{Object: "list"}
+	for _, id := range []string{sdk.ModelDefault, sdk.ModelPro, sdk.ModelLite, sdk.ModelEmbedding} {
+		list.Data = append(list.Data, sdk.Model{ID: id, Object: "model", OwnedBy: "hackersera"})
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) chat(w http.ResponseWriter, r *http.Request) {
+	var req sdk.ChatRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if len(req.Messages) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "messages is required")
+		return
+	}
+
+	s.mu.Lock()
+	s.chats = append(s.chats, req)
+	var reply sdk.Message
+	switch {
+	case len(s.replies) > 0:
+		reply, s.replies = s.replies[0], s.replies[1:]
+	case s.replyFunc != nil:
+		fn := s.replyFunc
+		s.mu.Unlock()
+		reply = fn(req)
+		s.mu.Lock()
+	default:
+		reply = sdk.Message{Role: "assistant", Content: "echo: " + lastUserText(req.Messages)}
+	}
+	if reply.Role == "" {
+		reply.Role = "assistant"
+	}
+	convo := s.recordTurn(r.Header.Get("X-Conversation-ID"), req, reply)
+	s.mu.Unlock()
+
+	finish := "stop"
+	if len(reply.ToolCalls) > 0 {
+		finish = "tool_calls"
+	}
+	prompt := sdk.EstimateMessageTokens(req.Messages)
+	completion := sdk.EstimateTokens(reply.ContentAsText())
+	usage := sdk.Usage{PromptTokens: prompt, CompletionTokens: completion, TotalTokens: prompt + completion}
+	id := "chatcmpl-" + convo.ID
+	created := s.now().Unix()
+
+	if !req.Stream {
+		writeJSON(w, http.StatusOK, sdk.ChatResponse{
+			ID: id, Object: "chat.completion", Created: created, Model: req.Model,
+			Choices:        []sdk.Choice{{Message: reply, FinishReason: finish}},
+			Usage:          usage,
+			ConversationID: convo.ID,
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	send := func(choice sdk.ChunkChoice, usage *sdk.Usage) {
+		data, _ := json.Marshal(sdk.ChatStreamChunk{ID: id, Object: "chat.completion.chunk", Created: created, Model: req.Model, Choices: []sdk.ChunkChoice{choice}, Usage: usage})
+		fmt.Fprintf(w, "data: %s\n\n", data)
+	}
+	send(sdk.ChunkChoice{Delta: sdk.Delta{Role: "assistant"}}, nil)
+	for _, word := range strings.SplitAfter(reply.ContentAsText(), " ") {
+		if word != "" {
+			send(sdk.ChunkChoice{Delta: sdk.Delta{Content: word}}, nil)
+		}
+	}
+	for i, tc := range reply.ToolCalls {
+		send(sdk.ChunkChoice{Delta: sdk.Delta{ToolCalls: []sdk.ToolCallDelta{{
+			Index: i, ID: tc.ID, Type: tc.Type,
+			Function: sdk.FunctionCallDelta{Name: tc.Function.Name, Arguments: tc.Function.Arguments},
+		}}}}, nil)
+	}
+	send(sdk.ChunkChoice{FinishReason: &finish}, &usage)
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+// recordTurn appends the exchange to the conversation id, creating it if
+// needed. s.mu must be held.
+func (s *Server) recordTurn(id string, req sdk.ChatRequest, reply sdk.Message) *sdk.ConversationDetail {
+	var convo *sdk.ConversationDetail
+	for _, c := range s.convos {
+		if c.ID == id {
+			convo = c
+		}
+	}
+	now := s.timestamp()
+	question := lastUserText(req.Messages)
+	if convo == nil {
+		if id == "" {
+			id = s.id("conv")
+		}
+		convo = &sdk.ConversationDetail{ID: id, Title: question, Model: req.Model, CreatedAt: now, Metadata: req.Metadata}
+		s.convos = append(s.convos, convo)
+	}
+	n := len(convo.Turns)
+	convo.Turns = append(convo.Turns,
+		sdk.ConversationTurn{ID: n + 1, Role: "user", Content: question, CreatedAt: now},
+		sdk.ConversationTurn{ID: n + 2, Role: "assistant", Content: reply.ContentAsText(), Model: req.Model, CreatedAt: now},
+	)
+	convo.TurnCount = len(convo.Turns) / 2
+	convo.UpdatedAt = now
+	return convo
+}
+
+func (s *Server) uploadDocuments(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+	var batch sdk.DocumentBatchUploadRequest
+	if json.Unmarshal(body, &batch) == nil && batch.Documents != nil {
+		list := sdk.DocumentListResponse{Object: "list"}
+		s.mu.Lock()
+		for _, d := range batch.Documents {
+			list.Data = append(list.Data, s.addDocument(d.Filename, d.Content, d.Tags).DocumentResponse)
+		}
+		s.mu.Unlock()
+		list.Total = len(list.Data)
+		writeJSON(w, http.StatusAccepted, list)
+		return
+	}
+
+	var req sdk.DocumentUploadRequest
+	if err := json.Unmarshal(body, &req); err != nil || strings.TrimSpace(req.Content) == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "content is required")
+		return
+	}
+	s.mu.Lock()
+	doc := s.addDocument(req.Filename, req.Content, req.Tags).DocumentResponse
+	s.mu.Unlock()
+	writeJSON(w, http.StatusAccepted, doc)
+}
+
+func (s *Server) uploadDocumentFile(w http.ResponseWriter, r *http.Request) {
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "file is required")
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+	var tags map[string]string
+	if t := r.FormValue("tags"); t != "" {
+		json.Unmarshal([]byte(t), &tags)
+	}
+	s.mu.Lock()
+	doc := s.addDocument(header.Filename, string(content), tags).DocumentResponse
+	s.mu.Unlock()
+	writeJSON(w, http.StatusAccepted, doc)
+}
+
+func (s *Server) listDocuments(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, sdk.DocumentListResponse{Object: "list", Data: s.Documents(), Total: len(s.Documents())})
+}
+
+func (s *Server) findDocument(id string) (int, *document) {
+	for i, d := range s.docs {
+		if d.ID == id {
+			return i, d
+		}
+	}
+	return -1, nil
+}
+
+func (s *Server) getDocument(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	_, d := s.findDocument(r.PathValue("id"))
+	s.mu.Unlock()
+	if d == nil {
+		writeError(w, http.StatusNotFound, "not_found", "document not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, d.DocumentResponse)
+}
+
+func (s *Server) deleteDocument(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s.mu.Lock()
+	i, d := s.findDocument(id)
+	if d != nil {
+		s.docs = append(s.docs[:i], s.docs[i+1:]...)
+	}
+	s.mu.Unlock()
+	if d == nil {
+		writeError(w, http.StatusNotFound, "not_found", "document not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, sdk.DocumentDeleteResponse{ID: id, Deleted: true})
+}
+
+// search scores each paragraph by the share of query words it contains.
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	var req sdk.SearchRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	terms := strings.Fields(strings.ToLower(req.Query))
+	if len(terms) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "query is required")
+		return
+	}
+	topK := req.TopK
+	if topK <= 0 {
+		topK = 5
+	}
+
+	var results []sdk.SearchResult
+	s.mu.Lock()
+	for _, d := range s.docs {
+		if !hasTags(d.Tags, req.Tags) {
+			continue
+		}
+		for i, chunk := range chunks(d.content) {
+			lower := strings.ToLower(chunk)
+			hits := 0
+			for _, t := range terms {
+				if strings.Contains(lower, t) {
+					hits++
+				}
+			}
+			score := float64(hits) / float64(len(terms))
+			if hits == 0 || score < req.Threshold {
+				continue
+			}
+			results = append(results, sdk.SearchResult{
+				ChunkID: fmt.Sprintf("%s-chunk-%d", d.ID, i), DocumentID: d.ID, Filename: d.Filename,
+				Content: chunk, Score: score, ChunkIndex: i,
+			})
+		}
+	}
+	s.mu.Unlock()
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > topK {
+		results = results[:topK]
+	}
+	writeJSON(w, http.StatusOK, sdk.SearchResponse{Object: "list", Data: results, Query: req.Query, Total: len(results)})
+}
+
+func (s *Server) listConversations(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+	filter := map[string]string{}
+	for key, values := range r.URL.Query() {
+		if name, ok := strings.CutPrefix(key, "metadata["); ok && strings.HasSuffix(name, "]") {
+			filter[strings.TrimSuffix(name, "]")] = values[0]
+		}
+	}
+
+	list := sdk.ConversationListResponse{Object: "list"}
+	s.mu.Lock()
+	// Most recently created first.
+	for i := len(s.convos) - 1; i >= 0 && len(list.Data) < limit; i-- {
+		c := s.convos[i]
+		if !hasTags(c.Metadata, filter) {
+			continue
+		}
+		list.Data = append(list.Data, sdk.Conversation{
+			ID: c.ID, UserID: c.UserID, Title: c.Title, Model: c.Model, TurnCount: c.TurnCount,
+			CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt, Metadata: c.Metadata,
+		})
+	}
+	s.mu.Unlock()
+	list.Total = len(list.Data)
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) getConversation(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for _, c := range s.Conversations() {
+		if c.ID == id {
+			writeJSON(w, http.StatusOK, c)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "not_found", "conversation not found")
+}
+
+func (s *Server) deleteConversation(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s.mu.Lock()
+	deleted := false
+	for i, c := range s.convos {
+		if c.ID == id {
+			s.convos = append(s.convos[:i], s.convos[i+1:]...)
+			deleted = true
+			break
+		}
+	}
+	s.mu.Unlock()
+	if !deleted {
+		writeError(w, http.StatusNotFound, "not_found", "conversation not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, sdk.ConversationDeleteResponse{ID: id, Deleted: true})
+}
+
+func (s *Server) listFacts(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	list := sdk.FactListResponse{Object: "list", Data: []sdk.Fact{}}
+	for _, f := range s.Facts() {
+		if v := q.Get("verified"); v != "" && strconv.FormatBool(f.Verified) != v {
+			continue
+		}
+		if limit > 0 && len(list.Data) == limit {
+			break
+		}
+		list.Data = append(list.Data, f)
+	}
+	list.Total = len(list.Data)
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) createFacts(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+	var batch sdk.FactBatchCreateRequest
+	if json.Unmarshal(body, &batch) == nil && batch.Facts != nil {
+		list := sdk.FactListResponse{Object: "list"}
+		s.mu.Lock()
+		for _, req := range batch.Facts {
+			list.Data = append(list.Data, *s.addFact(req))
+		}
+		s.mu.Unlock()
+		list.Total = len(list.Data)
+		writeJSON(w, http.StatusCreated, list)
+		return
+	}
+
+	var req sdk.FactCreateRequest
+	if err := json.Unmarshal(body, &req); err != nil || strings.TrimSpace(req.Content) == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "content is required")
+		return
+	}
+	s.mu.Lock()
+	f := *s.addFact(req)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusCreated, f)
+}
+
+func (s *Server) updateFact(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(r.PathValue("id"))
+	var req sdk.FactUpdateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	s.mu.Lock()
+	var fact *sdk.Fact
+	for _, f := range s.facts {
+		if f.ID == id {
+			fact = f
+		}
+	}
+	if fact != nil {
+		if req.Content != nil {
+			fact.Content = *req.Content
+		}
+		if req.Confidence != nil {
+			fact.Confidence = *req.Confidence
+		}
+		if req.Verified != nil {
+			fact.Verified = *req.Verified
+		}
+	}
+	var out sdk.Fact
+	if fact != nil {
+		out = *fact
+	}
+	s.mu.Unlock()
+	if fact == nil {
+		writeError(w, http.StatusNotFound, "not_found", "fact not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// ─── Helpers ────────────────────────────────────────────────────────────────
+
+// chunks splits content into paragraphs.
+func chunks(content string) []string {
+	var out []string
+	for _, p := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// hasTags reports whether tags include every key and value of want.
+func hasTags(tags, want map[string]string) bool {
+	for k, v := range want {
+		if tags[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func lastUserText(msgs []sdk.Message) string {
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Role == "user" {
+			return msgs[i].ContentAsText()
+		}
+	}
+	return ""
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, typ, message string) {
+	writeJSON(w, status, sdk.ErrorResponse{Error: sdk.ErrorDetail{Message: message, Type: typ}})
+}
+
+func errorType(status int) string {
+	switch {
+	case status == http.StatusUnauthorized:
+		return "authentication_error"
+	case status == http.StatusTooManyRequests:
+		return "rate_limit_error"
+	case status >= 500:
+		return "server_error"
+	default:
+		return "invalid_request_error"
+	}
+}
@@ -0,0 +1,116 @@
+package sdktest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	sdk "github.com/hackersera-dev-team/hackersera-ai-sdk"
+)
+
+func TestServerChat(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Reply("first answer")
+	client := srv.Client()
+	ctx := context.Background()
+
+	resp, err := client.ChatCompletion(ctx, sdk.ChatRequest{Model: sdk.ModelDefault, Messages: []sdk.Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Choices[0].Message.ContentAsText(); got != "first answer" {
+		t.Errorf("scripted reply = %q", got)
+	}
+
+	// Continue the same conversation with a streamed, echoed reply.
+	stream, err := client.StreamChatWithOptions(ctx,
+		sdk.ChatRequest{Model: sdk.ModelDefault, Messages: []sdk.Message{{Role: "user", Content: "say it back"}}},
+		sdk.RequestOptions{ConversationID: resp.ConversationID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+	var text strings.Builder
+	for {
+		chunk, err := stream.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range chunk.Choices {
+			text.WriteString(c.Delta.Content)
+		}
+	}
+	if text.String() != "echo: say it back" {
+		t.Errorf("streamed reply = %q", text.String())
+	}
+
+	convo, err := client.GetConversation(ctx, resp.ConversationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(convo.Turns) != 4 || convo.Turns[3].Content != "echo: say it back" {
+		t.Errorf("turns = %+v", convo.Turns)
+	}
+	if n := len(srv.ChatRequests()); n != 2 {
+		t.Errorf("recorded %d chat requests, want 2", n)
+	}
+
+	srv.Fail("/v1/chat/completions", 503, "overloaded")
+	_, err = client.ChatCompletion(ctx, sdk.ChatRequest{Model: sdk.ModelDefault, Messages: []sdk.Message{{Role: "user", Content: "hi"}}})
+	var apiErr *sdk.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 503 {
+		t.Errorf("expected injected 503, got %v", err)
+	}
+}
+
+func TestServerKnowledge(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	srv.AddDocument("pump.md", "Hold the reset button to restart the pump.\n\nCheck the pressure gauge afterwards.", map[string]string{"team": "ops"})
+	doc, err := client.UploadDocument(ctx, sdk.DocumentUploadRequest{Filename: "hr.md", Content: "Vacation requests go to HR."})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := client.Search(ctx, sdk.SearchRequest{Query: "restart pump", Tags: map[string]string{"team": "ops"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 1 || res.Data[0].Filename != "pump.md" || res.Data[0].Score != 1 {
+		t.Errorf("search = %+v", res)
+	}
+
+	if _, err := client.DeleteDocument(ctx, doc.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetDocument(ctx, doc.ID)
+	var apiErr *sdk.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 404 {
+		t.Errorf("deleted document: got %v, want 404", err)
+	}
+
+	fact, err := client.CreateFact(ctx, sdk.FactCreateRequest{Content: "The pump is in hall B.", Confidence: 0.9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verified := true
+	if _, err := client.UpdateFact(ctx, fact.ID, sdk.FactUpdateRequest{Verified: &verified}); err != nil {
+		t.Fatal(err)
+	}
+	facts, err := client.ListFacts(ctx, 10, &verified)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if facts.Total != 1 || facts.Data[0].Content != "The pump is in hall B." {
+		t.Errorf("facts = %+v", facts)
+	}
+}
@@ -0,0 +1,122 @@
+package hackeserasdk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// searchCacheMaxEntries bounds the cache size; expired entries are pruned
+// first, then the cache is cleared if it is still full.
+const searchCacheMaxEntries = 1024
+
+// searchCache is an in-memory TTL cache for Search responses.
+type searchCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]searchCacheEntry
+	now     func() time.Time
+}
+
+type searchCacheEntry struct {
+	resp    SearchResponse
+	expires time.Time
+}
+
+func newSearchCache(ttl time.Duration) *searchCache {
+	return &searchCache{
+		ttl:     ttl,
+		entries: make(map[string]searchCacheEntry),
+		now:     time.Now,
+	}
+}
+
+// searchCacheKey normalizes a request so that trivially different queries
+// (case, surrounding or repeated whitespace, tag order) share an entry. The
+// namespace and user the request is sent as are part of the key, since results
+// are scoped to them.
+func searchCacheKey(req SearchRequest, namespace, userID string) string {
+	var b strings.Builder
+	b.WriteString(namespace + "\x00" + userID + "\x00")
+	b.WriteString(strings.Join(strings.Fields(strings.ToLower(req.Query)), " "))
+	fmt.Fprintf(&b, "\x00%d\x00%g", req.TopK, req.Threshold)
+
+	keys := make([]string, 0, len(req.Tags))
+	for k := range req.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString("\x00" + k + "=" + req.Tags[k])
+	}
+	return b.String()
+}
+
+func (sc *searchCache) get(key string) (*SearchResponse, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	entry, ok := sc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !sc.now().Before(entry.expires) {
+		delete(sc.entries, key)
+		return nil, false
+	}
+
+	resp := entry.resp
+	resp.Data = append([]SearchResult(nil), entry.resp.Data...)
+	return &resp, true
+}
+
+func (sc *searchCache) put(key string, resp *SearchResponse) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	now := sc.now()
+	if len(sc.entries) >= searchCacheMaxEntries {
+		for k, e := range sc.entries {
+			if !now.Before(e.expires) {
+				delete(sc.entries, k)
+			}
+		}
+		if len(sc.entries) >= searchCacheMaxEntries {
+			sc.entries = make(map[string]searchCacheEntry)
+		}
+	}
+
+	stored := *resp
+	stored.Data = append([]SearchResult(nil), resp.Data...)
+	sc.entries[key] = searchCacheEntry{resp: stored, expires: now.Add(sc.ttl)}
+}
+
+func (sc *searchCache) clear() {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.entries = make(map[string]searchCacheEntry)
+}
+
+// WithSearchCache enables an in-memory cache for Search responses with the
+// given TTL. Entries are keyed by the namespace and user ID the search is sent
+// as and the normalized query, TopK, Threshold, and tag filters, and the whole
+// cache is invalidated when documents are uploaded or deleted through this
+// client. Pass a TTL <= 0 to disable caching.
+func (c *Client) WithSearchCache(ttl time.Duration) *Client {
+	if ttl <= 0 {
+		c.searchCache = nil
+		return c
+	}
+	c.searchCache = newSearchCache(ttl)
+	return c
+}
+
+// InvalidateSearchCache drops all cached Search responses. Call it when documents
+// change outside this client (another service, a batch job).
+func (c *Client) InvalidateSearchCache() {
+	if c.searchCache != nil {
+		c.searchCache.clear()
+	}
+}
@@ -0,0 +1,197 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSearchCacheHit(t *testing.T) {
+	var calls int32
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SearchResponse{
+			Object: "list",
+			Data:   []SearchResult{{ChunkID: "chunk-1", Score: 0.9}},
+			Total:  1,
+		})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithSearchCache(time.Minute)
+	ctx := context.Background()
+
+	first, err := client.Search(ctx, SearchRequest{Query: "VPN  Setup", TopK: 5, Tags: map[string]string{"a": "1", "b": "2"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first.Data[0].ChunkID = "mutated"
+
+	second, err := client.Search(ctx, SearchRequest{Query: " vpn setup ", TopK: 5, Tags: map[string]string{"b": "2", "a": "1"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Errorf("expected normalized query to hit the cache, got %d server calls", calls)
+	}
+	if second.Data[0].ChunkID != "chunk-1" {
+		t.Errorf("expected cached result to be isolated from caller mutation, got %q", second.Data[0].ChunkID)
+	}
+
+	if _, err := client.Search(ctx, SearchRequest{Query: "vpn setup", TopK: 10}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("expected different TopK to miss the cache, got %d server calls", calls)
+	}
+}
+
+func TestSearchCacheExpiry(t *testing.T) {
+	cache := newSearchCache(time.Second)
+	now := time.Unix(1700000000, 0)
+	cache.now = func() time.Time { return now }
+
+	key := searchCacheKey(SearchRequest{Query: "q"}, "", "")
+	cache.put(key, &SearchResponse{Query: "q"})
+	if _, ok := cache.get(key); !ok {
+		t.Fatal("expected fresh entry to be returned")
+	}
+
+	now = now.Add(time.Second)
+	if _, ok := cache.get(key); ok {
+		t.Error("expected expired entry to be evicted")
+	}
+}
+
+func TestSearchCacheInvalidatedByDocumentChanges(t *testing.T) {
+	var searches int32
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/search":
+			atomic.AddInt32(&searches, 1)
+			json.NewEncoder(w).Encode(SearchResponse{Object: "list"})
+		case r.Method == http.MethodDelete:
+			json.NewEncoder(w).Encode(DocumentDeleteResponse{ID: "doc-1", Deleted: true})
+		default:
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(DocumentResponse{ID: "doc-1", Status: "processing"})
+		}
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithSearchCache(time.Minute)
+	ctx := context.Background()
+	req := SearchRequest{Query: "runbook"}
+
+	client.Search(ctx, req)
+	client.Search(ctx, req)
+	if atomic.LoadInt32(&searches) != 1 {
+		t.Fatalf("expected 1 search before invalidation, got %d", searches)
+	}
+
+	if _, err := client.UploadDocument(ctx, DocumentUploadRequest{Content: "new"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.Search(ctx, req)
+	if atomic.LoadInt32(&searches) != 2 {
+		t.Errorf("expected upload to invalidate cache, got %d searches", searches)
+	}
+
+	if _, err := client.DeleteDocument(ctx, "doc-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.Search(ctx, req)
+	if atomic.LoadInt32(&searches) != 3 {
+		t.Errorf("expected delete to invalidate cache, got %d searches", searches)
+	}
+
+	client.InvalidateSearchCache()
+	client.Search(ctx, req)
+	if atomic.LoadInt32(&searches) != 4 {
+		t.Errorf("expected explicit invalidation to clear cache, got %d searches", searches)
+	}
+}
+
+func TestSearchCacheInvalidatedByReembed(t *testing.T) {
+	var searches int32
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/search" {
+			atomic.AddInt32(&searches, 1)
+			json.NewEncoder(w).Encode(SearchResponse{Object: "list"})
+			return
+		}
+		json.NewEncoder(w).Encode(ReembedJob{ID: "job-1", Status: JobStatusCompleted})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithSearchCache(time.Minute)
+	ctx := context.Background()
+	req := SearchRequest{Query: "runbook"}
+
+	client.Search(ctx, req)
+	if _, err := client.ReembedAll(ctx, ReembedRequest{NewModel: "hackersera-ai-embedding-v2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.Search(ctx, req)
+	if atomic.LoadInt32(&searches) != 2 {
+		t.Errorf("expected reembed to invalidate cache, got %d searches", searches)
+	}
+
+	if _, err := client.WaitForReembedJob(ctx, "job-1", 0, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.Search(ctx, req)
+	if atomic.LoadInt32(&searches) != 3 {
+		t.Errorf("expected finished job to invalidate cache, got %d searches", searches)
+	}
+}
+
+func TestSearchCacheScopedByNamespaceAndUser(t *testing.T) {
+	var searches int32
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&searches, 1)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SearchResponse{Object: "list", Query: r.Header.Get("X-Namespace") + "/" + r.Header.Get("X-User-ID")})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithSearchCache(time.Minute)
+	ctx := context.Background()
+	req := SearchRequest{Query: "runbook"}
+
+	scopes := []func() context.Context{
+		func() context.Context { client.SetNamespace("tenant-a"); return ctx },
+		func() context.Context { client.SetNamespace("tenant-b"); return ctx },
+		func() context.Context { client.SetUserID("u-1"); return ctx },
+		func() context.Context { return WithRequestOptions(ctx, RequestOptions{Namespace: "tenant-c"}) },
+		func() context.Context { return WithRequestOptions(ctx, RequestOptions{UserID: "u-2"}) },
+	}
+	for i, scope := range scopes {
+		scopedCtx := scope()
+		resp, err := client.Search(scopedCtx, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if atomic.LoadInt32(&searches) != int32(i+1) {
+			t.Errorf("scope %d: expected a cache miss, got cached %q", i, resp.Query)
+		}
+		client.Search(scopedCtx, req)
+		if atomic.LoadInt32(&searches) != int32(i+1) {
+			t.Errorf("scope %d: expected a repeated search to hit the cache", i)
+		}
+	}
+}
+
+func TestSearchCacheDisabled(t *testing.T) {
+	client := NewClient("http://localhost", "key").WithSearchCache(time.Minute).WithSearchCache(0)
+	if client.searchCache != nil {
+		t.Error("expected non-positive TTL to disable the cache")
+	}
+	client.InvalidateSearchCache() // must not panic when disabled
+}
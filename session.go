@@ -0,0 +1,309 @@
+package hackeserasdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ─── Chat Sessions ──────────────────────────────────────────────────────────
+
+// MemoryMode selects what happens to history that no longer fits a MemoryPolicy.
+type MemoryMode int
+
+const (
+	// MemoryTruncate drops the oldest messages.
+	MemoryTruncate MemoryMode = iota
+	// MemorySummarize replaces the oldest messages with a model-written summary,
+	// kept as a single system note after the leading system messages.
+	MemorySummarize
+	// MemorySlidingWindow drops the oldest messages like MemoryTruncate, then keeps
+	// dropping up to the next user message, so the window always starts at the
+	// beginning of an exchange rather than mid-reply or mid-tool-call.
+	MemorySlidingWindow
+)
+
+// SummaryNoteName is the Message.Name of the system note written by MemorySummarize.
+const SummaryNoteName = "conversation_summary"
+
+const defaultSummaryPrompt = "Summarize the conversation below for your own future reference. " +
+	"Keep names, facts, decisions, and open questions; omit pleasantries. Reply with the summary only."
+
+// MemoryPolicy bounds the history a ChatSession sends with each request.
+// Zero-valued limits are not enforced.
+type MemoryPolicy struct {
+	// MaxTurns is the maximum number of non-system messages kept.
+	MaxTurns int
+	// MaxTokens is the token budget for the whole history, estimated with
+	// EstimateMessageTokens. Set it below the model's context window minus the
+	// expected completion length.
+	MaxTokens int
+	// KeepSystem always keeps system messages, regardless of the limits.
+	KeepSystem bool
+	// KeepLastN always keeps the N most recent messages, even if they exceed MaxTokens.
+	KeepLastN int
+
+	// Mode selects how the overflow is removed.
+	Mode MemoryMode
+	// SummaryModel is the model used by MemorySummarize. Defaults to the session's model.
+	SummaryModel string
+	// SummaryPrompt overrides the instruction given to the summary model.
+	SummaryPrompt string
+}
+
+// Apply returns the messages that fit the policy, dropping the oldest first.
+// System messages stay in front when KeepSystem is set. The result never starts
+// with a tool result whose assistant tool call was dropped. MemorySummarize is
+// treated as MemoryTruncate here; ChatSession handles it, since it needs a client.
+func (p MemoryPolicy) Apply(msgs []Message) []Message {
+	system, _, kept := p.split(msgs, nil)
+	out := make([]Message, 0, len(system)+len(kept))
+	out = append(out, system...)
+	return append(out, kept...)
+}
+
+// split partitions msgs into pinned system messages, dropped overflow, and kept
+// messages. reserved counts against MaxTokens but is not part of msgs.
+func (p MemoryPolicy) split(msgs, reserved []Message) (system, dropped, kept []Message) {
+	var rest []Message
+	for _, m := range msgs {
+		if p.KeepSystem && m.Role == "system" {
+			system = append(system, m)
+		} else {
+			rest = append(rest, m)
+		}
+	}
+
+	drop := 0
+	if p.MaxTurns > 0 && len(rest) > p.MaxTurns {
+		drop = len(rest) - p.MaxTurns
+	}
+	if p.MaxTokens > 0 {
+		budget := p.MaxTokens - EstimateMessageTokens(system) - EstimateMessageTokens(reserved)
+		used := EstimateMessageTokens(rest[drop:])
+		for used > budget && len(rest)-drop > p.KeepLastN {
+			used -= EstimateMessageTokens(rest[drop : drop+1])
+			drop++
+		}
+	}
+	for drop < len(rest) && rest[drop].Role == "tool" && len(rest)-drop > p.KeepLastN {
+		drop++
+	}
+	if p.Mode == MemorySlidingWindow && drop > 0 {
+		next := drop
+		for next < len(rest) && rest[next].Role != "user" {
+			next++
+		}
+		if next < len(rest) && len(rest)-next >= p.KeepLastN {
+			drop = next
+		}
+	}
+	return system, rest[:drop], rest[drop:]
+}
+
+// ChatSession keeps client-side message history for a multi-turn chat and sends it
+// with every request, continuing the same server-side conversation.
+// A ChatSession is not safe for concurrent use.
+type ChatSession struct {
+	client   *Client
+	template ChatRequest
+	messages []Message
+
+	// SessionID identifies the session in its transcript. It is generated by
+	// NewChatSession and, unlike ConversationID, is known before the first
+	// reply and set for streamed sessions too.
+	SessionID string
+	// ConversationID is the server-side conversation, set after the first reply.
+	ConversationID string
+	// Options are sent with every request. ConversationID is filled in automatically.
+	Options RequestOptions
+	// Memory, if set, truncates or summarizes history before each request.
+	Memory *MemoryPolicy
+	// Transcript, if set, receives every completed exchange.
+	Transcript TranscriptSink
+
+	transcriptSeq int
+}
+
+// NewChatSession starts a session. template supplies the model and sampling
+// parameters for every request; its Messages seed the history (e.g. a system prompt).
+func (c *Client) NewChatSession(template ChatRequest) *ChatSession {
+	s := &ChatSession{client: c, template: template, SessionID: randomID("sess_")}
+	s.messages = append(s.messages, template.Messages...)
+	s.template.Messages = nil
+	return s
+}
+
+// Send appends a user message, applies the memory policy, and sends the history.
+// The assistant reply is appended to the history.
+func (s *ChatSession) Send(ctx context.Context, content string) (*ChatResponse, error) {
+	return s.SendMessage(ctx, Message{Role: "user", Content: content})
+}
+
+// SendMessage is like Send for an arbitrary message (multimodal content, tool results).
+// On error, the message is not kept in the history. If the exchange cannot be
+// appended to the Transcript, the reply is still returned and kept, along with
+// the error.
+func (s *ChatSession) SendMessage(ctx context.Context, msg Message) (*ChatResponse, error) {
+	history, req, opts, err := s.turn(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.ChatCompletionWithOptions(ctx, req, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	exchange := []Message{msg}
+	if len(resp.Choices) > 0 {
+		history = append(history, resp.Choices[0].Message)
+		exchange = append(exchange, resp.Choices[0].Message)
+	}
+	s.messages = history
+	if resp.ConversationID != "" {
+		s.ConversationID = resp.ConversationID
+	}
+	return resp, s.recordTranscript(ctx, exchange...)
+}
+
+// SendStream is SendMessage with a streamed reply. When the stream ends
+// normally the assembled reply is appended to the history and the Transcript;
+// a transcript failure is returned by the final Next in place of io.EOF. A
+// stream closed or failed early leaves the history unchanged.
+func (s *ChatSession) SendStream(ctx context.Context, msg Message) (*ChatStream, error) {
+	history, req, opts, err := s.turn(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := s.client.StreamChatWithOptions(ctx, req, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var content strings.Builder
+	var calls ToolCallAccumulator
+	stream.onChunk = func(chunk ChatStreamChunk) {
+		for _, c := range chunk.Choices {
+			if c.Index == 0 {
+				content.WriteString(c.Delta.Content)
+				calls.Add(c.Delta)
+			}
+		}
+	}
+	stream.onEOF = func() error {
+		reply := Message{Role: "assistant", Content: content.String(), ToolCalls: calls.ToolCalls()}
+		s.messages = append(history, reply)
+		return s.recordTranscript(ctx, msg, reply)
+	}
+	return stream, nil
+}
+
+// turn builds the request for sending msg: the history with msg appended and
+// the memory policy applied, and the options carrying the conversation ID.
+func (s *ChatSession) turn(ctx context.Context, msg Message) ([]Message, ChatRequest, RequestOptions, error) {
+	history := append(s.messages[:len(s.messages):len(s.messages)], msg)
+	if s.Memory != nil {
+		var err error
+		if history, err = s.compact(ctx, history); err != nil {
+			return nil, ChatRequest{}, RequestOptions{}, err
+		}
+	}
+
+	req := s.template
+	req.Messages = history
+	opts := s.Options
+	if s.ConversationID != "" {
+		opts.ConversationID = s.ConversationID
+	}
+	return history, req, opts, nil
+}
+
+// compact applies the memory policy to msgs, summarizing the overflow when the
+// policy's Mode is MemorySummarize. An earlier summary note is folded into the new one.
+func (s *ChatSession) compact(ctx context.Context, msgs []Message) ([]Message, error) {
+	p := *s.Memory
+	if p.Mode != MemorySummarize {
+		return p.Apply(msgs), nil
+	}
+
+	var note *Message
+	rest := make([]Message, 0, len(msgs))
+	for _, m := range msgs {
+		if m.Role == "system" && m.Name == SummaryNoteName {
+			m := m
+			note = &m
+			continue
+		}
+		rest = append(rest, m)
+	}
+
+	var reserved []Message
+	if note != nil {
+		reserved = []Message{*note}
+	}
+	system, dropped, kept := p.split(rest, reserved)
+	if len(dropped) > 0 {
+		summary, err := s.summarize(ctx, note, dropped)
+		if err != nil {
+			return nil, fmt.Errorf("summarize history: %w", err)
+		}
+		note = &Message{Role: "system", Name: SummaryNoteName, Content: summary}
+	}
+
+	out := make([]Message, 0, len(system)+1+len(kept))
+	out = append(out, system...)
+	if note != nil {
+		out = append(out, *note)
+	}
+	return append(out, kept...), nil
+}
+
+// summarize asks the summary model to condense the previous summary note (if any)
+// and the dropped messages into a single text.
+func (s *ChatSession) summarize(ctx context.Context, previous *Message, dropped []Message) (string, error) {
+	var transcript strings.Builder
+	if previous != nil {
+		fmt.Fprintf(&transcript, "earlier summary: %s\n", messageText(*previous))
+	}
+	for _, m := range dropped {
+		if text := messageText(m); text != "" {
+			fmt.Fprintf(&transcript, "%s: %s\n", m.Role, text)
+		}
+	}
+
+	model := s.Memory.SummaryModel
+	if model == "" {
+		model = s.template.Model
+	}
+	prompt := s.Memory.SummaryPrompt
+	if prompt == "" {
+		prompt = defaultSummaryPrompt
+	}
+
+	resp, err := s.client.ChatCompletionWithOptions(ctx, ChatRequest{
+		Model: model,
+		Messages: []Message{
+			{Role: "system", Content: prompt},
+			{Role: "user", Content: transcript.String()},
+		},
+	}, RequestOptions{CognitiveDisabled: true})
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("empty summary response")
+	}
+	return "Summary of the earlier conversation:\n" + messageText(resp.Choices[0].Message), nil
+}
+
+// History returns a copy of the session's message history.
+func (s *ChatSession) History() []Message {
+	return append([]Message(nil), s.messages...)
+}
+
+// SetHistory replaces the session's message history.
+func (s *ChatSession) SetHistory(msgs []Message) {
+	s.messages = append([]Message(nil), msgs...)
+}
@@ -0,0 +1,189 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMemoryPolicyApply(t *testing.T) {
+	msgs := []Message{
+		{Role: "system", Content: "be brief"},
+		{Role: "user", Content: "one"},
+		{Role: "assistant", Content: "two"},
+		{Role: "user", Content: "three"},
+		{Role: "assistant", Content: "four"},
+	}
+
+	got := MemoryPolicy{MaxTurns: 2, KeepSystem: true}.Apply(msgs)
+	if len(got) != 3 || got[0].Role != "system" || got[1].Content != "three" {
+		t.Errorf("expected system + last two turns, got %+v", got)
+	}
+
+	got = MemoryPolicy{MaxTurns: 2}.Apply(msgs)
+	if len(got) != 2 || got[0].Content != "three" {
+		t.Errorf("expected system message to be truncated without KeepSystem, got %+v", got)
+	}
+
+	budget := EstimateMessageTokens(msgs[:1]) + EstimateMessageTokens(msgs[3:])
+	got = MemoryPolicy{MaxTokens: budget, KeepSystem: true}.Apply(msgs)
+	if len(got) != 3 || got[1].Content != "three" {
+		t.Errorf("expected token budget to keep last two turns, got %+v", got)
+	}
+
+	got = MemoryPolicy{MaxTokens: 1, KeepLastN: 1}.Apply(msgs)
+	if len(got) != 1 || got[0].Content != "four" {
+		t.Errorf("expected KeepLastN to override token budget, got %+v", got)
+	}
+}
+
+func TestMemoryPolicyDropsOrphanedToolResults(t *testing.T) {
+	msgs := []Message{
+		{Role: "user", Content: "weather?"},
+		{Role: "assistant", ToolCalls: []ToolCall{{ID: "call_1", Type: "function"}}},
+		{Role: "tool", Content: "sunny", ToolCallID: "call_1"},
+		{Role: "assistant", Content: "It is sunny."},
+	}
+	got := MemoryPolicy{MaxTurns: 2}.Apply(msgs)
+	if len(got) != 1 || got[0].Role != "assistant" {
+		t.Errorf("expected orphaned tool result to be dropped, got %+v", got)
+	}
+}
+
+func TestMemoryPolicySlidingWindow(t *testing.T) {
+	msgs := []Message{
+		{Role: "system", Content: "be brief"},
+		{Role: "user", Content: "scan the subnet"},
+		{Role: "assistant", ToolCalls: []ToolCall{{ID: "call_1", Type: "function"}}},
+		{Role: "tool", Content: "3 hosts", ToolCallID: "call_1"},
+		{Role: "assistant", Content: "Found 3 hosts."},
+		{Role: "user", Content: "which are exposed?"},
+		{Role: "assistant", Content: "Only 10.0.0.5."},
+	}
+
+	got := MemoryPolicy{MaxTurns: 3, KeepSystem: true, Mode: MemorySlidingWindow}.Apply(msgs)
+	if len(got) != 3 || got[1].Content != "which are exposed?" {
+		t.Errorf("expected window to start at the last user message, got %+v", got)
+	}
+
+	got = MemoryPolicy{MaxTurns: 3, KeepSystem: true}.Apply(msgs)
+	if len(got) != 4 || got[1].Content != "Found 3 hosts." {
+		t.Errorf("expected truncate to keep the mid-exchange reply, got %+v", got)
+	}
+
+	got = MemoryPolicy{MaxTurns: 3, KeepLastN: 3, Mode: MemorySlidingWindow}.Apply(msgs[1:])
+	if len(got) != 3 {
+		t.Errorf("expected KeepLastN to cap the window slide, got %+v", got)
+	}
+}
+
+func TestChatSession(t *testing.T) {
+	calls := 0
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var req ChatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Model != ModelPro {
+			t.Errorf("expected model %q, got %q", ModelPro, req.Model)
+		}
+		if calls == 2 {
+			if r.Header.Get("X-Conversation-ID") != "conv-1" {
+				t.Errorf("expected conversation to continue, got %q", r.Header.Get("X-Conversation-ID"))
+			}
+			// MaxTurns 2 keeps the previous reply and the new question.
+			if len(req.Messages) != 3 || req.Messages[0].Role != "system" || req.Messages[1].Content != "reply 1" {
+				t.Errorf("expected truncated history, got %+v", req.Messages)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{
+			ConversationID: "conv-1",
+			Choices:        []Choice{{Message: Message{Role: "assistant", Content: "reply " + strings.Repeat("1", calls)}}},
+		})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	session := client.NewChatSession(ChatRequest{
+		Model:    ModelPro,
+		Messages: []Message{{Role: "system", Content: "be brief"}},
+	})
+	session.Memory = &MemoryPolicy{MaxTurns: 2, KeepSystem: true}
+
+	ctx := context.Background()
+	if _, err := session.Send(ctx, "first"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.ConversationID != "conv-1" {
+		t.Errorf("expected ConversationID conv-1, got %q", session.ConversationID)
+	}
+	if _, err := session.Send(ctx, "second"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	history := session.History()
+	if len(history) != 4 || history[3].Content != "reply 11" {
+		t.Errorf("expected stored history of system + 3 messages, got %+v", history)
+	}
+}
+
+func TestChatSessionSummarize(t *testing.T) {
+	summaries := 0
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		reply := "ok"
+		if req.Model == ModelLite {
+			summaries++
+			if r.Header.Get("X-Cognitive-Disabled") != "true" {
+				t.Error("expected summary request to disable cognitive processing")
+			}
+			transcript := messageText(req.Messages[1])
+			if summaries == 2 && !strings.Contains(transcript, "earlier summary:") {
+				t.Errorf("expected previous summary to be folded in, got %q", transcript)
+			}
+			reply = "user asked things"
+		} else {
+			for _, m := range req.Messages {
+				if m.Name == SummaryNoteName && !strings.Contains(messageText(m), "user asked things") {
+					t.Errorf("unexpected summary note %q", messageText(m))
+				}
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{
+			Choices: []Choice{{Message: Message{Role: "assistant", Content: reply}}},
+		})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	session := client.NewChatSession(ChatRequest{
+		Model:    ModelPro,
+		Messages: []Message{{Role: "system", Content: "be brief"}},
+	})
+	session.Memory = &MemoryPolicy{MaxTurns: 2, KeepSystem: true, Mode: MemorySummarize, SummaryModel: ModelLite}
+
+	ctx := context.Background()
+	for _, q := range []string{"one", "two", "three"} {
+		if _, err := session.Send(ctx, q); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if summaries != 2 {
+		t.Errorf("expected 2 summary requests, got %d", summaries)
+	}
+
+	history := session.History()
+	notes := 0
+	for _, m := range history {
+		if m.Name == SummaryNoteName {
+			notes++
+		}
+	}
+	if notes != 1 || history[1].Name != SummaryNoteName || len(history) != 5 {
+		t.Errorf("expected system, one summary note, and last turns, got %+v", history)
+	}
+}
@@ -0,0 +1,161 @@
+package hackeserasdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ─── Shutdown ───────────────────────────────────────────────────────────────
+
+// ErrClientClosed is returned by requests started after Shutdown.
+var ErrClientClosed = errors.New("client is shut down")
+
+// shutdownState tracks in-flight calls so Shutdown can wait for them. A call is
+// in flight from when it is sent until its response body is closed, which for
+// streams is when the ChatStream is closed.
+type shutdownState struct {
+	mu       sync.Mutex
+	closed   bool
+	inflight map[*inflightCall]struct{}
+	idle     chan struct{}
+	hooks    []func(context.Context) error
+}
+
+type inflightCall struct {
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+type shutdownBypassKey struct{}
+
+// OnShutdown registers fn to run during Shutdown once in-flight calls have
+// drained, e.g. to flush a buffered audit log or metrics exporter. Hooks run in
+// registration order with the Shutdown context.
+func (c *Client) OnShutdown(fn func(ctx context.Context) error) *Client {
+	c.shutdown.mu.Lock()
+	c.shutdown.hooks = append(c.shutdown.hooks, fn)
+	c.shutdown.mu.Unlock()
+	return c
+}
+
+// Shutdown stops the client for a clean exit. New calls fail with
+// ErrClientClosed; Health is unaffected. Shutdown then waits for in-flight
+// requests and open streams to finish. If ctx ends first they are canceled and
+// Shutdown stops waiting. Finally it sends any uploads queued by degraded mode
+// and runs the OnShutdown hooks. It returns ctx's error if the drain was cut
+// short, joined with any upload or hook errors.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	if err := client.Shutdown(ctx); err != nil {
+//		log.Printf("shutdown: %v", err)
+//	}
+func (c *Client) Shutdown(ctx context.Context) error {
+	s := &c.shutdown
+	s.mu.Lock()
+	s.closed = true
+	if s.idle == nil {
+		s.idle = make(chan struct{})
+		if len(s.inflight) == 0 {
+			close(s.idle)
+		}
+	}
+	idle := s.idle
+	s.mu.Unlock()
+
+	var errs []error
+	select {
+	case <-idle:
+	case <-ctx.Done():
+		s.mu.Lock()
+		for call := range s.inflight {
+			call.cancel()
+		}
+		s.mu.Unlock()
+		errs = append(errs, ctx.Err())
+	}
+
+	if err := c.FlushQueuedUploads(context.WithValue(ctx, shutdownBypassKey{}, true)); err != nil {
+		errs = append(errs, err)
+	}
+	s.mu.Lock()
+	hooks := s.hooks
+	s.mu.Unlock()
+	for _, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// track registers req as in flight and returns it with a context Shutdown can
+// cancel, bounded by timeout when it is positive. The returned done func must
+// be called once the call has finished.
+func (s *shutdownState) track(req *http.Request, timeout time.Duration) (*http.Request, func(), error) {
+	bypass, _ := req.Context().Value(shutdownBypassKey{}).(bool)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed && !bypass {
+		return nil, nil, ErrClientClosed
+	}
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(req.Context(), timeout)
+	} else {
+		ctx, cancel = context.WithCancel(req.Context())
+	}
+	call := &inflightCall{cancel: cancel}
+	if s.inflight == nil {
+		s.inflight = map[*inflightCall]struct{}{}
+	}
+	s.inflight[call] = struct{}{}
+	done := func() {
+		call.once.Do(func() {
+			cancel()
+			s.mu.Lock()
+			delete(s.inflight, call)
+			if s.idle != nil && len(s.inflight) == 0 {
+				select {
+				case <-s.idle:
+				default:
+					close(s.idle)
+				}
+			}
+			s.mu.Unlock()
+		})
+	}
+	return req.WithContext(ctx), done, nil
+}
+
+func (s *shutdownState) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// hold keeps the call in flight until resp's body is closed.
+func (s *shutdownState) hold(resp *http.Response, err error, done func()) (*http.Response, error) {
+	if err != nil || resp == nil {
+		done()
+		return resp, err
+	}
+	resp.Body = &trackedBody{ReadCloser: resp.Body, done: done}
+	return resp, nil
+}
+
+type trackedBody struct {
+	io.ReadCloser
+	done func()
+}
+
+func (b *trackedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.done()
+	return err
+}
@@ -0,0 +1,121 @@
+package hackeserasdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShutdownDrainsInFlight(t *testing.T) {
+	started := make(chan struct{}, 1)
+	unblock := make(chan struct{})
+	srv := newScriptedServer(t).handle("/v1/models", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"object":"list","data":[]}`)
+	})
+	srv.onChat = blockPro(started, unblock)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	var hookRan atomic.Bool
+	client.OnShutdown(func(ctx context.Context) error {
+		hookRan.Store(true)
+		return nil
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.ChatCompletion(context.Background(), ChatRequest{Model: ModelPro, Messages: []Message{{Role: "user", Content: "hi"}}})
+		done <- err
+	}()
+	<-started
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- client.Shutdown(context.Background()) }()
+
+	// Wait for Shutdown to close intake before issuing a new call.
+	deadline := time.Now().Add(time.Second)
+	for {
+		_, err := client.ListModels(context.Background())
+		if errors.Is(err, ErrClientClosed) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected ErrClientClosed after Shutdown, got %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case err := <-shutdown:
+		t.Fatalf("Shutdown returned before in-flight call finished: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	if hookRan.Load() {
+		t.Error("hook ran before in-flight call finished")
+	}
+
+	close(unblock)
+	if err := <-done; err != nil {
+		t.Errorf("expected in-flight call to complete, got %v", err)
+	}
+	if err := <-shutdown; err != nil {
+		t.Errorf("unexpected shutdown error: %v", err)
+	}
+	if !hookRan.Load() {
+		t.Error("expected OnShutdown hook to run")
+	}
+}
+
+func TestShutdownCancelsAtDeadline(t *testing.T) {
+	srv := newScriptedServer(t).handle("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"a\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	stream, err := client.StreamChat(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+	if _, err := stream.Next(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := client.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline error, got %v", err)
+	}
+	if _, err := stream.Next(); err == nil {
+		t.Error("expected canceled stream to fail")
+	}
+}
+
+func TestShutdownFlushesQueuedUploads(t *testing.T) {
+	var status atomic.Value
+	status.Store("degraded")
+	srv := newDegradedServer(t, &status)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithDegradedMode(DegradedPolicy{QueueUploads: true, CheckInterval: time.Hour})
+	if _, err := client.UploadDocument(context.Background(), DocumentUploadRequest{Content: "runbook", Filename: "a.md"}); !errors.Is(err, ErrUploadQueued) {
+		t.Fatalf("expected ErrUploadQueued, got %v", err)
+	}
+	if err := client.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.QueuedUploads() != 0 || srv.count("/v1/documents") != 1 {
+		t.Errorf("expected queued upload to be sent, got %d queued / %d sent", client.QueuedUploads(), srv.count("/v1/documents"))
+	}
+	if _, err := client.UploadDocument(context.Background(), DocumentUploadRequest{Content: "late", Filename: "b.md"}); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expected ErrClientClosed, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package hackeserasdk
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRequestSigningHMAC(t *testing.T) {
+	secret := []byte("gateway-secret")
+	attempts := 0
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(HeaderSignatureTimestamp), 10, 64)
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(SigningString(r.Method, r.URL.RequestURI(), ts, body))
+		sig, _ := base64.StdEncoding.DecodeString(r.Header.Get(HeaderSignature))
+		if !hmac.Equal(sig, mac.Sum(nil)) || len(body) == 0 {
+			t.Errorf("attempt %d: bad signature over %q", attempts, body)
+		}
+		if r.Header.Get(HeaderSignatureKeyID) != "svc-a" || r.Header.Get(HeaderSignatureAlgorithm) != "hmac-sha256" || r.Header.Get("X-Trace") != "on" {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-signed"})
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").
+		WithRetry(RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond}).
+		WithRequestSigning(SigningConfig{Signer: NewHMACSigner(secret), KeyID: "svc-a"}).
+		Use(func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				req.Header.Set("X-Trace", "on")
+				return next(req)
+			}
+		})
+	resp, err := client.ChatCompletion(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.ID != "chatcmpl-signed" || attempts != 2 {
+		t.Errorf("expected the retried attempt signed and accepted, got %q after %d attempts", resp.ID, attempts)
+	}
+}
+
+func TestRequestSigningEd25519CustomHeaders(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts, _ := strconv.ParseInt(r.Header.Get("X-Gw-Time"), 10, 64)
+		sig, _ := base64.StdEncoding.DecodeString(r.Header.Get("X-Gw-Sig"))
+		if !ed25519.Verify(pub, SigningString(r.Method, r.URL.RequestURI(), ts, nil), sig) {
+			t.Errorf("bad signature for %s %s", r.Method, r.URL.RequestURI())
+		}
+		if r.Header.Get(HeaderSignatureAlgorithm) != "" || r.Header.Get(HeaderSignature) != "" {
+			t.Errorf("expected default headers replaced, got %v", r.Header)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"object":"list","data":[]}`)
+	})
+	defer srv.Close()
+
+	signer, err := NewKeySigner(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(srv.URL, "test-key").WithRequestSigning(SigningConfig{
+		Signer:  signer,
+		Headers: SigningHeaders{Signature: "X-Gw-Sig", Timestamp: "X-Gw-Time", Algorithm: "-"},
+	})
+	if _, err := client.ListConversationsWithOptions(context.Background(), ConversationListOptions{Limit: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
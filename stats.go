@@ -0,0 +1,145 @@
+package hackeserasdk
+
+import (
+	"net/http"
+	"sync"
+)
+
+// ─── Client Stats ───────────────────────────────────────────────────────────
+
+// ClientStats is a snapshot of the client's request counters.
+// Every HTTP attempt is counted, so resilience features that re-send requests
+// show up here rather than only on the bill.
+type ClientStats struct {
+	// Attempts is the number of HTTP requests sent, including re-sends.
+	Attempts int64
+	// TransportErrors counts attempts that failed without an HTTP response
+	// (DNS, connection refused, timeouts, cancelled contexts).
+	TransportErrors int64
+	// StatusCodes counts responses by HTTP status code.
+	StatusCodes map[int]int64
+	// Retries is the number of re-sends made by WithRetry.
+	Retries int64
+	// RetryReasons counts retries by cause: "transport" or the HTTP status code.
+	RetryReasons map[string]int64
+	// StructuredOutputs is the number of ChatCompletionAs calls that got a reply.
+	StructuredOutputs int64
+	// Repairs is the number of repair requests sent for invalid structured output
+	// or malformed tool arguments; RepairFailures counts calls that stayed invalid
+	// after all repairs.
+	Repairs        int64
+	RepairFailures int64
+	// MalformedStreamChunks counts stream payloads that were not valid JSON.
+	MalformedStreamChunks int64
+}
+
+type clientStats struct {
+	mu              sync.Mutex
+	attempts        int64
+	transportErrors int64
+	statusCodes     map[int]int64
+	retries         int64
+	retryReasons    map[string]int64
+	structured      int64
+	repairs         int64
+	repairFailures  int64
+	malformedChunks int64
+}
+
+func (s *clientStats) recordAttempt(resp *http.Response, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	if err != nil {
+		s.transportErrors++
+		return
+	}
+	if s.statusCodes == nil {
+		s.statusCodes = make(map[int]int64)
+	}
+	s.statusCodes[resp.StatusCode]++
+}
+
+func (s *clientStats) recordRetry(reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retries++
+	if s.retryReasons == nil {
+		s.retryReasons = make(map[string]int64)
+	}
+	s.retryReasons[reason]++
+}
+
+func (s *clientStats) recordStructuredOutput(repairs int, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.structured++
+	s.repairs += int64(repairs)
+	if failed {
+		s.repairFailures++
+	}
+}
+
+func (s *clientStats) recordToolRepair(failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if failed {
+		s.repairFailures++
+	} else {
+		s.repairs++
+	}
+}
+
+func (s *clientStats) recordMalformedChunk() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.malformedChunks++
+}
+
+func (s *clientStats) snapshot() ClientStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := ClientStats{
+		Attempts:          s.attempts,
+		TransportErrors:   s.transportErrors,
+		StatusCodes:       make(map[int]int64, len(s.statusCodes)),
+		Retries:           s.retries,
+		RetryReasons:      make(map[string]int64, len(s.retryReasons)),
+		StructuredOutputs: s.structured,
+		Repairs:           s.repairs,
+		RepairFailures:    s.repairFailures,
+
+		MalformedStreamChunks: s.malformedChunks,
+	}
+	for code, n := range s.statusCodes {
+		out.StatusCodes[code] = n
+	}
+	for reason, n := range s.retryReasons {
+		out.RetryReasons[reason] = n
+	}
+	return out
+}
+
+func (s *clientStats) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts = 0
+	s.transportErrors = 0
+	s.statusCodes = nil
+	s.retries = 0
+	s.retryReasons = nil
+	s.structured = 0
+	s.repairs = 0
+	s.repairFailures = 0
+	s.malformedChunks = 0
+}
+
+// Stats returns a snapshot of the client's request counters.
+func (c *Client) Stats() ClientStats {
+	return c.stats.snapshot()
+}
+
+// ResetStats zeroes the client's request counters, e.g. after each metrics scrape.
+func (c *Client) ResetStats() {
+	c.stats.reset()
+}
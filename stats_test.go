@@ -0,0 +1,51 @@
+package hackeserasdk
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClientStats(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/models" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"object":"list","data":[]}`))
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("unavailable"))
+	})
+
+	client := NewClient(srv.URL, "test-key")
+	ctx := context.Background()
+
+	client.ListModels(ctx)
+	client.ListModels(ctx)
+	client.GetUsage(ctx)
+
+	stats := client.Stats()
+	if stats.Attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", stats.Attempts)
+	}
+	if stats.StatusCodes[http.StatusOK] != 2 || stats.StatusCodes[http.StatusServiceUnavailable] != 1 {
+		t.Errorf("unexpected status counts: %v", stats.StatusCodes)
+	}
+
+	srv.Close()
+	client.ListModels(ctx)
+	if got := client.Stats().TransportErrors; got != 1 {
+		t.Errorf("expected 1 transport error, got %d", got)
+	}
+
+	// Snapshots must not alias internal state.
+	stats.StatusCodes[http.StatusOK] = 100
+	if client.Stats().StatusCodes[http.StatusOK] != 2 {
+		t.Error("expected snapshot to be a copy")
+	}
+
+	client.ResetStats()
+	if s := client.Stats(); s.Attempts != 0 || len(s.StatusCodes) != 0 {
+		t.Errorf("expected reset stats, got %+v", s)
+	}
+}
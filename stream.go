@@ -0,0 +1,505 @@
+package hackeserasdk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ─── Chat Streams ───────────────────────────────────────────────────────────
+
+// ChatStream reads a streaming chat completion chunk by chunk, like sql.Rows.
+// Always Close it, typically with defer; Close releases the connection even if
+// the stream was not read to the end. A ChatStream is not safe for concurrent use.
+//
+//	stream, err := client.StreamChat(ctx, req)
+//	if err != nil {
+//		return err
+//	}
+//	defer stream.Close()
+//	for {
+//		chunk, err := stream.Next()
+//		if err == io.EOF {
+//			break
+//		}
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Print(chunk.Choices[0].Delta.Content)
+//	}
+type ChatStream struct {
+	body    io.ReadCloser
+	scanner *sseScanner
+	release func()
+
+	// onChunk sees every chunk returned by Next; onEOF runs when the stream
+	// ends normally and may replace io.EOF with an error. ChatSession uses them
+	// to record streamed replies.
+	onChunk func(ChatStreamChunk)
+	onEOF   func() error
+
+	err       error
+	closeOnce sync.Once
+
+	// log and hooks are the client's WithLogger and WithHooks; ctx is the
+	// request's context.
+	log   *clientLogger
+	hooks hookSet
+	ctx   context.Context
+	// malformed handles a payload that is not valid JSON; a non-nil result
+	// ends the stream.
+	malformed      func(payload string, err error) error
+	malformedCount int
+
+	// Timing for Stats. now is time.Now outside tests.
+	now        func() time.Time
+	start      time.Time
+	firstToken time.Time
+	end        time.Time
+	text       strings.Builder
+	usage      *Usage
+}
+
+// StreamStats reports the latency and throughput of a ChatStream, the SLOs of
+// interactive chat.
+type StreamStats struct {
+	// TimeToFirstToken runs from sending the request to the first content or
+	// tool call delta. It is zero until that delta arrives.
+	TimeToFirstToken time.Duration
+	// GenerationTime runs from the first token to the end of the stream, or
+	// to now while the stream is still being read.
+	GenerationTime time.Duration
+	// Total runs from sending the request to the end of the stream, or to now.
+	Total time.Duration
+	// CompletionTokens is the server's count when the stream reported usage,
+	// and otherwise estimated from the streamed text; Estimated tells which.
+	CompletionTokens int
+	Estimated        bool
+	// TokensPerSecond is CompletionTokens over GenerationTime, or zero before
+	// any time has passed since the first token.
+	TokensPerSecond float64
+	// MalformedChunks counts payloads skipped because they were not valid
+	// JSON. A nonzero count means the answer may be missing text.
+	MalformedChunks int
+}
+
+// DefaultMaxStreamLine is the longest SSE line a stream accepts unless
+// WithMaxStreamLine sets another limit.
+const DefaultMaxStreamLine = 1 << 20
+
+// WithMaxStreamLine sets the longest SSE line ChatStream and EventStream
+// accept. A single chunk carrying large tool call arguments can exceed the
+// default; a longer line ends the stream with a *StreamLineTooLongError.
+func (c *Client) WithMaxStreamLine(n int) *Client {
+	c.maxStreamLine = n
+	return c
+}
+
+// StreamLineTooLongError reports an SSE line longer than the client's limit.
+// The stream cannot continue past it. It unwraps to bufio.ErrTooLong.
+type StreamLineTooLongError struct {
+	Limit int
+}
+
+func (e *StreamLineTooLongError) Error() string {
+	return fmt.Sprintf("read stream: line exceeds %d bytes; raise the limit with WithMaxStreamLine", e.Limit)
+}
+
+func (e *StreamLineTooLongError) Unwrap() error { return bufio.ErrTooLong }
+
+// sseScanner is a line scanner that remembers its limit for error reporting.
+type sseScanner struct {
+	*bufio.Scanner
+	limit int
+}
+
+func (c *Client) newSSEScanner(r io.Reader) *sseScanner {
+	limit := c.maxStreamLine
+	if limit <= 0 {
+		limit = DefaultMaxStreamLine
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, min(limit, 64*1024)), limit)
+	return &sseScanner{Scanner: scanner, limit: limit}
+}
+
+// streamReadError wraps a scanner error, typing line overflows.
+func streamReadError(err error, s *sseScanner) error {
+	if errors.Is(err, bufio.ErrTooLong) {
+		return &StreamLineTooLongError{Limit: s.limit}
+	}
+	return fmt.Errorf("read stream: %w", err)
+}
+
+// StreamParseError reports an SSE payload that is not valid JSON. With
+// WithStrictStreaming it ends the stream; otherwise the payload is skipped and
+// only counted (ClientStats.MalformedStreamChunks, StreamStats.MalformedChunks)
+// and passed to Hooks.OnStreamParseError.
+type StreamParseError struct {
+	// Payload is the raw data of the SSE event.
+	Payload string
+	Err     error
+}
+
+func (e *StreamParseError) Error() string {
+	return "parse stream chunk: " + e.Err.Error()
+}
+
+func (e *StreamParseError) Unwrap() error { return e.Err }
+
+// WithStrictStreaming makes a malformed SSE payload end ChatStream and
+// EventStream with a *StreamParseError, also delivered on the error channel
+// of ChatCompletionStream, instead of being skipped. Use it where a broken
+// deployment must not pass for a short answer.
+func (c *Client) WithStrictStreaming(strict bool) *Client {
+	c.strictStreaming = strict
+	return c
+}
+
+// malformedChunk counts, logs, and reports a payload that failed to parse,
+// and returns the error ending the stream in strict mode.
+func (c *Client) malformedChunk(ctx context.Context, payload string, err error) error {
+	perr := &StreamParseError{Payload: payload, Err: err}
+	c.stats.recordMalformedChunk()
+	c.logger.malformed(ctx, "malformed stream chunk", err)
+	c.hooks.streamParseError(ctx, perr)
+	if c.strictStreaming {
+		return perr
+	}
+	return nil
+}
+
+// StreamChat sends a streaming chat completion request and returns the open stream.
+// Errors before the first chunk (validation, transport, non-200 status) are
+// returned here rather than from Next.
+func (c *Client) StreamChat(ctx context.Context, req ChatRequest) (*ChatStream, error) {
+	return c.openChatStream(ctx, req, nil)
+}
+
+// StreamChatWithOptions is StreamChat with per-request options.
+func (c *Client) StreamChatWithOptions(ctx context.Context, req ChatRequest, opts RequestOptions) (*ChatStream, error) {
+	return c.openChatStream(ctx, req, &opts)
+}
+
+func (c *Client) openChatStream(ctx context.Context, req ChatRequest, opts *RequestOptions) (*ChatStream, error) {
+	start := time.Now()
+	body, release, err := c.postStream(ctx, "/v1/chat/completions", req, opts)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.streamOpened(ctx, "/v1/chat/completions")
+	return &ChatStream{body: body, scanner: c.newSSEScanner(body), release: release, log: c.logger, hooks: c.hooks, ctx: ctx, now: time.Now, start: start,
+		malformed: func(payload string, err error) error { return c.malformedChunk(ctx, payload, err) }}, nil
+}
+
+// postStream sends req with Stream set to path and returns the open SSE body
+// and the function releasing its concurrency slot. Errors before the body
+// (validation, transport, non-200 status) are returned here.
+func (c *Client) postStream(ctx context.Context, path string, req ChatRequest, opts *RequestOptions) (io.ReadCloser, func(), error) {
+	req.Stream = true
+	applyOverrides(ctx, &req, opts)
+	if err := req.prepare(); err != nil {
+		return nil, nil, err
+	}
+	c.degradeChat(ctx, &req)
+	c.applyProfileDefaults(ctx, &req, opts)
+
+	if err := c.ensureInit(ctx); err != nil {
+		return nil, nil, err
+	}
+	release, err := c.limiter.acquire(ctx, req.Model)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		release()
+		return nil, nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	if opts != nil {
+		ctx = withOptionsTimeout(ctx, *opts)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		release()
+		return nil, nil, fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(httpReq)
+	if opts != nil {
+		applyOptions(httpReq, *opts)
+	}
+
+	httpReq, done, err := c.shutdown.track(httpReq, c.requestTimeout(httpReq))
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+
+	resp, err := c.sendStream(httpReq)
+	resp, err = c.shutdown.hold(resp, err, done)
+	if err != nil {
+		release()
+		return nil, nil, fmt.Errorf("send request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		release()
+		return nil, nil, c.parseError(resp)
+	}
+	return resp.Body, release, nil
+}
+
+// sendStream sends req with the configured http.Client, its transport and
+// connection pool included, but with the client's Timeout bounding only the
+// wait for the response headers: a stream is read for as long as it runs.
+func (c *Client) sendStream(req *http.Request) (*http.Response, error) {
+	timeout := c.degradedHTTPClient().Timeout
+	if timeout <= 0 {
+		return c.doWith(c.untimedHTTPClient(), req)
+	}
+	// The timer context is released with req's, which shutdown.track cancels
+	// when the stream is closed.
+	ctx, cancel := context.WithCancel(req.Context())
+	timer := time.AfterFunc(timeout, cancel)
+	resp, err := c.doWith(c.untimedHTTPClient(), req.WithContext(ctx))
+	if !timer.Stop() && req.Context().Err() == nil {
+		if err == nil {
+			resp.Body.Close()
+		}
+		return nil, fmt.Errorf("no response headers within %v: %w", timeout, context.DeadlineExceeded)
+	}
+	return resp, err
+}
+
+// Next returns the next chunk. It returns io.EOF when the stream ends normally;
+// any other error ends the stream and is also reported by Err.
+func (s *ChatStream) Next() (ChatStreamChunk, error) {
+	if s.err != nil {
+		return ChatStreamChunk{}, s.err
+	}
+
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+
+		// Remove "data: " prefix
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		data := strings.TrimPrefix(line, "data: ")
+
+		// End of stream
+		if data == "[DONE]" {
+			return ChatStreamChunk{}, s.finish(io.EOF)
+		}
+
+		var chunk ChatStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			s.malformedCount++
+			if perr := s.malformed(data, err); perr != nil {
+				return ChatStreamChunk{}, s.finish(perr)
+			}
+			continue
+		}
+		s.observe(chunk)
+		s.hooks.streamChunk(s.ctx, chunk)
+		if s.onChunk != nil {
+			s.onChunk(chunk)
+		}
+		return chunk, nil
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		return ChatStreamChunk{}, s.finish(streamReadError(err, s.scanner))
+	}
+	return ChatStreamChunk{}, s.finish(io.EOF)
+}
+
+// Err returns the error that ended the stream, or nil if it ended normally or
+// is still open.
+func (s *ChatStream) Err() error {
+	if s.err == io.EOF {
+		return nil
+	}
+	return s.err
+}
+
+// Close releases the stream's connection. It is safe to call more than once.
+func (s *ChatStream) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		if s.err == nil {
+			s.log.streamEnded(s.ctx, s, nil)
+		}
+		err = s.body.Close()
+		s.release()
+	})
+	return err
+}
+
+// Stats returns the stream's timing so far. Call it after the stream ended
+// for final figures.
+func (s *ChatStream) Stats() StreamStats {
+	end := s.end
+	if end.IsZero() {
+		end = s.now()
+	}
+	st := StreamStats{Total: end.Sub(s.start), MalformedChunks: s.malformedCount}
+	if s.usage != nil {
+		st.CompletionTokens = s.usage.CompletionTokens
+	} else {
+		st.CompletionTokens, st.Estimated = EstimateTokens(s.text.String()), true
+	}
+	if s.firstToken.IsZero() {
+		return st
+	}
+	st.TimeToFirstToken = s.firstToken.Sub(s.start)
+	st.GenerationTime = end.Sub(s.firstToken)
+	if st.GenerationTime > 0 {
+		st.TokensPerSecond = float64(st.CompletionTokens) / st.GenerationTime.Seconds()
+	}
+	return st
+}
+
+// observe records the timing and output of chunk for Stats.
+func (s *ChatStream) observe(chunk ChatStreamChunk) {
+	if chunk.Usage != nil {
+		s.usage = chunk.Usage
+	}
+	for _, c := range chunk.Choices {
+		if c.Delta.Content == "" && len(c.Delta.ToolCalls) == 0 {
+			continue
+		}
+		if s.firstToken.IsZero() {
+			s.firstToken = s.now()
+		}
+		s.text.WriteString(c.Delta.Content)
+		for _, tc := range c.Delta.ToolCalls {
+			s.text.WriteString(tc.Function.Name)
+			s.text.WriteString(tc.Function.Arguments)
+		}
+	}
+}
+
+// finish records the terminal error and closes the stream.
+func (s *ChatStream) finish(err error) error {
+	if err == io.EOF && s.onEOF != nil {
+		if hookErr := s.onEOF(); hookErr != nil {
+			err = hookErr
+		}
+	}
+	s.err = err
+	s.end = s.now()
+	s.log.streamEnded(s.ctx, s, err)
+	s.Close()
+	return err
+}
+
+// streamChannels adapts a ChatStream to the channel-based streaming API.
+func streamChannels(ctx context.Context, open func() (*ChatStream, error)) (<-chan ChatStreamChunk, <-chan error) {
+	chunks := make(chan ChatStreamChunk, 100)
+	errs := make(chan error, 1)
+
+	go func() {
+		stream, err := open()
+		if err != nil {
+			errs <- err
+			close(chunks)
+			close(errs)
+			return
+		}
+		forwardStream(ctx, stream, chunks, errs)
+	}()
+
+	return chunks, errs
+}
+
+// pumpStream is streamChannels for a stream that is already open.
+func pumpStream(ctx context.Context, stream *ChatStream) (<-chan ChatStreamChunk, <-chan error) {
+	chunks := make(chan ChatStreamChunk, 100)
+	errs := make(chan error, 1)
+	go forwardStream(ctx, stream, chunks, errs)
+	return chunks, errs
+}
+
+// forwardStream sends stream's chunks to chunks until it ends or ctx is done,
+// then closes the stream and both channels.
+func forwardStream(ctx context.Context, stream *ChatStream, chunks chan<- ChatStreamChunk, errs chan<- error) {
+	defer close(chunks)
+	defer close(errs)
+	defer stream.Close()
+
+	for {
+		chunk, err := stream.Next()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			errs <- err
+			return
+		}
+		select {
+		case chunks <- chunk:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// ToolCallAccumulator assembles streamed tool call fragments into complete calls.
+// The zero value is ready to use.
+//
+//	var acc hackeserasdk.ToolCallAccumulator
+//	for chunk := range chunks {
+//		acc.Add(chunk.Choices[0].Delta)
+//	}
+//	calls := acc.ToolCalls()
+type ToolCallAccumulator struct {
+	calls map[int]*ToolCall
+	order []int
+}
+
+// Add merges the tool call fragments of d.
+func (a *ToolCallAccumulator) Add(d Delta) {
+	for _, tc := range d.ToolCalls {
+		if a.calls == nil {
+			a.calls = make(map[int]*ToolCall)
+		}
+		call, ok := a.calls[tc.Index]
+		if !ok {
+			call = &ToolCall{Type: ToolTypeFunction}
+			a.calls[tc.Index] = call
+			a.order = append(a.order, tc.Index)
+		}
+		if tc.ID != "" {
+			call.ID = tc.ID
+		}
+		if tc.Type != "" {
+			call.Type = tc.Type
+		}
+		call.Function.Name += tc.Function.Name
+		call.Function.Arguments += tc.Function.Arguments
+	}
+}
+
+// ToolCalls returns the calls assembled so far, in the order they were started.
+func (a *ToolCallAccumulator) ToolCalls() []ToolCall {
+	if len(a.order) == 0 {
+		return nil
+	}
+	out := make([]ToolCall, 0, len(a.order))
+	for _, idx := range a.order {
+		out = append(out, *a.calls[idx])
+	}
+	return out
+}
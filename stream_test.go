@@ -0,0 +1,381 @@
+package hackeserasdk
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamChat(t *testing.T) {
+	srv := newScriptedServer(t).withSSE(
+		`{"id":"s1","choices":[{"index":0,"delta":{"role":"assistant","content":"Hello"}}]}`,
+		`{"id":"s1","choices":[{"index":0,"delta":{"content":" world"}}]}`,
+	)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	stream, err := client.StreamChat(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+
+	var content string
+	for {
+		chunk, err := stream.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		content += chunk.Choices[0].Delta.Content
+	}
+	if content != "Hello world" {
+		t.Errorf("expected %q, got %q", "Hello world", content)
+	}
+	if stream.Err() != nil {
+		t.Errorf("expected nil Err after normal end, got %v", stream.Err())
+	}
+	if _, err := stream.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF after end, got %v", err)
+	}
+}
+
+func TestStreamChatCloseEarlyReleasesSlot(t *testing.T) {
+	srv := newScriptedServer(t).withSSE(
+		`{"choices":[{"index":0,"delta":{"content":"a"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"b"}}]}`,
+	)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").
+		WithModelConcurrency(map[string]int{ModelDefault: 1}, ConcurrencyReject)
+	ctx := context.Background()
+
+	stream, err := client.StreamChat(ctx, ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := stream.Next(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.StreamChat(ctx, ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}); !errors.Is(err, ErrTooManyInFlight) {
+		t.Errorf("expected open stream to hold its slot, got %v", err)
+	}
+
+	stream.Close()
+	stream.Close()
+	next, err := client.StreamChat(ctx, ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("expected slot to be released by Close, got %v", err)
+	}
+	next.Close()
+}
+
+func TestStreamChatAPIError(t *testing.T) {
+	srv := newTestServer(t, http.MethodPost, "/v1/chat/completions", http.StatusUnauthorized,
+		ErrorResponse{Error: ErrorDetail{Message: "bad key", Type: "authentication_error"}})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "bad")
+	_, err := client.StreamChat(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 APIError from StreamChat, got %v", err)
+	}
+}
+
+func TestChatCompletionStreamStart(t *testing.T) {
+	limited := newScriptedServer(t).handle("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.Header().Set("X-RateLimit-Remaining-Requests", "0")
+		w.Header().Set("X-Served-By", "edge-3")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"message":"slow down","type":"rate_limit_error"}}`))
+	})
+	defer limited.Close()
+	req := ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}
+
+	chunks, errs, err := NewClient(limited.URL, "test-key").ChatCompletionStreamStart(context.Background(), req)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || chunks != nil || errs != nil {
+		t.Fatalf("expected a synchronous 429 APIError, got %v", err)
+	}
+	if apiErr.RetryAfter != 7*time.Second || apiErr.Header.Get("X-Served-By") != "edge-3" || apiErr.RateLimit == nil {
+		t.Errorf("expected response detail on the error, got %+v", apiErr)
+	}
+
+	srv := newScriptedServer(t, assistantReply("hello"))
+	defer srv.Close()
+	chunks, errs, err = NewClient(srv.URL, "test-key").ChatCompletionStreamStart(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var text strings.Builder
+	for chunk := range chunks {
+		text.WriteString(chunk.Choices[0].Delta.Content)
+	}
+	if err := <-errs; err != nil || text.String() != "hello" {
+		t.Errorf("expected streamed reply, got %q / %v", text.String(), err)
+	}
+}
+
+func TestStreamToolCallDeltas(t *testing.T) {
+	srv := newScriptedServer(t).withSSE(
+		`{"choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"nmap_scan","arguments":""}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"target\":"}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_2","type":"function","function":{"name":"whois","arguments":"{}"}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"10.0.0.0/24\"}"}}]}}]}`,
+	)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	chunks, errs := client.ChatCompletionStream(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	var acc ToolCallAccumulator
+	for chunk := range chunks {
+		acc.Add(chunk.Choices[0].Delta)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	calls := acc.ToolCalls()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 tool calls, got %+v", calls)
+	}
+	if calls[0].ID != "call_1" || calls[0].Function.Name != "nmap_scan" || calls[0].Function.Arguments != `{"target":"10.0.0.0/24"}` {
+		t.Errorf("unexpected first call: %+v", calls[0])
+	}
+	if calls[1].ID != "call_2" || calls[1].Function.Name != "whois" {
+		t.Errorf("unexpected second call: %+v", calls[1])
+	}
+}
+
+func TestStreamSafetyAnnotations(t *testing.T) {
+	srv := newScriptedServer(t).withSSE(
+		`{"choices":[{"index":0,"delta":{"role":"assistant","content":"Sure."}}]}`,
+		`{"choices":[{"index":0,"delta":{},"finish_reason":"stop","safety":{"categories":[{"category":"self_harm","severity":"high"}]}}]}`,
+	)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	chunks, errs := client.ChatCompletionStream(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	var safety *SafetyAnnotations
+	for chunk := range chunks {
+		if s := chunk.Choices[0].Safety; s != nil {
+			safety = s
+		}
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !safety.Flagged(SeverityHigh) || safety.Categories[0].Category != "self_harm" {
+		t.Errorf("expected final chunk annotations, got %+v", safety)
+	}
+}
+
+func TestStreamLineLimit(t *testing.T) {
+	args := `{"rows":"` + strings.Repeat("x", 200*1024) + `"}`
+	data, _ := json.Marshal(ChatStreamChunk{Choices: []ChunkChoice{{Delta: Delta{ToolCalls: []ToolCallDelta{{Index: 0, ID: "call_1", Function: FunctionCallDelta{Name: "export", Arguments: args}}}}}}})
+	srv := newScriptedServer(t).withSSE(string(data))
+	defer srv.Close()
+	req := ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "export"}}}
+
+	client := NewClient(srv.URL, "test-key").WithMaxStreamLine(64 * 1024)
+	stream, err := client.StreamChat(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = stream.Next()
+	var tooLong *StreamLineTooLongError
+	if !errors.As(err, &tooLong) || tooLong.Limit != 64*1024 || !errors.Is(err, bufio.ErrTooLong) {
+		t.Fatalf("expected StreamLineTooLongError, got %v", err)
+	}
+	if !errors.As(stream.Err(), &tooLong) {
+		t.Errorf("expected Err to report the overflow, got %v", stream.Err())
+	}
+
+	stream, err = client.WithMaxStreamLine(512*1024).StreamChat(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+	chunk, err := stream.Next()
+	if err != nil || chunk.Choices[0].Delta.ToolCalls[0].Function.Arguments != args {
+		t.Errorf("expected the raised limit to fit the chunk, got %v", err)
+	}
+}
+
+func TestStreamStats(t *testing.T) {
+	srv := newScriptedServer(t).withSSE(
+		`{"choices":[{"index":0,"delta":{"role":"assistant"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"Hello"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":" world"}}]}`,
+		`{"choices":[{"index":0,"delta":{},"finish_reason":"stop"}],"usage":{"prompt_tokens":3,"completion_tokens":20,"total_tokens":23}}`,
+	)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	stream, err := client.StreamChat(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+
+	// Each clock reading advances 250ms.
+	clock := time.Unix(1000, 0)
+	stream.start = clock
+	stream.now = func() time.Time {
+		clock = clock.Add(250 * time.Millisecond)
+		return clock
+	}
+	for {
+		if _, err := stream.Next(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	st := stream.Stats()
+	want := StreamStats{
+		TimeToFirstToken: 250 * time.Millisecond,
+		GenerationTime:   250 * time.Millisecond,
+		Total:            500 * time.Millisecond,
+		CompletionTokens: 20,
+		TokensPerSecond:  80,
+	}
+	if st != want {
+		t.Errorf("stats = %+v, want %+v", st, want)
+	}
+}
+
+// countingTransport counts the requests sent through it.
+type countingTransport struct {
+	n    int
+	base http.RoundTripper
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.n++
+	return t.base.RoundTrip(req)
+}
+
+func TestStreamUsesConfiguredTransport(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, part := range []string{"Hel", "lo"} {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", part)
+			w.(http.Flusher).Flush()
+			time.Sleep(60 * time.Millisecond)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+	defer srv.Close()
+
+	// The stream outlives the client Timeout, which bounds only the headers.
+	transport := &countingTransport{base: http.DefaultTransport}
+	client := NewClient(srv.URL, "test-key").WithHTTPClient(&http.Client{Transport: transport, Timeout: 50 * time.Millisecond})
+	stream, err := client.StreamChat(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+	var text string
+	for {
+		chunk, err := stream.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		text += chunk.Choices[0].Delta.Content
+	}
+	if text != "Hello" || transport.n != 1 {
+		t.Errorf("got %q over %d transport requests", text, transport.n)
+	}
+}
+
+func TestStreamResponseHeaderTimeout(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body) // lets the server notice the client hanging up
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithHTTPClient(&http.Client{Timeout: 20 * time.Millisecond})
+	_, err := client.StreamChat(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a response header timeout, got %v", err)
+	}
+}
+
+func TestStreamMalformedChunks(t *testing.T) {
+	sse := []string{
+		`{"choices":[{"index":0,"delta":{"content":"Hel"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":`,
+		`{"choices":[{"index":0,"delta":{"content":"lo"}}]}`,
+	}
+	req := ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}
+
+	t.Run("lenient", func(t *testing.T) {
+		srv := newScriptedServer(t).withSSE(sse...)
+		defer srv.Close()
+		var hooked []string
+		client := NewClient(srv.URL, "test-key").WithHooks(Hooks{
+			OnStreamParseError: func(ctx context.Context, err *StreamParseError) { hooked = append(hooked, err.Payload) },
+		})
+		stream, err := client.StreamChat(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer stream.Close()
+		var text string
+		for {
+			chunk, err := stream.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			text += chunk.Choices[0].Delta.Content
+		}
+		if text != "Hello" || stream.Stats().MalformedChunks != 1 || client.Stats().MalformedStreamChunks != 1 {
+			t.Errorf("text %q, stream stats %+v, client stats %+v", text, stream.Stats(), client.Stats())
+		}
+		if len(hooked) != 1 || hooked[0] != sse[1] {
+			t.Errorf("hooked payloads = %q", hooked)
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		srv := newScriptedServer(t).withSSE(sse...)
+		defer srv.Close()
+		client := NewClient(srv.URL, "test-key").WithStrictStreaming(true)
+		chunks, errs := client.ChatCompletionStream(context.Background(), req)
+		n := 0
+		for range chunks {
+			n++
+		}
+		var perr *StreamParseError
+		if err := <-errs; !errors.As(err, &perr) || perr.Payload != sse[1] {
+			t.Fatalf("expected a StreamParseError with the payload, got %v", err)
+		}
+		if n != 1 {
+			t.Errorf("expected the stream to end at the malformed chunk, got %d chunks", n)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package hackeserasdk
+
+import (
+	"io"
+	"sync"
+)
+
+// ─── Stream Multiplexing ────────────────────────────────────────────────────
+
+// StreamMux fans a single ChatStream out to several consumers. Each subscriber
+// has its own queue, so a slow consumer (a transcript writer, say) does not hold
+// back a fast one (a UI socket).
+//
+//	mux := hackeserasdk.NewStreamMux(stream)
+//	ui := mux.Subscribe(0)
+//	audit := mux.Subscribe(0)
+//	go forwardToSocket(ui.C)
+//	go writeTranscript(audit.C)
+//	if err := mux.Run(); err != nil {
+//		return err
+//	}
+type StreamMux struct {
+	stream *ChatStream
+
+	mu       sync.Mutex
+	subs     []*StreamSubscription
+	err      error
+	finished bool
+}
+
+// NewStreamMux wraps stream. The mux takes ownership and closes it when Run returns.
+func NewStreamMux(stream *ChatStream) *StreamMux {
+	return &StreamMux{stream: stream}
+}
+
+// Subscribe adds a consumer. maxPending bounds the chunks queued for it: when
+// the consumer falls that far behind, further chunks are dropped for it alone
+// and counted by Dropped. Zero means unbounded. Subscribers added after Run has
+// started only receive the chunks that follow; those added after it returned
+// get a closed C.
+func (m *StreamMux) Subscribe(maxPending int) *StreamSubscription {
+	ch := make(chan ChatStreamChunk)
+	s := &StreamSubscription{C: ch, ch: ch, maxPending: maxPending, done: make(chan struct{})}
+	s.cond = sync.NewCond(&s.mu)
+
+	m.mu.Lock()
+	if m.finished {
+		s.ended = true
+	} else {
+		m.subs = append(m.subs, s)
+	}
+	m.mu.Unlock()
+	go s.forward()
+	return s
+}
+
+// Run reads the stream to the end, delivering each chunk to every subscriber,
+// then closes the stream. Subscriber channels are closed once they have drained
+// their queue. Run returns the error that ended the stream, or nil at a normal end.
+func (m *StreamMux) Run() error {
+	defer m.stream.Close()
+
+	var err error
+	for {
+		var chunk ChatStreamChunk
+		chunk, err = m.stream.Next()
+		if err != nil {
+			break
+		}
+		m.mu.Lock()
+		subs := m.subs
+		m.mu.Unlock()
+		for _, s := range subs {
+			s.push(chunk)
+		}
+	}
+	if err == io.EOF {
+		err = nil
+	}
+
+	m.mu.Lock()
+	m.err, m.finished = err, true
+	subs := m.subs
+	m.mu.Unlock()
+	for _, s := range subs {
+		s.end()
+	}
+	return err
+}
+
+// Err returns the error that ended the stream once Run has returned.
+func (m *StreamMux) Err() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.err
+}
+
+// StreamSubscription is one consumer of a StreamMux.
+type StreamSubscription struct {
+	// C delivers the stream's chunks in order. It is closed after the stream
+	// ends and every queued chunk has been received, or when Cancel is called.
+	C <-chan ChatStreamChunk
+
+	ch         chan ChatStreamChunk
+	maxPending int
+	done       chan struct{}
+	cancelOnce sync.Once
+
+	mu        sync.Mutex
+	cond      *sync.Cond
+	queue     []ChatStreamChunk
+	ended     bool
+	cancelled bool
+	dropped   int
+}
+
+// Dropped returns the number of chunks skipped because the subscriber's queue was full.
+func (s *StreamSubscription) Dropped() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// Cancel stops delivery to this subscriber and closes C. Other subscribers are
+// not affected.
+func (s *StreamSubscription) Cancel() {
+	s.cancelOnce.Do(func() {
+		s.mu.Lock()
+		s.cancelled = true
+		s.queue = nil
+		s.cond.Signal()
+		s.mu.Unlock()
+		close(s.done)
+	})
+}
+
+func (s *StreamSubscription) push(chunk ChatStreamChunk) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancelled {
+		return
+	}
+	if s.maxPending > 0 && len(s.queue) >= s.maxPending {
+		s.dropped++
+		return
+	}
+	s.queue = append(s.queue, chunk)
+	s.cond.Signal()
+}
+
+func (s *StreamSubscription) end() {
+	s.mu.Lock()
+	s.ended = true
+	s.cond.Signal()
+	s.mu.Unlock()
+}
+
+// forward moves queued chunks to C until the stream ends or the subscriber cancels.
+func (s *StreamSubscription) forward() {
+	defer close(s.ch)
+	for {
+		s.mu.Lock()
+		for len(s.queue) == 0 && !s.ended && !s.cancelled {
+			s.cond.Wait()
+		}
+		if s.cancelled || len(s.queue) == 0 {
+			s.mu.Unlock()
+			return
+		}
+		chunk := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		select {
+		case s.ch <- chunk:
+		case <-s.done:
+			return
+		}
+	}
+}
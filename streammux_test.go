@@ -0,0 +1,129 @@
+package hackeserasdk
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func collectContent(sub *StreamSubscription) string {
+	var text string
+	for chunk := range sub.C {
+		text += chunk.Choices[0].Delta.Content
+	}
+	return text
+}
+
+func TestStreamMuxFansOut(t *testing.T) {
+	srv := newScriptedServer(t).withSSE(
+		`{"choices":[{"index":0,"delta":{"content":"a"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"b"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"c"}}]}`,
+	)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	stream, err := client.StreamChat(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mux := NewStreamMux(stream)
+	subs := []*StreamSubscription{mux.Subscribe(0), mux.Subscribe(0)}
+	cancelled := mux.Subscribe(0)
+	cancelled.Cancel()
+
+	var wg sync.WaitGroup
+	got := make([]string, len(subs))
+	for i, sub := range subs {
+		wg.Add(1)
+		go func(i int, sub *StreamSubscription) {
+			defer wg.Done()
+			got[i] = collectContent(sub)
+		}(i, sub)
+	}
+
+	if err := mux.Run(); err != nil {
+		t.Fatalf("unexpected run error: %v", err)
+	}
+	wg.Wait()
+
+	for i, text := range got {
+		if text != "abc" {
+			t.Errorf("subscriber %d: expected abc, got %q", i, text)
+		}
+	}
+	if text := collectContent(cancelled); text != "" {
+		t.Errorf("expected cancelled subscriber to receive nothing, got %q", text)
+	}
+	if mux.Err() != nil {
+		t.Errorf("expected nil Err, got %v", mux.Err())
+	}
+}
+
+func TestStreamMuxDropsForSlowSubscriber(t *testing.T) {
+	srv := newScriptedServer(t).withSSE(
+		`{"choices":[{"index":0,"delta":{"content":"1"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"2"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"3"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"4"}}]}`,
+	)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	stream, err := client.StreamChat(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mux := NewStreamMux(stream)
+	fast := mux.Subscribe(0)
+	slow := mux.Subscribe(1)
+
+	var fastText string
+	done := make(chan struct{})
+	go func() {
+		fastText = collectContent(fast)
+		close(done)
+	}()
+
+	// slow is not read until the stream has ended.
+	if err := mux.Run(); err != nil {
+		t.Fatalf("unexpected run error: %v", err)
+	}
+	<-done
+	slowText := collectContent(slow)
+
+	if fastText != "1234" {
+		t.Errorf("expected fast subscriber to receive everything, got %q", fastText)
+	}
+	if slow.Dropped() == 0 || len(slowText)+slow.Dropped() != 4 {
+		t.Errorf("expected slow subscriber to drop chunks, got %q with %d dropped", slowText, slow.Dropped())
+	}
+}
+
+func TestStreamMuxSubscribeAfterRun(t *testing.T) {
+	srv := newScriptedServer(t).withSSE(`{"choices":[{"index":0,"delta":{"content":"a"}}]}`)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	stream, err := client.StreamChat(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mux := NewStreamMux(stream)
+	if err := mux.Run(); err != nil {
+		t.Fatalf("unexpected run error: %v", err)
+	}
+
+	late := mux.Subscribe(0)
+	select {
+	case _, ok := <-late.C:
+		if ok {
+			t.Error("expected no chunks for a late subscriber")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected late subscriber's channel to be closed")
+	}
+}
@@ -0,0 +1,139 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// ─── Structured Output ──────────────────────────────────────────────────────
+
+// RepairPolicy controls how ChatCompletionAs recovers from replies that fail to
+// parse or validate.
+type RepairPolicy struct {
+	// MaxRepairs is the number of times the parse or validation error is fed
+	// back to the model for a corrected reply. Zero disables repair.
+	MaxRepairs int
+	// Validate, if set, checks the decoded value, passed as a *T; a non-nil error
+	// triggers repair like a parse failure.
+	Validate func(v interface{}) error
+}
+
+// OutputError is returned when a structured reply still fails to parse or
+// validate after all repair attempts.
+type OutputError struct {
+	// Content is the last reply received.
+	Content string
+	// Attempts is the number of completions requested, including repairs.
+	Attempts int
+	Err      error
+}
+
+func (e *OutputError) Error() string {
+	return fmt.Sprintf("structured output invalid after %d attempt(s): %v", e.Attempts, e.Err)
+}
+
+func (e *OutputError) Unwrap() error { return e.Err }
+
+// ChatCompletionAs sends req and decodes the reply's JSON content into a T. If
+// req has no ResponseFormat, Grammar, or Regex, JSON mode is requested. Replies
+// that fail to decode (or fail policy.Validate) are answered with the error and
+// a request to correct it, up to policy.MaxRepairs times. The returned response
+// is the one the value was decoded from. Repairs are counted in ClientStats.
+//
+//	type Triage struct {
+//		Severity string   `json:"severity"`
+//		Hosts    []string `json:"hosts"`
+//	}
+//	triage, _, err := hackeserasdk.ChatCompletionAs[Triage](ctx, client, req,
+//		hackeserasdk.RepairPolicy{MaxRepairs: 2})
+func ChatCompletionAs[T any](ctx context.Context, c *Client, req ChatRequest, policy RepairPolicy) (T, *ChatResponse, error) {
+	var zero T
+	if req.ResponseFormat == nil && req.Grammar == "" && req.Regex == "" {
+		req.ResponseFormat = &ResponseFormat{Type: "json_object"}
+	}
+	req.Messages = append([]Message(nil), req.Messages...)
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.ChatCompletion(ctx, req)
+		if err != nil {
+			return zero, nil, err
+		}
+		if len(resp.Choices) == 0 {
+			return zero, resp, fmt.Errorf("structured output: empty response")
+		}
+
+		content := messageText(resp.Choices[0].Message)
+		var v T
+		err = json.Unmarshal([]byte(stripCodeFence(content)), &v)
+		if err == nil && policy.Validate != nil {
+			err = policy.Validate(&v)
+		}
+		if err == nil {
+			c.stats.recordStructuredOutput(attempt-1, false)
+			return v, resp, nil
+		}
+
+		if attempt > policy.MaxRepairs {
+			c.stats.recordStructuredOutput(attempt-1, true)
+			return zero, resp, &OutputError{Content: content, Attempts: attempt, Err: err}
+		}
+		req.Messages = append(req.Messages,
+			Message{Role: "assistant", Content: content},
+			Message{Role: "user", Content: repairPrompt(err)},
+		)
+	}
+}
+
+// ChatCompletionInto is ChatCompletionAs with the reply constrained to the JSON
+// Schema of T (see SchemaFor), named after T. A response format already carrying
+// a schema is kept. A malformed reply is repaired once.
+//
+//	finding, _, err := hackeserasdk.ChatCompletionInto[Finding](ctx, client, req)
+func ChatCompletionInto[T any](ctx context.Context, c *Client, req ChatRequest) (T, *ChatResponse, error) {
+	if req.ResponseFormat == nil || req.ResponseFormat.JSONSchema == nil {
+		req.ResponseFormat = JSONSchemaResponse(schemaName[T](), SchemaFor[T]())
+	}
+	return ChatCompletionAs[T](ctx, c, req, RepairPolicy{MaxRepairs: 1})
+}
+
+// schemaName derives a json_schema name from T's Go type name.
+func schemaName[T any]() string {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, t.Name())
+	if name == "" {
+		return "response"
+	}
+	return name
+}
+
+// repairPrompt asks the model to correct its previous reply.
+func repairPrompt(err error) string {
+	return "Your previous reply was not valid: " + err.Error() +
+		". Reply again with only the corrected JSON, no explanation."
+}
+
+// stripCodeFence removes a surrounding ```json ... ``` fence, which models add
+// despite JSON mode often enough to be worth tolerating.
+func stripCodeFence(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+	s = strings.TrimPrefix(s, "```")
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[i+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "```"))
+}
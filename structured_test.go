@@ -0,0 +1,151 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type triage struct {
+	Severity string   `json:"severity"`
+	Hosts    []string `json:"hosts"`
+}
+
+func TestChatCompletionAsRepairs(t *testing.T) {
+	srv := newScriptedServer(t,
+		assistantReply(`{"severity": "high", "hosts": ["10.0.0.5"`),
+		assistantReply(`{"severity": "", "hosts": []}`),
+		assistantReply("```json\n{\"severity\": \"high\", \"hosts\": [\"10.0.0.5\"]}\n```"),
+	)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	validate := func(v interface{}) error {
+		if v.(*triage).Severity == "" {
+			return errors.New("severity is required")
+		}
+		return nil
+	}
+	got, _, err := ChatCompletionAs[triage](context.Background(), client, ChatRequest{
+		Model:    ModelDefault,
+		Messages: []Message{{Role: "user", Content: "Triage this alert"}},
+	}, RepairPolicy{MaxRepairs: 2, Validate: validate})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Severity != "high" || len(got.Hosts) != 1 {
+		t.Errorf("unexpected result: %+v", got)
+	}
+
+	seen := srv.chats()
+	if seen[0].ResponseFormat == nil || seen[0].ResponseFormat.Type != "json_object" {
+		t.Errorf("expected JSON mode, got %+v", seen[0].ResponseFormat)
+	}
+	if len(seen[2].Messages) != 5 || !strings.Contains(messageText(seen[2].Messages[4]), "severity is required") {
+		t.Errorf("expected validation error fed back, got %+v", seen[2].Messages)
+	}
+
+	stats := client.Stats()
+	if stats.StructuredOutputs != 1 || stats.Repairs != 2 || stats.RepairFailures != 0 {
+		t.Errorf("unexpected repair stats: %+v", stats)
+	}
+}
+
+func TestChatCompletionAsGivesUp(t *testing.T) {
+	srv := newScriptedServer(t, assistantReply("not json"), assistantReply("still not json"))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	_, _, err := ChatCompletionAs[triage](context.Background(), client, ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}, RepairPolicy{MaxRepairs: 1})
+	var outErr *OutputError
+	if !errors.As(err, &outErr) || outErr.Attempts != 2 || outErr.Content != "still not json" {
+		t.Fatalf("expected OutputError after 2 attempts, got %v", err)
+	}
+	if stats := client.Stats(); stats.RepairFailures != 1 {
+		t.Errorf("expected 1 repair failure, got %+v", stats)
+	}
+}
+
+func TestChatCompletionInto(t *testing.T) {
+	srv := newScriptedServer(t,
+		assistantReply(`not json`),
+		assistantReply(`{"severity": "low", "hosts": ["10.0.0.9"]}`),
+	)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	got, _, err := ChatCompletionInto[triage](context.Background(), client, ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Severity != "low" || len(got.Hosts) != 1 {
+		t.Errorf("unexpected value: %+v", got)
+	}
+
+	format := srv.chats()[0].ResponseFormat
+	if format == nil || format.Type != "json_schema" || format.JSONSchema == nil {
+		t.Fatalf("expected json_schema response format, got %+v", format)
+	}
+	if format.JSONSchema.Name != "triage" || !format.JSONSchema.Strict {
+		t.Errorf("unexpected schema format: %+v", format.JSONSchema)
+	}
+	schema := format.JSONSchema.Schema.(map[string]interface{})
+	if props := schema["properties"].(map[string]interface{}); len(props) != 2 {
+		t.Errorf("expected schema derived from triage, got %v", schema)
+	}
+}
+
+func TestChatCompletionIntoRepairsOnce(t *testing.T) {
+	srv := newScriptedServer(t, assistantReply(`nope`), assistantReply(`still nope`))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	_, _, err := ChatCompletionInto[triage](context.Background(), client, ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}})
+	var outErr *OutputError
+	if !errors.As(err, &outErr) || outErr.Attempts != 2 {
+		t.Fatalf("expected OutputError after 2 attempts, got %v", err)
+	}
+}
+
+func TestJSONSchemaResponseFormatRequiresName(t *testing.T) {
+	req := ChatRequest{Model: ModelDefault, ResponseFormat: &ResponseFormat{Type: "json_schema"}}
+	if err := req.normalize(); err == nil || !strings.Contains(err.Error(), "named schema") {
+		t.Errorf("expected missing schema error, got %v", err)
+	}
+	req.ResponseFormat = JSONSchemaResponse("triage", SchemaFor[triage]())
+	if err := req.normalize(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestJSONSchemaResponseStrictOnlyWhenCompatible(t *testing.T) {
+	type optional struct {
+		Severity string  `json:"severity"`
+		Note     *string `json:"note"`
+	}
+	type withMap struct {
+		Labels map[string]string `json:"labels"`
+	}
+	type nested struct {
+		Triage triage   `json:"triage"`
+		Items  []triage `json:"items"`
+	}
+	cases := map[string]struct {
+		schema interface{}
+		strict bool
+	}{
+		"all required":    {SchemaFor[triage](), true},
+		"nested":          {SchemaFor[nested](), true},
+		"optional field":  {SchemaFor[optional](), false},
+		"map":             {SchemaFor[withMap](), false},
+		"unconstrained":   {SchemaFor[interface{}](), false},
+		"raw json schema": {json.RawMessage(`{"type":"object","properties":{"a":{"type":"string"}},"required":["a"],"additionalProperties":false}`), true},
+	}
+	for name, tc := range cases {
+		if got := JSONSchemaResponse("t", tc.schema).JSONSchema.Strict; got != tc.strict {
+			t.Errorf("%s: expected strict=%v, got %v", name, tc.strict, got)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package hackeserasdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// ─── Request Templates ──────────────────────────────────────────────────────
+
+// RequestTemplate is a ChatRequest skeleton (system prompt, tools, sampling
+// parameters) with named variables, so a prompt can ship as configuration
+// rather than code. Variables are written {{name}} in message text and
+// Metadata values. A template is plain JSON; YAML libraries that decode through
+// JSON tags, such as sigs.k8s.io/yaml, read it as well.
+//
+//	{
+//	  "name": "triage",
+//	  "variables": [{"name": "product"}, {"name": "tone", "default": "concise"}],
+//	  "request": {
+//	    "model": "hackersera-ai-pro",
+//	    "temperature": 0.2,
+//	    "messages": [
+//	      {"role": "system", "content": "You triage {{product}} tickets. Be {{tone}}."},
+//	      {"role": "user", "content": "{{ticket}}"}
+//	    ]
+//	  }
+//	}
+type RequestTemplate struct {
+	Name      string             `json:"name,omitempty"`
+	Variables []TemplateVariable `json:"variables,omitempty"`
+	Request   ChatRequest        `json:"request"`
+}
+
+// TemplateVariable declares a template variable. A variable without a Default
+// must be bound by Instantiate.
+type TemplateVariable struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Default     *string `json:"default,omitempty"`
+}
+
+var templateVarPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// ParseRequestTemplate decodes a JSON template and checks that every variable
+// it uses is declared.
+func ParseRequestTemplate(data []byte) (*RequestTemplate, error) {
+	var t RequestTemplate
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("decode template: %w", err)
+	}
+	if err := t.check(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Instantiate returns a copy of the template's request with vars bound and
+// validates it. Binding a variable the template does not declare, or leaving a
+// required one unbound, is a *ValidationError. The template is not modified.
+func (t *RequestTemplate) Instantiate(vars map[string]string) (ChatRequest, error) {
+	if err := t.check(); err != nil {
+		return ChatRequest{}, err
+	}
+	values := map[string]string{}
+	for _, v := range t.Variables {
+		if bound, ok := vars[v.Name]; ok {
+			values[v.Name] = bound
+		} else if v.Default != nil {
+			values[v.Name] = *v.Default
+		} else {
+			return ChatRequest{}, invalidf("vars", "template %s: variable %q is required", t.label(), v.Name)
+		}
+	}
+	for name := range vars {
+		if _, ok := values[name]; !ok {
+			return ChatRequest{}, invalidf("vars", "template %s: unknown variable %q", t.label(), name)
+		}
+	}
+
+	req, err := t.copyRequest()
+	if err != nil {
+		return ChatRequest{}, err
+	}
+	rewriteTemplateText(&req, true, func(s string) string {
+		return templateVarPattern.ReplaceAllStringFunc(s, func(m string) string {
+			return values[templateVarPattern.FindStringSubmatch(m)[1]]
+		})
+	})
+	if err := req.Validate(); err != nil {
+		return ChatRequest{}, err
+	}
+	return req, nil
+}
+
+// check reports variables that are used but not declared, or declared twice.
+func (t *RequestTemplate) check() error {
+	declared := map[string]bool{}
+	for _, v := range t.Variables {
+		if declared[v.Name] {
+			return invalidf("variables", "template %s: variable %q is declared twice", t.label(), v.Name)
+		}
+		declared[v.Name] = true
+	}
+	var undeclared []string
+	seen := map[string]bool{}
+	req := t.Request
+	rewriteTemplateText(&req, false, func(s string) string {
+		for _, m := range templateVarPattern.FindAllStringSubmatch(s, -1) {
+			if name := m[1]; !declared[name] && !seen[name] {
+				seen[name] = true
+				undeclared = append(undeclared, name)
+			}
+		}
+		return s
+	})
+	if len(undeclared) > 0 {
+		sort.Strings(undeclared)
+		return invalidf("variables", "template %s: undeclared variables %q", t.label(), undeclared)
+	}
+	return nil
+}
+
+// rewriteTemplateText passes every templated string in req to fn: string
+// message content, text content parts, and Metadata values. With write set the
+// results are stored back, so req must be a copy the caller owns.
+func rewriteTemplateText(req *ChatRequest, write bool, fn func(string) string) {
+	for i := range req.Messages {
+		switch content := req.Messages[i].Content.(type) {
+		case string:
+			if s := fn(content); write {
+				req.Messages[i].Content = s
+			}
+		case []ContentPart:
+			for j := range content {
+				if content[j].Type != "text" {
+					continue
+				}
+				if s := fn(content[j].Text); write {
+					content[j].Text = s
+				}
+			}
+		}
+	}
+	for k, v := range req.Metadata {
+		if s := fn(v); write {
+			req.Metadata[k] = s
+		}
+	}
+}
+
+// copyRequest deep-copies the template's request through its JSON form, which
+// is also the form templates are stored in.
+func (t *RequestTemplate) copyRequest() (ChatRequest, error) {
+	data, err := json.Marshal(t.Request)
+	if err != nil {
+		return ChatRequest{}, fmt.Errorf("marshal template request: %w", err)
+	}
+	var req ChatRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return ChatRequest{}, fmt.Errorf("copy template request: %w", err)
+	}
+	return req, nil
+}
+
+func (t *RequestTemplate) label() string {
+	if t.Name == "" {
+		return "(unnamed)"
+	}
+	return strconv.Quote(t.Name)
+}
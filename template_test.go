@@ -0,0 +1,106 @@
+package hackeserasdk
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const triageTemplate = `{
+	"name": "triage",
+	"variables": [
+		{"name": "product", "description": "Product line"},
+		{"name": "tone", "default": "concise"},
+		{"name": "ticket"}
+	],
+	"request": {
+		"model": "hackersera-ai-pro",
+		"temperature": 0.2,
+		"tools": [{"type": "function", "function": {"name": "lookup_order", "parameters": {"type": "object"}}}],
+		"metadata": {"product": "{{product}}"},
+		"messages": [
+			{"role": "system", "content": "You triage {{ product }} tickets. Be {{tone}}."},
+			{"role": "user", "content": [{"type": "text", "text": "{{ticket}}"}]}
+		]
+	}
+}`
+
+func TestRequestTemplateInstantiate(t *testing.T) {
+	tmpl, err := ParseRequestTemplate([]byte(triageTemplate))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req, err := tmpl.Instantiate(map[string]string{"product": "VPN", "ticket": "Drops after sleep"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Model != ModelPro || *req.Temperature != 0.2 || len(req.Tools) != 1 {
+		t.Errorf("expected skeleton parameters to carry over, got %+v", req)
+	}
+	if got := messageText(req.Messages[0]); got != "You triage VPN tickets. Be concise." {
+		t.Errorf("unexpected system prompt %q", got)
+	}
+	if got := req.Messages[1].Parts()[0].Text; got != "Drops after sleep" {
+		t.Errorf("unexpected user text %q", got)
+	}
+	if req.Metadata["product"] != "VPN" {
+		t.Errorf("expected metadata bound, got %v", req.Metadata)
+	}
+
+	// The template itself keeps its placeholders.
+	if got := tmpl.Request.Messages[1].Parts()[0].Text; got != "{{ticket}}" || tmpl.Request.Metadata["product"] != "{{product}}" {
+		t.Errorf("expected template unchanged, got %q / %v", got, tmpl.Request.Metadata)
+	}
+	*req.Temperature = 1
+	if *tmpl.Request.Temperature != 0.2 {
+		t.Error("expected instantiated request not to share pointers with the template")
+	}
+}
+
+func TestRequestTemplateVariableErrors(t *testing.T) {
+	tmpl, err := ParseRequestTemplate([]byte(triageTemplate))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var vErr *ValidationError
+	if _, err := tmpl.Instantiate(map[string]string{"product": "VPN"}); !errors.As(err, &vErr) || !strings.Contains(err.Error(), `"ticket" is required`) {
+		t.Errorf("expected missing variable error, got %v", err)
+	}
+	if _, err := tmpl.Instantiate(map[string]string{"product": "VPN", "ticket": "x", "topic": "billing"}); !errors.As(err, &vErr) || !strings.Contains(err.Error(), "unknown variable") {
+		t.Errorf("expected unknown variable error, got %v", err)
+	}
+
+	undeclared := `{"request": {"model": "hackersera-ai", "messages": [{"role": "user", "content": "{{who}} and {{what}}"}]}}`
+	if _, err := ParseRequestTemplate([]byte(undeclared)); !errors.As(err, &vErr) || !strings.Contains(err.Error(), `["what" "who"]`) {
+		t.Errorf("expected undeclared variables error, got %v", err)
+	}
+}
+
+func TestRequestTemplateRoundTrip(t *testing.T) {
+	tone := "formal"
+	tmpl := RequestTemplate{
+		Name:      "greeting",
+		Variables: []TemplateVariable{{Name: "name"}, {Name: "tone", Default: &tone}},
+		Request: ChatRequest{
+			Model:     ModelDefault,
+			MaxTokens: IntPtr(100),
+			Messages:  []Message{{Role: "user", Content: "Greet {{name}} in a {{tone}} tone."}},
+		},
+	}
+	data, err := json.Marshal(tmpl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parsed, err := ParseRequestTemplate(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req, err := parsed.Instantiate(map[string]string{"name": "Ada"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if messageText(req.Messages[0]) != "Greet Ada in a formal tone." || *req.MaxTokens != 100 {
+		t.Errorf("unexpected request after round trip: %+v", req)
+	}
+}
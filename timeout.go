@@ -0,0 +1,60 @@
+package hackeserasdk
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ─── Per-Request Timeouts ───────────────────────────────────────────────────
+
+// WithEndpointTimeouts bounds calls by API path, e.g. a 2-minute budget for
+// chat completions and 5 seconds for model listing, without separate contexts
+// or clients. A key covers its path and every path below it
+// ("/v1/documents" also covers "/v1/documents/{id}"); the longest matching key
+// wins. RequestOptions.Timeout takes precedence for a single call.
+//
+//	client.WithEndpointTimeouts(map[string]time.Duration{
+//		"/v1/chat/completions": 2 * time.Minute,
+//		"/v1/models":           5 * time.Second,
+//	})
+func (c *Client) WithEndpointTimeouts(timeouts map[string]time.Duration) *Client {
+	c.endpointTimeouts = make(map[string]time.Duration, len(timeouts))
+	for path, d := range timeouts {
+		c.endpointTimeouts[strings.TrimRight(path, "/")] = d
+	}
+	return c
+}
+
+type timeoutKey struct{}
+
+// withOptionsTimeout records the Timeout of options passed to a *WithOptions
+// method, which override those on ctx.
+func withOptionsTimeout(ctx context.Context, opts RequestOptions) context.Context {
+	if opts.Timeout <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, timeoutKey{}, opts.Timeout)
+}
+
+// requestTimeout returns the timeout bounding req, or 0 for none.
+func (c *Client) requestTimeout(req *http.Request) time.Duration {
+	ctx := req.Context()
+	if d, ok := ctx.Value(timeoutKey{}).(time.Duration); ok {
+		return d
+	}
+	if opts, ok := RequestOptionsFromContext(ctx); ok && opts.Timeout > 0 {
+		return opts.Timeout
+	}
+
+	var timeout time.Duration
+	best := -1
+	for prefix, d := range c.endpointTimeouts {
+		path := req.URL.Path
+		if len(prefix) > best && (path == prefix || strings.HasPrefix(path, prefix+"/")) {
+			timeout, best = d, len(prefix)
+		}
+	}
+	return timeout
+}
@@ -0,0 +1,70 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEndpointTimeouts(t *testing.T) {
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/models":
+			json.NewEncoder(w).Encode(ModelList{Object: "list"})
+		default:
+			json.NewEncoder(w).Encode(ChatResponse{ID: "chatcmpl-slow"})
+		}
+	})
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key").WithEndpointTimeouts(map[string]time.Duration{
+		"/v1/models":           20 * time.Millisecond,
+		"/v1/chat/completions": time.Minute,
+	})
+	ctx := context.Background()
+	req := ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}
+
+	if _, err := client.ListModels(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the endpoint timeout, got %v", err)
+	}
+	if _, err := client.ChatCompletion(ctx, req); err != nil {
+		t.Errorf("chat within its budget failed: %v", err)
+	}
+
+	// RequestOptions.Timeout overrides the endpoint map, from the context or
+	// passed directly.
+	long := WithRequestOptions(ctx, RequestOptions{Timeout: time.Minute})
+	if _, err := client.ListModels(long); err != nil {
+		t.Errorf("context timeout did not override the endpoint map: %v", err)
+	}
+	if _, err := client.ChatCompletionWithOptions(long, req, RequestOptions{Timeout: 20 * time.Millisecond}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the per-call timeout, got %v", err)
+	}
+}
+
+func TestRequestTimeoutPathMatching(t *testing.T) {
+	client := NewClient("http://api", "key").WithEndpointTimeouts(map[string]time.Duration{
+		"/v1/documents/":      time.Second,
+		"/v1/documents/batch": 2 * time.Second,
+	})
+	for path, want := range map[string]time.Duration{
+		"/v1/documents":           time.Second,
+		"/v1/documents/doc-1":     time.Second,
+		"/v1/documents/batch-get": time.Second,
+		"/v1/documents/batch":     2 * time.Second,
+		"/v1/documentsx":          0,
+	} {
+		if got := client.requestTimeout(httptest.NewRequest(http.MethodGet, path, nil)); got != want {
+			t.Errorf("%s: timeout %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package hackeserasdk
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// ─── Token Estimation ───────────────────────────────────────────────────────
+
+// messageTokenOverhead approximates the per-message framing tokens (role, separators)
+// added by chat templates.
+const messageTokenOverhead = 4
+
+// EstimateTokens returns an approximate token count for text.
+//
+// It is a fast heuristic, not the model's tokenizer: one token per four letters
+// or digits of each word (at least one per word), one per punctuation mark, and
+// one per CJK character. It tends to overestimate slightly, which is the safe
+// direction for context budgeting.
+func EstimateTokens(text string) int {
+	tokens := 0
+	runLen := 0
+	flush := func() {
+		if runLen > 0 {
+			tokens += (runLen + 3) / 4
+			runLen = 0
+		}
+	}
+
+	for len(text) > 0 {
+		r, size := utf8.DecodeRuneInString(text)
+		text = text[size:]
+
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+			unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+			flush()
+			tokens++
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			runLen++
+		default:
+			flush()
+			tokens++
+		}
+	}
+	flush()
+	return tokens
+}
+
+// EstimateMessageTokens returns an approximate token count for a message list,
+// including per-message overhead. Only text content is counted; image parts are
+// not estimated.
+func EstimateMessageTokens(msgs []Message) int {
+	total := 0
+	for _, m := range msgs {
+		total += messageTokenOverhead + EstimateTokens(messageText(m))
+		for _, tc := range m.ToolCalls {
+			total += EstimateTokens(tc.Function.Name) + EstimateTokens(tc.Function.Arguments)
+		}
+	}
+	return total
+}
+
+// messageText returns the text portion of a message's content.
+func messageText(m Message) string {
+	return m.ContentAsText()
+}
@@ -0,0 +1,31 @@
+package hackeserasdk
+
+import "testing"
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"hello", 2},
+		{"hi there", 3},
+		{"Hello, world!", 6},
+		{"日本語", 3},
+	}
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.want {
+			t.Errorf("EstimateTokens(%q): expected %d, got %d", tt.text, tt.want, got)
+		}
+	}
+}
+
+func TestEstimateMessageTokens(t *testing.T) {
+	msgs := []Message{
+		{Role: "user", Content: "hi there"},
+		{Role: "user", Content: []ContentPart{{Type: "text", Text: "hello"}, {Type: "image_url"}}},
+	}
+	if got := EstimateMessageTokens(msgs); got != 2*messageTokenOverhead+5 {
+		t.Errorf("expected %d, got %d", 2*messageTokenOverhead+5, got)
+	}
+}
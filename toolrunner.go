@@ -0,0 +1,453 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ─── Tool Runner ────────────────────────────────────────────────────────────
+
+// defaultMaxToolIterations bounds RunTools when ToolRunner.MaxIterations is unset.
+const defaultMaxToolIterations = 10
+
+// ErrMaxToolIterations is returned by RunTools when the model is still calling
+// tools after ToolRunner.MaxIterations completions.
+var ErrMaxToolIterations = errors.New("tool runner: max iterations reached")
+
+// ToolHandler executes a tool call. args is the call's JSON arguments. A string
+// result is sent to the model as-is; anything else is JSON-encoded. A returned
+// error is reported to the model as the tool result rather than ending the run.
+type ToolHandler func(ctx context.Context, args json.RawMessage) (interface{}, error)
+
+// TypedToolHandler adapts a function taking a parameter struct to a ToolHandler.
+// Arguments that do not decode into P are treated as malformed and go through
+// the runner's RepairPolicy.
+func TypedToolHandler[P any](fn func(ctx context.Context, params P) (interface{}, error)) ToolHandler {
+	return func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+		var params P
+		if len(args) > 0 {
+			if err := json.Unmarshal(args, &params); err != nil {
+				return nil, &argumentsError{err: err}
+			}
+		}
+		return fn(ctx, params)
+	}
+}
+
+// FuncTool is a tool definition paired with the handler that executes it.
+type FuncTool struct {
+	Tool    Tool
+	Handler ToolHandler
+}
+
+// ToolFromFunc builds a function tool from a Go function. The parameter schema
+// is derived from P with SchemaFor, and call arguments are decoded into P before
+// fn runs. Add the result to a ToolRunner with Add.
+//
+//	scan := hackeserasdk.ToolFromFunc("scan_ports", "Scan TCP ports on a host",
+//		func(ctx context.Context, p ScanParams) (ScanResult, error) {
+//			return scanner.Scan(ctx, p.Host, p.Ports)
+//		})
+//	runner := hackeserasdk.NewToolRunner().Add(scan)
+func ToolFromFunc[P, R any](name, description string, fn func(ctx context.Context, params P) (R, error)) FuncTool {
+	return FuncTool{
+		Tool: Tool{
+			Type:     ToolTypeFunction,
+			Function: ToolFunction{Name: name, Description: description, Parameters: SchemaFor[P]()},
+		},
+		Handler: TypedToolHandler(func(ctx context.Context, params P) (interface{}, error) {
+			return fn(ctx, params)
+		}),
+	}
+}
+
+// DecodeArguments decodes a function call's JSON arguments into a P, for callers
+// that execute tool calls themselves.
+func DecodeArguments[P any](call FunctionCall) (P, error) {
+	var params P
+	if call.Arguments == "" {
+		return params, nil
+	}
+	if err := json.Unmarshal([]byte(call.Arguments), &params); err != nil {
+		return params, fmt.Errorf("decode %s arguments: %w", call.Name, err)
+	}
+	return params, nil
+}
+
+// argumentsError marks a handler failure caused by malformed tool arguments.
+type argumentsError struct{ err error }
+
+func (e *argumentsError) Error() string { return "invalid arguments: " + e.err.Error() }
+
+func (e *argumentsError) Unwrap() error { return e.err }
+
+// ToolRunner holds Go handlers for function tools, executed by RunTools.
+// Register all tools before the first run; a ToolRunner may then be shared by
+// concurrent runs.
+type ToolRunner struct {
+	// MaxIterations is the maximum number of completions per run. Defaults to 10.
+	MaxIterations int
+	// Repair controls how malformed tool arguments are handled: the error is sent
+	// back as the tool result up to MaxRepairs times per run, after which RunTools
+	// fails with an *OutputError. Validate is not used.
+	Repair RepairPolicy
+	// Screener, if set, screens handler output for prompt injection before it is
+	// sent to the model. A blocked output is replaced with an error result.
+	Screener *InjectionScreener
+	// Workers is the number of tool calls from one reply executed concurrently.
+	// Zero or one runs them sequentially. Results are always sent in call order.
+	Workers int
+
+	tools    []Tool
+	handlers map[string]ToolHandler
+}
+
+// NewToolRunner returns an empty ToolRunner.
+func NewToolRunner() *ToolRunner {
+	return &ToolRunner{handlers: make(map[string]ToolHandler)}
+}
+
+// Register adds a function tool. parameters is the JSON Schema of its arguments.
+// Registering an existing name replaces it.
+func (r *ToolRunner) Register(name, description string, parameters interface{}, handler ToolHandler) *ToolRunner {
+	tool := Tool{
+		Type:     ToolTypeFunction,
+		Function: ToolFunction{Name: name, Description: description, Parameters: parameters},
+	}
+	if _, ok := r.handlers[name]; ok {
+		for i := range r.tools {
+			if r.tools[i].Function.Name == name {
+				r.tools[i] = tool
+			}
+		}
+	} else {
+		r.tools = append(r.tools, tool)
+	}
+	r.handlers[name] = handler
+	return r
+}
+
+// Add registers tools built with ToolFromFunc.
+func (r *ToolRunner) Add(tools ...FuncTool) *ToolRunner {
+	for _, t := range tools {
+		r.Register(t.Tool.Function.Name, t.Tool.Function.Description, t.Tool.Function.Parameters, t.Handler)
+	}
+	return r
+}
+
+// Tools returns the definitions of the registered tools.
+func (r *ToolRunner) Tools() []Tool {
+	return append([]Tool(nil), r.tools...)
+}
+
+// ToolRun is the outcome of RunTools. It marshals to a JSON transcript of the
+// run (iterations, token usage, and steps) for audit logs; the response and
+// message history are left out.
+type ToolRun struct {
+	// Response is the last completion received.
+	Response *ChatResponse `json:"-"`
+	// Messages is the full history: the request's messages followed by every
+	// assistant reply and tool result.
+	Messages []Message `json:"-"`
+	// Iterations is the number of completions requested.
+	Iterations int `json:"iterations"`
+	// Usage is the token usage summed over all completions.
+	Usage Usage `json:"usage"`
+	// DurationMs is the wall time of the whole run.
+	DurationMs int64 `json:"duration_ms"`
+	// Steps records each completion and the tool calls it made.
+	Steps []ToolRunStep `json:"steps"`
+}
+
+// ToolRunStep is one completion in a tool run.
+type ToolRunStep struct {
+	Iteration int    `json:"iteration"`
+	Model     string `json:"model,omitempty"`
+	Usage     Usage  `json:"usage"`
+	// DurationMs is the completion's latency, excluding tool execution.
+	DurationMs int64 `json:"duration_ms"`
+	// Content is the assistant's text, if any.
+	Content   string           `json:"content,omitempty"`
+	ToolCalls []ToolCallRecord `json:"tool_calls,omitempty"`
+}
+
+// ToolCallRecord is the execution of one tool call in a tool run.
+type ToolCallRecord struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+	// Output is the tool result sent to the model.
+	Output string `json:"output"`
+	// Error is the handler, lookup, or argument error, if the call failed.
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	// Findings are the Screener's matches in the handler output.
+	Findings []InjectionFinding `json:"findings,omitempty"`
+}
+
+// RunTools sends req with the runner's tools added and executes the tool calls
+// in each reply, appending the results and re-sending, until the model answers
+// without calling a tool. Built-in tool calls are left to the server. When
+// MaxIterations is reached the partial run is returned with ErrMaxToolIterations.
+//
+//	runner := hackeserasdk.NewToolRunner().Register("lookup_cve", "Look up a CVE by ID",
+//		schema, hackeserasdk.TypedToolHandler(func(ctx context.Context, p CVEQuery) (interface{}, error) {
+//			return db.Lookup(p.ID)
+//		}))
+//	run, err := client.RunTools(ctx, req, runner)
+func (c *Client) RunTools(ctx context.Context, req ChatRequest, runner *ToolRunner) (*ToolRun, error) {
+	req.Tools = mergeTools(req.Tools, runner.tools)
+	req.Messages = append([]Message(nil), req.Messages...)
+
+	maxIter := runner.MaxIterations
+	if maxIter <= 0 {
+		maxIter = defaultMaxToolIterations
+	}
+	run := &ToolRun{}
+	start := time.Now()
+	finish := func(err error) (*ToolRun, error) {
+		run.Messages = req.Messages
+		run.DurationMs = time.Since(start).Milliseconds()
+		return run, err
+	}
+	repairs := 0
+
+	for run.Iterations < maxIter {
+		sent := time.Now()
+		resp, err := c.ChatCompletion(ctx, req)
+		if err != nil {
+			return finish(err)
+		}
+		run.Response = resp
+		run.Iterations++
+		run.Usage.PromptTokens += resp.Usage.PromptTokens
+		run.Usage.CompletionTokens += resp.Usage.CompletionTokens
+		run.Usage.TotalTokens += resp.Usage.TotalTokens
+		step := ToolRunStep{
+			Iteration:  run.Iterations,
+			Model:      resp.Model,
+			Usage:      resp.Usage,
+			DurationMs: time.Since(sent).Milliseconds(),
+		}
+		if len(resp.Choices) == 0 {
+			run.Steps = append(run.Steps, step)
+			return finish(fmt.Errorf("tool runner: empty response"))
+		}
+
+		reply := resp.Choices[0].Message
+		step.Content = messageText(reply)
+		req.Messages = append(req.Messages, reply)
+		calls := localToolCalls(reply.ToolCalls)
+		if len(calls) == 0 {
+			run.Steps = append(run.Steps, step)
+			return finish(nil)
+		}
+
+		results := runner.dispatch(ctx, calls)
+		for i, call := range calls {
+			output, err := results[i].output, results[i].err
+			record := ToolCallRecord{
+				ID:         call.ID,
+				Name:       call.Function.Name,
+				Arguments:  call.Function.Arguments,
+				DurationMs: results[i].duration.Milliseconds(),
+			}
+
+			var argErr *argumentsError
+			switch {
+			case errors.As(err, &argErr):
+				if repairs >= runner.Repair.MaxRepairs {
+					c.stats.recordToolRepair(true)
+					record.Error = argErr.Error()
+					step.ToolCalls = append(step.ToolCalls, record)
+					run.Steps = append(run.Steps, step)
+					return finish(&OutputError{Content: call.Function.Arguments, Attempts: repairs + 1, Err: argErr})
+				}
+				repairs++
+				c.stats.recordToolRepair(false)
+				output = repairToolPrompt(call, argErr)
+			case err != nil:
+				output = "error: " + err.Error()
+			default:
+				output, record.Findings = runner.screen(output)
+			}
+			if err != nil {
+				record.Error = err.Error()
+			}
+			record.Output = output
+			step.ToolCalls = append(step.ToolCalls, record)
+			req.Messages = append(req.Messages, Message{Role: "tool", ToolCallID: call.ID, Content: output})
+		}
+		run.Steps = append(run.Steps, step)
+	}
+
+	return finish(ErrMaxToolIterations)
+}
+
+// RecordToolRun appends the tool calls of run to a conversation with
+// AppendConversationTurn, one "tool" turn per call, so agent activity shows up
+// in the conversation history. The completions themselves are recorded by the
+// server when the run's requests carry the conversation ID.
+func (c *Client) RecordToolRun(ctx context.Context, conversationID string, run *ToolRun) error {
+	for _, step := range run.Steps {
+		for _, call := range step.ToolCalls {
+			metadata := map[string]string{
+				"tool_call_id": call.ID,
+				"tool_name":    call.Name,
+				"iteration":    strconv.Itoa(step.Iteration),
+			}
+			if call.Error != "" {
+				metadata["error"] = call.Error
+			}
+			_, err := c.AppendConversationTurn(ctx, conversationID, ConversationTurnRequest{
+				Role:      "tool",
+				Content:   call.Name + "(" + call.Arguments + ") -> " + call.Output,
+				LatencyMs: call.DurationMs,
+				Metadata:  metadata,
+			})
+			if err != nil {
+				return fmt.Errorf("record tool call %s: %w", call.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Dispatch executes the local tool calls of an assistant reply, up to Workers at
+// a time, and returns one "tool" message per call in the order the calls were
+// made, ready to append after reply in the follow-up request. Failures are
+// reported to the model in the message content as RunTools does; use Dispatch
+// to drive a custom agent loop.
+func (r *ToolRunner) Dispatch(ctx context.Context, reply Message) []Message {
+	calls := localToolCalls(reply.ToolCalls)
+	results := r.dispatch(ctx, calls)
+	messages := make([]Message, len(calls))
+	for i, call := range calls {
+		output, err := results[i].output, results[i].err
+		var argErr *argumentsError
+		switch {
+		case errors.As(err, &argErr):
+			output = repairToolPrompt(call, argErr)
+		case err != nil:
+			output = "error: " + err.Error()
+		default:
+			output, _ = r.screen(output)
+		}
+		messages[i] = Message{Role: "tool", ToolCallID: call.ID, Content: output}
+	}
+	return messages
+}
+
+type toolResult struct {
+	output   string
+	err      error
+	duration time.Duration
+}
+
+// dispatch runs calls on at most Workers goroutines, returning results by index.
+func (r *ToolRunner) dispatch(ctx context.Context, calls []ToolCall) []toolResult {
+	results := make([]toolResult, len(calls))
+	run := func(i int) {
+		started := time.Now()
+		output, err := r.call(ctx, calls[i])
+		results[i] = toolResult{output: output, err: err, duration: time.Since(started)}
+	}
+
+	workers := r.Workers
+	if workers <= 1 || len(calls) <= 1 {
+		for i := range calls {
+			run(i)
+		}
+		return results
+	}
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i := range calls {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() { <-sem; wg.Done() }()
+			run(i)
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// screen applies the runner's Screener to a handler's output.
+func (r *ToolRunner) screen(output string) (string, []InjectionFinding) {
+	if r.Screener == nil {
+		return output, nil
+	}
+	screened := r.Screener.Screen(output)
+	if screened.Blocked {
+		return "error: tool output withheld: possible prompt injection", screened.Findings
+	}
+	return screened.Content, screened.Findings
+}
+
+// repairToolPrompt asks the model to retry a call whose arguments did not parse.
+func repairToolPrompt(call ToolCall, err *argumentsError) string {
+	return err.Error() + ". Call " + call.Function.Name + " again with corrected JSON arguments."
+}
+
+// call runs the handler for one tool call and renders its result. Lookup and
+// handler failures are returned as errors; malformed arguments as an *argumentsError.
+func (r *ToolRunner) call(ctx context.Context, call ToolCall) (string, error) {
+	handler, ok := r.handlers[call.Function.Name]
+	if !ok {
+		return "", fmt.Errorf("unknown tool %s", call.Function.Name)
+	}
+	args := json.RawMessage(call.Function.Arguments)
+	if len(args) > 0 && !json.Valid(args) {
+		return "", &argumentsError{err: errors.New("arguments are not valid JSON")}
+	}
+
+	result, err := handler(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	if s, ok := result.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("encode result: %w", err)
+	}
+	return string(data), nil
+}
+
+// mergeTools appends the runner's tools to the request's, skipping function
+// tools the request already defines.
+func mergeTools(reqTools, runnerTools []Tool) []Tool {
+	out := append([]Tool(nil), reqTools...)
+	defined := make(map[string]bool, len(reqTools))
+	for _, t := range reqTools {
+		if t.Type == ToolTypeFunction || t.Type == "" {
+			defined[t.Function.Name] = true
+		}
+	}
+	for _, t := range runnerTools {
+		if !defined[t.Function.Name] {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// localToolCalls returns the calls the caller must execute.
+func localToolCalls(calls []ToolCall) []ToolCall {
+	var out []ToolCall
+	for _, tc := range calls {
+		if !tc.BuiltIn() {
+			out = append(out, tc)
+		}
+	}
+	return out
+}
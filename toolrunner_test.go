@@ -0,0 +1,351 @@
+package hackeserasdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type portScan struct {
+	Host  string `json:"host"`
+	Ports []int  `json:"ports"`
+}
+
+func TestRunToolsExecutesHandlers(t *testing.T) {
+	srv := newScriptedServer(t,
+		toolCallReply("call_1", "scan_ports", `{"host":"10.0.0.5","ports":[22,443]}`),
+		assistantReply("Port 22 is open."),
+	)
+	defer srv.Close()
+
+	var got portScan
+	runner := NewToolRunner().Register("scan_ports", "Scan TCP ports", map[string]interface{}{"type": "object"},
+		TypedToolHandler(func(ctx context.Context, p portScan) (interface{}, error) {
+			got = p
+			return map[string]interface{}{"open": []int{22}}, nil
+		}))
+
+	client := NewClient(srv.URL, "test-key")
+	run, err := client.RunTools(context.Background(), ChatRequest{
+		Model:    ModelDefault,
+		Messages: []Message{{Role: "user", Content: "scan 10.0.0.5"}},
+	}, runner)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Host != "10.0.0.5" || len(got.Ports) != 2 {
+		t.Errorf("expected decoded params, got %+v", got)
+	}
+	seen := srv.chats()
+	if run.Iterations != 2 || len(seen) != 2 {
+		t.Fatalf("expected 2 iterations, got %d", run.Iterations)
+	}
+	if len(seen[0].Tools) != 1 || seen[0].Tools[0].Function.Name != "scan_ports" {
+		t.Errorf("expected runner tools in request, got %+v", seen[0].Tools)
+	}
+	last := seen[1].Messages[len(seen[1].Messages)-1]
+	if last.Role != "tool" || last.ToolCallID != "call_1" || last.Content != `{"open":[22]}` {
+		t.Errorf("unexpected tool result message: %+v", last)
+	}
+	if len(run.Messages) != 4 || messageText(run.Messages[3]) != "Port 22 is open." {
+		t.Errorf("unexpected run history: %+v", run.Messages)
+	}
+}
+
+func TestRunToolsReportsHandlerErrors(t *testing.T) {
+	srv := newScriptedServer(t,
+		toolCallReply("call_1", "whois", `{}`),
+		toolCallReply("call_2", "traceroute", `{}`),
+		assistantReply("done"),
+	)
+	defer srv.Close()
+
+	runner := NewToolRunner().Register("whois", "", nil, func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+		return nil, errors.New("registry unreachable")
+	})
+
+	client := NewClient(srv.URL, "test-key")
+	if _, err := client.RunTools(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}, runner); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	seen := srv.chats()
+	if msg := seen[1].Messages[2]; msg.Content != "error: registry unreachable" {
+		t.Errorf("expected handler error as tool result, got %v", msg.Content)
+	}
+	if msg := seen[2].Messages[4]; msg.Content != "error: unknown tool traceroute" {
+		t.Errorf("expected unknown tool result, got %v", msg.Content)
+	}
+}
+
+func TestRunToolsRepairsArguments(t *testing.T) {
+	srv := newScriptedServer(t,
+		toolCallReply("call_1", "scan_ports", `{"host":"10.0.0.5","ports":"22"}`),
+		toolCallReply("call_2", "scan_ports", `{"host":"10.0.0.5","ports":[22]}`),
+		assistantReply("done"),
+	)
+	defer srv.Close()
+
+	calls := 0
+	runner := NewToolRunner().Register("scan_ports", "", nil,
+		TypedToolHandler(func(ctx context.Context, p portScan) (interface{}, error) {
+			calls++
+			return "ok", nil
+		}))
+	runner.Repair = RepairPolicy{MaxRepairs: 1}
+
+	client := NewClient(srv.URL, "test-key")
+	if _, err := client.RunTools(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}, runner); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected handler to run once, got %d", calls)
+	}
+	if stats := client.Stats(); stats.Repairs != 1 {
+		t.Errorf("expected 1 repair, got %d", stats.Repairs)
+	}
+}
+
+func TestRunToolsRepairExhausted(t *testing.T) {
+	srv := newScriptedServer(t,
+		toolCallReply("call_1", "scan_ports", `{"host":`),
+	)
+	defer srv.Close()
+
+	runner := NewToolRunner().Register("scan_ports", "", nil,
+		TypedToolHandler(func(ctx context.Context, p portScan) (interface{}, error) { return "ok", nil }))
+
+	client := NewClient(srv.URL, "test-key")
+	_, err := client.RunTools(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}, runner)
+	var outErr *OutputError
+	if !errors.As(err, &outErr) {
+		t.Fatalf("expected OutputError, got %v", err)
+	}
+	if outErr.Content != `{"host":` {
+		t.Errorf("expected malformed arguments in error, got %q", outErr.Content)
+	}
+}
+
+func TestRunToolsMaxIterations(t *testing.T) {
+	srv := newScriptedServer(t,
+		toolCallReply("call_1", "ping", `{}`),
+		toolCallReply("call_2", "ping", `{}`),
+	)
+	defer srv.Close()
+
+	runner := NewToolRunner().Register("ping", "", nil, func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+		return "pong", nil
+	})
+	runner.MaxIterations = 2
+
+	client := NewClient(srv.URL, "test-key")
+	run, err := client.RunTools(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}, runner)
+	if !errors.Is(err, ErrMaxToolIterations) {
+		t.Fatalf("expected ErrMaxToolIterations, got %v", err)
+	}
+	if run.Iterations != 2 || len(run.Messages) != 5 {
+		t.Errorf("expected partial run, got %d iterations and %d messages", run.Iterations, len(run.Messages))
+	}
+}
+
+func TestToolFromFunc(t *testing.T) {
+	srv := newScriptedServer(t,
+		toolCallReply("call_1", "scan_ports", `{"host":"10.0.0.5","ports":[22]}`),
+		assistantReply("done"),
+	)
+	defer srv.Close()
+
+	scan := ToolFromFunc("scan_ports", "Scan TCP ports", func(ctx context.Context, p portScan) ([]int, error) {
+		return p.Ports, nil
+	})
+	params := scan.Tool.Function.Parameters.(map[string]interface{})
+	if params["type"] != "object" || len(params["properties"].(map[string]interface{})) != 2 {
+		t.Errorf("unexpected parameter schema: %v", params)
+	}
+
+	client := NewClient(srv.URL, "test-key")
+	if _, err := client.RunTools(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}, NewToolRunner().Add(scan)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	seen := srv.chats()
+	if got := seen[1].Messages[2].Content; got != "[22]" {
+		t.Errorf("expected encoded result, got %v", got)
+	}
+}
+
+func TestDecodeArguments(t *testing.T) {
+	p, err := DecodeArguments[portScan](FunctionCall{Name: "scan_ports", Arguments: `{"host":"h","ports":[1]}`})
+	if err != nil || p.Host != "h" || len(p.Ports) != 1 {
+		t.Errorf("unexpected decode: %+v, %v", p, err)
+	}
+	if _, err := DecodeArguments[portScan](FunctionCall{Name: "scan_ports", Arguments: `{"ports":"1"}`}); err == nil {
+		t.Error("expected error for mismatched arguments")
+	}
+}
+
+func TestToolRunTranscript(t *testing.T) {
+	srv := newScriptedServer(t,
+		toolCallReply("call_1", "whois", `{"domain":"example.com"}`),
+		assistantReply("Registered in 1995."),
+	)
+	defer srv.Close()
+
+	runner := NewToolRunner().Register("whois", "", nil, func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+		return nil, errors.New("rate limited")
+	})
+
+	client := NewClient(srv.URL, "test-key")
+	run, err := client.RunTools(context.Background(), ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "user", Content: "hi"}}}, runner)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(run.Steps) != 2 {
+		t.Fatalf("expected 2 steps, got %+v", run.Steps)
+	}
+	if len(run.Steps[0].ToolCalls) != 1 || run.Steps[1].Content != "Registered in 1995." {
+		t.Errorf("unexpected steps: %+v", run.Steps)
+	}
+	call := run.Steps[0].ToolCalls[0]
+	if call.Name != "whois" || call.Arguments != `{"domain":"example.com"}` || call.Error != "rate limited" || call.Output != "error: rate limited" {
+		t.Errorf("unexpected tool call record: %+v", call)
+	}
+
+	data, err := json.Marshal(run)
+	if err != nil {
+		t.Fatalf("marshal transcript: %v", err)
+	}
+	var transcript map[string]interface{}
+	json.Unmarshal(data, &transcript)
+	if transcript["iterations"] != float64(2) || len(transcript["steps"].([]interface{})) != 2 {
+		t.Errorf("unexpected transcript JSON: %s", data)
+	}
+	if _, ok := transcript["Messages"]; ok {
+		t.Error("expected message history to be left out of the transcript")
+	}
+}
+
+func TestRecordToolRun(t *testing.T) {
+	var turns []ConversationTurnRequest
+	srv := newTestServerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/conversations/conv-3/turns" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var req ConversationTurnRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		turns = append(turns, req)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ConversationTurn{Role: req.Role, Content: req.Content})
+	})
+	defer srv.Close()
+
+	run := &ToolRun{Steps: []ToolRunStep{
+		{Iteration: 1, ToolCalls: []ToolCallRecord{
+			{ID: "call_1", Name: "whois", Arguments: `{}`, Output: "ok"},
+			{ID: "call_2", Name: "dig", Arguments: `{}`, Output: "error: timeout", Error: "timeout"},
+		}},
+		{Iteration: 2, Content: "done"},
+	}}
+
+	client := NewClient(srv.URL, "test-key")
+	if err := client.RecordToolRun(context.Background(), "conv-3", run); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(turns) != 2 {
+		t.Fatalf("expected 2 turns, got %d", len(turns))
+	}
+	if turns[0].Role != "tool" || turns[0].Content != "whois({}) -> ok" || turns[0].Metadata["iteration"] != "1" {
+		t.Errorf("unexpected first turn: %+v", turns[0])
+	}
+	if turns[1].Metadata["error"] != "timeout" {
+		t.Errorf("expected error metadata, got %+v", turns[1].Metadata)
+	}
+}
+
+func TestToolRunnerDispatchConcurrentInOrder(t *testing.T) {
+	var inFlight, peak int32
+	runner := NewToolRunner().Register("lookup", "Look up a host", nil,
+		func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+			n := atomic.AddInt32(&inFlight, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+			var p struct {
+				Host string `json:"host"`
+			}
+			json.Unmarshal(args, &p)
+			return p.Host, nil
+		})
+	runner.Workers = 2
+
+	var reply Message
+	for i := 0; i < 5; i++ {
+		reply.ToolCalls = append(reply.ToolCalls, ToolCall{
+			ID: fmt.Sprintf("call_%d", i), Type: ToolTypeFunction,
+			Function: FunctionCall{Name: "lookup", Arguments: fmt.Sprintf(`{"host":"10.0.0.%d"}`, i)},
+		})
+	}
+	reply.ToolCalls = append(reply.ToolCalls, ToolCall{ID: "call_bad", Type: ToolTypeFunction, Function: FunctionCall{Name: "missing"}})
+
+	messages := runner.Dispatch(context.Background(), reply)
+	if len(messages) != 6 {
+		t.Fatalf("expected 6 tool messages, got %d", len(messages))
+	}
+	for i := 0; i < 5; i++ {
+		m := messages[i]
+		if m.Role != "tool" || m.ToolCallID != fmt.Sprintf("call_%d", i) || m.Content != fmt.Sprintf("10.0.0.%d", i) {
+			t.Errorf("message %d out of order or wrong: %+v", i, m)
+		}
+	}
+	if messages[5].Content != "error: unknown tool missing" {
+		t.Errorf("expected unknown tool error, got %v", messages[5].Content)
+	}
+	if peak != 2 {
+		t.Errorf("expected 2 concurrent calls, got %d", peak)
+	}
+}
+
+func TestRunToolsParallelToolCalls(t *testing.T) {
+	reply := toolCallReply("call_1", "scan_ports", `{"host":"10.0.0.5"}`)
+	reply.ToolCalls = append(reply.ToolCalls, ToolCall{
+		ID: "call_2", Type: ToolTypeFunction, Function: FunctionCall{Name: "scan_ports", Arguments: `{"host":"10.0.0.6"}`},
+	})
+	srv := newScriptedServer(t, reply, assistantReply("Done."))
+	defer srv.Close()
+
+	runner := NewToolRunner().Register("scan_ports", "Scan TCP ports", nil,
+		TypedToolHandler(func(ctx context.Context, p portScan) (interface{}, error) {
+			return p.Host, nil
+		}))
+	runner.Workers = 4
+
+	client := NewClient(srv.URL, "test-key")
+	run, err := client.RunTools(context.Background(), ChatRequest{
+		Model:             ModelDefault,
+		Messages:          []Message{{Role: "user", Content: "Scan both hosts"}},
+		ParallelToolCalls: BoolPtr(true),
+	}, runner)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	seen := srv.chats()
+	if seen[0].ParallelToolCalls == nil || !*seen[0].ParallelToolCalls {
+		t.Error("expected parallel_tool_calls to be sent")
+	}
+	msgs := seen[1].Messages
+	if msgs[2].ToolCallID != "call_1" || msgs[2].Content != "10.0.0.5" || msgs[3].ToolCallID != "call_2" || msgs[3].Content != "10.0.0.6" {
+		t.Errorf("unexpected tool results: %+v", msgs[2:])
+	}
+	if len(run.Steps[0].ToolCalls) != 2 {
+		t.Errorf("expected 2 recorded calls, got %d", len(run.Steps[0].ToolCalls))
+	}
+}
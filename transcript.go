@@ -0,0 +1,187 @@
+package hackeserasdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// ─── Transcripts ────────────────────────────────────────────────────────────
+
+// TranscriptEntry is one message of a ChatSession as appended to a
+// TranscriptSink. Entries are written as JSON lines.
+type TranscriptEntry struct {
+	// SessionID is the ChatSession's SessionID, unique per session.
+	SessionID      string `json:"session_id,omitempty"`
+	ConversationID string `json:"conversation_id,omitempty"`
+	// Seq numbers the session's entries from 1, so gaps and reordering are
+	// detectable in storage.
+	Seq     int       `json:"seq"`
+	Time    time.Time `json:"time"`
+	Message Message   `json:"message"`
+}
+
+// TranscriptSink durably stores session transcripts independent of server
+// retention. A ChatSession with a Transcript appends each completed exchange
+// (the user message and the reply) in one call; streamed replies are appended
+// once the stream ends. Implementations must be safe for concurrent use when
+// shared between sessions.
+type TranscriptSink interface {
+	AppendTranscript(ctx context.Context, entries []TranscriptEntry) error
+}
+
+// encodeTranscript renders entries as JSON lines.
+func encodeTranscript(entries []TranscriptEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return nil, fmt.Errorf("encode transcript entry %d: %w", e.Seq, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// TranscriptWriterSink appends JSON lines to an io.Writer. Each call is one
+// Write, so entries from concurrent sessions do not interleave.
+type TranscriptWriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewTranscriptWriterSink returns a sink writing to w.
+func NewTranscriptWriterSink(w io.Writer) *TranscriptWriterSink {
+	return &TranscriptWriterSink{w: w}
+}
+
+// AppendTranscript implements TranscriptSink.
+func (s *TranscriptWriterSink) AppendTranscript(ctx context.Context, entries []TranscriptEntry) error {
+	data, err := encodeTranscript(entries)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(data)
+	return err
+}
+
+// TranscriptFileSink appends JSON lines to a file and syncs it after every
+// append, so a completed exchange survives a crash.
+type TranscriptFileSink struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// OpenTranscriptFile opens path for appending, creating it if needed.
+func OpenTranscriptFile(path string) (*TranscriptFileSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open transcript: %w", err)
+	}
+	return &TranscriptFileSink{f: f}, nil
+}
+
+// AppendTranscript implements TranscriptSink.
+func (s *TranscriptFileSink) AppendTranscript(ctx context.Context, entries []TranscriptEntry) error {
+	data, err := encodeTranscript(entries)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.f.Write(data); err != nil {
+		return fmt.Errorf("write transcript: %w", err)
+	}
+	if err := s.f.Sync(); err != nil {
+		return fmt.Errorf("sync transcript: %w", err)
+	}
+	return nil
+}
+
+// Close closes the file.
+func (s *TranscriptFileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
+
+// ObjectPutter writes one object to a blob store such as S3, GCS, or Azure
+// Blob Storage. With the AWS SDK it is a thin wrapper:
+//
+//	type s3Putter struct {
+//		client *s3.Client
+//		bucket string
+//	}
+//
+//	func (p s3Putter) PutObject(ctx context.Context, key string, body []byte) error {
+//		_, err := p.client.PutObject(ctx, &s3.PutObjectInput{
+//			Bucket: &p.bucket, Key: &key, Body: bytes.NewReader(body),
+//		})
+//		return err
+//	}
+type ObjectPutter interface {
+	PutObject(ctx context.Context, key string, body []byte) error
+}
+
+// TranscriptObjectSink stores transcripts in a blob store. Objects cannot be
+// appended to, so each append is its own object, keyed
+// "<prefix><session>/<seq>.jsonl" with the first entry's Seq zero-padded so
+// keys list in order. Keys are per session, so sessions never overwrite each
+// other's objects; the conversation ID is recorded in each entry instead.
+// Entries without a SessionID are keyed by ConversationID, or "unassigned".
+type TranscriptObjectSink struct {
+	store  ObjectPutter
+	prefix string
+}
+
+// NewTranscriptObjectSink returns a sink writing objects under prefix, for
+// example "transcripts/".
+func NewTranscriptObjectSink(store ObjectPutter, prefix string) *TranscriptObjectSink {
+	return &TranscriptObjectSink{store: store, prefix: prefix}
+}
+
+// AppendTranscript implements TranscriptSink.
+func (s *TranscriptObjectSink) AppendTranscript(ctx context.Context, entries []TranscriptEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	data, err := encodeTranscript(entries)
+	if err != nil {
+		return err
+	}
+	session := entries[0].SessionID
+	if session == "" {
+		session = entries[0].ConversationID
+	}
+	if session == "" {
+		session = "unassigned"
+	}
+	key := fmt.Sprintf("%s%s/%08d.jsonl", s.prefix, session, entries[0].Seq)
+	if err := s.store.PutObject(ctx, key, data); err != nil {
+		return fmt.Errorf("put transcript %s: %w", key, err)
+	}
+	return nil
+}
+
+// recordTranscript appends msgs to the session's transcript, if it has one.
+func (s *ChatSession) recordTranscript(ctx context.Context, msgs ...Message) error {
+	if s.Transcript == nil {
+		return nil
+	}
+	now := time.Now()
+	entries := make([]TranscriptEntry, len(msgs))
+	for i, m := range msgs {
+		s.transcriptSeq++
+		entries[i] = TranscriptEntry{SessionID: s.SessionID, ConversationID: s.ConversationID, Seq: s.transcriptSeq, Time: now, Message: m}
+	}
+	if err := s.Transcript.AppendTranscript(ctx, entries); err != nil {
+		return fmt.Errorf("append transcript: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package hackeserasdk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func decodeTranscript(t *testing.T, r io.Reader) []TranscriptEntry {
+	t.Helper()
+	var entries []TranscriptEntry
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var e TranscriptEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("decode transcript line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestChatSessionTranscript(t *testing.T) {
+	srv := newScriptedServer(t, assistantReply("hello"), assistantReply("still here"))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	client := NewClient(srv.URL, "test-key")
+	session := client.NewChatSession(ChatRequest{Model: ModelDefault, Messages: []Message{{Role: "system", Content: "be brief"}}})
+	session.Transcript = NewTranscriptWriterSink(&buf)
+
+	ctx := context.Background()
+	for _, q := range []string{"hi", "you there?"} {
+		if _, err := session.Send(ctx, q); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	entries := decodeTranscript(t, &buf)
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries (system prompt excluded), got %+v", entries)
+	}
+	for i, e := range entries {
+		if e.Seq != i+1 || e.Time.IsZero() {
+			t.Errorf("unexpected entry %d: %+v", i, e)
+		}
+	}
+	if entries[2].Message.Content != "you there?" || entries[3].Message.Content != "still here" || entries[3].Message.Role != "assistant" {
+		t.Errorf("unexpected second exchange: %+v", entries[2:])
+	}
+}
+
+func TestChatSessionStreamTranscript(t *testing.T) {
+	srv := newScriptedServer(t).withSSE(
+		`{"choices":[{"index":0,"delta":{"role":"assistant","content":"Hel"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"lo"}}]}`,
+	)
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	client := NewClient(srv.URL, "test-key")
+	session := client.NewChatSession(ChatRequest{Model: ModelDefault})
+	session.Transcript = NewTranscriptWriterSink(&buf)
+
+	stream, err := session.SendStream(context.Background(), Message{Role: "user", Content: "hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+	for {
+		if _, err := stream.Next(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Fatal("expected nothing recorded before the stream ends")
+		}
+	}
+
+	entries := decodeTranscript(t, &buf)
+	if len(entries) != 2 || entries[1].Message.Content != "Hello" {
+		t.Errorf("expected finalized reply recorded, got %+v", entries)
+	}
+	if history := session.History(); len(history) != 2 || history[1].Content != "Hello" {
+		t.Errorf("expected reply in history, got %+v", history)
+	}
+}
+
+type failingSink struct{}
+
+func (failingSink) AppendTranscript(context.Context, []TranscriptEntry) error {
+	return errors.New("disk full")
+}
+
+func TestChatSessionTranscriptFailure(t *testing.T) {
+	srv := newScriptedServer(t, assistantReply("hello"), assistantReply("streamed"))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "test-key")
+	session := client.NewChatSession(ChatRequest{Model: ModelDefault})
+	session.Transcript = failingSink{}
+
+	resp, err := session.Send(context.Background(), "hi")
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("expected transcript error, got %v", err)
+	}
+	if resp == nil || len(session.History()) != 2 {
+		t.Error("expected the reply to be returned and kept despite the transcript error")
+	}
+
+	stream, err := session.SendStream(context.Background(), Message{Role: "user", Content: "again"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Close()
+	for err == nil {
+		_, err = stream.Next()
+	}
+	if err == io.EOF || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("expected final Next to report the transcript error, got %v", err)
+	}
+}
+
+func TestTranscriptFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	for i := 0; i < 2; i++ {
+		sink, err := OpenTranscriptFile(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := sink.AppendTranscript(context.Background(), []TranscriptEntry{{Seq: i + 1, Message: Message{Role: "user", Content: "hi"}}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sink.Close()
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	if entries := decodeTranscript(t, f); len(entries) != 2 || entries[1].Seq != 2 {
+		t.Errorf("expected reopened file to be appended to, got %+v", entries)
+	}
+}
+
+type memoryPutter map[string][]byte
+
+func (m memoryPutter) PutObject(ctx context.Context, key string, body []byte) error {
+	m[key] = body
+	return nil
+}
+
+func TestTranscriptObjectSink(t *testing.T) {
+	store := memoryPutter{}
+	sink := NewTranscriptObjectSink(store, "transcripts/")
+	ctx := context.Background()
+	sink.AppendTranscript(ctx, []TranscriptEntry{{Seq: 1}, {Seq: 2}})
+	sink.AppendTranscript(ctx, []TranscriptEntry{{ConversationID: "conv-1", Seq: 3}, {ConversationID: "conv-1", Seq: 4}})
+
+	if body, ok := store["transcripts/unassigned/00000001.jsonl"]; !ok || len(decodeTranscript(t, bytes.NewReader(body))) != 2 {
+		t.Errorf("expected first exchange under unassigned, got keys %v", store)
+	}
+	if _, ok := store["transcripts/conv-1/00000003.jsonl"]; !ok {
+		t.Errorf("expected second exchange under its conversation, got keys %v", store)
+	}
+}
+
+func TestTranscriptObjectSinkKeysBySession(t *testing.T) {
+	srv := newScriptedServer(t).withSSE(`{"choices":[{"index":0,"delta":{"content":"hi"}}]}`)
+	defer srv.Close()
+
+	store := memoryPutter{}
+	sink := NewTranscriptObjectSink(store, "transcripts/")
+	client := NewClient(srv.URL, "test-key")
+	ctx := context.Background()
+
+	// Two streamed sessions get no conversation ID, and both start at Seq 1.
+	var ids []string
+	for i := 0; i < 2; i++ {
+		session := client.NewChatSession(ChatRequest{Model: ModelDefault})
+		session.Transcript = sink
+		stream, err := session.SendStream(ctx, Message{Role: "user", Content: "hello"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for {
+			if _, err := stream.Next(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		stream.Close()
+		ids = append(ids, session.SessionID)
+	}
+
+	if ids[0] == "" || ids[0] == ids[1] {
+		t.Fatalf("expected distinct session IDs, got %q", ids)
+	}
+	if len(store) != 2 {
+		t.Fatalf("expected one object per session, got keys %v", store)
+	}
+	for _, id := range ids {
+		body, ok := store["transcripts/"+id+"/00000001.jsonl"]
+		if !ok {
+			t.Fatalf("missing object for session %s, got keys %v", id, store)
+		}
+		if entries := decodeTranscript(t, bytes.NewReader(body)); len(entries) != 2 || entries[0].SessionID != id {
+			t.Errorf("unexpected entries for session %s: %+v", id, entries)
+		}
+	}
+}
@@ -1,5 +1,17 @@
 package hackeserasdk
 
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // ─── Model Constants ────────────────────────────────────────────────────────
 
 const (
@@ -43,18 +55,215 @@ type ChatRequest struct {
 	FrequencyPenalty    *float64        `json:"frequency_penalty,omitempty"`
 	User                string          `json:"user,omitempty"`
 	Tools               []Tool          `json:"tools,omitempty"`
-	ToolChoice          interface{}     `json:"tool_choice,omitempty"`
+	ToolChoice          *ToolChoice     `json:"tool_choice,omitempty"`
 	ResponseFormat      *ResponseFormat `json:"response_format,omitempty"`
 	Seed                *int            `json:"seed,omitempty"`
+	// ParallelToolCalls lets the model request several tool calls in one reply
+	// (true) or at most one (false). Nil uses the server default.
+	ParallelToolCalls *bool `json:"parallel_tool_calls,omitempty"`
+	// LogitBias maps token IDs (as strings) to a bias from -100 to 100.
+	// Use -100 to effectively ban a token, e.g. markdown fences in machine-read output.
+	LogitBias map[string]int `json:"logit_bias,omitempty"`
+	// N is the number of choices to generate. Each is billed as a completion.
+	N *int `json:"n,omitempty"`
+	// LogProbs requests per-token log probabilities in Choice.LogProbs.
+	// TopLogProbs (0 to MaxTopLogProbs) adds that many alternatives per
+	// position and requires LogProbs.
+	LogProbs    *bool `json:"logprobs,omitempty"`
+	TopLogProbs *int  `json:"top_logprobs,omitempty"`
+	// PromptCacheKey groups requests that share a long stable prefix so the
+	// server routes them to the same prompt cache.
+	PromptCacheKey string `json:"prompt_cache_key,omitempty"`
+	// Prediction supplies expected output (e.g. the unchanged parts of a file being
+	// edited) so matching tokens can be accepted instead of generated.
+	Prediction *Prediction `json:"prediction,omitempty"`
+	// Grammar constrains decoding to a GBNF grammar. Regex constrains it to a
+	// regular expression. At most one may be set, and neither with ResponseFormat.
+	Grammar string `json:"grammar,omitempty"`
+	Regex   string `json:"regex,omitempty"`
+	// Metadata is stored on the conversation and the turn, returned by
+	// ListConversations and SearchConversations, and filterable with
+	// ConversationListOptions.Metadata (e.g. ticket ID, channel, customer ID).
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// ContextDocuments are retrieved from for this request only, alongside the
+	// knowledge base, and are not stored. Use them to ground answers in a
+	// pasted report or a file the user attached.
+	ContextDocuments []InlineDocument `json:"context_documents,omitempty"`
+}
+
+// InlineDocument is ephemeral grounding content sent with a chat request.
+// Name labels the document in citations.
+type InlineDocument struct {
+	Name    string `json:"name,omitempty"`
+	Content string `json:"content"`
+}
+
+// Prediction is static predicted output content for a chat request.
+type Prediction struct {
+	// Type is always "content".
+	Type string `json:"type"`
+	// Content is a string or a slice of ContentPart text parts.
+	Content interface{} `json:"content"`
+}
+
+// PredictedContent returns a static content prediction for text.
+func PredictedContent(text string) *Prediction {
+	return &Prediction{Type: "content", Content: text}
 }
 
-// Message represents a single message in a conversation.
+// MaxStopSequences is the maximum number of stop sequences accepted per request.
+const MaxStopSequences = 4
+
+// MaxTopLogProbs is the most alternatives ChatRequest.TopLogProbs may request.
+const MaxTopLogProbs = 20
+
+// Metadata limits accepted per chat request.
+const (
+	MaxMetadataPairs       = 16
+	MaxMetadataKeyLength   = 64
+	MaxMetadataValueLength = 512
+)
+
+// Validate checks the request without sending it: a model and at least one
+// message with a role are required, sampling parameters must be in range, and
+// the limits enforced by the server are checked. Failures are *ValidationError.
+// Chat endpoints run the same checks before any network call.
+func (r ChatRequest) Validate() error {
+	return r.prepare()
+}
+
+// prepare validates the request and normalizes it in place.
+func (r *ChatRequest) prepare() error {
+	if r.Model == "" {
+		return invalidf("model", "model is required")
+	}
+	if len(r.Messages) == 0 {
+		return invalidf("messages", "at least one message is required")
+	}
+	for i, m := range r.Messages {
+		if m.Role == "" {
+			return invalidf(fmt.Sprintf("messages[%d].role", i), "message %d has no role", i)
+		}
+	}
+	if r.Temperature != nil && (*r.Temperature < 0 || *r.Temperature > 2) {
+		return invalidf("temperature", "temperature must be between 0 and 2, got %g", *r.Temperature)
+	}
+	if r.TopP != nil && (*r.TopP < 0 || *r.TopP > 1) {
+		return invalidf("top_p", "top_p must be between 0 and 1, got %g", *r.TopP)
+	}
+	if r.PresencePenalty != nil && (*r.PresencePenalty < -2 || *r.PresencePenalty > 2) {
+		return invalidf("presence_penalty", "presence_penalty must be between -2 and 2, got %g", *r.PresencePenalty)
+	}
+	if r.FrequencyPenalty != nil && (*r.FrequencyPenalty < -2 || *r.FrequencyPenalty > 2) {
+		return invalidf("frequency_penalty", "frequency_penalty must be between -2 and 2, got %g", *r.FrequencyPenalty)
+	}
+	if r.MaxTokens != nil && *r.MaxTokens < 1 {
+		return invalidf("max_tokens", "max_tokens must be at least 1, got %d", *r.MaxTokens)
+	}
+	if r.MaxCompletionTokens != nil && *r.MaxCompletionTokens < 1 {
+		return invalidf("max_completion_tokens", "max_completion_tokens must be at least 1, got %d", *r.MaxCompletionTokens)
+	}
+	for i, d := range r.ContextDocuments {
+		if strings.TrimSpace(d.Content) == "" {
+			return invalidf(fmt.Sprintf("context_documents[%d].content", i), "context document %d has no content", i)
+		}
+	}
+	return r.normalize()
+}
+
+// normalize drops empty and duplicate stop sequences, collapses a MaxTokens
+// equal to MaxCompletionTokens into the latter, and checks request limits that
+// the server would otherwise reject with a less specific error.
+func (r *ChatRequest) normalize() error {
+	if r.MaxTokens != nil && r.MaxCompletionTokens != nil {
+		if *r.MaxTokens != *r.MaxCompletionTokens {
+			return invalidf("max_tokens", "max_tokens (%d) and max_completion_tokens (%d) conflict; set one", *r.MaxTokens, *r.MaxCompletionTokens)
+		}
+		r.MaxTokens = nil
+	}
+	if len(r.Stop) > 0 {
+		stops := make([]string, 0, len(r.Stop))
+		seen := make(map[string]bool, len(r.Stop))
+		for _, s := range r.Stop {
+			if s == "" || seen[s] {
+				continue
+			}
+			seen[s] = true
+			stops = append(stops, s)
+		}
+		if len(stops) > MaxStopSequences {
+			return invalidf("stop", "at most %d stop sequences allowed, got %d", MaxStopSequences, len(stops))
+		}
+		r.Stop = stops
+	}
+	for token, bias := range r.LogitBias {
+		if bias < -100 || bias > 100 {
+			return invalidf("logit_bias", "logit_bias for token %q must be between -100 and 100, got %d", token, bias)
+		}
+	}
+	if r.N != nil && *r.N < 1 {
+		return invalidf("n", "n must be at least 1, got %d", *r.N)
+	}
+	if r.TopLogProbs != nil {
+		if *r.TopLogProbs < 0 || *r.TopLogProbs > MaxTopLogProbs {
+			return invalidf("top_logprobs", "top_logprobs must be between 0 and %d, got %d", MaxTopLogProbs, *r.TopLogProbs)
+		}
+		if r.LogProbs == nil || !*r.LogProbs {
+			return invalidf("top_logprobs", "top_logprobs requires logprobs")
+		}
+	}
+	if r.Grammar != "" && r.Regex != "" {
+		return invalidf("grammar", "grammar and regex are mutually exclusive")
+	}
+	if (r.Grammar != "" || r.Regex != "") && r.ResponseFormat != nil {
+		return invalidf("response_format", "grammar or regex cannot be combined with response_format")
+	}
+	if err := r.ToolChoice.validate(r.Tools); err != nil {
+		return err
+	}
+	if len(r.Metadata) > MaxMetadataPairs {
+		return invalidf("metadata", "at most %d metadata pairs allowed, got %d", MaxMetadataPairs, len(r.Metadata))
+	}
+	for k, v := range r.Metadata {
+		if k == "" || len(k) > MaxMetadataKeyLength {
+			return invalidf("metadata", "metadata key %q must be 1 to %d bytes", k, MaxMetadataKeyLength)
+		}
+		if len(v) > MaxMetadataValueLength {
+			return invalidf("metadata", "metadata value for %q exceeds %d bytes", k, MaxMetadataValueLength)
+		}
+	}
+	if f := r.ResponseFormat; f != nil && f.Type == "json_schema" && (f.JSONSchema == nil || f.JSONSchema.Name == "") {
+		return invalidf("response_format", "response_format json_schema requires a named schema")
+	}
+	return nil
+}
+
+// Message represents a single message in a conversation. Content is either a
+// string or a []ContentPart (see TextContent, ImageURLContent, ImageFromFile);
+// decoded messages use the same two types.
 type Message struct {
 	Role       string      `json:"role"`
 	Content    interface{} `json:"content"`
 	Name       string      `json:"name,omitempty"`
 	ToolCalls  []ToolCall  `json:"tool_calls,omitempty"`
 	ToolCallID string      `json:"tool_call_id,omitempty"`
+	// CacheControl marks this message as the end of a cacheable prompt prefix.
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
+}
+
+// CacheControlEphemeral is the cache type for prompt prefixes reused across requests.
+const CacheControlEphemeral = "ephemeral"
+
+// CacheControl is a prompt-cache hint attached to a message.
+type CacheControl struct {
+	Type string `json:"type"`
+}
+
+// Cacheable returns a copy of the message marked as the end of a cacheable prefix,
+// typically a long, stable system prompt or document context.
+func (m Message) Cacheable() Message {
+	m.CacheControl = &CacheControl{Type: CacheControlEphemeral}
+	return m
 }
 
 // ContentPart represents a single part of a multimodal content array.
@@ -66,13 +275,40 @@ type ContentPart struct {
 
 // ImageURL represents an image URL in a multimodal content part.
 type ImageURL struct {
-	URL string `json:"url"`
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
 }
 
-// Tool represents a tool definition for function calling.
+// Tool types. ToolTypeFunction tools are executed by the caller; the others are
+// built-in tools run by the server, whose results come back on the ToolCall.
+const (
+	ToolTypeFunction        = "function"
+	ToolTypeWebSearch       = "web_search"
+	ToolTypeKnowledgeSearch = "knowledge_search"
+	ToolTypeCodeInterpreter = "code_interpreter"
+)
+
+// Tool represents a tool definition. Function is used for ToolTypeFunction;
+// built-in tools are configured through the field matching their Type.
 type Tool struct {
 	Type     string       `json:"type"`
 	Function ToolFunction `json:"function"`
+
+	WebSearch       *WebSearchConfig       `json:"web_search,omitempty"`
+	KnowledgeSearch *KnowledgeSearchConfig `json:"knowledge_search,omitempty"`
+	CodeInterpreter *CodeInterpreterConfig `json:"code_interpreter,omitempty"`
+}
+
+// MarshalJSON omits the function definition for built-in tools.
+func (t Tool) MarshalJSON() ([]byte, error) {
+	type toolAlias Tool
+	if t.Type == ToolTypeFunction || t.Type == "" {
+		return json.Marshal(toolAlias(t))
+	}
+	return json.Marshal(struct {
+		toolAlias
+		Function *ToolFunction `json:"function,omitempty"`
+	}{toolAlias: toolAlias(t)})
 }
 
 // ToolFunction represents the function definition within a tool.
@@ -82,11 +318,159 @@ type ToolFunction struct {
 	Parameters  interface{} `json:"parameters,omitempty"`
 }
 
-// ToolCall represents a tool call made by the assistant.
+// WebSearchConfig configures the built-in web search tool.
+type WebSearchConfig struct {
+	MaxResults     int      `json:"max_results,omitempty"`
+	AllowedDomains []string `json:"allowed_domains,omitempty"`
+	BlockedDomains []string `json:"blocked_domains,omitempty"`
+}
+
+// KnowledgeSearchConfig configures the built-in knowledge base search tool.
+type KnowledgeSearchConfig struct {
+	TopK      int               `json:"top_k,omitempty"`
+	Threshold float64           `json:"threshold,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+}
+
+// CodeInterpreterConfig configures the built-in sandboxed code interpreter tool.
+type CodeInterpreterConfig struct {
+	// FileIDs are documents made available to the sandbox.
+	FileIDs []string `json:"file_ids,omitempty"`
+	// TimeoutSeconds caps a single execution.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+}
+
+// WebSearchTool returns a built-in web search tool definition. cfg may be nil.
+func WebSearchTool(cfg *WebSearchConfig) Tool {
+	return Tool{Type: ToolTypeWebSearch, WebSearch: cfg}
+}
+
+// KnowledgeSearchTool returns a built-in knowledge base search tool definition. cfg may be nil.
+func KnowledgeSearchTool(cfg *KnowledgeSearchConfig) Tool {
+	return Tool{Type: ToolTypeKnowledgeSearch, KnowledgeSearch: cfg}
+}
+
+// CodeInterpreterTool returns a built-in code interpreter tool definition. cfg may be nil.
+func CodeInterpreterTool(cfg *CodeInterpreterConfig) Tool {
+	return Tool{Type: ToolTypeCodeInterpreter, CodeInterpreter: cfg}
+}
+
+// ToolChoice controls whether and which tool the model calls. Build one with
+// ToolChoiceAuto, ToolChoiceNone, ToolChoiceRequired, or ToolChoiceFunction;
+// each call returns a new value, so requests never share one.
+type ToolChoice struct {
+	mode     string
+	function string
+}
+
+// ToolChoiceAuto lets the model decide whether to call a tool (the default with tools).
+func ToolChoiceAuto() *ToolChoice { return &ToolChoice{mode: "auto"} }
+
+// ToolChoiceNone forbids tool calls.
+func ToolChoiceNone() *ToolChoice { return &ToolChoice{mode: "none"} }
+
+// ToolChoiceRequired makes the model call at least one tool.
+func ToolChoiceRequired() *ToolChoice { return &ToolChoice{mode: "required"} }
+
+// ToolChoiceFunction forces a call to the named function tool.
+func ToolChoiceFunction(name string) *ToolChoice {
+	return &ToolChoice{mode: ToolTypeFunction, function: name}
+}
+
+// Mode returns "auto", "none", "required", or "function".
+func (tc ToolChoice) Mode() string { return tc.mode }
+
+// FunctionName returns the forced function's name, or "" for the other modes.
+func (tc ToolChoice) FunctionName() string { return tc.function }
+
+// MarshalJSON encodes the modes as strings and a forced function as
+// {"type":"function","function":{"name":...}}.
+func (tc ToolChoice) MarshalJSON() ([]byte, error) {
+	if tc.mode == ToolTypeFunction {
+		return json.Marshal(map[string]interface{}{
+			"type":     ToolTypeFunction,
+			"function": map[string]string{"name": tc.function},
+		})
+	}
+	return json.Marshal(tc.mode)
+}
+
+// UnmarshalJSON decodes either form written by MarshalJSON.
+func (tc *ToolChoice) UnmarshalJSON(data []byte) error {
+	var mode string
+	if err := json.Unmarshal(data, &mode); err == nil {
+		*tc = ToolChoice{mode: mode}
+		return nil
+	}
+	var obj struct {
+		Type     string `json:"type"`
+		Function struct {
+			Name string `json:"name"`
+		} `json:"function"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("decode tool_choice: %w", err)
+	}
+	*tc = ToolChoice{mode: obj.Type, function: obj.Function.Name}
+	return nil
+}
+
+// validate checks the choice against the request's tools. A nil choice is valid.
+func (tc *ToolChoice) validate(tools []Tool) error {
+	if tc == nil {
+		return nil
+	}
+	switch tc.mode {
+	case "auto", "none":
+		return nil
+	case "required":
+		if len(tools) == 0 {
+			return invalidf("tool_choice", "tool_choice required needs at least one tool")
+		}
+		return nil
+	case ToolTypeFunction:
+		if tc.function == "" {
+			return invalidf("tool_choice", "tool_choice function needs a name")
+		}
+		for _, t := range tools {
+			if (t.Type == ToolTypeFunction || t.Type == "") && t.Function.Name == tc.function {
+				return nil
+			}
+		}
+		return invalidf("tool_choice", "tool_choice function %q is not among the request's tools", tc.function)
+	default:
+		return invalidf("tool_choice", "unknown tool_choice %q", tc.mode)
+	}
+}
+
+// ToolCall represents a tool call made by the assistant. For built-in tools,
+// the field matching Type holds what the server ran and its result.
 type ToolCall struct {
 	ID       string       `json:"id"`
 	Type     string       `json:"type"`
 	Function FunctionCall `json:"function"`
+
+	WebSearch       *WebSearchCall       `json:"web_search,omitempty"`
+	KnowledgeSearch *KnowledgeSearchCall `json:"knowledge_search,omitempty"`
+	CodeInterpreter *CodeInterpreterCall `json:"code_interpreter,omitempty"`
+}
+
+// MarshalJSON omits the function call for built-in tool calls, so history
+// containing them can be sent back unchanged.
+func (tc ToolCall) MarshalJSON() ([]byte, error) {
+	type toolCallAlias ToolCall
+	if tc.Type == ToolTypeFunction || tc.Type == "" {
+		return json.Marshal(toolCallAlias(tc))
+	}
+	return json.Marshal(struct {
+		toolCallAlias
+		Function *FunctionCall `json:"function,omitempty"`
+	}{toolCallAlias: toolCallAlias(tc)})
+}
+
+// BuiltIn reports whether the call was executed by the server rather than the caller.
+func (tc ToolCall) BuiltIn() bool {
+	return tc.Type != ToolTypeFunction && tc.Type != ""
 }
 
 // FunctionCall represents the function name and arguments in a tool call.
@@ -95,9 +479,59 @@ type FunctionCall struct {
 	Arguments string `json:"arguments"`
 }
 
-// ResponseFormat specifies the desired response format.
+// WebSearchCall is the server-side execution of a web search tool call.
+type WebSearchCall struct {
+	Query   string            `json:"query"`
+	Results []WebSearchResult `json:"results,omitempty"`
+}
+
+// WebSearchResult is a single web search hit.
+type WebSearchResult struct {
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// KnowledgeSearchCall is the server-side execution of a knowledge search tool call.
+type KnowledgeSearchCall struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results,omitempty"`
+}
+
+// CodeInterpreterCall is the server-side execution of a code interpreter tool call.
+type CodeInterpreterCall struct {
+	Code     string `json:"code"`
+	Output   string `json:"output,omitempty"`
+	Error    string `json:"error,omitempty"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// ResponseFormat specifies the desired response format: "text", "json_object",
+// or "json_schema" with JSONSchema set.
 type ResponseFormat struct {
-	Type string `json:"type"`
+	Type       string            `json:"type"`
+	JSONSchema *JSONSchemaFormat `json:"json_schema,omitempty"`
+}
+
+// JSONSchemaFormat constrains the reply to a JSON Schema.
+type JSONSchemaFormat struct {
+	// Name identifies the schema; letters, digits, underscores, and dashes only.
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Schema      interface{} `json:"schema"`
+	// Strict asks the server to enforce the schema exactly rather than as guidance.
+	Strict bool `json:"strict,omitempty"`
+}
+
+// JSONSchemaResponse returns a json_schema response format. Strict is set when
+// the schema meets strict-mode rules: every object lists all its properties as
+// required and sets additionalProperties to false, and no subschema is
+// unconstrained. Schemas with optional fields or maps are sent as guidance.
+func JSONSchemaResponse(name string, schema interface{}) *ResponseFormat {
+	return &ResponseFormat{
+		Type:       "json_schema",
+		JSONSchema: &JSONSchemaFormat{Name: name, Schema: schema, Strict: strictCompatible(schema)},
+	}
 }
 
 // ChatResponse represents a non-streaming chat completion response.
@@ -109,14 +543,207 @@ type ChatResponse struct {
 	Choices        []Choice `json:"choices"`
 	Usage          Usage    `json:"usage"`
 	ConversationID string   `json:"conversation_id,omitempty"`
+	// SystemFingerprint identifies the serving configuration. Together with
+	// ChatRequest.Seed it tells whether two responses are expected to reproduce.
+	SystemFingerprint string `json:"system_fingerprint,omitempty"`
+	// Backend and DeploymentID identify the inference backend and deployment
+	// that served the request, when the server reports them.
+	Backend      string `json:"backend,omitempty"`
+	DeploymentID string `json:"deployment_id,omitempty"`
+	// Citations lists the retrieved chunks the answer draws on. Spans maps ranges
+	// of the first choice's content to the chunk they came from. Both are returned
+	// when RequestOptions.IncludeCitations is set.
+	Citations []Citation     `json:"citations,omitempty"`
+	Spans     []CitationSpan `json:"spans,omitempty"`
+	// CognitiveTrace reports what the cognitive layer did for this request.
+	// It is returned when RequestOptions.IncludeCognitiveTrace is set.
+	CognitiveTrace *CognitiveTrace `json:"cognitive_trace,omitempty"`
+	// Confidence is the server's assessment of how well the answer is grounded.
+	// It is returned when RequestOptions.IncludeConfidence is set.
+	Confidence *AnswerConfidence `json:"confidence,omitempty"`
+	// ClientRequestID is the X-Client-Request-ID sent with the request.
+	ClientRequestID string `json:"-"`
+	// RequestID is the server's ID for the request, from the X-Request-ID
+	// response header; it matches UsageRecord.RequestID.
+	RequestID string `json:"-"`
+	// RateLimit is read from the response's X-RateLimit-* headers, if any.
+	RateLimit *RateLimitInfo `json:"-"`
+}
+
+// Reasons reported in AnswerConfidence.Reason when context is insufficient.
+const (
+	InsufficientNoResults     = "no_results"
+	InsufficientLowRelevance  = "low_relevance"
+	InsufficientContradictory = "contradictory_sources"
+)
+
+// AnswerConfidence reports how well an answer is supported by retrieved context.
+type AnswerConfidence struct {
+	// Score is the answer confidence, from 0 (unsupported) to 1.
+	Score float64 `json:"score"`
+	// RetrievalScore is the best relevance score among the retrieved chunks.
+	RetrievalScore float64 `json:"retrieval_score,omitempty"`
+	// InsufficientContext is set when retrieval was too weak to ground an answer;
+	// Reason is one of the Insufficient* constants.
+	InsufficientContext bool   `json:"insufficient_context"`
+	Reason              string `json:"reason,omitempty"`
+}
+
+// LowConfidence reports whether the response should be routed for review: the
+// server flagged insufficient context or scored the answer below threshold.
+// It returns false when no confidence was returned.
+func (r *ChatResponse) LowConfidence(threshold float64) bool {
+	if r.Confidence == nil {
+		return false
+	}
+	return r.Confidence.InsufficientContext || r.Confidence.Score < threshold
+}
+
+// Citation is a retrieved chunk cited by an answer.
+type Citation struct {
+	ChunkID    string  `json:"chunk_id"`
+	DocumentID string  `json:"document_id"`
+	Filename   string  `json:"filename,omitempty"`
+	Score      float64 `json:"score,omitempty"`
+}
+
+// CitationSpan maps a range of the answer text to its source chunk.
+// Start and End are character (Unicode code point) offsets into the answer,
+// with End exclusive.
+type CitationSpan struct {
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+	ChunkID string `json:"chunk_id"`
+}
+
+// Text returns the part of answer covered by the span, or "" if the span is
+// out of range.
+func (s CitationSpan) Text(answer string) string {
+	runes := []rune(answer)
+	if s.Start < 0 || s.End > len(runes) || s.Start >= s.End {
+		return ""
+	}
+	return string(runes[s.Start:s.End])
+}
+
+// CognitiveTrace is a debug report of the context the cognitive layer assembled
+// for a chat request.
+type CognitiveTrace struct {
+	FactsInjected      []TraceFact       `json:"facts_injected,omitempty"`
+	PreferencesApplied map[string]string `json:"preferences_applied,omitempty"`
+	ChunksRetrieved    []SearchResult    `json:"chunks_retrieved,omitempty"`
+	Cache              TraceCache        `json:"cache"`
+	// Translated reports whether retrieved chunks were translated (see TranslateContext).
+	Translated bool `json:"translated,omitempty"`
+	// ContextTokens is the number of prompt tokens added by the cognitive layer.
+	ContextTokens int `json:"context_tokens,omitempty"`
+}
+
+// TraceFact is a learned fact injected into the prompt.
+type TraceFact struct {
+	ID         int     `json:"id"`
+	Content    string  `json:"content"`
+	Confidence float64 `json:"confidence"`
+}
+
+// TraceCache reports the response cache lookup for a request.
+type TraceCache struct {
+	Consulted bool `json:"consulted"`
+	Hit       bool `json:"hit"`
+	// Similarity is the score of the closest cached entry, when consulted.
+	Similarity float64 `json:"similarity,omitempty"`
 }
 
 // Choice represents a single completion choice.
 type Choice struct {
-	Index        int         `json:"index"`
-	Message      Message     `json:"message"`
-	FinishReason string      `json:"finish_reason"`
-	LogProbs     interface{} `json:"logprobs"`
+	Index        int     `json:"index"`
+	Message      Message `json:"message"`
+	FinishReason string  `json:"finish_reason"`
+	// LogProbs is set when the request enabled ChatRequest.LogProbs.
+	LogProbs *LogProbs `json:"logprobs,omitempty"`
+	// Safety holds the server's moderation result for this choice's content,
+	// when it reports one.
+	Safety *SafetyAnnotations `json:"safety,omitempty"`
+}
+
+// Severities reported in SafetyCategory.Severity, from least to most severe.
+const (
+	SeveritySafe   = "safe"
+	SeverityLow    = "low"
+	SeverityMedium = "medium"
+	SeverityHigh   = "high"
+)
+
+// SafetyAnnotations is the server-side moderation result for generated content.
+type SafetyAnnotations struct {
+	Categories []SafetyCategory `json:"categories,omitempty"`
+	// Redactions lists spans the server replaced in the content.
+	Redactions []SafetyRedaction `json:"redactions,omitempty"`
+}
+
+// SafetyCategory is the moderation result for one category, such as
+// "violence" or "self_harm". Filtered is set when the server withheld or
+// redacted content because of it.
+type SafetyCategory struct {
+	Category string `json:"category"`
+	Severity string `json:"severity"`
+	Filtered bool   `json:"filtered,omitempty"`
+}
+
+// SafetyRedaction marks a span of the content replaced by the server. Start and
+// End are character (Unicode code point) offsets into the returned content,
+// with End exclusive, covering the replacement marker.
+type SafetyRedaction struct {
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	Category string `json:"category"`
+}
+
+var severityRank = map[string]int{SeveritySafe: 0, SeverityLow: 1, SeverityMedium: 2, SeverityHigh: 3}
+
+// Flagged reports whether any category is filtered or rated at or above
+// severity. Unknown severities are treated as high. It returns false for nil
+// annotations.
+func (a *SafetyAnnotations) Flagged(severity string) bool {
+	if a == nil {
+		return false
+	}
+	threshold, ok := severityRank[severity]
+	if !ok {
+		threshold = severityRank[SeverityHigh]
+	}
+	for _, c := range a.Categories {
+		rank, ok := severityRank[c.Severity]
+		if !ok {
+			rank = severityRank[SeverityHigh]
+		}
+		if c.Filtered || rank >= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// LogProbs holds the log probabilities of a choice's content tokens.
+type LogProbs struct {
+	Content []TokenLogProb `json:"content"`
+}
+
+// TokenLogProb is the log probability of one generated token. Bytes is the
+// token's UTF-8 encoding, which matters for tokens that split a character.
+// TopLogProbs lists the most likely alternatives, most likely first.
+type TokenLogProb struct {
+	Token       string       `json:"token"`
+	LogProb     float64      `json:"logprob"`
+	Bytes       []int        `json:"bytes,omitempty"`
+	TopLogProbs []TopLogProb `json:"top_logprobs,omitempty"`
+}
+
+// TopLogProb is an alternative token considered at a position.
+type TopLogProb struct {
+	Token   string  `json:"token"`
+	LogProb float64 `json:"logprob"`
+	Bytes   []int   `json:"bytes,omitempty"`
 }
 
 // Usage represents token usage information.
@@ -124,6 +751,38 @@ type Usage struct {
 	PromptTokens     int `json:"prompt_tokens"`
 	CompletionTokens int `json:"completion_tokens"`
 	TotalTokens      int `json:"total_tokens"`
+	// CachedTokens is the number of prompt tokens served from the prompt cache.
+	// It is filled from prompt_tokens_details.cached_tokens when the server
+	// reports it there.
+	CachedTokens            int                      `json:"cached_tokens,omitempty"`
+	PromptTokensDetails     *PromptTokensDetails     `json:"prompt_tokens_details,omitempty"`
+	CompletionTokensDetails *CompletionTokensDetails `json:"completion_tokens_details,omitempty"`
+}
+
+// PromptTokensDetails breaks down prompt token usage.
+type PromptTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+// CompletionTokensDetails breaks down completion token usage.
+// The prediction counts are reported when ChatRequest.Prediction is set.
+type CompletionTokensDetails struct {
+	AcceptedPredictionTokens int `json:"accepted_prediction_tokens"`
+	RejectedPredictionTokens int `json:"rejected_prediction_tokens"`
+}
+
+// UnmarshalJSON decodes usage and normalizes CachedTokens from the nested details.
+func (u *Usage) UnmarshalJSON(data []byte) error {
+	type usageAlias Usage
+	var raw usageAlias
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*u = Usage(raw)
+	if u.CachedTokens == 0 && u.PromptTokensDetails != nil {
+		u.CachedTokens = u.PromptTokensDetails.CachedTokens
+	}
+	return nil
 }
 
 // ─── Streaming ──────────────────────────────────────────────────────────────
@@ -136,6 +795,10 @@ type ChatStreamChunk struct {
 	Model   string        `json:"model"`
 	Choices []ChunkChoice `json:"choices"`
 	Usage   *Usage        `json:"usage,omitempty"`
+	// SystemFingerprint, Backend, and DeploymentID mirror the ChatResponse fields.
+	SystemFingerprint string `json:"system_fingerprint,omitempty"`
+	Backend           string `json:"backend,omitempty"`
+	DeploymentID      string `json:"deployment_id,omitempty"`
 }
 
 // ChunkChoice represents a single choice in a streaming chunk.
@@ -143,17 +806,38 @@ type ChunkChoice struct {
 	Index        int     `json:"index"`
 	Delta        Delta   `json:"delta"`
 	FinishReason *string `json:"finish_reason"`
+	// Safety is the moderation result for the content streamed so far. Servers
+	// usually send it on the final chunk of a choice.
+	Safety *SafetyAnnotations `json:"safety,omitempty"`
 }
 
 // Delta represents the incremental content in a streaming chunk.
 type Delta struct {
 	Role    string `json:"role,omitempty"`
 	Content string `json:"content,omitempty"`
+	// ToolCalls carries tool call fragments. Combine them with a ToolCallAccumulator.
+	ToolCalls []ToolCallDelta `json:"tool_calls,omitempty"`
+}
+
+// ToolCallDelta is a fragment of a streamed tool call. The first fragment for an
+// Index carries the ID, Type, and function name; later ones append to Arguments.
+type ToolCallDelta struct {
+	Index    int               `json:"index"`
+	ID       string            `json:"id,omitempty"`
+	Type     string            `json:"type,omitempty"`
+	Function FunctionCallDelta `json:"function"`
+}
+
+// FunctionCallDelta is a fragment of a streamed function call.
+type FunctionCallDelta struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
 }
 
 // ─── Request Options ────────────────────────────────────────────────────────
 
-// RequestOptions holds per-request header options for cognitive features.
+// RequestOptions holds per-request header options for cognitive features and
+// chat parameter overrides.
 type RequestOptions struct {
 	// UserID sets the X-User-ID header for user profiling.
 	UserID string
@@ -161,6 +845,39 @@ type RequestOptions struct {
 	ConversationID string
 	// CognitiveDisabled sets X-Cognitive-Disabled to skip cognitive processing.
 	CognitiveDisabled bool
+	// TranslateContext sets X-Translate-Context so retrieved chunks are translated
+	// to the user's preferred language (from their profile) before prompt assembly.
+	TranslateContext bool
+	// IncludeCitations sets X-Include-Citations so the response carries Citations
+	// and character-offset Spans linking the answer to source chunks.
+	IncludeCitations bool
+	// IncludeCognitiveTrace sets X-Include-Cognitive-Trace so the response carries a
+	// CognitiveTrace of injected facts, applied preferences, retrieved chunks, and
+	// cache lookups. Intended for debugging; it adds to the response size.
+	IncludeCognitiveTrace bool
+	// IncludeConfidence sets X-Include-Confidence so the response carries an
+	// AnswerConfidence score and an insufficient-context signal.
+	IncludeConfidence bool
+	// Namespace sets the X-Namespace header to scope the request to a tenant.
+	Namespace string
+	// Headers sets additional request headers (e.g. experiment or tracing tags).
+	Headers map[string]string
+	// ClientRequestID sets X-Client-Request-ID for this request instead of a generated ID.
+	ClientRequestID string
+	// Timeout bounds the call, retries included, overriding
+	// WithEndpointTimeouts; for a stream it bounds reading it to the end.
+	// Zero leaves only ctx and the http.Client timeout, which still caps
+	// non-streaming calls.
+	Timeout time.Duration
+
+	// Temperature, TopP, MaxTokens, and Seed override the ChatRequest fields of
+	// the same name for this call only, so a shared prototype request can be
+	// reused across goroutines without copying and mutating it. They apply to
+	// chat completions and are ignored by other endpoints.
+	Temperature *float64
+	TopP        *float64
+	MaxTokens   *int
+	Seed        *int
 }
 
 // ─── Models ─────────────────────────────────────────────────────────────────
@@ -187,21 +904,54 @@ type EmbeddingRequest struct {
 	Input      interface{} `json:"input"`
 	Model      string      `json:"model"`
 	Dimensions *int        `json:"dimensions,omitempty"`
+	// User identifies the end user for usage attribution, like ChatRequest.User.
+	User string `json:"user,omitempty"`
+	// EncodingFormat is EmbeddingEncodingFloat (the default) or
+	// EmbeddingEncodingBase64. Base64 responses are much smaller for
+	// high-dimensional vectors and are decoded transparently.
+	EncodingFormat string `json:"encoding_format,omitempty"`
+	// Float32 decodes vectors into EmbeddingData.Embedding32 instead of
+	// Embedding, halving their memory. It is not sent to the server.
+	Float32 bool `json:"-"`
 }
 
+// Embedding encoding formats.
+const (
+	EmbeddingEncodingFloat  = "float"
+	EmbeddingEncodingBase64 = "base64"
+)
+
 // EmbeddingResponse represents the response from the embeddings endpoint.
 type EmbeddingResponse struct {
 	Object string          `json:"object"`
 	Data   []EmbeddingData `json:"data"`
 	Model  string          `json:"model"`
 	Usage  EmbeddingUsage  `json:"usage"`
+	// RateLimit is read from the response's X-RateLimit-* headers, if any.
+	RateLimit *RateLimitInfo `json:"-"`
 }
 
 // EmbeddingData represents a single embedding vector.
 type EmbeddingData struct {
 	Object    string    `json:"object"`
 	Embedding []float64 `json:"embedding"`
-	Index     int       `json:"index"`
+	// Embedding32 holds the vector instead of Embedding when the request set
+	// Float32.
+	Embedding32 []float32 `json:"-"`
+	Index       int       `json:"index"`
+}
+
+// Vector32 returns the vector as float32, converting Embedding if the request
+// did not set Float32.
+func (d EmbeddingData) Vector32() []float32 {
+	if d.Embedding32 != nil || d.Embedding == nil {
+		return d.Embedding32
+	}
+	v := make([]float32, len(d.Embedding))
+	for i, x := range d.Embedding {
+		v[i] = float32(x)
+	}
+	return v
 }
 
 // EmbeddingUsage represents token usage for embeddings.
@@ -210,12 +960,137 @@ type EmbeddingUsage struct {
 	TotalTokens  int `json:"total_tokens"`
 }
 
+// EmbeddingModelInfo describes an embedding model's output dimensions.
+type EmbeddingModelInfo struct {
+	Model string `json:"model"`
+	// Dimensions is the model's native output size.
+	Dimensions int `json:"dimensions"`
+	// SupportedDimensions lists the reduced sizes EmbeddingRequest.Dimensions
+	// may request. FlexibleDimensions means any size from 1 to Dimensions.
+	SupportedDimensions []int `json:"supported_dimensions,omitempty"`
+	FlexibleDimensions  bool  `json:"flexible_dimensions,omitempty"`
+	MaxInputTokens      int   `json:"max_input_tokens,omitempty"`
+}
+
+// SupportsDimensions reports whether the model can return vectors of size n.
+func (i *EmbeddingModelInfo) SupportsDimensions(n int) bool {
+	if n == i.Dimensions {
+		return true
+	}
+	if i.FlexibleDimensions {
+		return n >= 1 && n <= i.Dimensions
+	}
+	for _, d := range i.SupportedDimensions {
+		if d == n {
+			return true
+		}
+	}
+	return false
+}
+
+// ─── Audio ──────────────────────────────────────────────────────────────────
+
+// Response formats for audio transcription and translation. JSON and
+// verbose JSON are decoded; the others are returned as AudioResponse.Text.
+const (
+	AudioFormatJSON        = "json"
+	AudioFormatVerboseJSON = "verbose_json"
+	AudioFormatText        = "text"
+	AudioFormatSRT         = "srt"
+	AudioFormatVTT         = "vtt"
+)
+
+// Timestamp granularities for verbose JSON transcriptions.
+const (
+	TimestampSegment = "segment"
+	TimestampWord    = "word"
+)
+
+// AudioRequest is a transcription or translation request. Language and
+// TimestampGranularities apply to transcriptions only.
+type AudioRequest struct {
+	// File is the audio content. It is read fully into the multipart body.
+	File io.Reader
+	// Filename's extension tells the server the audio format (e.g. "call.mp3").
+	Filename string
+	// Model defaults to the server's speech model.
+	Model string
+	// Language is the ISO-639-1 code of the spoken language; it improves
+	// accuracy and latency. Leave empty to have it detected.
+	Language string
+	// Prompt guides style or spelling, e.g. product names in the recording.
+	Prompt string
+	// ResponseFormat is one of the AudioFormat* constants. Defaults to JSON.
+	ResponseFormat string
+	Temperature    *float64
+	// TimestampGranularities requests segment and/or word timestamps. It
+	// requires AudioFormatVerboseJSON.
+	TimestampGranularities []string
+}
+
+// AudioResponse is the result of a transcription or translation. Task,
+// Language, Duration, Segments, and Words are set for verbose JSON responses;
+// Words only when word timestamps were requested.
+type AudioResponse struct {
+	Text     string         `json:"text"`
+	Task     string         `json:"task,omitempty"`
+	Language string         `json:"language,omitempty"`
+	Duration float64        `json:"duration,omitempty"`
+	Segments []AudioSegment `json:"segments,omitempty"`
+	Words    []AudioWord    `json:"words,omitempty"`
+}
+
+// AudioSegment is a timed span of the transcript. Start and End are seconds
+// from the beginning of the audio.
+type AudioSegment struct {
+	ID               int     `json:"id"`
+	Start            float64 `json:"start"`
+	End              float64 `json:"end"`
+	Text             string  `json:"text"`
+	Tokens           []int   `json:"tokens,omitempty"`
+	Temperature      float64 `json:"temperature,omitempty"`
+	AvgLogProb       float64 `json:"avg_logprob,omitempty"`
+	CompressionRatio float64 `json:"compression_ratio,omitempty"`
+	NoSpeechProb     float64 `json:"no_speech_prob,omitempty"`
+}
+
+// AudioWord is one transcribed word with its timing in seconds.
+type AudioWord struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// Output formats for CreateSpeech.
+const (
+	SpeechFormatMP3  = "mp3"
+	SpeechFormatOpus = "opus"
+	SpeechFormatAAC  = "aac"
+	SpeechFormatFLAC = "flac"
+	SpeechFormatWAV  = "wav"
+	SpeechFormatPCM  = "pcm"
+)
+
+// SpeechRequest is a text-to-speech request.
+type SpeechRequest struct {
+	// Model defaults to the server's speech model.
+	Model string `json:"model,omitempty"`
+	Input string `json:"input"`
+	Voice string `json:"voice"`
+	// ResponseFormat is one of the SpeechFormat* constants. Defaults to MP3.
+	ResponseFormat string `json:"response_format,omitempty"`
+	// Speed is the playback rate, from 0.25 to 4.0. Defaults to 1.0.
+	Speed *float64 `json:"speed,omitempty"`
+}
+
 // ─── Health ─────────────────────────────────────────────────────────────────
 
 // HealthResponse represents the response from the health endpoint.
 type HealthResponse struct {
 	Status  string `json:"status"`
 	Version string `json:"version"`
+	// Checks is set by servers that report per-component health.
+	Checks HealthChecks `json:"checks,omitempty"`
 }
 
 // ─── Documents (RAG) ────────────────────────────────────────────────────────
@@ -225,6 +1100,9 @@ type DocumentUploadRequest struct {
 	Content  string            `json:"content"`
 	Filename string            `json:"filename,omitempty"`
 	Tags     map[string]string `json:"tags,omitempty"`
+	// CallbackURL receives a DocumentEvent POST when indexing succeeds or fails,
+	// so ingestion pipelines don't need to poll GetDocument.
+	CallbackURL string `json:"callback_url,omitempty"`
 }
 
 // DocumentBatchUploadRequest represents a batch document upload request.
@@ -232,6 +1110,22 @@ type DocumentBatchUploadRequest struct {
 	Documents []DocumentUploadRequest `json:"documents"`
 }
 
+// DocumentFileUploadRequest represents a binary document upload (PDF, image, office file).
+type DocumentFileUploadRequest struct {
+	// File is the document content. It is read fully into the multipart body.
+	File     io.Reader
+	Filename string
+	Tags     map[string]string
+	// OCR runs optical character recognition on scanned pages and images so
+	// their text becomes searchable.
+	OCR bool
+	// OCRLanguages lists the languages to recognize (e.g. "eng", "deu").
+	// Leave empty to let the server detect them.
+	OCRLanguages []string
+	// CallbackURL receives a DocumentEvent POST when indexing succeeds or fails.
+	CallbackURL string
+}
+
 // DocumentResponse represents a document returned by the API.
 type DocumentResponse struct {
 	ID         string            `json:"id"`
@@ -239,10 +1133,24 @@ type DocumentResponse struct {
 	Status     string            `json:"status"`
 	ChunkCount int               `json:"chunk_count"`
 	Tags       map[string]string `json:"tags,omitempty"`
+	Summary    string            `json:"summary,omitempty"`
 	CreatedAt  string            `json:"created_at"`
 	Error      string            `json:"error,omitempty"`
 }
 
+// Document event types delivered to upload callback URLs.
+const (
+	DocumentEventIndexed = "document.indexed"
+	DocumentEventFailed  = "document.failed"
+)
+
+// DocumentEvent is the JSON payload POSTed to an upload's CallbackURL.
+type DocumentEvent struct {
+	Event      string           `json:"event"`
+	Document   DocumentResponse `json:"document"`
+	OccurredAt string           `json:"occurred_at"`
+}
+
 // DocumentListResponse represents the response from listing documents.
 type DocumentListResponse struct {
 	Object string             `json:"object"`
@@ -250,12 +1158,269 @@ type DocumentListResponse struct {
 	Total  int                `json:"total"`
 }
 
+// MaxGetDocumentsBatch is the number of IDs GetDocuments sends per request;
+// longer lists are split across several requests.
+const MaxGetDocumentsBatch = 100
+
+// DocumentBatchGetRequest represents a request for several documents by ID.
+type DocumentBatchGetRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// DocumentBatchGetResponse represents the documents found by GetDocuments.
+// IDs with no matching document are listed in NotFound rather than failing
+// the call.
+type DocumentBatchGetResponse struct {
+	Data     []DocumentResponse `json:"data"`
+	NotFound []string           `json:"not_found,omitempty"`
+}
+
 // DocumentDeleteResponse represents the response from deleting a document.
 type DocumentDeleteResponse struct {
 	ID      string `json:"id"`
 	Deleted bool   `json:"deleted"`
 }
 
+// Summary lengths for SummaryOptions.Length.
+const (
+	SummaryShort  = "short"
+	SummaryMedium = "medium"
+	SummaryLong   = "long"
+)
+
+// SummaryOptions controls document summarization.
+type SummaryOptions struct {
+	// Length is one of SummaryShort, SummaryMedium, or SummaryLong (server default: medium).
+	Length string `json:"length,omitempty"`
+	// Focus steers the summary toward a topic (e.g. "remediation steps").
+	Focus string `json:"focus,omitempty"`
+	// Store persists the summary as document metadata.
+	Store bool `json:"store,omitempty"`
+}
+
+// DocumentSummary represents the response from summarizing a document.
+type DocumentSummary struct {
+	DocumentID string `json:"document_id"`
+	Summary    string `json:"summary"`
+	Length     string `json:"length"`
+	Stored     bool   `json:"stored"`
+	Usage      Usage  `json:"usage"`
+}
+
+// DocumentTag is a tag key/value pair in use and the number of documents carrying it.
+type DocumentTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// DocumentTagListResponse represents the response from listing document tags.
+type DocumentTagListResponse struct {
+	Object string        `json:"object"`
+	Data   []DocumentTag `json:"data"`
+}
+
+// TagRenameRequest renames a tag across all documents. With Value empty, the key
+// itself is renamed for every value; otherwise only Key=Value is rewritten to
+// NewKey=NewValue (NewKey defaults to Key).
+type TagRenameRequest struct {
+	Key      string `json:"key"`
+	Value    string `json:"value,omitempty"`
+	NewKey   string `json:"new_key,omitempty"`
+	NewValue string `json:"new_value,omitempty"`
+}
+
+// TagMergeRequest rewrites every listed value of Key to Into, e.g. merging
+// "run-book" and "Runbook" into "runbook".
+type TagMergeRequest struct {
+	Key    string   `json:"key"`
+	Values []string `json:"values"`
+	Into   string   `json:"into"`
+}
+
+// TagUpdateResponse reports how many documents a tag rename or merge changed.
+type TagUpdateResponse struct {
+	Updated int `json:"updated"`
+}
+
+// ExportOptions controls knowledge base export.
+type ExportOptions struct {
+	// IncludeEmbeddings adds each chunk's vector to the archive. This makes the
+	// archive several times larger but allows restoring without re-embedding.
+	IncludeEmbeddings bool
+}
+
+// Export record types.
+const (
+	ExportRecordManifest = "manifest"
+	ExportRecordDocument = "document"
+	ExportRecordChunk    = "chunk"
+)
+
+// ExportRecord is one line of a knowledge base export archive.
+//
+// The archive is newline-delimited JSON. The first line is a "manifest" record
+// carrying the format version and export time. Each "document" record is followed
+// by the "chunk" records belonging to it, ordered by chunk index.
+type ExportRecord struct {
+	Type string `json:"type"`
+
+	// Manifest fields.
+	Version    int    `json:"version,omitempty"`
+	ExportedAt string `json:"exported_at,omitempty"`
+
+	// Document fields (Document is set for "document" records).
+	Document *DocumentResponse `json:"document,omitempty"`
+
+	// Chunk fields.
+	ChunkID    string    `json:"chunk_id,omitempty"`
+	DocumentID string    `json:"document_id,omitempty"`
+	ChunkIndex int       `json:"chunk_index,omitempty"`
+	Content    string    `json:"content,omitempty"`
+	Embedding  []float64 `json:"embedding,omitempty"`
+}
+
+// ReadKnowledgeBaseExport decodes an export archive, calling fn for each record.
+// Iteration stops at the first error returned by fn.
+func ReadKnowledgeBaseExport(r io.Reader, fn func(ExportRecord) error) error {
+	dec := json.NewDecoder(r)
+	for {
+		var rec ExportRecord
+		if err := dec.Decode(&rec); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("decode export record: %w", err)
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+}
+
+// SnapshotCreateRequest represents a request to create a knowledge base snapshot.
+type SnapshotCreateRequest struct {
+	Name string `json:"name"`
+}
+
+// KnowledgeSnapshot represents a point-in-time copy of documents, facts, and graph state.
+type KnowledgeSnapshot struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Status        string `json:"status"`
+	DocumentCount int    `json:"document_count"`
+	FactCount     int    `json:"fact_count"`
+	NodeCount     int    `json:"node_count"`
+	EdgeCount     int    `json:"edge_count"`
+	SizeBytes     int64  `json:"size_bytes"`
+	CreatedAt     string `json:"created_at"`
+	Error         string `json:"error,omitempty"`
+}
+
+// SnapshotListResponse represents the response from listing snapshots.
+type SnapshotListResponse struct {
+	Object string              `json:"object"`
+	Data   []KnowledgeSnapshot `json:"data"`
+	Total  int                 `json:"total"`
+}
+
+// SnapshotRestoreResponse represents the response from restoring a snapshot.
+type SnapshotRestoreResponse struct {
+	SnapshotID string `json:"snapshot_id"`
+	Status     string `json:"status"`
+}
+
+// Background job statuses.
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+	JobStatusCancelled = "cancelled"
+)
+
+// ReembedRequest represents a request to re-embed the knowledge base with a new model.
+type ReembedRequest struct {
+	NewModel string `json:"new_model"`
+	// Dimensions optionally reduces the new model's output dimensions.
+	Dimensions *int `json:"dimensions,omitempty"`
+	// BatchSize is the number of chunks embedded per backend call (server default if zero).
+	BatchSize int `json:"batch_size,omitempty"`
+}
+
+// ReembedJob represents the state of a re-embedding job.
+type ReembedJob struct {
+	ID              string `json:"id"`
+	Status          string `json:"status"`
+	OldModel        string `json:"old_model"`
+	NewModel        string `json:"new_model"`
+	Dimensions      int    `json:"dimensions,omitempty"`
+	TotalChunks     int    `json:"total_chunks"`
+	ProcessedChunks int    `json:"processed_chunks"`
+	FailedChunks    int    `json:"failed_chunks"`
+	StartedAt       string `json:"started_at,omitempty"`
+	CompletedAt     string `json:"completed_at,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// Progress returns the completed fraction of the job, from 0 to 1.
+func (j *ReembedJob) Progress() float64 {
+	if j.TotalChunks == 0 {
+		if j.Status == JobStatusCompleted {
+			return 1
+		}
+		return 0
+	}
+	return float64(j.ProcessedChunks) / float64(j.TotalChunks)
+}
+
+// Done reports whether the job has reached a terminal status.
+func (j *ReembedJob) Done() bool {
+	return j.Status == JobStatusCompleted || j.Status == JobStatusFailed || j.Status == JobStatusCancelled
+}
+
+// ─── Files ──────────────────────────────────────────────────────────────────
+
+// File purposes. The purpose decides which endpoints may reference the file.
+const (
+	FilePurposeBatch      = "batch"
+	FilePurposeFineTune   = "fine-tune"
+	FilePurposeAssistants = "assistants"
+	FilePurposeUserData   = "user_data"
+)
+
+// FileUploadRequest represents a file upload to /v1/files.
+type FileUploadRequest struct {
+	// File is the file content, streamed into the multipart body.
+	File     io.Reader
+	Filename string
+	// Purpose is one of the FilePurpose* constants.
+	Purpose string
+}
+
+// FileObject is a file stored with the Files API. CreatedAt is a Unix time.
+type FileObject struct {
+	ID        string `json:"id"`
+	Object    string `json:"object"`
+	Bytes     int64  `json:"bytes"`
+	CreatedAt int64  `json:"created_at"`
+	Filename  string `json:"filename"`
+	Purpose   string `json:"purpose"`
+	Status    string `json:"status,omitempty"`
+}
+
+// FileListResponse represents the response from listing files.
+type FileListResponse struct {
+	Object string       `json:"object"`
+	Data   []FileObject `json:"data"`
+}
+
+// FileDeleteResponse represents the response from deleting a file.
+type FileDeleteResponse struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+}
+
 // ─── Search (RAG) ───────────────────────────────────────────────────────────
 
 // SearchRequest represents a semantic search request.
@@ -284,6 +1449,34 @@ type SearchResponse struct {
 	Total  int            `json:"total"`
 }
 
+// ─── Language ───────────────────────────────────────────────────────────────
+
+// LanguageDetectRequest represents a language detection request.
+type LanguageDetectRequest struct {
+	Text string `json:"text"`
+}
+
+// LanguageDetectResponse represents the detected language of a text.
+type LanguageDetectResponse struct {
+	Language   string  `json:"language"`
+	Confidence float64 `json:"confidence"`
+}
+
+// TranslateRequest represents a translation request.
+// Languages are ISO 639-1 codes (e.g. "en", "de", "ja").
+type TranslateRequest struct {
+	Text       string `json:"text"`
+	TargetLang string `json:"target_lang"`
+	SourceLang string `json:"source_lang,omitempty"`
+}
+
+// TranslateResponse represents the response from the translation endpoint.
+type TranslateResponse struct {
+	Text       string `json:"text"`
+	SourceLang string `json:"source_lang"`
+	TargetLang string `json:"target_lang"`
+}
+
 // ─── Conversations ──────────────────────────────────────────────────────────
 
 // Conversation represents a conversation summary.
@@ -295,6 +1488,16 @@ type Conversation struct {
 	TurnCount int    `json:"turn_count"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
+	// Metadata is the metadata of the conversation's chat requests.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// ConversationListOptions filters ListConversationsWithOptions.
+type ConversationListOptions struct {
+	// Limit caps the number of results (server default: 50).
+	Limit int
+	// Metadata keeps only conversations whose metadata contains every pair.
+	Metadata map[string]string
 }
 
 // ConversationTurn represents a single turn in a conversation.
@@ -307,6 +1510,19 @@ type ConversationTurn struct {
 	CompletionTokens int    `json:"completion_tokens,omitempty"`
 	LatencyMs        int64  `json:"latency_ms,omitempty"`
 	CreatedAt        string `json:"created_at"`
+	// Metadata holds caller-supplied annotations set with AppendConversationTurn.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// ConversationTurnRequest is a turn appended with AppendConversationTurn.
+type ConversationTurnRequest struct {
+	Role             string            `json:"role"`
+	Content          string            `json:"content"`
+	Model            string            `json:"model,omitempty"`
+	PromptTokens     int               `json:"prompt_tokens,omitempty"`
+	CompletionTokens int               `json:"completion_tokens,omitempty"`
+	LatencyMs        int64             `json:"latency_ms,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
 }
 
 // ConversationListResponse represents the response from listing conversations.
@@ -326,6 +1542,7 @@ type ConversationDetail struct {
 	CreatedAt string             `json:"created_at"`
 	UpdatedAt string             `json:"updated_at"`
 	Turns     []ConversationTurn `json:"turns"`
+	Metadata  map[string]string  `json:"metadata,omitempty"`
 }
 
 // ConversationSearchResult represents a single search result from conversation search.
@@ -335,6 +1552,8 @@ type ConversationSearchResult struct {
 	Role           string `json:"role"`
 	Content        string `json:"content"`
 	CreatedAt      string `json:"created_at"`
+	// Metadata is the metadata of the turn's chat request.
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // ConversationSearchResponse represents the response from searching conversations.
@@ -392,6 +1611,39 @@ type ProfileUpdateRequest struct {
 	Preferences map[string]string `json:"preferences,omitempty"`
 }
 
+// ─── User Memories ──────────────────────────────────────────────────────────
+
+// UserMemory is a long-term memory scoped to one user. Unlike facts, which are
+// shared across all users, memories are only injected into that user's chats.
+type UserMemory struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id"`
+	Content   string `json:"content"`
+	Source    string `json:"source,omitempty"`
+	CreatedAt string `json:"created_at"`
+	// LastUsedAt is when the memory was last injected into a chat.
+	LastUsedAt string `json:"last_used_at,omitempty"`
+}
+
+// UserMemoryRequest represents a request to store a user memory.
+type UserMemoryRequest struct {
+	Content string `json:"content"`
+	Source  string `json:"source,omitempty"`
+}
+
+// UserMemoryListResponse represents the response from listing a user's memories.
+type UserMemoryListResponse struct {
+	Object string       `json:"object"`
+	Data   []UserMemory `json:"data"`
+	Total  int          `json:"total"`
+}
+
+// UserMemoryDeleteResponse represents the response from deleting a user memory.
+type UserMemoryDeleteResponse struct {
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
 // ─── Knowledge Graph ────────────────────────────────────────────────────────
 
 // KnowledgeNode represents a node in the knowledge graph.
@@ -418,6 +1670,96 @@ type KnowledgeGraphResponse struct {
 	Edges  []KnowledgeEdge `json:"edges"`
 	Query  string          `json:"query"`
 	Total  int             `json:"total"`
+	// AsOf is the point in time the graph reflects, for time-travel queries.
+	AsOf string `json:"as_of,omitempty"`
+}
+
+// KnowledgeGraphStatsOptions scopes GetKnowledgeGraphStatsWithOptions.
+type KnowledgeGraphStatsOptions struct {
+	// Range bounds the hits counted for TopConcepts. Defaults to the last 7 days.
+	Range TimeRange
+	// TopN is the number of top concepts returned. Zero uses the server default.
+	TopN int
+}
+
+// KnowledgeGraphStats summarizes the knowledge graph: its size by node type and
+// edge relation, the concepts hit most often in a range, and concepts first
+// seen in the past week.
+type KnowledgeGraphStats struct {
+	TotalNodes      int            `json:"total_nodes"`
+	TotalEdges      int            `json:"total_edges"`
+	NodesByType     map[string]int `json:"nodes_by_type"`
+	EdgesByRelation map[string]int `json:"edges_by_relation"`
+	TopConcepts     []ConceptStat  `json:"top_concepts"`
+	NewConcepts     []ConceptStat  `json:"new_concepts"`
+	// From and To echo the range TopConcepts covers, in RFC 3339.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// ConceptStat is a knowledge graph node with its hit count in the reported
+// range. FirstSeenAt is an RFC 3339 timestamp.
+type ConceptStat struct {
+	ID          string `json:"id"`
+	Label       string `json:"label"`
+	Type        string `json:"type"`
+	Hits        int    `json:"hits"`
+	FirstSeenAt string `json:"first_seen_at,omitempty"`
+}
+
+// Entity types returned by ExtractEntities.
+const (
+	EntityHost         = "host"
+	EntityIP           = "ip"
+	EntityCVE          = "cve"
+	EntityOrganization = "organization"
+	EntityProduct      = "product"
+	EntityPerson       = "person"
+)
+
+// EntityExtractionRequest represents an entity and keyword extraction request.
+// Set either Text or DocumentID.
+type EntityExtractionRequest struct {
+	Text       string `json:"text,omitempty"`
+	DocumentID string `json:"document_id,omitempty"`
+	// Types restricts extraction to the given entity types. Empty means all.
+	Types []string `json:"types,omitempty"`
+	// Apply adds extracted entities to the document's tags and the knowledge graph.
+	Apply bool `json:"apply,omitempty"`
+}
+
+// Entity represents a typed entity found in the text.
+type Entity struct {
+	Type       string  `json:"type"`
+	Value      string  `json:"value"`
+	Count      int     `json:"count"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Keyword represents a salient keyword with its relevance score.
+type Keyword struct {
+	Term  string  `json:"term"`
+	Score float64 `json:"score"`
+}
+
+// EntityExtractionResponse represents the response from entity extraction.
+type EntityExtractionResponse struct {
+	DocumentID string            `json:"document_id,omitempty"`
+	Entities   []Entity          `json:"entities"`
+	Keywords   []Keyword         `json:"keywords"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Nodes      []KnowledgeNode   `json:"nodes,omitempty"`
+}
+
+// EntitiesOfType returns the extracted entities with the given type.
+func (r *EntityExtractionResponse) EntitiesOfType(entityType string) []Entity {
+	var out []Entity
+	for _, e := range r.Entities {
+		if e.Type == entityType {
+			out = append(out, e)
+		}
+	}
+	return out
 }
 
 // ─── Learned Facts ──────────────────────────────────────────────────────────
@@ -432,6 +1774,22 @@ type Fact struct {
 	Verified       bool    `json:"verified"`
 	UsedCount      int     `json:"used_count"`
 	CreatedAt      string  `json:"created_at"`
+	// SupportingChunks lists the documents and chunks cited as evidence for the fact.
+	SupportingChunks []SupportingChunk `json:"supporting_chunks,omitempty"`
+}
+
+// SupportingChunk is a document passage linked to a fact as evidence.
+type SupportingChunk struct {
+	ChunkID    string `json:"chunk_id,omitempty"`
+	DocumentID string `json:"document_id"`
+	Filename   string `json:"filename,omitempty"`
+	Content    string `json:"content,omitempty"`
+}
+
+// FactLinkRequest represents a request to link a fact to a supporting document or chunk.
+type FactLinkRequest struct {
+	DocumentID string `json:"document_id,omitempty"`
+	ChunkID    string `json:"chunk_id,omitempty"`
 }
 
 // FactCreateRequest represents a request to create a single fact.
@@ -521,6 +1879,115 @@ type UsageRecentResponse struct {
 	Data   []UsageRecord `json:"data"`
 }
 
+// TimeRange bounds a report. A zero From or To leaves that side open.
+type TimeRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// LastDays returns the range covering the n days up to now.
+func LastDays(n int) TimeRange {
+	now := time.Now()
+	return TimeRange{From: now.AddDate(0, 0, -n), To: now}
+}
+
+// query adds the range to params as RFC 3339 "from" and "to" values.
+func (r TimeRange) query(params url.Values) {
+	if !r.From.IsZero() {
+		params.Set("from", r.From.UTC().Format(time.RFC3339))
+	}
+	if !r.To.IsZero() {
+		params.Set("to", r.To.UTC().Format(time.RFC3339))
+	}
+}
+
+// UserUsage is one user's activity over a time range.
+type UserUsage struct {
+	UserID           string         `json:"user_id"`
+	Requests         int            `json:"requests"`
+	Turns            int            `json:"turns"`
+	PromptTokens     int            `json:"prompt_tokens"`
+	CompletionTokens int            `json:"completion_tokens"`
+	TotalTokens      int            `json:"total_tokens"`
+	AvgLatencyMs     float64        `json:"avg_latency_ms"`
+	ByModel          []UsageByModel `json:"by_model,omitempty"`
+	// PositiveFeedback and NegativeFeedback count the user's feedback on
+	// responses in the range.
+	PositiveFeedback int `json:"positive_feedback"`
+	NegativeFeedback int `json:"negative_feedback"`
+	// From and To echo the range the server aggregated, in RFC 3339.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// FeedbackRatio returns the share of the user's feedback that was positive, or
+// -1 if the user gave none.
+func (u *UserUsage) FeedbackRatio() float64 {
+	total := u.PositiveFeedback + u.NegativeFeedback
+	if total == 0 {
+		return -1
+	}
+	return float64(u.PositiveFeedback) / float64(total)
+}
+
+// Usage alert metrics.
+const (
+	// UsageMetricTokens is the total tokens consumed within the window.
+	UsageMetricTokens = "tokens"
+	// UsageMetricRequests is the number of requests within the window.
+	UsageMetricRequests = "requests"
+	// UsageMetricErrorRate is the fraction (0-1) of requests with a 5xx or 429 status.
+	UsageMetricErrorRate = "error_rate"
+)
+
+// UsageAlertRequest represents a request to register a usage-threshold notification.
+type UsageAlertRequest struct {
+	Metric    string  `json:"metric"`
+	Threshold float64 `json:"threshold"`
+	// Window is the evaluation window as a duration string (e.g. "1h", "24h").
+	Window     string `json:"window"`
+	WebhookURL string `json:"webhook_url"`
+	// Model restricts the alert to a single model. Empty means all models.
+	Model string `json:"model,omitempty"`
+}
+
+// UsageAlert represents a registered usage alert.
+type UsageAlert struct {
+	ID              string  `json:"id"`
+	Metric          string  `json:"metric"`
+	Threshold       float64 `json:"threshold"`
+	Window          string  `json:"window"`
+	WebhookURL      string  `json:"webhook_url"`
+	Model           string  `json:"model,omitempty"`
+	Enabled         bool    `json:"enabled"`
+	LastTriggeredAt string  `json:"last_triggered_at,omitempty"`
+	CreatedAt       string  `json:"created_at"`
+}
+
+// UsageAlertListResponse represents the response from listing usage alerts.
+type UsageAlertListResponse struct {
+	Object string       `json:"object"`
+	Data   []UsageAlert `json:"data"`
+	Total  int          `json:"total"`
+}
+
+// UsageAlertDeleteResponse represents the response from deleting a usage alert.
+type UsageAlertDeleteResponse struct {
+	ID      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
+// UsageAlertEvent is the JSON payload POSTed to an alert's webhook URL.
+type UsageAlertEvent struct {
+	AlertID     string  `json:"alert_id"`
+	Metric      string  `json:"metric"`
+	Threshold   float64 `json:"threshold"`
+	Value       float64 `json:"value"`
+	Window      string  `json:"window"`
+	Model       string  `json:"model,omitempty"`
+	TriggeredAt string  `json:"triggered_at"`
+}
+
 // ─── Cache Stats ────────────────────────────────────────────────────────────
 
 // CacheStatsResponse represents the response from the cache stats endpoint.
@@ -535,13 +2002,142 @@ type CacheStatsResponse struct {
 	NewestEntry    string  `json:"newest_entry,omitempty"`
 }
 
+// ─── Retention ──────────────────────────────────────────────────────────────
+
+// Retention scopes.
+const (
+	RetentionConversations = "conversations"
+	RetentionUsage         = "usage"
+	RetentionCache         = "cache"
+)
+
+// RetentionPolicy limits how long records in a scope are kept.
+type RetentionPolicy struct {
+	Scope string
+	// MaxAge is the age after which records are purged, e.g. 90*24*time.Hour.
+	// Zero removes the limit.
+	MaxAge    time.Duration
+	UpdatedAt string
+}
+
+type retentionPolicyJSON struct {
+	Scope         string `json:"scope"`
+	MaxAgeSeconds int64  `json:"max_age_seconds"`
+	UpdatedAt     string `json:"updated_at,omitempty"`
+}
+
+// MarshalJSON encodes MaxAge as whole seconds.
+func (p RetentionPolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(retentionPolicyJSON{Scope: p.Scope, MaxAgeSeconds: int64(p.MaxAge / time.Second), UpdatedAt: p.UpdatedAt})
+}
+
+// UnmarshalJSON decodes MaxAge from whole seconds.
+func (p *RetentionPolicy) UnmarshalJSON(data []byte) error {
+	var raw retentionPolicyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = RetentionPolicy{Scope: raw.Scope, MaxAge: time.Duration(raw.MaxAgeSeconds) * time.Second, UpdatedAt: raw.UpdatedAt}
+	return nil
+}
+
+// RetentionPolicyListResponse represents the response from listing retention policies.
+type RetentionPolicyListResponse struct {
+	Object string            `json:"object"`
+	Data   []RetentionPolicy `json:"data"`
+}
+
+// PurgeResponse reports how many records PurgeExpired deleted per scope.
+type PurgeResponse struct {
+	ConversationsDeleted int    `json:"conversations_deleted"`
+	UsageRecordsDeleted  int    `json:"usage_records_deleted"`
+	CacheEntriesDeleted  int    `json:"cache_entries_deleted"`
+	PurgedAt             string `json:"purged_at"`
+}
+
 // ─── Readiness ──────────────────────────────────────────────────────────────
 
 // ReadyResponse represents the response from the readiness endpoint.
 type ReadyResponse struct {
-	Ready   bool              `json:"ready"`
-	Version string            `json:"version"`
-	Checks  map[string]string `json:"checks"`
+	Ready   bool         `json:"ready"`
+	Version string       `json:"version"`
+	Checks  HealthChecks `json:"checks"`
+}
+
+// Failed returns the checks not reporting "ok", so orchestration can react to
+// the specific dependency that is down.
+func (r *ReadyResponse) Failed() []ComponentCheck {
+	return r.Checks.Failed()
+}
+
+// ComponentCheck is the state of one dependency reported by Health or Ready.
+type ComponentCheck struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// OK reports whether the component's status is "ok".
+func (c ComponentCheck) OK() bool { return c.Status == "ok" }
+
+// Latency returns LatencyMs as a duration.
+func (c ComponentCheck) Latency() time.Duration {
+	return time.Duration(c.LatencyMs * float64(time.Millisecond))
+}
+
+// HealthChecks is the component list of a health or readiness report. It
+// decodes from an array of checks or from an object keyed by component name
+// whose values are check objects or bare status strings, the form older servers
+// send; checks decoded from an object are sorted by name.
+type HealthChecks []ComponentCheck
+
+// Get returns the check for the named component.
+func (h HealthChecks) Get(name string) (ComponentCheck, bool) {
+	for _, c := range h {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return ComponentCheck{}, false
+}
+
+// Failed returns the checks not reporting "ok".
+func (h HealthChecks) Failed() []ComponentCheck {
+	var failed []ComponentCheck
+	for _, c := range h {
+		if !c.OK() {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// UnmarshalJSON decodes any of the check forms described on HealthChecks.
+func (h *HealthChecks) UnmarshalJSON(data []byte) error {
+	var list []ComponentCheck
+	if err := json.Unmarshal(data, &list); err == nil {
+		*h = list
+		return nil
+	}
+	var byName map[string]json.RawMessage
+	if err := json.Unmarshal(data, &byName); err != nil {
+		return fmt.Errorf("checks: expected an array or object: %w", err)
+	}
+	out := make(HealthChecks, 0, len(byName))
+	for name, raw := range byName {
+		check := ComponentCheck{Name: name}
+		if err := json.Unmarshal(raw, &check.Status); err != nil {
+			if err := json.Unmarshal(raw, &check); err != nil {
+				return fmt.Errorf("checks.%s: %w", name, err)
+			}
+			check.Name = name
+		}
+		out = append(out, check)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	*h = out
+	return nil
 }
 
 // ─── Errors ─────────────────────────────────────────────────────────────────
@@ -550,10 +2146,79 @@ type ReadyResponse struct {
 type APIError struct {
 	StatusCode int
 	ErrorBody  ErrorResponse
+	// Method and Endpoint are the HTTP method and URL path of the failed
+	// request; the query is left out as it may carry user data.
+	Method   string
+	Endpoint string
+	// Body is the raw error response body.
+	Body []byte
+	// Err is the underlying error when the response could not be read in
+	// full, and nil otherwise.
+	Err error
+	// ClientRequestID is the X-Client-Request-ID of the failed request.
+	// Include it when reporting issues to HackersEra support.
+	ClientRequestID string
+	// RequestID is the server's ID for the request (X-Request-ID), when the
+	// response carried one.
+	RequestID string
+	// RetryAfter is the wait requested by a 429 or 503 response's Retry-After header.
+	RetryAfter time.Duration
+	// RateLimit is read from the response's X-RateLimit-* headers, if any.
+	RateLimit *RateLimitInfo
+	// Header is the error response's full header set.
+	Header http.Header
 }
 
+// maxErrorMessage caps the message in APIError.Error, for error bodies that
+// are not JSON (an HTML page from a proxy).
+const maxErrorMessage = 256
+
+// Error formats the error as
+//
+//	POST /v1/chat/completions: 429 rate_limit_error: message (request ID req_...)
+//
+// using the server's request ID when known and the client's otherwise.
 func (e *APIError) Error() string {
-	return e.ErrorBody.Error.Message
+	var b strings.Builder
+	if e.Method != "" {
+		b.WriteString(e.Method + " " + e.Endpoint + ": ")
+	}
+	b.WriteString(strconv.Itoa(e.StatusCode))
+	if typ := e.ErrorBody.Error.Type; typ != "" && typ != "unknown_error" {
+		b.WriteString(" " + typ)
+	}
+	if msg := strings.TrimSpace(e.ErrorBody.Error.Message); msg != "" {
+		if len(msg) > maxErrorMessage {
+			msg = msg[:maxErrorMessage] + "..."
+		}
+		b.WriteString(": " + msg)
+	} else if text := http.StatusText(e.StatusCode); text != "" {
+		b.WriteString(" " + text)
+	}
+	if id := e.RequestID; id != "" {
+		b.WriteString(" (request ID " + id + ")")
+	} else if e.ClientRequestID != "" {
+		b.WriteString(" (request ID " + e.ClientRequestID + ")")
+	}
+	return b.String()
+}
+
+// Unwrap returns Err.
+func (e *APIError) Unwrap() error { return e.Err }
+
+// ValidationError is returned for a request rejected client-side, before any
+// network call. Field names the offending JSON field.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid request: " + e.Message
+}
+
+func invalidf(field, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)}
 }
 
 // ErrorResponse represents the JSON error body from the API.
@@ -0,0 +1,307 @@
+// Package usage builds periodic usage digests: request and token totals, top
+// models and users, error rate, an estimated cost, and cache savings, as
+// structured data and as Markdown ready to post to a chat channel or email.
+//
+//	digest, err := usage.BuildDigestWithOptions(ctx, client, hackeserasdk.LastDays(7), usage.Options{
+//		Prices: map[string]usage.Price{
+//			hackeserasdk.ModelDefault: {Prompt: 0.50, Completion: 1.50},
+//		},
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Println(digest.Markdown())
+//
+// Model totals and the error rate come from the server's recent usage records
+// that fall in the period; Digest.Partial reports when those records do not
+// reach back to the start of the period. Users are the owners of conversations
+// active in the period. Cache statistics are the server's running totals, not
+// bounded by the period.
+package usage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/hackersera-dev-team/hackersera-ai-sdk"
+)
+
+// Price is the cost of a model per million tokens, in any currency.
+type Price struct {
+	Prompt     float64
+	Completion float64
+}
+
+// Options configures BuildDigestWithOptions.
+type Options struct {
+	// Prices maps model IDs to their prices. Models without a price are
+	// listed in Digest.UnpricedModels and left out of the cost estimate.
+	Prices map[string]Price
+	// Currency prefixes amounts in the Markdown. Defaults to "$".
+	Currency string
+	// TopN caps the models and users listed. Defaults to 5.
+	TopN int
+	// ConversationLimit caps how many conversations are read to find the
+	// period's users. Defaults to 1000.
+	ConversationLimit int
+}
+
+// ModelUsage is one model's activity in the period.
+type ModelUsage struct {
+	Model            string
+	Requests         int
+	Errors           int
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	// EstimatedCost is 0 for unpriced models.
+	EstimatedCost float64
+}
+
+// CacheSavings summarizes the response cache.
+type CacheSavings struct {
+	Hits        int64
+	TokensSaved int64
+	// EstimatedSavings values the saved tokens at the period's average cost
+	// per token.
+	EstimatedSavings float64
+}
+
+// Digest is a usage report over a period.
+type Digest struct {
+	From, To         time.Time
+	Requests         int
+	Errors           int
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	// ErrorRate is the share of requests answered with a 5xx or 429 status.
+	ErrorRate float64
+	// Partial is set when the server's recent usage records start after From,
+	// so the totals cover only the later part of the period.
+	Partial bool
+
+	EstimatedCost  float64
+	UnpricedModels []string
+
+	// TopModels and TopUsers are ordered by total tokens, highest first.
+	TopModels []ModelUsage
+	TopUsers  []sdk.UserUsage
+	// ActiveUsers counts every user seen in the period, not only TopUsers.
+	ActiveUsers int
+
+	Cache CacheSavings
+
+	currency string
+}
+
+// BuildDigest builds the digest for period with default Options, so without a
+// cost estimate.
+func BuildDigest(ctx context.Context, client *sdk.Client, period sdk.TimeRange) (*Digest, error) {
+	return BuildDigestWithOptions(ctx, client, period, Options{})
+}
+
+// BuildDigestWithOptions builds the digest for period. A zero period.To means
+// now. It fails if any of the usage, cache, or conversation endpoints fails.
+func BuildDigestWithOptions(ctx context.Context, client *sdk.Client, period sdk.TimeRange, opts Options) (*Digest, error) {
+	if opts.Currency == "" {
+		opts.Currency = "$"
+	}
+	if opts.TopN <= 0 {
+		opts.TopN = 5
+	}
+	if opts.ConversationLimit <= 0 {
+		opts.ConversationLimit = 1000
+	}
+	if period.To.IsZero() {
+		period.To = time.Now()
+	}
+	d := &Digest{From: period.From, To: period.To, currency: opts.Currency}
+
+	recent, err := client.GetRecentUsage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("recent usage: %w", err)
+	}
+	d.addRecords(recent.Data, period, opts)
+
+	if err := d.addUsers(ctx, client, period, opts); err != nil {
+		return nil, err
+	}
+
+	cache, err := client.GetCacheStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cache stats: %w", err)
+	}
+	d.Cache = CacheSavings{Hits: cache.TotalHits, TokensSaved: cache.TokensSaved}
+	if d.TotalTokens > 0 {
+		d.Cache.EstimatedSavings = float64(cache.TokensSaved) * d.EstimatedCost / float64(d.TotalTokens)
+	}
+	return d, nil
+}
+
+// addRecords totals the records inside period by model.
+func (d *Digest) addRecords(records []sdk.UsageRecord, period sdk.TimeRange, opts Options) {
+	byModel := map[string]*ModelUsage{}
+	var oldest time.Time
+	for _, rec := range records {
+		at, err := time.Parse(time.RFC3339, rec.CreatedAt)
+		if err != nil {
+			continue
+		}
+		if oldest.IsZero() || at.Before(oldest) {
+			oldest = at
+		}
+		if at.Before(period.From) || at.After(period.To) {
+			continue
+		}
+		m := byModel[rec.Model]
+		if m == nil {
+			m = &ModelUsage{Model: rec.Model}
+			byModel[rec.Model] = m
+		}
+		m.Requests++
+		m.PromptTokens += rec.PromptTokens
+		m.CompletionTokens += rec.CompletionTokens
+		m.TotalTokens += rec.TotalTokens
+		if rec.StatusCode >= 500 || rec.StatusCode == 429 {
+			m.Errors++
+		}
+	}
+	d.Partial = oldest.IsZero() || oldest.After(period.From)
+
+	models := make([]ModelUsage, 0, len(byModel))
+	for _, m := range byModel {
+		if price, ok := opts.Prices[m.Model]; ok {
+			m.EstimatedCost = (float64(m.PromptTokens)*price.Prompt + float64(m.CompletionTokens)*price.Completion) / 1e6
+		} else {
+			d.UnpricedModels = append(d.UnpricedModels, m.Model)
+		}
+		d.Requests += m.Requests
+		d.Errors += m.Errors
+		d.PromptTokens += m.PromptTokens
+		d.CompletionTokens += m.CompletionTokens
+		d.TotalTokens += m.TotalTokens
+		d.EstimatedCost += m.EstimatedCost
+		models = append(models, *m)
+	}
+	sort.Strings(d.UnpricedModels)
+	sort.Slice(models, func(i, j int) bool {
+		if models[i].TotalTokens != models[j].TotalTokens {
+			return models[i].TotalTokens > models[j].TotalTokens
+		}
+		return models[i].Model < models[j].Model
+	})
+	if len(models) > opts.TopN {
+		models = models[:opts.TopN]
+	}
+	d.TopModels = models
+	if d.Requests > 0 {
+		d.ErrorRate = float64(d.Errors) / float64(d.Requests)
+	}
+}
+
+// addUsers fetches the usage of every user with a conversation active in
+// period and keeps the heaviest.
+func (d *Digest) addUsers(ctx context.Context, client *sdk.Client, period sdk.TimeRange, opts Options) error {
+	convos, err := client.ListConversationsWithOptions(ctx, sdk.ConversationListOptions{Limit: opts.ConversationLimit})
+	if err != nil {
+		return fmt.Errorf("list conversations: %w", err)
+	}
+	seen := map[string]bool{}
+	var users []sdk.UserUsage
+	for _, c := range convos.Data {
+		if c.UserID == "" || seen[c.UserID] {
+			continue
+		}
+		if at, err := time.Parse(time.RFC3339, c.UpdatedAt); err == nil && at.Before(period.From) {
+			continue
+		}
+		seen[c.UserID] = true
+		u, err := client.GetUserUsage(ctx, c.UserID, period)
+		if err != nil {
+			return fmt.Errorf("usage of user %s: %w", c.UserID, err)
+		}
+		if u.UserID == "" {
+			u.UserID = c.UserID
+		}
+		users = append(users, *u)
+	}
+	d.ActiveUsers = len(users)
+	sort.Slice(users, func(i, j int) bool {
+		if users[i].TotalTokens != users[j].TotalTokens {
+			return users[i].TotalTokens > users[j].TotalTokens
+		}
+		return users[i].UserID < users[j].UserID
+	})
+	if len(users) > opts.TopN {
+		users = users[:opts.TopN]
+	}
+	d.TopUsers = users
+	return nil
+}
+
+// Markdown renders the digest as a Markdown report.
+func (d *Digest) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Usage digest: %s to %s\n\n", d.From.Format("2006-01-02"), d.To.Format("2006-01-02"))
+	fmt.Fprintf(&b, "- **Requests:** %s (%.1f%% errors)\n", thousands(d.Requests), d.ErrorRate*100)
+	fmt.Fprintf(&b, "- **Tokens:** %s (%s prompt, %s completion)\n",
+		thousands(d.TotalTokens), thousands(d.PromptTokens), thousands(d.CompletionTokens))
+	fmt.Fprintf(&b, "- **Estimated cost:** %s", d.money(d.EstimatedCost))
+	if len(d.UnpricedModels) > 0 {
+		fmt.Fprintf(&b, " (excluding %s)", strings.Join(d.UnpricedModels, ", "))
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "- **Active users:** %d\n", d.ActiveUsers)
+	if d.Partial {
+		b.WriteString("\n_Usage records cover only part of this period._\n")
+	}
+
+	if len(d.TopModels) > 0 {
+		b.WriteString("\n## Top models\n\n| Model | Requests | Tokens | Errors | Est. cost |\n|---|---:|---:|---:|---:|\n")
+		for _, m := range d.TopModels {
+			fmt.Fprintf(&b, "| %s | %s | %s | %d | %s |\n",
+				m.Model, thousands(m.Requests), thousands(m.TotalTokens), m.Errors, d.money(m.EstimatedCost))
+		}
+	}
+
+	if len(d.TopUsers) > 0 {
+		b.WriteString("\n## Top users\n\n| User | Requests | Tokens | Positive feedback |\n|---|---:|---:|---:|\n")
+		for _, u := range d.TopUsers {
+			feedback := "n/a"
+			if ratio := u.FeedbackRatio(); ratio >= 0 {
+				feedback = fmt.Sprintf("%.0f%%", ratio*100)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", u.UserID, thousands(u.Requests), thousands(u.TotalTokens), feedback)
+		}
+	}
+
+	b.WriteString("\n## Cache\n\n")
+	fmt.Fprintf(&b, "%s hits saved %s tokens (about %s).\n",
+		thousands(int(d.Cache.Hits)), thousands(int(d.Cache.TokensSaved)), d.money(d.Cache.EstimatedSavings))
+	return b.String()
+}
+
+func (d *Digest) money(v float64) string {
+	currency := d.currency
+	if currency == "" {
+		currency = "$"
+	}
+	return currency + strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// thousands formats n with comma separators.
+func thousands(n int) string {
+	s := strconv.Itoa(n)
+	if n < 0 {
+		return "-" + thousands(-n)
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
@@ -0,0 +1,81 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/hackersera-dev-team/hackersera-ai-sdk"
+)
+
+func TestBuildDigest(t *testing.T) {
+	from := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	at := func(days int) string { return from.AddDate(0, 0, days).Format(time.RFC3339) }
+
+	mux := http.NewServeMux()
+	reply := func(path string, v interface{}) {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(v)
+		})
+	}
+	reply("/v1/usage/recent", sdk.UsageRecentResponse{Data: []sdk.UsageRecord{
+		{Model: "hackersera-pro", PromptTokens: 1_000_000, CompletionTokens: 500_000, TotalTokens: 1_500_000, StatusCode: 200, CreatedAt: at(1)},
+		{Model: "hackersera-pro", StatusCode: 503, CreatedAt: at(2)},
+		{Model: "hackersera-lite", PromptTokens: 100, CompletionTokens: 100, TotalTokens: 200, StatusCode: 200, CreatedAt: at(3)},
+		// Before the period: only proves the records reach back far enough.
+		{Model: "hackersera-pro", TotalTokens: 9_999_999, StatusCode: 200, CreatedAt: at(-1)},
+	}})
+	reply("/v1/conversations", sdk.ConversationListResponse{Data: []sdk.Conversation{
+		{ID: "c1", UserID: "alice", UpdatedAt: at(2)},
+		{ID: "c2", UserID: "bob", UpdatedAt: at(3)},
+		{ID: "c3", UserID: "alice", UpdatedAt: at(4)},
+		{ID: "c4", UserID: "carol", UpdatedAt: at(-3)},
+	}})
+	reply("/v1/usage/users/alice", sdk.UserUsage{UserID: "alice", Requests: 2, TotalTokens: 1_500_000, PositiveFeedback: 3, NegativeFeedback: 1})
+	reply("/v1/usage/users/bob", sdk.UserUsage{UserID: "bob", Requests: 1, TotalTokens: 200})
+	reply("/v1/cache/stats", sdk.CacheStatsResponse{TotalHits: 40, TokensSaved: 150_000})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	d, err := BuildDigestWithOptions(context.Background(), sdk.NewClient(srv.URL, "key"), sdk.TimeRange{From: from, To: to}, Options{
+		Prices: map[string]Price{"hackersera-pro": {Prompt: 2, Completion: 6}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Requests != 3 || d.Errors != 1 || d.TotalTokens != 1_500_200 || d.Partial {
+		t.Errorf("totals = %+v", d)
+	}
+	if len(d.TopModels) != 2 || d.TopModels[0].Model != "hackersera-pro" || d.TopModels[0].EstimatedCost != 5 {
+		t.Errorf("top models = %+v", d.TopModels)
+	}
+	if d.EstimatedCost != 5 || len(d.UnpricedModels) != 1 || d.UnpricedModels[0] != "hackersera-lite" {
+		t.Errorf("cost = %v, unpriced = %v", d.EstimatedCost, d.UnpricedModels)
+	}
+	if d.ActiveUsers != 2 || d.TopUsers[0].UserID != "alice" {
+		t.Errorf("users = %d %+v", d.ActiveUsers, d.TopUsers)
+	}
+	if d.Cache.TokensSaved != 150_000 || d.Cache.EstimatedSavings < 0.49 || d.Cache.EstimatedSavings > 0.51 {
+		t.Errorf("cache = %+v", d.Cache)
+	}
+
+	md := d.Markdown()
+	for _, want := range []string{
+		"# Usage digest: 2026-10-05 to 2026-10-12",
+		"**Requests:** 3 (33.3% errors)",
+		"**Estimated cost:** $5.00 (excluding hackersera-lite)",
+		"| hackersera-pro | 2 | 1,500,000 | 1 | $5.00 |",
+		"| alice | 2 | 1,500,000 | 75% |",
+		"40 hits saved 150,000 tokens (about $0.50).",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Package vectors provides the vector math needed for simple local semantic
+// matching over HackersEra AI embeddings, without an external library. Every
+// function accepts []float64 (EmbeddingData.Embedding) or []float32
+// (EmbeddingData.Embedding32).
+//
+//	corpus := make([][]float32, len(resp.Data))
+//	for i, d := range resp.Data {
+//		corpus[i] = d.Embedding32
+//	}
+//	for _, m := range vectors.TopK(query, corpus, 5) {
+//		fmt.Println(texts[m.Index], m.Score)
+//	}
+package vectors
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// Float is the element type of an embedding vector.
+type Float interface {
+	~float32 | ~float64
+}
+
+// DotProduct returns the dot product of a and b, accumulated in float64. It
+// panics if the lengths differ.
+func DotProduct[T Float](a, b []T) float64 {
+	if len(a) != len(b) {
+		panic("vectors: DotProduct of vectors with different lengths")
+	}
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+// CosineSimilarity returns the cosine of the angle between a and b, or 0 if
+// either is a zero vector. It panics if the lengths differ.
+func CosineSimilarity[T Float](a, b []T) float64 {
+	dot := DotProduct(a, b)
+	na, nb := DotProduct(a, a), DotProduct(b, b)
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
+
+// Normalize scales v to unit length in place, so DotProduct of normalized
+// vectors equals their cosine similarity. A zero vector is left unchanged.
+func Normalize[T Float](v []T) {
+	norm := math.Sqrt(DotProduct(v, v))
+	if norm == 0 {
+		return
+	}
+	for i := range v {
+		v[i] = T(float64(v[i]) / norm)
+	}
+}
+
+// Match is a corpus vector ranked by TopK.
+type Match struct {
+	// Index is the vector's position in the corpus.
+	Index int
+	Score float64
+}
+
+// TopK returns the k corpus vectors most similar to query by cosine
+// similarity, best first; ties keep corpus order. It scans the whole corpus,
+// which suits up to a few hundred thousand vectors. It panics if a corpus
+// vector's length differs from the query's.
+func TopK[T Float](query []T, corpus [][]T, k int) []Match {
+	if k <= 0 {
+		return nil
+	}
+	qn := math.Sqrt(DotProduct(query, query))
+	h := make(matchHeap, 0, min(k, len(corpus)))
+	for i, v := range corpus {
+		var score float64
+		if vn := math.Sqrt(DotProduct(v, v)); qn != 0 && vn != 0 {
+			score = DotProduct(query, v) / (qn * vn)
+		}
+		m := Match{Index: i, Score: score}
+		if len(h) < k {
+			heap.Push(&h, m)
+		} else if worse(h[0], m) {
+			h[0] = m
+			heap.Fix(&h, 0)
+		}
+	}
+	sort.Slice(h, func(i, j int) bool { return worse(h[j], h[i]) })
+	return h
+}
+
+// worse reports whether a ranks below b: a lower score, or an equal score
+// further down the corpus.
+func worse(a, b Match) bool {
+	if a.Score != b.Score {
+		return a.Score < b.Score
+	}
+	return a.Index > b.Index
+}
+
+// matchHeap is a min-heap with the worst kept match on top.
+type matchHeap []Match
+
+func (h matchHeap) Len() int           { return len(h) }
+func (h matchHeap) Less(i, j int) bool { return worse(h[i], h[j]) }
+func (h matchHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *matchHeap) Push(x any)        { *h = append(*h, x.(Match)) }
+func (h *matchHeap) Pop() any {
+	old := *h
+	m := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return m
+}
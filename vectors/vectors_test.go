@@ -0,0 +1,75 @@
+package vectors
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCosineSimilarity(t *testing.T) {
+	a64, b64 := []float64{1, 2, 3}, []float64{2, 4, 6}
+	a32, b32 := []float32{1, 2, 3}, []float32{-3, 0, 1}
+	if got := CosineSimilarity(a64, b64); math.Abs(got-1) > 1e-12 {
+		t.Errorf("expected parallel vectors to score 1, got %v", got)
+	}
+	if got := CosineSimilarity(a32, b32); math.Abs(got) > 1e-7 {
+		t.Errorf("expected orthogonal vectors to score 0, got %v", got)
+	}
+	if got := CosineSimilarity([]float32{0, 0}, []float32{1, 1}); got != 0 {
+		t.Errorf("expected 0 for a zero vector, got %v", got)
+	}
+	if got := DotProduct(a32, []float32{1, 1, 1}); got != 6 {
+		t.Errorf("expected dot product 6, got %v", got)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	v := []float32{3, 4}
+	Normalize(v)
+	if math.Abs(float64(v[0])-0.6) > 1e-6 || math.Abs(float64(v[1])-0.8) > 1e-6 {
+		t.Errorf("unexpected normalized vector %v", v)
+	}
+	zero := []float64{0, 0}
+	Normalize(zero)
+	if zero[0] != 0 || zero[1] != 0 {
+		t.Errorf("expected zero vector unchanged, got %v", zero)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for mismatched lengths")
+		}
+	}()
+	DotProduct([]float64{1}, []float64{1, 2})
+}
+
+func TestTopK(t *testing.T) {
+	query := []float32{1, 0}
+	corpus := [][]float32{
+		{0, 1},     // orthogonal
+		{1, 1},     // 45 degrees
+		{2, 0},     // parallel
+		{-1, 0},    // opposite
+		{0, 0},     // zero vector
+		{0.5, 0.5}, // same direction as {1, 1}
+	}
+	got := TopK(query, corpus, 3)
+	want := []int{2, 1, 5}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 matches, got %+v", got)
+	}
+	for i, m := range got {
+		if m.Index != want[i] {
+			t.Errorf("match %d: expected index %d, got %+v", i, want[i], got)
+		}
+	}
+	if math.Abs(got[0].Score-1) > 1e-9 || math.Abs(got[1].Score-got[2].Score) > 1e-9 {
+		t.Errorf("unexpected scores %+v", got)
+	}
+
+	if all := TopK([]float64{1, 0}, [][]float64{{1, 0}, {0, 1}}, 10); len(all) != 2 || all[0].Index != 0 {
+		t.Errorf("expected k larger than the corpus to return every vector ranked, got %+v", all)
+	}
+	if TopK(query, corpus, 0) != nil {
+		t.Error("expected no matches for k=0")
+	}
+}